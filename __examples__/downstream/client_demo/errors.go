@@ -0,0 +1,15 @@
+package client_demo
+
+import (
+	github_com_go_courier_statuserror "github.com/go-courier/statuserror"
+)
+
+var (
+	ErrClientClosedRequest    = &github_com_go_courier_statuserror.StatusErr{Key: "ClientClosedRequest", Code: 499000000, Msg: "ClientClosedRequest", CanBeTalkError: false}
+	ErrContextCanceled        = &github_com_go_courier_statuserror.StatusErr{Key: "ContextCanceled", Code: 499000000, Msg: "ContextCanceled", CanBeTalkError: false}
+	ErrInternalServerError    = &github_com_go_courier_statuserror.StatusErr{Key: "InternalServerError", Code: 500999001, Msg: "InternalServerError", CanBeTalkError: false}
+	ErrRequestFailed          = &github_com_go_courier_statuserror.StatusErr{Key: "RequestFailed", Code: 500000000, Msg: "RequestFailed", CanBeTalkError: false}
+	ErrRequestTransformFailed = &github_com_go_courier_statuserror.StatusErr{Key: "RequestTransformFailed", Code: 400000000, Msg: "RequestTransformFailed", CanBeTalkError: false}
+	ErrUnauthorized           = &github_com_go_courier_statuserror.StatusErr{Key: "Unauthorized", Code: 401999001, Msg: "Unauthorized", CanBeTalkError: true}
+	ErrUnknownError           = &github_com_go_courier_statuserror.StatusErr{Key: "UnknownError", Code: 500000000, Msg: "UnknownError", CanBeTalkError: false}
+)
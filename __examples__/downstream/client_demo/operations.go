@@ -193,6 +193,7 @@ func (req *FormURLEncoded) Invoke(c github_com_go_courier_courier.Client, metas
 }
 
 type GetByID struct {
+	// Length: [6, +∞)
 	ID       string                                                        `in:"path" name:"id" validate:"@string[6,]"`
 	Label    []string                                                      `in:"query" name:"label,omitempty"`
 	Name     string                                                        `in:"query" name:"name,omitempty"`
@@ -374,6 +375,7 @@ func (req *RedirectWhenError) Invoke(c github_com_go_courier_courier.Client, met
 }
 
 type RemoveByID struct {
+	// Length: [6, +∞)
 	ID string `in:"path" name:"id" validate:"@string[6,]"`
 }
 
@@ -431,6 +433,7 @@ func (req *ShowImage) Invoke(c github_com_go_courier_courier.Client, metas ...gi
 }
 
 type UpdateByID struct {
+	// Length: [6, +∞)
 	ID   string `in:"path" name:"id" validate:"@string[6,]"`
 	Data Data   `in:"body"`
 }
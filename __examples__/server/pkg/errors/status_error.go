@@ -13,10 +13,13 @@ func (StatusError) ServiceCode() int {
 const (
 	// InternalServerError
 	// Something wrong in server
+	// @i18n zh 服务器内部错误
 	InternalServerError StatusError = http.StatusInternalServerError*1e6 + iota + 1
 )
 
 const (
 	// @errTalk Unauthorized
+	// @i18n zh 未授权
+	// @i18n ja 認証されていません
 	Unauthorized StatusError = http.StatusUnauthorized*1e6 + iota + 1
 )
@@ -0,0 +1,16 @@
+package errors
+
+var StatusErrorMsgCatalog = map[string]map[string]string{
+	"ja": map[string]string{
+		"Unauthorized": "認証されていません",
+	},
+	"zh": map[string]string{
+		"InternalServerError": "服务器内部错误",
+		"Unauthorized":        "未授权",
+	},
+}
+
+func (v StatusError) LocalizedMsg(lang string) (string, bool) {
+	msg, ok := StatusErrorMsgCatalog[lang][v.Key()]
+	return msg, ok
+}
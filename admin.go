@@ -0,0 +1,107 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+)
+
+// NewAdminHandler creates handler for admin listener
+//
+//	/healthz        liveness
+//	/readyz         readiness, mounted by HttpTransport
+//	/debug/config   runtime settings, mounted by HttpTransport
+//	/debug/routes   registered routes
+//	/debug/vars     expvar
+//	/debug/pprof/*  pprof
+//
+// extra handlers (like /metrics) could be mounted by HttpTransport.AdminHandlers
+func NewAdminHandler(routeMetas []*HttpRouteMeta, handlers map[string]http.Handler) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("ok"))
+	})
+
+	mux.Handle("/debug/routes", routesDebugHandler(routeMetas))
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	for pattern, h := range handlers {
+		mux.Handle(pattern, h)
+	}
+
+	return mux
+}
+
+type RouteDebugInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationID"`
+	Operators   []string `json:"operators"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+func routesDebugHandler(routeMetas []*HttpRouteMeta) http.Handler {
+	routes := make([]RouteDebugInfo, 0, len(routeMetas))
+
+	for _, routeMeta := range routeMetas {
+		last := routeMeta.OperatorFactoryWithRouteMetas[len(routeMeta.OperatorFactoryWithRouteMetas)-1]
+
+		info := RouteDebugInfo{
+			Method:      routeMeta.Method(),
+			Path:        reHttpRouterPath.ReplaceAllString(routeMeta.Path(), "/{$1}"),
+			OperationID: last.ID,
+			Deprecated:  last.Deprecated,
+		}
+
+		for _, alias := range last.PathAliases {
+			info.Aliases = append(info.Aliases, reHttpRouterPath.ReplaceAllString(alias, "/{$1}"))
+		}
+
+		for _, opFactory := range routeMeta.OperatorFactoryWithRouteMetas {
+			if opFactory.NoOutput {
+				continue
+			}
+			info.Operators = append(info.Operators, opFactory.String())
+		}
+
+		routes = append(routes, info)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Path+routes[i].Method < routes[j].Path+routes[j].Method
+	})
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(rw).Encode(routes)
+	})
+}
+
+func (t *HttpTransport) newAdminServer() *http.Server {
+	handlers := map[string]http.Handler{
+		"/readyz": &t.readiness,
+	}
+	if t.RuntimeConfig != nil {
+		handlers["/debug/config"] = t.RuntimeConfig
+	}
+	for pattern, h := range t.AdminHandlers {
+		handlers[pattern] = h
+	}
+
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", t.AdminPort),
+		Handler: NewAdminHandler(t.routeMetas, handlers),
+	}
+}
@@ -0,0 +1,63 @@
+package httptransport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/httptransport/__examples__/server/cmd/app/routes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+func TestAdminHandler(t *testing.T) {
+	routeMetas := make([]*httptransport.HttpRouteMeta, 0)
+	for _, route := range routes.RootRouter.Routes() {
+		routeMetas = append(routeMetas, httptransport.NewHttpRouteMeta(route))
+	}
+
+	h := httptransport.NewAdminHandler(routeMetas, map[string]http.Handler{
+		"/metrics": http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, _ = rw.Write([]byte("# metrics"))
+		}),
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+		return rw
+	}
+
+	t.Run("healthz", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, get("/healthz").Code)
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		rw := get("/debug/routes")
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		list := make([]httptransport.RouteDebugInfo, 0)
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &list))
+		require.Len(t, list, len(routeMetas))
+
+		found := false
+		for _, r := range list {
+			if r.OperationID == "GetByID" {
+				found = true
+				require.Equal(t, "/demo/restful/{id}", r.Path)
+				require.Equal(t, http.MethodGet, r.Method)
+			}
+		}
+		require.True(t, found)
+	})
+
+	t.Run("pprof", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, get("/debug/pprof/").Code)
+	})
+
+	t.Run("extra handlers", func(t *testing.T) {
+		require.Equal(t, "# metrics", get("/metrics").Body.String())
+	})
+}
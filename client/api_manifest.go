@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-courier/httptransport/client/roundtrippers"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// APIManifest describes third-party API which called by request structs only, like
+//
+//	{
+//	  "name": "github",
+//	  "baseURL": "https://api.github.com",
+//	  "timeout": "10s",
+//	  "auth": { "type": "bearer", "valueFromEnv": "GITHUB_TOKEN" },
+//	  "errors": { "404": "RepoNotFound" },
+//	  "operations": [{ "request": "GetRepo", "response": "Repo" }]
+//	}
+//
+// request structs declare Method, Path and parameters with tags `in` and `name` as generated clients,
+// typed client of operations could be generated by generator.NewAPIClientGenerator.
+type APIManifest struct {
+	Name    string `json:"name"`
+	BaseURL string `json:"baseURL"`
+	// timeout of each call, like 10s
+	Timeout string   `json:"timeout,omitempty"`
+	Auth    *APIAuth `json:"auth,omitempty"`
+	// keys of status errors by status code of error responses
+	Errors     map[int]string `json:"errors,omitempty"`
+	Operations []APIOperation `json:"operations,omitempty"`
+}
+
+type APIAuth struct {
+	// bearer, basic, header or query
+	Type string `json:"type"`
+	// name of header or query
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+	// env var of value, to keep credentials out of manifest
+	ValueFromEnv string `json:"valueFromEnv,omitempty"`
+}
+
+type APIOperation struct {
+	// type name of request struct
+	Request string `json:"request"`
+	// type name of response body, like Repo or []Repo, empty when no body
+	Response string `json:"response,omitempty"`
+}
+
+func LoadAPIManifest(filename string) (*APIManifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAPIManifest(data)
+}
+
+func ParseAPIManifest(data []byte) (*APIManifest, error) {
+	m := &APIManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "invalid api manifest")
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *APIManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("missing name of api manifest")
+	}
+	if m.BaseURL == "" {
+		return errors.Errorf("missing baseURL of api %s", m.Name)
+	}
+	if m.Timeout != "" {
+		if _, err := time.ParseDuration(m.Timeout); err != nil {
+			return errors.Wrapf(err, "invalid timeout of api %s", m.Name)
+		}
+	}
+	for i, op := range m.Operations {
+		if op.Request == "" {
+			return errors.Errorf("missing request of operations[%d] of api %s", i, m.Name)
+		}
+	}
+	return nil
+}
+
+// NewClient creates Client by base url, auth, timeout and error mapping of manifest
+func (m *APIManifest) NewClient() (*Client, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(m.BaseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid baseURL of api %s", m.Name)
+	}
+
+	c := &Client{
+		Protocol: u.Scheme,
+		Host:     u.Hostname(),
+		BasePath: strings.TrimRight(u.Path, "/"),
+	}
+
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid port of api %s", m.Name)
+		}
+		c.Port = uint16(port)
+	}
+
+	if m.Timeout != "" {
+		c.Timeout, _ = time.ParseDuration(m.Timeout)
+	}
+
+	c.SetDefaults()
+
+	if m.Auth != nil {
+		value := m.Auth.Value
+		if m.Auth.ValueFromEnv != "" {
+			value = os.Getenv(m.Auth.ValueFromEnv)
+		}
+		if value == "" {
+			return nil, errors.Errorf("missing value of auth of api %s", m.Name)
+		}
+
+		c.HttpTransports = append(c.HttpTransports, roundtrippers.NewAuthRoundTripper(roundtrippers.AuthOptions{
+			Type:  m.Auth.Type,
+			Name:  m.Auth.Name,
+			Value: value,
+		}))
+	}
+
+	if len(m.Errors) > 0 {
+		newError := c.NewError
+
+		c.NewError = func(resp *http.Response) error {
+			err := newError(resp)
+			if key, ok := m.Errors[resp.StatusCode]; ok {
+				if statusErr, ok := err.(*statuserror.StatusErr); ok {
+					statusErr.Key = key
+				}
+			}
+			return err
+		}
+	}
+
+	return c, nil
+}
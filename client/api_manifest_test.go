@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIManifest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = rw.Write([]byte(`{"message":"bad credentials"}`))
+			return
+		}
+		if r.URL.Path != "/v1/data" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	_ = os.Setenv("TEST_API_TOKEN", "token")
+	defer os.Unsetenv("TEST_API_TOKEN")
+
+	filename := filepath.Join(t.TempDir(), "api.json")
+	_ = os.WriteFile(filename, []byte(`{
+  "name": "demo",
+  "baseURL": "`+srv.URL+`/v1/",
+  "timeout": "1s",
+  "auth": { "type": "bearer", "valueFromEnv": "TEST_API_TOKEN" },
+  "errors": { "401": "Unauthorized", "404": "DataNotFound" },
+  "operations": [{ "request": "GetData", "response": "Data" }]
+}`), os.ModePerm)
+
+	m, err := LoadAPIManifest(filename)
+	require.NoError(t, err)
+	require.Equal(t, []APIOperation{{Request: "GetData", Response: "Data"}}, m.Operations)
+
+	t.Run("call", func(t *testing.T) {
+		c, err := m.NewClient()
+		require.NoError(t, err)
+		require.Equal(t, "/v1", c.BasePath)
+
+		data := &Data{}
+		_, err = c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+	})
+
+	t.Run("error mapping", func(t *testing.T) {
+		m2 := *m
+		m2.Auth = &APIAuth{Type: "bearer", Value: "invalid"}
+
+		c, err := m2.NewClient()
+		require.NoError(t, err)
+
+		_, err = c.Do(context.Background(), &GetData{}).Into(nil)
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "Unauthorized", statusErr.Key)
+		require.Equal(t, http.StatusUnauthorized, statusErr.StatusCode())
+	})
+
+	t.Run("missing auth value", func(t *testing.T) {
+		m2 := *m
+		m2.Auth = &APIAuth{Type: "bearer", ValueFromEnv: "TEST_API_TOKEN_MISSING"}
+
+		_, err := m2.NewClient()
+		require.Error(t, err)
+	})
+}
+
+func TestParseAPIManifest(t *testing.T) {
+	_, err := ParseAPIManifest([]byte(`{"name":"demo"}`))
+	require.Error(t, err)
+
+	_, err = ParseAPIManifest([]byte(`{"name":"demo","baseURL":"https://api.demo.com","timeout":"10"}`))
+	require.Error(t, err)
+
+	_, err = ParseAPIManifest([]byte(`{"name":"demo","baseURL":"https://api.demo.com","operations":[{"response":"Data"}]}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,19 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"context"
+
+	"github.com/go-courier/courier"
+)
+
+// Call do request and decode response into TResp
+//
+//	data, meta, err := client.Call[Data](ctx, c, &GetByID{ID: "1"})
+func Call[TResp any](ctx context.Context, c courier.Client, req interface{}, metas ...courier.Metadata) (TResp, courier.Metadata, error) {
+	var resp TResp
+	meta, err := c.Do(ctx, req, metas...).Into(&resp)
+	return resp, meta, err
+}
@@ -0,0 +1,37 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("X-Meta", "1")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	t.Run("value", func(t *testing.T) {
+		data, meta, err := Call[Data](context.Background(), c, &GetData{})
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+		require.Equal(t, "1", meta.Get("X-Meta"))
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		data, _, err := Call[*Data](context.Background(), c, &GetData{})
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+	})
+}
@@ -1,14 +1,22 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net"
 	"net/http"
 	"net/textproto"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-courier/statuserror"
@@ -26,6 +34,9 @@ import (
 
 type HttpTransport func(rt http.RoundTripper) http.RoundTripper
 
+// ProtocolUnix of Client to call service on unix domain socket, Host should be path of the socket
+const ProtocolUnix = "unix"
+
 type Client struct {
 	Protocol              string
 	Host                  string
@@ -34,6 +45,58 @@ type Client struct {
 	RequestTransformerMgr *httptransport.RequestTransformerMgr
 	HttpTransports        []HttpTransport
 	NewError              func(resp *http.Response) error
+	// factories of error by status code, body of error response will be decoded into the created error.
+	// NewError will be used when status code not matched.
+	ErrorBodies map[int]func() error
+	// force HTTP/2 without fallback to HTTP/1.1
+	HTTP2 bool
+	// HTTP/2 over cleartext TCP with prior knowledge, works when Protocol is http
+	H2C bool
+	// reuse connections by a cached http.Client instead of short connection per request.
+	// DefaultHttpTransport in context only effects when the cached http.Client created.
+	KeepAlive bool
+	// max idle connections per host, works when KeepAlive
+	MaxIdleConnsPerHost int
+	// max time an idle connection will remain idle before closing itself, works when KeepAlive
+	IdleConnTimeout time.Duration
+	// limits connections of each host with queueing and tracks statistics of them, works when KeepAlive without HTTP2 or H2C
+	ConnPool *ConnPool
+	// dialer with dual-stack controls, overwrites DialContext of DefaultHttpTransport in context
+	Dialer *Dialer
+	// custom dial for connections, overwrites Dialer
+	DialContext func(ctx context.Context, network string, addr string) (net.Conn, error)
+	// transformers to decode response body when Content-Type of response missing or unknown
+	DecodeFallback transformers.FallbackChain
+	// tls options for https, overwrites TLSClientConfig of DefaultHttpTransport in context
+	TLS *TLS
+	// proxy url of outbound requests, scheme could be http, https or socks5.
+	// not works with HTTP2 or H2C.
+	Proxy string
+	// use proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY of environment when Proxy empty
+	ProxyFromEnvironment bool
+	// resolves Host like srv://user-service at request time
+	Resolver Resolver
+	// cookie jar for session cookies of upstream, shared by all calls
+	CookieJar http.CookieJar
+	// redirect policy, like NoRedirect or MaxRedirects(n), default follows at most 10 redirects
+	CheckRedirect CheckRedirect
+	// hosts to fail over to in order when dial or tls handshake to Host failed,
+	// with same Protocol, and same Port unless host:port
+	FailoverHosts []string
+	// max bytes of response body which Into decodes, zero means no limit.
+	// not limits IntoReader, which streams body to the caller.
+	MaxResponseBodyBytes int64
+	// max duration to read response body after headers received, distinct from Timeout
+	ReadTimeout time.Duration
+	// prefix of paths of requests, like /v1 of third-party APIs
+	BasePath string
+	// address to dial instead of Host, like 10.0.0.1 or 10.0.0.1:8443, port of Host used when missing.
+	// Host header and SNI of tls keep Host, for canary testing or bypassing service mesh.
+	ConnectTo string
+
+	mu         sync.Mutex
+	httpClient *http.Client
+	tlsConfig  *tls.Config
 }
 
 func (c *Client) SetDefaults() {
@@ -44,6 +107,17 @@ func (c *Client) SetDefaults() {
 	if c.HttpTransports == nil {
 		c.HttpTransports = []HttpTransport{roundtrippers.NewLogRoundTripper()}
 	}
+	if c.KeepAlive {
+		if c.MaxIdleConnsPerHost == 0 {
+			c.MaxIdleConnsPerHost = 10
+		}
+		if c.IdleConnTimeout == 0 {
+			c.IdleConnTimeout = 90 * time.Second
+		}
+	}
+	if c.Dialer != nil {
+		c.Dialer.SetDefaults()
+	}
 	if c.NewError == nil {
 		c.NewError = func(resp *http.Response) error {
 			return &statuserror.StatusErr{
@@ -87,61 +161,430 @@ func DefaultHttpTransportFromContext(ctx context.Context) *http.Transport {
 	return nil
 }
 
+// TimeoutDescriber could be implemented by request to limit the whole call,
+// generated clients implement it by x-timeout of operation
+type TimeoutDescriber interface {
+	Timeout() time.Duration
+}
+
+// CredentialDescriber could be implemented by request to select named credential,
+// see roundtrippers.NewCredentialsRoundTripper
+type CredentialDescriber interface {
+	Credential() string
+}
+
+// RetryableDescriber could be implemented by request to mark whether it could be retried by retry round tripper,
+// generated clients implement it by x-retry of operation
+type RetryableDescriber interface {
+	Retryable() bool
+}
+
 func (c *Client) Do(ctx context.Context, req interface{}, metas ...courier.Metadata) courier.Result {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cancel := context.CancelFunc(nil)
+
+	timeout := time.Duration(0)
+
+	if timeoutDescriber, ok := req.(TimeoutDescriber); ok {
+		timeout = timeoutDescriber.Timeout()
+	}
+
+	if opts := resolveRequestOptions(ctx); opts != nil && opts.timeout > 0 {
+		timeout = opts.timeout
+	}
+
+	if timeout > 0 {
+		// deadline of ctx wins when earlier
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	result := c.do(ctx, req, metas...)
+
+	if result.Response == nil {
+		if cancel != nil {
+			cancel()
+		}
+		return result
+	}
+
+	if cancel != nil {
+		// body should be read before cancel
+		result.Response.Body = &cancelOnCloseBody{ReadCloser: result.Response.Body, cancel: cancel}
+	}
+
+	return trackResult(result)
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (c *Client) do(ctx context.Context, req interface{}, metas ...courier.Metadata) *Result {
+	failoverUrls := []string(nil)
+
 	request, ok := req.(*http.Request)
 	if !ok {
-		request2, err := c.newRequest(ctx, req, metas...)
+		request2, urls, err := c.newRequest(ctx, req, metas...)
 		if err != nil {
 			return &Result{
 				Err:            statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed"),
 				NewError:       c.NewError,
+				ErrorBodies:    c.ErrorBodies,
 				TransformerMgr: c.RequestTransformerMgr.TransformerMgr,
 			}
 		}
 		request = request2
+		failoverUrls = urls
 	}
 
-	httpClient := ClientFromContext(ctx)
-	if httpClient == nil {
-		httpClient = GetShortConnClientContext(ctx, c.Timeout, c.HttpTransports...)
+	cancelRead := context.CancelFunc(func() {})
+	if c.ReadTimeout > 0 {
+		// to interrupt reading body when read timeout
+		readCtx, cancel := context.WithCancel(request.Context())
+		request = request.WithContext(readCtx)
+		cancelRead = cancel
 	}
 
-	resp, err := httpClient.Do(request)
-	if err != nil {
-		if errors.Unwrap(err) == context.Canceled {
+	httpClient := ClientFromContext(ctx)
+	if httpClient == nil {
+		hc, err := c.httpClientContext(ctx)
+		if err != nil {
+			cancelRead()
 			return &Result{
-				Err:            statuserror.Wrap(err, 499, "ClientClosedRequest"),
+				Err:            statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed"),
 				NewError:       c.NewError,
+				ErrorBodies:    c.ErrorBodies,
 				TransformerMgr: c.RequestTransformerMgr.TransformerMgr,
 			}
 		}
+		httpClient = hc
+	}
+
+	resp, attemptedHosts, err := doWithFailover(httpClient, request, failoverUrls)
+	if err != nil {
+		cancelRead()
+
+		withAttemptedHosts := func(statusErr *statuserror.StatusErr) *statuserror.StatusErr {
+			if len(failoverUrls) > 0 {
+				for _, host := range attemptedHosts {
+					statusErr = statusErr.AppendSource(host)
+				}
+			}
+			return statusErr
+		}
 
 		return &Result{
-			Err:            statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed"),
+			Err:            withAttemptedHosts(transportStatusErr(err)),
 			NewError:       c.NewError,
+			ErrorBodies:    c.ErrorBodies,
 			TransformerMgr: c.RequestTransformerMgr.TransformerMgr,
 		}
 	}
+
+	if c.ReadTimeout > 0 && resp.Body != nil {
+		resp.Body = newDeadlineBody(resp.Body, c.ReadTimeout, cancelRead)
+	} else {
+		cancelRead()
+	}
+
 	return &Result{
-		NewError:       c.NewError,
-		TransformerMgr: c.RequestTransformerMgr.TransformerMgr,
-		Response:       resp,
+		NewError:             c.NewError,
+		ErrorBodies:          c.ErrorBodies,
+		TransformerMgr:       c.RequestTransformerMgr.TransformerMgr,
+		DecodeFallback:       c.DecodeFallback,
+		MaxResponseBodyBytes: c.MaxResponseBodyBytes,
+		Response:             resp,
+	}
+}
+
+func (c *Client) httpClientContext(ctx context.Context) (*http.Client, error) {
+	if c.Proxy != "" && (c.HTTP2 || c.H2C) {
+		return nil, errors.Errorf("proxy is not supported with HTTP2 or H2C")
+	}
+
+	connectTo, connectToOfRequest := c.ConnectTo, false
+	if opts := resolveRequestOptions(ctx); opts != nil && opts.connectTo != "" {
+		connectTo, connectToOfRequest = opts.connectTo, true
+	}
+
+	if c.Dialer != nil || c.DialContext != nil || c.Protocol == ProtocolUnix || c.TLS != nil || c.Proxy != "" || c.ProxyFromEnvironment || connectTo != "" {
+		t, err := c.defaultHttpTransport(ctx, connectTo)
+		if err != nil {
+			return nil, err
+		}
+		ctx = ContextWithDefaultHttpTransport(ctx, t)
+	}
+
+	if c.CookieJar != nil {
+		ctx = ContextWithCookieJar(ctx, c.CookieJar)
+	}
+
+	if c.CheckRedirect != nil {
+		ctx = ContextWithCheckRedirect(ctx, c.CheckRedirect)
+	}
+
+	// connections to address of request should not be pooled with connections to Host
+	if !c.KeepAlive || connectToOfRequest {
+		if c.HTTP2 || c.H2C {
+			return GetHttp2ClientContext(ctx, c.Timeout, c.H2C, c.HttpTransports...), nil
+		}
+		return GetShortConnClientContext(ctx, c.Timeout, c.HttpTransports...), nil
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.httpClient == nil {
+		c.httpClient = c.newKeepAliveClient(ctx)
+	}
+
+	return c.httpClient, nil
+}
+
+func (c *Client) newKeepAliveClient(ctx context.Context) *http.Client {
+	client := &http.Client{
+		Timeout: c.Timeout,
+	}
+
+	applyHttpClientContext(ctx, client)
+
+	if c.HTTP2 || c.H2C {
+		client.Transport = newHttp2Transport(ctx, c.H2C)
+	} else {
+		t := DefaultHttpTransportFromContext(ctx)
+
+		if t != nil {
+			t = t.Clone()
+		} else {
+			t = &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 5 * time.Second,
+				ExpectContinueTimeout: 1 * time.Second,
+			}
+		}
+
+		t.DisableKeepAlives = false
+		t.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+		t.IdleConnTimeout = c.IdleConnTimeout
+
+		if c.ConnPool != nil {
+			dial := t.DialContext
+			if dial == nil {
+				dial = (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+			}
+			t.DialContext = c.ConnPool.DialContext(dial)
+		}
+
+		if err := http2.ConfigureTransport(t); err != nil {
+			panic(err)
+		}
+
+		client.Transport = t
+
+		if c.ConnPool != nil {
+			client.Transport = c.ConnPool.RoundTripper(client.Transport)
+		}
+	}
+
+	for i := range c.HttpTransports {
+		httpTransport := c.HttpTransports[i]
+		client.Transport = httpTransport(client.Transport)
+	}
+
+	return client
+}
+
+// defaultHttpTransport applies dial, connect to, TLS and proxy on DefaultHttpTransport in context
+func (c *Client) defaultHttpTransport(ctx context.Context, connectTo string) (*http.Transport, error) {
+	t := DefaultHttpTransportFromContext(ctx)
+
+	if t != nil {
+		t = t.Clone()
+	} else {
+		t = &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 5 * time.Second,
+			}).DialContext,
+			DisableKeepAlives:     !c.KeepAlive,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}
+	}
+
+	if c.Dialer != nil {
+		t.DialContext = c.Dialer.DialContext
+	}
+
+	if c.DialContext != nil {
+		t.DialContext = c.DialContext
+	}
+
+	if c.Protocol == ProtocolUnix {
+		dial := t.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{Timeout: 5 * time.Second}).DialContext
+		}
+		socket := c.Host
+
+		t.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			return dial(ctx, "unix", socket)
+		}
+	}
+
+	if connectTo != "" {
+		dial := t.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{Timeout: 5 * time.Second}).DialContext
+		}
+
+		t.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			return dial(ctx, network, connectAddrOf(connectTo, addr))
+		}
+	}
+
+	if c.TLS != nil {
+		cfg, err := c.tlsClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		// cloned, http2.ConfigureTransport appends NextProtos
+		t.TLSClientConfig = cfg.Clone()
+	}
+
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		t.Proxy = proxy
+	}
+
+	return t, nil
+}
+
+// tlsClientConfig loads certificates of TLS once
+func (c *Client) tlsClientConfig() (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tlsConfig == nil {
+		cfg, err := c.TLS.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		c.tlsConfig = cfg
+	}
+
+	return c.tlsConfig, nil
+}
+
+// CloseIdleConnections closes idle connections of the cached http.Client when KeepAlive
+func (c *Client) CloseIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.httpClient != nil {
+		c.httpClient.CloseIdleConnections()
+	}
+}
+
+// URL returns full url of path with Protocol, Host and Port of Client,
+// Host with HostSchemeSRV will not be resolved.
+func (c *Client) URL(path string) string {
+	return c.toUrl(path)
 }
 
 func (c *Client) toUrl(path string) string {
-	protocol := c.Protocol
+	if c.Protocol == ProtocolUnix {
+		// connections dialed to socket of Host
+		return toUrl("http", "localhost", 0, path)
+	}
+	return toUrl(c.Protocol, c.Host, c.Port, path)
+}
+
+// resolveUrls returns urls of path to try in order, by Host and FailoverHosts,
+// or endpoints resolved by Resolver when Host with HostSchemeSRV
+func (c *Client) resolveUrls(ctx context.Context, path string) ([]string, error) {
+	if !strings.HasPrefix(c.Host, HostSchemeSRV) {
+		urls := []string{c.toUrl(path)}
+		if c.Protocol != ProtocolUnix {
+			for _, host := range c.FailoverHosts {
+				port := c.Port
+				if _, _, err := net.SplitHostPort(host); err == nil {
+					port = 0
+				}
+				urls = append(urls, toUrl(c.Protocol, host, port, path))
+			}
+		}
+		return urls, nil
+	}
+
+	if c.Resolver == nil {
+		return nil, errors.Errorf("missing Resolver for host %s", c.Host)
+	}
+
+	serviceName := c.Host[len(HostSchemeSRV):]
+
+	endpoints := make([]Endpoint, 0)
+
+	if endpointsResolver, ok := c.Resolver.(EndpointsResolver); ok {
+		resolved, err := endpointsResolver.ResolveEndpoints(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved) == 0 {
+			return nil, errors.Errorf("no endpoints of %s", serviceName)
+		}
+		endpoints = resolved
+	} else {
+		scheme, host, port, err := c.Resolver.Resolve(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, Endpoint{Scheme: scheme, Host: host, Port: port})
+	}
+
+	urls := make([]string, len(endpoints))
+
+	for i, endpoint := range endpoints {
+		scheme := endpoint.Scheme
+		if scheme == "" {
+			scheme = c.Protocol
+		}
+		urls[i] = toUrl(scheme, endpoint.Host, endpoint.Port, path)
+	}
+
+	return urls, nil
+}
+
+func toUrl(protocol string, host string, port uint16, path string) string {
 	if protocol == "" {
 		protocol = "http"
 	}
-	url := fmt.Sprintf("%s://%s", protocol, c.Host)
-	if c.Port > 0 {
-		url = fmt.Sprintf("%s:%d", url, c.Port)
+	url := fmt.Sprintf("%s://%s", protocol, host)
+	if port > 0 {
+		url = fmt.Sprintf("%s:%d", url, port)
 	}
 	return url + path
 }
 
-func (c *Client) newRequest(ctx context.Context, req interface{}, metas ...courier.Metadata) (*http.Request, error) {
+// newRequest creates request to the first resolved url, and returns the rest for failover
+func (c *Client) newRequest(ctx context.Context, req interface{}, metas ...courier.Metadata) (*http.Request, []string, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -157,9 +600,42 @@ func (c *Client) newRequest(ctx context.Context, req interface{}, metas ...couri
 		path = pathDescriber.Path()
 	}
 
-	request, err := c.RequestTransformerMgr.NewRequestWithContext(ctx, method, c.toUrl(path), req)
+	path = c.BasePath + path
+
+	// operation id of outbound request, generated clients named request struct by operation id
+	if roundtrippers.OperationIDFromContext(ctx) == "" {
+		ctx = roundtrippers.ContextWithOperationID(ctx, reflect.Indirect(reflect.ValueOf(req)).Type().Name())
+	}
+
+	if credentialDescriber, ok := req.(CredentialDescriber); ok {
+		if roundtrippers.CredentialFromContext(ctx) == "" {
+			ctx = roundtrippers.ContextWithCredential(ctx, credentialDescriber.Credential())
+		}
+	}
+
+	if retryableDescriber, ok := req.(RetryableDescriber); ok {
+		if _, ok := roundtrippers.RetryableFromContext(ctx); !ok {
+			ctx = roundtrippers.ContextWithRetryable(ctx, retryableDescriber.Retryable())
+		}
+	}
+
+	opts := resolveRequestOptions(ctx)
+
+	urls := []string(nil)
+
+	if opts != nil && opts.host != "" {
+		urls = []string{opts.url(c, path)}
+	} else {
+		resolved, err := c.resolveUrls(ctx, path)
+		if err != nil {
+			return nil, nil, statuserror.Wrap(err, http.StatusServiceUnavailable, "ResolveFailed")
+		}
+		urls = resolved
+	}
+
+	request, err := c.RequestTransformerMgr.NewRequestWithContext(ctx, method, urls[0], req)
 	if err != nil {
-		return nil, statuserror.Wrap(err, http.StatusBadRequest, "RequestTransformFailed")
+		return nil, nil, statuserror.Wrap(err, http.StatusBadRequest, "RequestTransformFailed")
 	}
 
 	request = request.WithContext(ctx)
@@ -170,14 +646,37 @@ func (c *Client) newRequest(ctx context.Context, req interface{}, metas ...couri
 		}
 	}
 
-	return request, nil
+	if opts != nil {
+		opts.apply(request)
+	}
+
+	return request, urls[1:], nil
 }
 
+// ErrResultConsumed will be returned when body of Result consumed more than once
+var ErrResultConsumed = errors.New("body of result already consumed")
+
+// Result owns body of response,
+// one and only one of Into, IntoReader or Discard must be called to release the connection.
+// Build with tag `debug` to report results which never consumed.
 type Result struct {
 	TransformerMgr transformers.TransformerMgr
+	DecodeFallback transformers.FallbackChain
 	Response       *http.Response
 	NewError       func(resp *http.Response) error
+	ErrorBodies    map[int]func() error
 	Err            error
+	// max bytes of body to decode by Into, zero means no limit
+	MaxResponseBodyBytes int64
+
+	consumed int32
+}
+
+func (r *Result) consume() error {
+	if !atomic.CompareAndSwapInt32(&r.consumed, 0, 1) {
+		return ErrResultConsumed
+	}
+	return nil
 }
 
 func (r *Result) StatusCode() int {
@@ -194,7 +693,66 @@ func (r *Result) Meta() courier.Metadata {
 	return courier.Metadata{}
 }
 
+// ByStatus of Into decodes body into value registered by status code, for APIs of which schema varies by status.
+// value nil means body discarded without error even when status code not ok,
+// status codes not registered handled as usual, error returned when not ok, else body discarded.
+// Result.StatusCode tells which one decoded.
+//
+//	page, ticket := &Page{}, &Ticket{}
+//	_, err := c.Do(ctx, req).Into(client.ByStatus{
+//		http.StatusOK:       page,
+//		http.StatusAccepted: ticket,
+//		http.StatusNotFound: nil,
+//	})
+type ByStatus map[int]interface{}
+
+// On registers body for decoding response of statusCode, like ByStatus,
+// for error responses with schemas defined in openapi, which are decoded into typed values instead of StatusErr.
+//
+//	order, conflict := &Order{}, &OrderConflict{}
+//	_, err := c.Do(ctx, req).(*client.Result).
+//		On(http.StatusOK, order).
+//		On(http.StatusConflict, conflict).
+//		Do()
+func (r *Result) On(statusCode int, body interface{}) *StatusDecoder {
+	return (&StatusDecoder{result: r, byStatus: ByStatus{}}).On(statusCode, body)
+}
+
+// StatusDecoder collects values by status code for Result
+type StatusDecoder struct {
+	result   *Result
+	byStatus ByStatus
+}
+
+func (d *StatusDecoder) On(statusCode int, body interface{}) *StatusDecoder {
+	d.byStatus[statusCode] = body
+	return d
+}
+
+// Do decodes body into value registered by status code of response, see ByStatus
+func (d *StatusDecoder) Do() (courier.Metadata, error) {
+	return d.result.Into(d.byStatus)
+}
+
+// Into decodes body into value and closes body,
+// *io.ReadCloser works as IntoReader and io.Writer copies the raw body.
 func (r *Result) Into(body interface{}) (courier.Metadata, error) {
+	if err := r.consume(); err != nil {
+		return r.Meta(), err
+	}
+
+	if rc, ok := body.(*io.ReadCloser); ok {
+		stream, meta, err := r.intoReader()
+		if err == nil {
+			*rc = stream
+		}
+		return meta, err
+	}
+
+	return r.into(body)
+}
+
+func (r *Result) into(body interface{}) (courier.Metadata, error) {
 	defer func() {
 		if r.Response != nil && r.Response.Body != nil {
 			r.Response.Body.Close()
@@ -207,32 +765,63 @@ func (r *Result) Into(body interface{}) (courier.Metadata, error) {
 
 	meta := courier.Metadata(r.Response.Header)
 
-	if !isOk(r.Response.StatusCode) {
-		body = r.NewError(r.Response)
+	expected := false
+
+	if byStatus, ok := body.(ByStatus); ok {
+		body, expected = byStatus[r.Response.StatusCode]
+	}
+
+	if !expected && !isOk(r.Response.StatusCode) {
+		body = r.newError()
 	}
 
 	if body == nil {
 		return meta, nil
 	}
 
-	decode := func(body interface{}) error {
-		contentType := meta.Get(httpx.HeaderContentType)
+	var bodyReader io.Reader = r.Response.Body
 
-		if contentType != "" {
-			contentType, _, _ = mime.ParseMediaType(contentType)
+	if r.MaxResponseBodyBytes > 0 {
+		if r.Response.ContentLength > r.MaxResponseBodyBytes {
+			if err, ok := body.(error); ok {
+				// no need to read the body of error
+				return meta, err
+			}
+			return meta, responseBodyStatusErr(ErrResponseBodyTooLarge)
 		}
+		bodyReader = &limitedBody{Reader: bodyReader, remaining: r.MaxResponseBodyBytes}
+	}
+
+	decode := func(body interface{}) error {
+		contentType := meta.Get(httpx.HeaderContentType)
 
 		rv := reflect.ValueOf(body)
+		typ := typesutil.FromRType(rv.Type())
+
+		var transformer transformers.Transformer
+		var reader = bodyReader
+		var err error
+
+		if r.DecodeFallback.ShouldFallback(context.Background(), r.TransformerMgr, typ, contentType) {
+			transformer, reader, err = r.DecodeFallback.Resolve(context.Background(), r.TransformerMgr, typ, reader)
+		} else {
+			if contentType != "" {
+				contentType, _, _ = mime.ParseMediaType(contentType)
+			}
 
-		transformer, err := r.TransformerMgr.NewTransformer(context.Background(), typesutil.FromRType(rv.Type()), transformers.TransformerOption{
-			MIME: contentType,
-		})
+			transformer, err = r.TransformerMgr.NewTransformer(context.Background(), typ, transformers.TransformerOption{
+				MIME: contentType,
+			})
+		}
 
 		if err != nil {
 			return statuserror.Wrap(err, http.StatusInternalServerError, "ReadFailed")
 		}
 
-		if e := transformer.DecodeFromReader(r.Response.Body, rv, textproto.MIMEHeader(r.Response.Header)); e != nil {
+		if e := transformer.DecodeFromReader(reader, rv, textproto.MIMEHeader(r.Response.Header)); e != nil {
+			if statusErr := responseBodyStatusErr(e); statusErr != nil {
+				return statusErr
+			}
 			return statuserror.Wrap(e, http.StatusInternalServerError, "DecodeFailed")
 		}
 
@@ -241,13 +830,17 @@ func (r *Result) Into(body interface{}) (courier.Metadata, error) {
 
 	switch v := body.(type) {
 	case error:
-		// to unmarshal status error
+		// to unmarshal status error,
+		// when body could not be decoded, keep status code of response with the decode error
 		if err := decode(v); err != nil {
-			return meta, err
+			return meta, statuserror.Wrap(err, r.Response.StatusCode, "DecodeFailed")
 		}
 		return meta, v
 	case io.Writer:
-		if _, err := io.Copy(v, r.Response.Body); err != nil {
+		if _, err := io.Copy(v, bodyReader); err != nil {
+			if statusErr := responseBodyStatusErr(err); statusErr != nil {
+				return meta, statusErr
+			}
 			return meta, statuserror.Wrap(err, http.StatusInternalServerError, "WriteFailed")
 		}
 	default:
@@ -259,6 +852,149 @@ func (r *Result) Into(body interface{}) (courier.Metadata, error) {
 	return meta, nil
 }
 
+// IntoReader transfers ownership of response body to the caller, which must close it.
+// Client.Timeout still limits reading of the body, keep it zero for long-lived responses.
+// When status code is not ok, body will be decoded as error and closed.
+func (r *Result) IntoReader() (io.ReadCloser, courier.Metadata, error) {
+	if err := r.consume(); err != nil {
+		return nil, r.Meta(), err
+	}
+	return r.intoReader()
+}
+
+// Deprecated use IntoReader instead
+func (r *Result) Stream() (io.ReadCloser, courier.Metadata, error) {
+	return r.IntoReader()
+}
+
+// max bytes to drain for reusing connection, rest of body will be dropped with the connection
+const maxDiscardBytes = 256 << 10
+
+// Discard drains and closes body for reusing connection.
+// When status code is not ok, body will be decoded as error.
+func (r *Result) Discard() (courier.Metadata, error) {
+	if err := r.consume(); err != nil {
+		return r.Meta(), err
+	}
+
+	if r.Err != nil || !isOk(r.Response.StatusCode) {
+		return r.into(nil)
+	}
+
+	if r.Response.Body != nil {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(r.Response.Body, maxDiscardBytes))
+		r.Response.Body.Close()
+	}
+
+	return courier.Metadata(r.Response.Header), nil
+}
+
+func (r *Result) intoReader() (io.ReadCloser, courier.Metadata, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+
+	if !isOk(r.Response.StatusCode) {
+		meta, err := r.into(nil)
+		return nil, meta, err
+	}
+
+	meta := courier.Metadata(r.Response.Header)
+
+	if r.Response.ContentLength >= 0 && meta.Get(httpx.HeaderContentLength) == "" {
+		meta.Set(httpx.HeaderContentLength, strconv.FormatInt(r.Response.ContentLength, 10))
+	}
+
+	if r.Response.Body == nil {
+		return ioutil.NopCloser(bytes.NewReader(nil)), meta, nil
+	}
+
+	return r.Response.Body, meta, nil
+}
+
+// Events consumes server-sent events of response until stream ended or cb returns error
+func (r *Result) Events(cb func(e *transformers.Event) error) (courier.Metadata, error) {
+	stream, meta, err := r.IntoReader()
+	if err != nil {
+		return meta, err
+	}
+	defer stream.Close()
+
+	if err := transformers.ReadEvents(stream, cb); err != nil {
+		return meta, statuserror.Wrap(err, http.StatusInternalServerError, "ReadFailed")
+	}
+
+	return meta, nil
+}
+
+// IntoItems decodes top-level json array of response body item by item into each, as func(item T) error,
+// large lists decoded without holding all of them in memory, stopped when each returns error.
+//
+//	_, err := result.IntoItems(func(user User) error {
+//		return nil
+//	})
+func (r *Result) IntoItems(each interface{}) (courier.Metadata, error) {
+	fn := reflect.ValueOf(each)
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 || fn.Type().NumOut() != 1 || fn.Type().Out(0) != rtypeError {
+		meta, _ := r.Discard()
+		return meta, errors.Errorf("item receiver should be func(item T) error, but got %T", each)
+	}
+
+	stream, meta, err := r.IntoReader()
+	if err != nil {
+		return meta, err
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+
+	tok, err := decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return meta, nil
+		}
+		return meta, statuserror.Wrap(err, http.StatusInternalServerError, "ReadFailed")
+	}
+
+	if tok == nil {
+		// null as no items
+		return meta, nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return meta, statuserror.Wrap(errors.Errorf("should be json array, but got %v", tok), http.StatusInternalServerError, "DecodeFailed")
+	}
+
+	itemType := fn.Type().In(0)
+
+	for idx := 0; decoder.More(); idx++ {
+		item := reflect.New(itemType)
+
+		if err := decoder.Decode(item.Interface()); err != nil {
+			return meta, statuserror.Wrap(errors.Wrapf(err, "[%d]", idx), http.StatusInternalServerError, "DecodeFailed")
+		}
+
+		if err, _ := fn.Call([]reflect.Value{item.Elem()})[0].Interface().(error); err != nil {
+			return meta, err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return meta, statuserror.Wrap(err, http.StatusInternalServerError, "DecodeFailed")
+	}
+
+	return meta, nil
+}
+
+var rtypeError = reflect.TypeOf((*error)(nil)).Elem()
+
+func (r *Result) newError() error {
+	if newErrorBody, ok := r.ErrorBodies[r.Response.StatusCode]; ok {
+		return newErrorBody()
+	}
+	return r.NewError(r.Response)
+}
+
 func isOk(code int) bool {
 	return code >= http.StatusOK && code < http.StatusMultipleChoices
 }
@@ -295,6 +1031,8 @@ func GetShortConnClientContext(ctx context.Context, timeout time.Duration, httpT
 		Transport: t,
 	}
 
+	applyHttpClientContext(ctx, client)
+
 	for i := range httpTransports {
 		httpTransport := httpTransports[i]
 		client.Transport = httpTransport(client.Transport)
@@ -302,3 +1040,87 @@ func GetShortConnClientContext(ctx context.Context, timeout time.Duration, httpT
 
 	return client
 }
+
+// GetHttp2ClientContext returns client which only talks HTTP/2,
+// when allowHTTP, requests of http will be sent over cleartext TCP with prior knowledge (h2c).
+func GetHttp2ClientContext(ctx context.Context, timeout time.Duration, allowHTTP bool, httpTransports ...HttpTransport) *http.Client {
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: &shortConnHttp2Transport{Transport: newHttp2Transport(ctx, allowHTTP)},
+	}
+
+	applyHttpClientContext(ctx, client)
+
+	for i := range httpTransports {
+		httpTransport := httpTransports[i]
+		client.Transport = httpTransport(client.Transport)
+	}
+
+	return client
+}
+
+func newHttp2Transport(ctx context.Context, allowHTTP bool) *http2.Transport {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+	}
+
+	t := &http2.Transport{
+		AllowHTTP: allowHTTP,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			if allowHTTP {
+				return dialer.Dial(network, addr)
+			}
+			return tls.DialWithDialer(dialer, network, addr, cfg)
+		},
+	}
+
+	if defaultTransport := DefaultHttpTransportFromContext(ctx); defaultTransport != nil {
+		if defaultTransport.TLSClientConfig != nil {
+			t.TLSClientConfig = defaultTransport.TLSClientConfig.Clone()
+		}
+
+		if dialContext := defaultTransport.DialContext; dialContext != nil {
+			t.DialTLS = func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				conn, err := dialContext(context.Background(), network, addr)
+				if err != nil || allowHTTP {
+					return conn, err
+				}
+				tlsConn := tls.Client(conn, cfg)
+				if err := tlsConn.Handshake(); err != nil {
+					conn.Close()
+					return nil, err
+				}
+				return tlsConn, nil
+			}
+		}
+	}
+
+	return t
+}
+
+// shortConnHttp2Transport closes idle connections once response body closed,
+// as http2.Transport pools connections and could not disable keep-alives.
+type shortConnHttp2Transport struct {
+	*http2.Transport
+}
+
+func (t *shortConnHttp2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		t.Transport.CloseIdleConnections()
+		return nil, err
+	}
+	resp.Body = &closeIdleConnectionsOnClose{ReadCloser: resp.Body, transport: t.Transport}
+	return resp, nil
+}
+
+type closeIdleConnectionsOnClose struct {
+	io.ReadCloser
+	transport *http2.Transport
+}
+
+func (body *closeIdleConnectionsOnClose) Close() error {
+	err := body.ReadCloser.Close()
+	body.transport.CloseIdleConnections()
+	return err
+}
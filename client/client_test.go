@@ -2,15 +2,30 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-courier/httptransport/client/roundtrippers"
 	"github.com/go-courier/httptransport/httpx"
 	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type IpInfo struct {
@@ -117,3 +132,760 @@ func TestClient(t *testing.T) {
 		require.Equal(t, 200, rw.StatusCode)
 	})
 }
+
+type GetData struct {
+	httpx.MethodGet
+}
+
+func (GetData) Path() string {
+	return "/data"
+}
+
+type Data struct {
+	ID string `json:"id"`
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	c := &Client{
+		Host: u.Hostname(),
+		Port: uint16(port),
+	}
+	c.SetDefaults()
+	return c
+}
+
+func TestClientWithHTTP2(t *testing.T) {
+	handler := func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.Proto + `"}`))
+	}
+
+	t.Run("h2c with prior knowledge", func(t *testing.T) {
+		c := newTestClient(t, h2c.NewHandler(http.HandlerFunc(handler), &http2.Server{}).ServeHTTP)
+		c.H2C = true
+
+		data := Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(&data)
+		require.NoError(t, err)
+		require.Equal(t, "HTTP/2.0", data.ID)
+	})
+
+	t.Run("http2 over tls", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(handler))
+		srv.EnableHTTP2 = true
+		srv.StartTLS()
+		t.Cleanup(srv.Close)
+
+		u, _ := url.Parse(srv.URL)
+		port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+		c := &Client{
+			Protocol: "https",
+			Host:     u.Hostname(),
+			Port:     uint16(port),
+			HTTP2:    true,
+		}
+		c.SetDefaults()
+
+		ctx := ContextWithDefaultHttpTransport(context.Background(), &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		})
+
+		data := Data{}
+		_, err := c.Do(ctx, &GetData{}).Into(&data)
+		require.NoError(t, err)
+		require.Equal(t, "HTTP/2.0", data.ID)
+	})
+}
+
+func TestClientWithKeepAlive(t *testing.T) {
+	newConns := int32(0)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	}))
+	srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	request := func(c *Client) {
+		for i := 0; i < 3; i++ {
+			data := Data{}
+			_, err := c.Do(context.Background(), &GetData{}).Into(&data)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("short connections", func(t *testing.T) {
+		atomic.StoreInt32(&newConns, 0)
+
+		c := &Client{Host: u.Hostname(), Port: uint16(port)}
+		c.SetDefaults()
+
+		request(c)
+		require.Equal(t, int32(3), atomic.LoadInt32(&newConns))
+	})
+
+	t.Run("keep alive", func(t *testing.T) {
+		atomic.StoreInt32(&newConns, 0)
+
+		c := &Client{Host: u.Hostname(), Port: uint16(port), KeepAlive: true}
+		c.SetDefaults()
+		defer c.CloseIdleConnections()
+
+		require.Equal(t, 10, c.MaxIdleConnsPerHost)
+		require.Equal(t, 90*time.Second, c.IdleConnTimeout)
+
+		request(c)
+		require.Equal(t, int32(1), atomic.LoadInt32(&newConns))
+	})
+}
+
+func TestClientWithAudit(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	records := make([]*roundtrippers.AuditRecord, 0)
+
+	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewAuditRoundTripper(roundtrippers.AuditSinkFunc(func(ctx context.Context, record *roundtrippers.AuditRecord) {
+		records = append(records, record)
+	})))
+
+	data := Data{}
+	_, err := c.Do(context.Background(), &GetData{}).Into(&data)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	require.Equal(t, "GetData", records[0].OperationID)
+	require.Equal(t, "/data", records[0].Path)
+}
+
+type UnprocessableEntity struct {
+	Reason string `json:"reason"`
+}
+
+func (e *UnprocessableEntity) Error() string {
+	return e.Reason
+}
+
+func TestClientWithErrorBody(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("case") {
+		case "status-err":
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"key":"InvalidID","code":400000001,"msg":"invalid id","desc":"id should be uuid","errorFields":[{"field":"id","msg":"invalid","in":"path"}]}`))
+		case "gateway":
+			rw.Header().Set("Content-Type", "text/html")
+			rw.WriteHeader(http.StatusBadGateway)
+			_, _ = rw.Write([]byte(`<html>Bad Gateway</html>`))
+		case "unprocessable":
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = rw.Write([]byte(`{"reason":"duplicated"}`))
+		case "malformed":
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = rw.Write([]byte(`{"reason":1}`))
+		}
+	})
+
+	do := func(c *Client, cs string) error {
+		req, _ := http.NewRequest(http.MethodGet, c.URL("/?case="+cs), nil)
+		_, err := c.Do(context.Background(), req).Into(&Data{})
+		return err
+	}
+
+	t.Run("remote status error", func(t *testing.T) {
+		statusErr, ok := statuserror.IsStatusErr(do(c, "status-err"))
+		require.True(t, ok)
+		require.Equal(t, "InvalidID", statusErr.Key)
+		require.Equal(t, 400000001, statusErr.Code)
+		require.Equal(t, "id should be uuid", statusErr.Desc)
+		require.Len(t, statusErr.ErrorFields, 1)
+	})
+
+	t.Run("undecodable body should keep status code", func(t *testing.T) {
+		statusErr, ok := statuserror.IsStatusErr(do(c, "gateway"))
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadGateway, statusErr.StatusCode())
+	})
+
+	t.Run("error bodies by status code", func(t *testing.T) {
+		c.ErrorBodies = map[int]func() error{
+			http.StatusUnprocessableEntity: func() error {
+				return &UnprocessableEntity{}
+			},
+		}
+
+		err := do(c, "unprocessable")
+		require.Equal(t, &UnprocessableEntity{Reason: "duplicated"}, err)
+
+		statusErr, ok := statuserror.IsStatusErr(do(c, "malformed"))
+		require.True(t, ok)
+		require.Equal(t, http.StatusUnprocessableEntity, statusErr.StatusCode())
+		require.Equal(t, "DecodeFailed", statusErr.Key)
+
+		_, ok = statuserror.IsStatusErr(do(c, "status-err"))
+		require.True(t, ok)
+	})
+}
+
+func TestClientWithStream(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("case") == "failed" {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"key":"NotFound","code":404000000,"msg":"not found"}`))
+			return
+		}
+		rw.Header().Set("Content-Type", "application/octet-stream")
+		rw.Header().Set("Content-Length", "4")
+		_, _ = rw.Write([]byte("data"))
+	})
+
+	t.Run("into read closer", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, c.URL("/"), nil)
+
+		var rc io.ReadCloser
+		meta, err := c.Do(context.Background(), req).Into(&rc)
+		require.NoError(t, err)
+		defer rc.Close()
+
+		require.Equal(t, "application/octet-stream", meta.Get("Content-Type"))
+		require.Equal(t, "4", meta.Get("Content-Length"))
+
+		data, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "data", string(data))
+	})
+
+	t.Run("stream failed", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, c.URL("/?case=failed"), nil)
+
+		rc, _, err := c.Do(context.Background(), req).(*Result).IntoReader()
+		require.Nil(t, rc)
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "NotFound", statusErr.Key)
+	})
+}
+
+func TestClientWithEvents(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			_ = transformers.WriteEvent(rw, &transformers.Event{ID: strconv.Itoa(i), Data: map[string]int{"percent": i * 50}})
+			rw.(http.Flusher).Flush()
+		}
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, c.URL("/"), nil)
+
+	percents := make([]int, 0)
+
+	_, err := c.Do(context.Background(), req).(*Result).Events(func(e *transformers.Event) error {
+		v := map[string]int{}
+		if err := e.DataInto(&v); err != nil {
+			return err
+		}
+		percents = append(percents, v["percent"])
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 50, 100}, percents)
+}
+
+func TestClientWithNDJSON(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/x-ndjson")
+		for i := 0; i < 3; i++ {
+			_, _ = rw.Write([]byte(`{"id":"` + strconv.Itoa(i) + `"}` + "\n"))
+			rw.(http.Flusher).Flush()
+		}
+	})
+
+	ids := make([]string, 0)
+
+	_, err := c.Do(context.Background(), &GetData{}).Into(func(data Data) error {
+		ids = append(ids, data.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1", "2"}, ids)
+}
+
+func TestClientWithJSONArrayItems(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("status") != "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"key":"BadRequest","code":400000000}`))
+			return
+		}
+		_, _ = rw.Write([]byte(`[`))
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				_, _ = rw.Write([]byte(`,`))
+			}
+			_, _ = rw.Write([]byte(`{"id":"` + strconv.Itoa(i) + `"}`))
+			rw.(http.Flusher).Flush()
+		}
+		_, _ = rw.Write([]byte(`]`))
+	})
+
+	t.Run("each item", func(t *testing.T) {
+		ids := make([]string, 0)
+
+		_, err := c.Do(context.Background(), &GetData{}).(*Result).IntoItems(func(data Data) error {
+			ids = append(ids, data.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2"}, ids)
+	})
+
+	t.Run("stopped by error of each", func(t *testing.T) {
+		ids := make([]string, 0)
+
+		_, err := c.Do(context.Background(), &GetData{}).(*Result).IntoItems(func(data *Data) error {
+			ids = append(ids, data.ID)
+			if len(ids) == 2 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		})
+		require.Equal(t, io.ErrUnexpectedEOF, err)
+		require.Equal(t, []string{"0", "1"}, ids)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		ctx := ContextWithRequestOptions(context.Background(), WithQuery("status", "400"))
+
+		_, err := c.Do(ctx, &GetData{}).(*Result).IntoItems(func(data Data) error {
+			return nil
+		})
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "BadRequest", statusErr.Key)
+	})
+
+	t.Run("invalid receiver", func(t *testing.T) {
+		_, err := c.Do(context.Background(), &GetData{}).(*Result).IntoItems(func(data Data) {})
+		require.Error(t, err)
+	})
+}
+
+func TestClientWithByStatus(t *testing.T) {
+	type Ticket struct {
+		TicketID string `json:"ticketID"`
+	}
+
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("status") {
+		case "202":
+			rw.WriteHeader(http.StatusAccepted)
+			_, _ = rw.Write([]byte(`{"ticketID":"t1"}`))
+		case "404":
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"key":"NotFound","code":404000000}`))
+		case "500":
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte(`{"key":"InternalError","code":500000000}`))
+		default:
+			_, _ = rw.Write([]byte(`{"id":"1"}`))
+		}
+	})
+
+	do := func(status string) (*Result, *Data, *Ticket, error) {
+		ctx := context.Background()
+		if status != "" {
+			ctx = ContextWithRequestOptions(ctx, WithQuery("status", status))
+		}
+
+		data, ticket := &Data{}, &Ticket{}
+
+		result := c.Do(ctx, &GetData{}).(*Result)
+		_, err := result.Into(ByStatus{
+			http.StatusOK:       data,
+			http.StatusAccepted: ticket,
+			http.StatusNotFound: nil,
+		})
+		return result, data, ticket, err
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		result, data, ticket, err := do("")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, result.StatusCode())
+		require.Equal(t, "1", data.ID)
+		require.Empty(t, ticket.TicketID)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		result, data, ticket, err := do("202")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, result.StatusCode())
+		require.Empty(t, data.ID)
+		require.Equal(t, "t1", ticket.TicketID)
+	})
+
+	t.Run("not found as nil", func(t *testing.T) {
+		result, _, _, err := do("404")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, result.StatusCode())
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		_, _, _, err := do("500")
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "InternalError", statusErr.Key)
+	})
+}
+
+func TestResultOn(t *testing.T) {
+	type Conflict struct {
+		CurrentID string `json:"currentID"`
+	}
+
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("status") {
+		case "409":
+			rw.WriteHeader(http.StatusConflict)
+			_, _ = rw.Write([]byte(`{"currentID":"2"}`))
+		case "500":
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte(`{"key":"InternalError","code":500000000}`))
+		default:
+			_, _ = rw.Write([]byte(`{"id":"1"}`))
+		}
+	})
+
+	do := func(status string) (*Result, *Data, *Conflict, error) {
+		ctx := context.Background()
+		if status != "" {
+			ctx = ContextWithRequestOptions(ctx, WithQuery("status", status))
+		}
+
+		data, conflict := &Data{}, &Conflict{}
+
+		result := c.Do(ctx, &GetData{}).(*Result)
+		_, err := result.On(http.StatusOK, data).On(http.StatusConflict, conflict).Do()
+		return result, data, conflict, err
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		_, data, conflict, err := do("")
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+		require.Empty(t, conflict.CurrentID)
+	})
+
+	t.Run("conflict decoded", func(t *testing.T) {
+		result, data, conflict, err := do("409")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, result.StatusCode())
+		require.Empty(t, data.ID)
+		require.Equal(t, "2", conflict.CurrentID)
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		_, _, _, err := do("500")
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "InternalError", statusErr.Key)
+	})
+}
+
+func TestClientWithDecodeFallback(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		// skip sniffing of net/http
+		rw.Header()["Content-Type"] = nil
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+	c.DecodeFallback = transformers.FallbackChain{"xml", "json"}
+
+	data := Data{}
+	_, err := c.Do(context.Background(), &GetData{}).Into(&data)
+	require.NoError(t, err)
+	require.Equal(t, "1", data.ID)
+}
+
+func TestResultConsumption(t *testing.T) {
+	newConns := int32(0)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	}))
+	srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	c := &Client{Host: u.Hostname(), Port: uint16(port), KeepAlive: true}
+	c.SetDefaults()
+	defer c.CloseIdleConnections()
+
+	t.Run("consume once", func(t *testing.T) {
+		result := c.Do(context.Background(), &GetData{}).(*Result)
+
+		data := Data{}
+		_, err := result.Into(&data)
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+
+		_, err = result.Into(&data)
+		require.Equal(t, ErrResultConsumed, err)
+
+		_, _, err = result.IntoReader()
+		require.Equal(t, ErrResultConsumed, err)
+
+		_, err = result.Discard()
+		require.Equal(t, ErrResultConsumed, err)
+	})
+
+	t.Run("discard for reusing connection", func(t *testing.T) {
+		atomic.StoreInt32(&newConns, 0)
+
+		for i := 0; i < 3; i++ {
+			meta, err := c.Do(context.Background(), &GetData{}).(*Result).Discard()
+			require.NoError(t, err)
+			require.Equal(t, "application/json", meta.Get("Content-Type"))
+		}
+
+		// connection of previous case could be reused too
+		require.LessOrEqual(t, atomic.LoadInt32(&newConns), int32(1))
+	})
+}
+
+type GetDataWithCallDefaults struct {
+	GetData
+}
+
+func (GetDataWithCallDefaults) Timeout() time.Duration {
+	return 50 * time.Millisecond
+}
+
+func (GetDataWithCallDefaults) Retryable() bool {
+	return false
+}
+
+func TestClientWithCallDefaults(t *testing.T) {
+	delay := int64(0)
+
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Duration(atomic.LoadInt64(&delay)))
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	t.Run("body could be read before timeout", func(t *testing.T) {
+		data := Data{}
+		_, err := c.Do(context.Background(), &GetDataWithCallDefaults{}).Into(&data)
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		atomic.StoreInt64(&delay, int64(200*time.Millisecond))
+		defer atomic.StoreInt64(&delay, 0)
+
+		_, err := c.Do(context.Background(), &GetDataWithCallDefaults{}).Into(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("retryable in context", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		})
+
+		retryable := (*bool)(nil)
+
+		c.HttpTransports = append(c.HttpTransports, func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if v, ok := roundtrippers.RetryableFromContext(req.Context()); ok {
+					retryable = &v
+				}
+				return rt.RoundTrip(req)
+			})
+		})
+
+		_, err := c.Do(context.Background(), &GetDataWithCallDefaults{}).Into(nil)
+		require.NoError(t, err)
+		require.NotNil(t, retryable)
+		require.False(t, *retryable)
+	})
+}
+
+type GetDataOfTenant struct {
+	GetData
+}
+
+func (GetDataOfTenant) Credential() string {
+	return "tenant-b"
+}
+
+func TestClientWithCredentials(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.Header.Get("Authorization") + `"}`))
+	})
+
+	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewCredentialsRoundTripper(roundtrippers.CredentialsOptions{
+		Credentials: map[string]roundtrippers.AuthOptions{
+			"tenant-a": {Type: roundtrippers.AuthTypeBearer, Value: "a"},
+			"tenant-b": {Type: roundtrippers.AuthTypeBearer, Value: "b"},
+		},
+		Default: "tenant-a",
+	}))
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		req      interface{}
+		expected string
+	}{
+		{"default", context.Background(), &GetData{}, "Bearer a"},
+		{"by request", context.Background(), &GetDataOfTenant{}, "Bearer b"},
+		{"context wins", roundtrippers.ContextWithCredential(context.Background(), "tenant-a"), &GetDataOfTenant{}, "Bearer a"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := Data{}
+			_, err := c.Do(tc.ctx, tc.req).Into(&data)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, data.ID)
+		})
+	}
+}
+
+func TestClientWithCircuitBreaker(t *testing.T) {
+	calls := int32(0)
+
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusInternalServerError)
+		_, _ = rw.Write([]byte(`{"key":"InternalError","code":500000000}`))
+	})
+
+	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewCircuitBreakerRoundTripper(roundtrippers.CircuitBreakerOptions{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	}))
+
+	for i := 0; i < 10; i++ {
+		_, _ = c.Do(context.Background(), &GetData{}).Into(&Data{})
+	}
+
+	// HttpTransports wrapped for each request in short-conn mode
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClientWithRateLimit(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewRateLimitRoundTripper(roundtrippers.RateLimitOptions{
+		Rate:     1,
+		Burst:    2,
+		FailFast: true,
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+		require.NoError(t, err)
+	}
+
+	_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+	require.True(t, errors.Is(err, roundtrippers.ErrRateLimited))
+}
+
+func TestClientWithHedge(t *testing.T) {
+	attempts := int32(0)
+
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		// first attempt of each request is slow
+		if atomic.AddInt32(&attempts, 1)%2 == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	hedge := roundtrippers.NewHedgeRoundTripper(roundtrippers.HedgeOptions{Delay: 10 * time.Millisecond})
+	c.HttpTransports = append(c.HttpTransports, hedge)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, roundtrippers.HedgeStats{Fired: 2, Won: 2}, hedge(nil).(*roundtrippers.HedgeRoundTripper).Stats())
+}
+
+func TestClientWithRecordReplay(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewRecordReplayRoundTripper(roundtrippers.RecordReplayOptions{
+		Cassette: cassette,
+		Mode:     roundtrippers.RecordModeRecord,
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+		require.NoError(t, err)
+	}
+
+	data, err := ioutil.ReadFile(cassette)
+	require.NoError(t, err)
+
+	recorded := roundtrippers.Cassette{}
+	require.NoError(t, json.Unmarshal(data, &recorded))
+	require.Len(t, recorded.Interactions, 2)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
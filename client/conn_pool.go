@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// ErrConnPoolExhausted will be wrapped as status error ConnPoolExhausted,
+// when no connection of host available in MaxWait of ConnPool
+var ErrConnPoolExhausted = errors.New("connection pool exhausted")
+
+// ConnPool limits connections of each host with queueing, and tracks statistics of them,
+// so a slow upstream can't monopolize sockets of the whole process. Works when KeepAlive,
+// could be shared by clients to limit connections of hosts in process.
+type ConnPool struct {
+	// max connections of each host in use, requests beyond will wait in queue, zero means no limit
+	MaxConnsPerHost int
+	// max duration waiting in queue, zero means waiting until context done
+	MaxWait time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostConnPool
+}
+
+// ConnPoolStats of host
+type ConnPoolStats struct {
+	Host string `json:"host"`
+	// connections in use by requests
+	Active int `json:"active"`
+	// connections opened but not in use
+	Idle int `json:"idle"`
+	// requests waiting in queue
+	Waiting int `json:"waiting"`
+	// requests waited and total duration of waiting
+	WaitCount    int64         `json:"waitCount"`
+	WaitDuration time.Duration `json:"waitDuration"`
+}
+
+type hostConnPool struct {
+	sem chan struct{}
+
+	opened       int
+	active       int
+	waiting      int
+	waitCount    int64
+	waitDuration time.Duration
+}
+
+func (p *ConnPool) host(host string) *hostConnPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hosts == nil {
+		p.hosts = map[string]*hostConnPool{}
+	}
+
+	h, ok := p.hosts[host]
+	if !ok {
+		h = &hostConnPool{}
+		if p.MaxConnsPerHost > 0 {
+			h.sem = make(chan struct{}, p.MaxConnsPerHost)
+		}
+		p.hosts[host] = h
+	}
+
+	return h
+}
+
+// Stats of hosts sorted by host
+func (p *ConnPool) Stats() []ConnPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	list := make([]ConnPoolStats, 0, len(p.hosts))
+
+	for host, h := range p.hosts {
+		idle := h.opened - h.active
+		if idle < 0 {
+			// connections multiplexed or dialed by others
+			idle = 0
+		}
+
+		list = append(list, ConnPoolStats{
+			Host:         host,
+			Active:       h.active,
+			Idle:         idle,
+			Waiting:      h.waiting,
+			WaitCount:    h.waitCount,
+			WaitDuration: h.waitDuration,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Host < list[j].Host
+	})
+
+	return list
+}
+
+// acquire waits for a connection of host, returns release to call when response body closed
+func (p *ConnPool) acquire(ctx context.Context, host string) (func(), error) {
+	h := p.host(host)
+
+	if h.sem != nil {
+		select {
+		case h.sem <- struct{}{}:
+		default:
+			if err := p.wait(ctx, h); err != nil {
+				if err == ErrConnPoolExhausted {
+					return nil, statuserror.Wrap(errors.Wrapf(err, "%s", host), http.StatusServiceUnavailable, "ConnPoolExhausted")
+				}
+				return nil, err
+			}
+		}
+	}
+
+	p.mu.Lock()
+	h.active++
+	p.mu.Unlock()
+
+	once := sync.Once{}
+
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			h.active--
+			p.mu.Unlock()
+
+			if h.sem != nil {
+				<-h.sem
+			}
+		})
+	}, nil
+}
+
+func (p *ConnPool) wait(ctx context.Context, h *hostConnPool) error {
+	startedAt := time.Now()
+
+	p.mu.Lock()
+	h.waiting++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		h.waiting--
+		h.waitCount++
+		h.waitDuration += time.Since(startedAt)
+		p.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+
+	if p.MaxWait > 0 {
+		timer := time.NewTimer(p.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case h.sem <- struct{}{}:
+		return nil
+	case <-timeout:
+		return ErrConnPoolExhausted
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DialContext wraps dial to track connections opened of each host
+func (p *ConnPool) DialContext(dial func(ctx context.Context, network string, addr string) (net.Conn, error)) func(ctx context.Context, network string, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		h := p.host(addr)
+
+		p.mu.Lock()
+		h.opened++
+		p.mu.Unlock()
+
+		return &pooledConn{Conn: conn, onClose: func() {
+			p.mu.Lock()
+			h.opened--
+			p.mu.Unlock()
+		}}, nil
+	}
+}
+
+type pooledConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *pooledConn) Close() error {
+	c.once.Do(c.onClose)
+	return c.Conn.Close()
+}
+
+// RoundTripper limits requests of each host in use of connections
+func (p *ConnPool) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &connPoolRoundTripper{pool: p, nextRoundTripper: next}
+}
+
+type connPoolRoundTripper struct {
+	pool             *ConnPool
+	nextRoundTripper http.RoundTripper
+}
+
+func (rt *connPoolRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := rt.pool.acquire(req.Context(), canonicalAddr(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		release()
+		return resp, nil
+	}
+
+	// connection in use until body closed
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// canonicalAddr returns host:port of request, same as addr of dial
+func canonicalAddr(req *http.Request) string {
+	host, port := req.URL.Hostname(), req.URL.Port()
+	if port == "" {
+		port = "80"
+		if req.URL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
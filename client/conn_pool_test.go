@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnPool(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	pool := &ConnPool{MaxConnsPerHost: 1, MaxWait: 50 * time.Millisecond}
+
+	c := &http.Client{
+		Transport: pool.RoundTripper(&http.Transport{
+			DialContext: pool.DialContext((&net.Dialer{}).DialContext),
+		}),
+	}
+
+	get := func(ctx context.Context) (*http.Response, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		return c.Do(req)
+	}
+
+	host := srv.Listener.Addr().String()
+
+	resp, err := get(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, []ConnPoolStats{{Host: host, Active: 1}}, pool.Stats())
+
+	t.Run("wait timeout when body not closed", func(t *testing.T) {
+		_, err := get(context.Background())
+		require.Error(t, err)
+
+		statusErr, ok := statuserror.IsStatusErr(err.(interface{ Unwrap() error }).Unwrap())
+		require.True(t, ok)
+		require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode())
+
+		stats := pool.Stats()[0]
+		require.Equal(t, int64(1), stats.WaitCount)
+		require.True(t, stats.WaitDuration >= 50*time.Millisecond)
+	})
+
+	t.Run("canceled when waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := get(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	_, _ = ioutil.ReadAll(resp.Body)
+	require.NoError(t, resp.Body.Close())
+
+	t.Run("reuse connection after released", func(t *testing.T) {
+		resp, err := get(context.Background())
+		require.NoError(t, err)
+		_, _ = ioutil.ReadAll(resp.Body)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, 0, pool.Stats()[0].Active)
+		require.Equal(t, 1, pool.Stats()[0].Idle)
+	})
+}
+
+func TestClientWithConnPool(t *testing.T) {
+	pool := &ConnPool{MaxConnsPerHost: 2}
+
+	c := &Client{KeepAlive: true, ConnPool: pool, HttpTransports: []HttpTransport{}}
+	c.SetDefaults()
+
+	rt, ok := c.newKeepAliveClient(context.Background()).Transport.(*connPoolRoundTripper)
+	require.True(t, ok)
+	require.Equal(t, pool, rt.pool)
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Dialer with dual-stack controls for environments with broken IPv6 (or IPv4) routes
+type Dialer struct {
+	// timeout of each dial, default 5s
+	Timeout time.Duration
+	// keep-alive period of tcp connection, zero means disabled
+	KeepAlive time.Duration
+	// delay before racing the other address family (happy eyeballs), default 300ms.
+	// negative means disable fast fallback, addresses will be tried one by one.
+	FallbackDelay time.Duration
+	// dial IPv6 addresses first, otherwise follow the order of resolver
+	PreferIPv6  bool
+	DisableIPv4 bool
+	DisableIPv6 bool
+	// default net.DefaultResolver
+	Resolver *net.Resolver
+}
+
+func (d *Dialer) SetDefaults() {
+	if d.Timeout == 0 {
+		d.Timeout = 5 * time.Second
+	}
+	if d.FallbackDelay == 0 {
+		d.FallbackDelay = 300 * time.Millisecond
+	}
+}
+
+func (d *Dialer) netDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:       d.Timeout,
+		KeepAlive:     d.KeepAlive,
+		FallbackDelay: d.FallbackDelay,
+		Resolver:      d.Resolver,
+	}
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	if network != "tcp" {
+		return d.netDialer().DialContext(ctx, network, addr)
+	}
+
+	switch {
+	case d.DisableIPv4 && d.DisableIPv6:
+		return nil, errors.Errorf("dial %s: both IPv4 and IPv6 disabled", addr)
+	case d.DisableIPv4:
+		return d.netDialer().DialContext(ctx, "tcp6", addr)
+	case d.DisableIPv6:
+		return d.netDialer().DialContext(ctx, "tcp4", addr)
+	case !d.PreferIPv6:
+		return d.netDialer().DialContext(ctx, network, addr)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if net.ParseIP(host) != nil {
+		return d.netDialer().DialContext(ctx, network, addr)
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ipAddrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	ipv6, ipv4 := make([]string, 0), make([]string, 0)
+	for _, ipAddr := range ipAddrs {
+		hostPort := net.JoinHostPort(ipAddr.String(), port)
+		if ipAddr.IP.To4() != nil {
+			ipv4 = append(ipv4, hostPort)
+		} else {
+			ipv6 = append(ipv6, hostPort)
+		}
+	}
+
+	if len(ipv6) == 0 || len(ipv4) == 0 || d.FallbackDelay < 0 {
+		return d.dialSerial(ctx, append(ipv6, ipv4...))
+	}
+
+	return d.dialParallel(ctx, ipv6, ipv4)
+}
+
+func (d *Dialer) dialSerial(ctx context.Context, addrs []string) (net.Conn, error) {
+	dialer := d.netDialer()
+
+	var lastErr error
+
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no addresses to dial")
+	}
+
+	return nil, lastErr
+}
+
+// dialParallel races fallbacks after FallbackDelay or failure of primaries
+func (d *Dialer) dialParallel(ctx context.Context, primaries []string, fallbacks []string) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type dialResult struct {
+		conn    net.Conn
+		err     error
+		primary bool
+	}
+
+	results := make(chan dialResult)
+
+	dial := func(addrs []string, primary bool) {
+		conn, err := d.dialSerial(ctx, addrs)
+		select {
+		case results <- dialResult{conn: conn, err: err, primary: primary}:
+		case <-ctx.Done():
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}
+
+	go dial(primaries, true)
+
+	fallbackTimer := time.NewTimer(d.FallbackDelay)
+	defer fallbackTimer.Stop()
+
+	fallbackStarted := false
+	startFallback := func() {
+		if !fallbackStarted {
+			fallbackStarted = true
+			go dial(fallbacks, false)
+		}
+	}
+
+	var firstErr error
+	pending := 2
+
+	for {
+		select {
+		case <-fallbackTimer.C:
+			startFallback()
+		case res := <-results:
+			if res.err == nil {
+				return res.conn, nil
+			}
+			if firstErr == nil || res.primary {
+				firstErr = res.err
+			}
+			pending--
+			if pending == 0 {
+				return nil, firstErr
+			}
+			startFallback()
+		}
+	}
+}
+
+// connectAddrOf returns address to dial for addr of request, port of addr used when connectTo without port
+func connectAddrOf(connectTo string, addr string) string {
+	if _, _, err := net.SplitHostPort(connectTo); err == nil {
+		return connectTo
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return connectTo
+	}
+
+	// like [::1]
+	host := strings.TrimSuffix(strings.TrimPrefix(connectTo, "["), "]")
+
+	return net.JoinHostPort(host, port)
+}
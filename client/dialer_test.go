@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialer(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().String()
+
+	dial := func(d *Dialer, addr string) error {
+		d.SetDefaults()
+		conn, err := d.DialContext(context.Background(), "tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+
+	t.Run("disable ipv6", func(t *testing.T) {
+		require.NoError(t, dial(&Dialer{DisableIPv6: true}, addr))
+	})
+
+	t.Run("disable ipv4", func(t *testing.T) {
+		require.Error(t, dial(&Dialer{DisableIPv4: true}, addr))
+	})
+
+	t.Run("disable both", func(t *testing.T) {
+		require.Error(t, dial(&Dialer{DisableIPv4: true, DisableIPv6: true}, addr))
+	})
+
+	t.Run("fallback when primaries failed", func(t *testing.T) {
+		d := &Dialer{PreferIPv6: true}
+		d.SetDefaults()
+
+		conn, err := d.dialParallel(context.Background(), []string{"[::1]:1"}, []string{addr})
+		require.NoError(t, err)
+		conn.Close()
+	})
+
+	t.Run("client with dialer", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"id":"1"}`))
+		})
+		c.Host = "localhost"
+		c.Dialer = &Dialer{PreferIPv6: true, FallbackDelay: -1}
+		c.SetDefaults()
+
+		data := Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(&data)
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+	})
+}
+
+func TestClientConnectTo(t *testing.T) {
+	mu := sync.Mutex{}
+	serverName := ""
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.Host + `"}`))
+	}))
+	srv.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			mu.Lock()
+			serverName = hello.ServerName
+			mu.Unlock()
+			return nil, nil
+		},
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	t.Run("of client", func(t *testing.T) {
+		c := &Client{
+			Protocol:  "https",
+			Host:      "api.example.com",
+			Port:      uint16(port),
+			ConnectTo: "127.0.0.1",
+			TLS:       &TLS{InsecureSkipVerify: true},
+		}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "api.example.com:"+u.Port(), data.ID)
+
+		mu.Lock()
+		require.Equal(t, "api.example.com", serverName)
+		mu.Unlock()
+	})
+
+	t.Run("of request", func(t *testing.T) {
+		c := &Client{
+			Protocol:  "https",
+			Host:      "canary.example.com",
+			Port:      443,
+			KeepAlive: true,
+			TLS:       &TLS{InsecureSkipVerify: true},
+		}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(ContextWithRequestOptions(context.Background(), WithConnectTo(u.Host)), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "canary.example.com:443", data.ID)
+
+		mu.Lock()
+		require.Equal(t, "canary.example.com", serverName)
+		mu.Unlock()
+	})
+}
+
+func TestConnectAddrOf(t *testing.T) {
+	require.Equal(t, "10.0.0.1:8443", connectAddrOf("10.0.0.1:8443", "api.example.com:443"))
+	require.Equal(t, "10.0.0.1:443", connectAddrOf("10.0.0.1", "api.example.com:443"))
+	require.Equal(t, "[::1]:443", connectAddrOf("[::1]", "api.example.com:443"))
+	require.Equal(t, "[::1]:443", connectAddrOf("::1", "api.example.com:443"))
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// doWithFailover sends request to next of failoverUrls when dial or tls handshake failed,
+// returns hosts attempted in order.
+func doWithFailover(httpClient *http.Client, request *http.Request, failoverUrls []string) (*http.Response, []string, error) {
+	attempted := []string{request.URL.Host}
+
+	resp, err := httpClient.Do(request)
+
+	for _, rawUrl := range failoverUrls {
+		if err == nil || !isConnectError(err) || request.Context().Err() != nil {
+			break
+		}
+
+		next, ok := requestWithUrl(request, rawUrl)
+		if !ok {
+			break
+		}
+
+		request = next
+		attempted = append(attempted, request.URL.Host)
+
+		resp, err = httpClient.Do(request)
+	}
+
+	return resp, attempted, err
+}
+
+// requestWithUrl clones request to url with rewound body,
+// false when body could not be rewound.
+func requestWithUrl(request *http.Request, rawUrl string) (*http.Request, bool) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, false
+	}
+
+	next := request.Clone(request.Context())
+	next.URL = u
+	next.Host = u.Host
+
+	if request.Body != nil && request.Body != http.NoBody {
+		if request.GetBody == nil {
+			return nil, false
+		}
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		next.Body = body
+	}
+
+	return next, true
+}
+
+// isConnectError checks whether request failed before sent, by dialing or tls handshake
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var recordHeaderErr tls.RecordHeaderError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+
+	return errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateInvalidErr)
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithFailover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"up"}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	// port without listener
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	downAddr := l.Addr().String()
+	l.Close()
+
+	t.Run("failover hosts", func(t *testing.T) {
+		_, downPort, _ := net.SplitHostPort(downAddr)
+		p, _ := strconv.ParseUint(downPort, 10, 16)
+
+		c := &Client{
+			Host:          "127.0.0.1",
+			Port:          uint16(p),
+			FailoverHosts: []string{"localhost", u.Host},
+		}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "up", data.ID)
+	})
+
+	t.Run("attempted hosts in sources", func(t *testing.T) {
+		_, downPort, _ := net.SplitHostPort(downAddr)
+		otherDownAddr := "localhost:" + downPort
+
+		c := &Client{
+			Host:          downAddr,
+			FailoverHosts: []string{otherDownAddr},
+		}
+		c.SetDefaults()
+
+		_, err := c.Do(context.Background(), &GetData{}).Into(nil)
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "ConnectionRefused", statusErr.Key)
+		require.Equal(t, []string{downAddr, otherDownAddr}, statusErr.Sources)
+	})
+
+	t.Run("endpoints of resolver", func(t *testing.T) {
+		downHost, downPort, _ := net.SplitHostPort(downAddr)
+		p, _ := strconv.ParseUint(downPort, 10, 16)
+
+		c := &Client{
+			Host: "srv://user-service",
+			Resolver: NewCachedResolver(func(ctx context.Context, serviceName string) ([]Endpoint, error) {
+				return []Endpoint{{Host: downHost, Port: uint16(p)}, {Host: u.Hostname(), Port: uint16(port)}}, nil
+			}, 0),
+		}
+		c.SetDefaults()
+
+		for i := 0; i < 2; i++ {
+			data := &Data{}
+			_, err := c.Do(context.Background(), &GetData{}).Into(data)
+			require.NoError(t, err)
+			require.Equal(t, "up", data.ID)
+		}
+	})
+
+	t.Run("not connect error", func(t *testing.T) {
+		require.False(t, isConnectError(context.Canceled))
+		require.True(t, isConnectError(&url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}}))
+	})
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/client"
+)
+
+// NewAPIClientGenerator generates typed client of third-party API described by manifest,
+// into the package of request structs, which declared without server code.
+func NewAPIClientGenerator(pkgName string, manifest *client.APIManifest) *APIClientGenerator {
+	return &APIClientGenerator{
+		PkgName:  pkgName,
+		Manifest: manifest,
+	}
+}
+
+type APIClientGenerator struct {
+	PkgName  string
+	Manifest *client.APIManifest
+}
+
+func (g *APIClientGenerator) Scan(file *codegen.File) {
+	serviceClientGenerator := NewServiceClientGenerator(g.Manifest.Name, file)
+	operationGenerator := NewOperationGenerator(g.Manifest.Name, file)
+
+	g.WriteNewHttpClient(file)
+
+	methods := make([]*codegen.FuncType, 0)
+
+	for _, op := range g.Manifest.Operations {
+		methods = append(methods, serviceClientGenerator.InvokeMethod(op.Request, true, responseTypeOf(op), true).(*codegen.FuncType))
+	}
+
+	serviceClientGenerator.WriteClientInterfaceWith(methods...)
+	serviceClientGenerator.WriteClient()
+	serviceClientGenerator.WriteContextMethods()
+
+	for _, op := range g.Manifest.Operations {
+		file.WriteBlock(
+			serviceClientGenerator.InvokeMethod(op.Request, true, responseTypeOf(op), false),
+		)
+	}
+
+	for _, op := range g.Manifest.Operations {
+		operationGenerator.WriteInvokeMethods(op.Request, responseTypeOf(op))
+	}
+}
+
+// WriteNewHttpClient writes constructor of client.Client by base url, auth, timeout and error mapping of manifest
+func (g *APIClientGenerator) WriteNewHttpClient(file *codegen.File) {
+	m := g.Manifest
+
+	fields := []string{
+		"Name: " + strconv.Quote(m.Name),
+		"BaseURL: " + strconv.Quote(m.BaseURL),
+	}
+
+	if m.Timeout != "" {
+		fields = append(fields, "Timeout: "+strconv.Quote(m.Timeout))
+	}
+
+	if m.Auth != nil {
+		authFields := []string{"Type: " + strconv.Quote(m.Auth.Type)}
+		if m.Auth.Name != "" {
+			authFields = append(authFields, "Name: "+strconv.Quote(m.Auth.Name))
+		}
+		if m.Auth.Value != "" {
+			authFields = append(authFields, "Value: "+strconv.Quote(m.Auth.Value))
+		}
+		if m.Auth.ValueFromEnv != "" {
+			authFields = append(authFields, "ValueFromEnv: "+strconv.Quote(m.Auth.ValueFromEnv))
+		}
+		fields = append(fields, "Auth: &"+file.Use("github.com/go-courier/httptransport/client", "APIAuth")+"{"+strings.Join(authFields, ", ")+"}")
+	}
+
+	if len(m.Errors) > 0 {
+		codes := make([]int, 0, len(m.Errors))
+		for code := range m.Errors {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+
+		errorFields := make([]string, len(codes))
+		for i, code := range codes {
+			errorFields[i] = strconv.Itoa(code) + ": " + strconv.Quote(m.Errors[code])
+		}
+		fields = append(fields, "Errors: map[int]string{"+strings.Join(errorFields, ", ")+"}")
+	}
+
+	file.WriteBlock(
+		codegen.Func().
+			Named("NewHttp"+codegen.UpperCamelCase("Client-"+m.Name)).
+			Return(
+				codegen.Var(codegen.Star(codegen.Type(file.Use("github.com/go-courier/httptransport/client", "Client")))),
+				codegen.Var(codegen.Error),
+			).
+			Do(
+				codegen.Return(codegen.Expr(
+					"(&" + file.Use("github.com/go-courier/httptransport/client", "APIManifest") + "{\n" + strings.Join(fields, ",\n") + ",\n}).NewClient()",
+				)),
+			),
+	)
+}
+
+func (g *APIClientGenerator) Output(dir string) {
+	filename := path.Join(dir, codegen.LowerSnakeCase("Client-"+g.Manifest.Name)+"__generated.go")
+
+	file := codegen.NewFile(g.PkgName, filename)
+	g.Scan(file)
+	_, _ = file.WriteFile()
+
+	log.Printf("generated client of %s into %s", g.Manifest.Name, color.MagentaString(filename))
+}
+
+func responseTypeOf(op client.APIOperation) codegen.SnippetType {
+	if op.Response == "" {
+		return nil
+	}
+	return codegen.Type(op.Response)
+}
@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientGenerator(t *testing.T) {
+	m, err := client.ParseAPIManifest([]byte(`{
+  "name": "github",
+  "baseURL": "https://api.github.com",
+  "timeout": "10s",
+  "auth": { "type": "bearer", "valueFromEnv": "GITHUB_TOKEN" },
+  "errors": { "404": "RepoNotFound", "403": "RateLimited" },
+  "operations": [
+    { "request": "GetRepo", "response": "Repo" },
+    { "request": "DeleteRepo" }
+  ]
+}`))
+	require.NoError(t, err)
+
+	file := codegen.NewFile("github", "client_github__generated.go")
+	NewAPIClientGenerator("github", m).Scan(file)
+
+	code := string(file.Bytes())
+
+	require.Contains(t, code, `func NewHttpClientGithub() (*github_com_go_courier_httptransport_client.Client, error) {`)
+	require.Contains(t, code, `Auth:    &github_com_go_courier_httptransport_client.APIAuth{Type: "bearer", ValueFromEnv: "GITHUB_TOKEN"},`)
+	require.Contains(t, code, `Errors:  map[int]string{403: "RateLimited", 404: "RepoNotFound"},`)
+	require.Contains(t, code, `GetRepo(req *GetRepo, metas ...github_com_go_courier_courier.Metadata) (*Repo, github_com_go_courier_courier.Metadata, error)`)
+	require.Contains(t, code, `DeleteRepo(req *DeleteRepo, metas ...github_com_go_courier_courier.Metadata) (github_com_go_courier_courier.Metadata, error)`)
+	require.Contains(t, code, `ctx = github_com_go_courier_metax.ContextWith(ctx, "operationID", "github.GetRepo")`)
+	require.Contains(t, code, `func (req *DeleteRepo) InvokeContext(ctx context.Context, c github_com_go_courier_courier.Client, metas ...github_com_go_courier_courier.Metadata) (github_com_go_courier_courier.Metadata, error) {`)
+}
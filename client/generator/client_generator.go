@@ -13,7 +13,9 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/openapi/generator"
 	"github.com/go-courier/oas"
+	"github.com/go-courier/packagesx"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
@@ -45,6 +47,28 @@ func NewClientGenerator(serviceName string, u *url.URL, opts ...GenOptionFn) *Cl
 	return g
 }
 
+// NewClientGeneratorByOpenAPI creates generator by spec in memory,
+// like OpenAPI of openapi/generator.OpenAPIGenerator after routes scanned
+func NewClientGeneratorByOpenAPI(serviceName string, openAPI *oas.OpenAPI, opts ...GenOptionFn) *ClientGenerator {
+	g := &ClientGenerator{
+		ServiceName: serviceName,
+		openAPI:     openAPI,
+	}
+
+	for _, o := range opts {
+		o(&g.GenOption)
+	}
+
+	return g
+}
+
+// NewClientGeneratorByPackage creates generator by scanning routes of main package of server
+func NewClientGeneratorByPackage(serviceName string, pkg *packagesx.Package, opts ...GenOptionFn) *ClientGenerator {
+	openAPIGenerator := generator.NewOpenAPIGenerator(pkg)
+	openAPIGenerator.Scan(context.Background())
+	return NewClientGeneratorByOpenAPI(serviceName, openAPIGenerator.OpenAPI(), opts...)
+}
+
 type ClientGenerator struct {
 	ServiceName string
 	URL         *url.URL
@@ -171,5 +195,14 @@ func (g *ClientGenerator) Output(cwd string) {
 		_, _ = file.WriteFile()
 	}
 
+	{
+		file := codegen.NewFile(pkgName, path.Join(rootPath, "errors.go"))
+		statusErrGenerator := NewStatusErrGenerator(g.ServiceName, file)
+		statusErrGenerator.Scan(ctx, g.openAPI)
+		if len(statusErrGenerator.StatusErrs) > 0 {
+			_, _ = file.WriteFile()
+		}
+	}
+
 	log.Printf("generated client of %s into %s", g.ServiceName, color.MagentaString(rootPath))
 }
@@ -1,11 +1,14 @@
 package generator
 
 import (
+	"encoding/json"
 	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/go-courier/httptransport/openapi/generator"
+	"github.com/go-courier/oas"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,3 +27,38 @@ func TestToColonPath(t *testing.T) {
 	require.Equal(t, "/user/:userID/tags/:tagID", toColonPath("/user/{userID}/tags/{tagID}"))
 	require.Equal(t, "/user/:userID", toColonPath("/user/{userID}"))
 }
+
+func TestEachOperationSkipsOperational(t *testing.T) {
+	openAPI := oas.NewOpenAPI()
+
+	healthz := oas.NewOperation("Healthz")
+	healthz.AddExtension(generator.XOperational, true)
+	openAPI.AddOperation(oas.GET, "/healthz", healthz)
+	openAPI.AddOperation(oas.GET, "/users", oas.NewOperation("ListUsers"))
+
+	ids := make([]string, 0)
+	eachOperation(openAPI, func(method string, path string, op *oas.Operation) {
+		ids = append(ids, op.OperationId)
+	})
+
+	require.Equal(t, []string{"ListUsers"}, ids)
+}
+
+func TestClientGeneratorByOpenAPI(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	data, err := os.ReadFile(filepath.Join(cwd, "../../__examples__/server/cmd/app/openapi.json"))
+	require.NoError(t, err)
+
+	openAPI := &oas.OpenAPI{}
+	require.NoError(t, json.Unmarshal(data, openAPI))
+
+	dir := t.TempDir()
+
+	NewClientGeneratorByOpenAPI("demo", openAPI).Output(dir)
+
+	for _, name := range []string{"client.go", "operations.go", "types.go", "errors.go"} {
+		_, err := os.Stat(filepath.Join(dir, "client_demo", name))
+		require.NoError(t, err, name)
+	}
+}
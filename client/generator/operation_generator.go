@@ -4,9 +4,13 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/openapi/generator"
 	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
 )
 
 func NewOperationGenerator(serviceName string, file *codegen.File) *OperationGenerator {
@@ -30,6 +34,18 @@ func toColonPath(path string) string {
 	})
 }
 
+// matchPathParams checks path params of path with parameters in path,
+// which are fields of request, to avoid malformed urls of client generated
+func matchPathParams(path string, parameters []*oas.Parameter) error {
+	names := make([]string, 0)
+	for _, parameter := range parameters {
+		if parameter.In == oas.PositionPath {
+			names = append(names, parameter.Name)
+		}
+	}
+	return httptransport.NewPathnamePattern(path).MatchParams(names...)
+}
+
 func (g *OperationGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
 	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
 		g.WriteOperation(ctx, method, path, op)
@@ -46,6 +62,10 @@ func (g *OperationGenerator) ID(id string) string {
 func (g *OperationGenerator) WriteOperation(ctx context.Context, method string, path string, operation *oas.Operation) {
 	id := operation.OperationId
 
+	if err := matchPathParams(path, operation.Parameters); err != nil {
+		panic(errors.Wrapf(err, "invalid operation %s", id))
+	}
+
 	fields := make([]*codegen.SnippetField, 0)
 
 	for i := range operation.Parameters {
@@ -76,10 +96,18 @@ func (g *OperationGenerator) WriteOperation(ctx context.Context, method string,
 			Do(codegen.Return(g.File.Val(method))),
 	)
 
+	g.WriteCallDefaults(id, operation)
+
 	respType, statusErrors := g.ResponseType(ctx, &operation.Responses)
 
 	g.File.Write(codegen.Comments(statusErrors...).Bytes())
 
+	g.WriteInvokeMethods(id, respType)
+}
+
+// WriteInvokeMethods writes Do, InvokeContext and Invoke of request,
+// InvokeContext and Invoke decode body into respType when not nil
+func (g *OperationGenerator) WriteInvokeMethods(id string, respType codegen.SnippetType) {
 	ctxWithMeta := `ctx = ` + g.File.Use("github.com/go-courier/metax", "ContextWith") + `(ctx, "operationID","` + g.ID(id) + `")`
 
 	g.File.WriteBlock(
@@ -178,7 +206,41 @@ meta, err := req.Do(ctx, c, metas...).Into(resp)
 				codegen.Return(codegen.Expr("req.InvokeContext(context.Background(), c, metas...)")),
 			),
 	)
+}
 
+// WriteCallDefaults writes Timeout and Retryable of request by x-timeout and x-retry of operation
+func (g *OperationGenerator) WriteCallDefaults(id string, operation *oas.Operation) {
+	if timeout := timeoutOf(operation.Extensions[generator.XTimeout]); timeout > 0 {
+		g.File.WriteBlock(
+			codegen.Func().
+				Named("Timeout").Return(codegen.Var(codegen.Type(g.File.Use("time", "Duration")))).
+				MethodOf(codegen.Var(codegen.Type(id))).
+				Do(codegen.Return(codegen.Expr("? * ?", g.File.Val(int64(timeout/time.Millisecond)), codegen.Id(g.File.Use("time", "Millisecond"))))),
+		)
+	}
+
+	if retry, ok := operation.Extensions[generator.XRetry].(bool); ok {
+		g.File.WriteBlock(
+			codegen.Func().
+				Named("Retryable").Return(codegen.Var(codegen.Bool)).
+				MethodOf(codegen.Var(codegen.Type(id))).
+				Do(codegen.Return(g.File.Val(retry))),
+		)
+	}
+}
+
+func timeoutOf(v interface{}) time.Duration {
+	switch x := v.(type) {
+	case string:
+		d, err := time.ParseDuration(x)
+		if err != nil {
+			panic(errors.Wrapf(err, "invalid %s", generator.XTimeout))
+		}
+		return d
+	case float64:
+		return time.Duration(x * float64(time.Second))
+	}
+	return 0
 }
 
 func (g *OperationGenerator) ParamField(ctx context.Context, parameter *oas.Parameter) *codegen.SnippetField {
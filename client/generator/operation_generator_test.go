@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/openapi/generator"
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationGenerator_WriteCallDefaults(t *testing.T) {
+	t.Run("timeout and retry", func(t *testing.T) {
+		file := codegen.NewFile("client_demo", "client_demo.go")
+
+		op := oas.NewOperation("GetByID")
+		op.AddExtension(generator.XTimeout, "1.5s")
+		op.AddExtension(generator.XRetry, true)
+
+		NewOperationGenerator("demo", file).WriteCallDefaults("GetByID", op)
+
+		require.Equal(t, `package client_demo
+
+import (
+	time "time"
+)
+
+func (GetByID) Timeout() time.Duration {
+	return 1500 * time.Millisecond
+}
+
+func (GetByID) Retryable() bool {
+	return true
+}
+`, string(file.Bytes()))
+	})
+
+	t.Run("timeout in seconds", func(t *testing.T) {
+		file := codegen.NewFile("client_demo", "client_demo.go")
+
+		op := oas.NewOperation("GetByID")
+		op.AddExtension(generator.XTimeout, float64(3))
+
+		NewOperationGenerator("demo", file).WriteCallDefaults("GetByID", op)
+
+		require.Contains(t, string(file.Bytes()), "return 3000 * time.Millisecond")
+		require.NotContains(t, string(file.Bytes()), "Retryable")
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		op := oas.NewOperation("GetByID")
+		op.AddExtension(generator.XTimeout, "5")
+
+		require.Panics(t, func() {
+			NewOperationGenerator("demo", codegen.NewFile("client_demo", "client_demo.go")).WriteCallDefaults("GetByID", op)
+		})
+	})
+}
+
+func TestMatchPathParams(t *testing.T) {
+	parameters := []*oas.Parameter{
+		oas.PathParameter("userID", oas.String()),
+		oas.QueryParameter("size", oas.Integer(), false),
+	}
+
+	require.NoError(t, matchPathParams("/users/:userID", parameters))
+	require.EqualError(t, matchPathParams("/users/:id", parameters), "path params of /users/:id unmatched, missing [id], extra [userID]")
+
+	require.Panics(t, func() {
+		op := oas.NewOperation("GetUser")
+		op.AddParameter(oas.PathParameter("userID", oas.String()))
+
+		NewOperationGenerator("demo", codegen.NewFile("client_demo", "client_demo.go")).WriteOperation(context.Background(), "GET", "/users/:id", op)
+	})
+}
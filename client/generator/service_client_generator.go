@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/openapi/generator"
 	"github.com/go-courier/oas"
 )
 
@@ -20,10 +21,22 @@ type ServiceClientGenerator struct {
 }
 
 func (g *ServiceClientGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
+	g.WriteSpecHash(openapi)
+
 	g.WriteClientInterface(ctx, openapi)
 
 	g.WriteClient()
 
+	g.WriteContextMethods()
+
+	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
+		g.File.WriteBlock(
+			g.OperationMethod(ctx, op, false),
+		)
+	})
+}
+
+func (g *ServiceClientGenerator) WriteContextMethods() {
 	g.File.WriteBlock(codegen.Expr(`
 
 func (c *` + g.ClientInstanceName() + `) WithContext(ctx context.Context) ` + g.ClientInterfaceName() + ` {
@@ -41,15 +54,35 @@ func (c *` + g.ClientInstanceName() + `) Context() context.Context {
 }
 
 `))
+}
+
+// WriteSpecHash writes x-spec-hash of spec which client generated by,
+// could be verified by roundtrippers.NewSpecHashRoundTripper(SpecHash)
+func (g *ServiceClientGenerator) WriteSpecHash(openapi *oas.OpenAPI) {
+	specHash, ok := openapi.Extensions[generator.XSpecHash].(string)
+	if !ok || specHash == "" {
+		return
+	}
+
+	g.File.WriteBlock(
+		codegen.DeclConst(
+			codegen.Assign(codegen.Id("SpecHash")).By(g.File.Val(specHash)),
+		),
+	)
+}
+
+func (g *ServiceClientGenerator) WriteClientInterface(ctx context.Context, openapi *oas.OpenAPI) {
+	methods := make([]*codegen.FuncType, 0)
 
 	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
-		g.File.WriteBlock(
-			g.OperationMethod(ctx, op, false),
-		)
+		methods = append(methods, g.OperationMethod(ctx, op, true).(*codegen.FuncType))
 	})
+
+	g.WriteClientInterfaceWith(methods...)
 }
 
-func (g *ServiceClientGenerator) WriteClientInterface(ctx context.Context, openapi *oas.OpenAPI) {
+// WriteClientInterfaceWith writes interface of client with WithContext, Context and methods of operations
+func (g *ServiceClientGenerator) WriteClientInterfaceWith(methods ...*codegen.FuncType) {
 	varContext := codegen.Var(codegen.Type(g.File.Use("context", "Context")))
 
 	snippets := []codegen.SnippetCanBeInterfaceMethod{
@@ -57,9 +90,9 @@ func (g *ServiceClientGenerator) WriteClientInterface(ctx context.Context, opena
 		codegen.Func().Named("Context").Return(varContext),
 	}
 
-	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
-		snippets = append(snippets, g.OperationMethod(ctx, op, true).(*codegen.FuncType))
-	})
+	for i := range methods {
+		snippets = append(snippets, methods[i])
+	}
 
 	g.File.WriteBlock(
 		codegen.DeclType(
@@ -109,24 +142,31 @@ func (g *ServiceClientGenerator) WriteClient() {
 func (g *ServiceClientGenerator) OperationMethod(ctx context.Context, operation *oas.Operation, asInterface bool) codegen.Snippet {
 	mediaType, _ := mediaTypeAndStatusErrors(&operation.Responses)
 
-	params := make([]*codegen.SnippetField, 0)
-
 	hasReq := len(operation.Parameters) != 0 || requestBodyMediaType(operation.RequestBody) != nil
 
+	respType := codegen.SnippetType(nil)
+
+	if mediaType != nil {
+		respType, _ = NewTypeGenerator(g.ServiceName, g.File).Type(ctx, mediaType.Schema)
+	}
+
+	return g.InvokeMethod(operation.OperationId, hasReq, respType, asInterface)
+}
+
+// InvokeMethod of client calls InvokeContext of request with context of client
+func (g *ServiceClientGenerator) InvokeMethod(id string, hasReq bool, respType codegen.SnippetType, asInterface bool) codegen.Snippet {
+	params := make([]*codegen.SnippetField, 0)
+
 	if hasReq {
-		params = append(params, codegen.Var(codegen.Star(codegen.Type(operation.OperationId)), "req"))
+		params = append(params, codegen.Var(codegen.Star(codegen.Type(id)), "req"))
 	}
 
 	params = append(params, codegen.Var(codegen.Ellipsis(codegen.Type(g.File.Use("github.com/go-courier/courier", "Metadata"))), "metas"))
 
 	returns := make([]*codegen.SnippetField, 0)
 
-	if mediaType != nil {
-		respType, _ := NewTypeGenerator(g.ServiceName, g.File).Type(ctx, mediaType.Schema)
-
-		if respType != nil {
-			returns = append(returns, codegen.Var(codegen.Star(respType)))
-		}
+	if respType != nil {
+		returns = append(returns, codegen.Var(codegen.Star(respType)))
 	}
 
 	returns = append(
@@ -137,7 +177,7 @@ func (g *ServiceClientGenerator) OperationMethod(ctx context.Context, operation
 
 	m := codegen.Func(params...).
 		Return(returns...).
-		Named(operation.OperationId)
+		Named(id)
 
 	if asInterface {
 		return m
@@ -150,5 +190,5 @@ func (g *ServiceClientGenerator) OperationMethod(ctx context.Context, operation
 		return m.Do(codegen.Return(codegen.Expr("req.InvokeContext(c.Context(), c.Client, metas...)")))
 	}
 
-	return m.Do(codegen.Return(codegen.Expr("(&?{}).InvokeContext(c.Context(), c.Client, metas...)", codegen.Type(operation.OperationId))))
+	return m.Do(codegen.Return(codegen.Expr("(&?{}).InvokeContext(c.Context(), c.Client, metas...)", codegen.Type(id))))
 }
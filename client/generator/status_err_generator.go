@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/oas"
+	"github.com/go-courier/statuserror"
+)
+
+func NewStatusErrGenerator(serviceName string, file *codegen.File) *StatusErrGenerator {
+	return &StatusErrGenerator{
+		ServiceName: serviceName,
+		File:        file,
+		StatusErrs:  map[string]*statuserror.StatusErr{},
+	}
+}
+
+// StatusErrGenerator writes status errors of operations by x-status-errors as variables,
+// which could be matched by errors.Is(err, ErrXxx) with same key and code
+type StatusErrGenerator struct {
+	ServiceName string
+	File        *codegen.File
+	StatusErrs  map[string]*statuserror.StatusErr
+}
+
+func (g *StatusErrGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
+	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
+		_, statusErrors := mediaTypeAndStatusErrors(&op.Responses)
+
+		for _, summary := range statusErrors {
+			statusErr, err := statuserror.ParseStatusErrSummary(summary)
+			if err != nil {
+				continue
+			}
+			g.StatusErrs[statusErr.Key] = statusErr
+		}
+	})
+
+	if len(g.StatusErrs) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(g.StatusErrs))
+	for key := range g.StatusErrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	specs := make([]codegen.SnippetSpec, 0, len(keys))
+
+	for _, key := range keys {
+		statusErr := g.StatusErrs[key]
+
+		specs = append(specs, codegen.Assign(codegen.Id(g.VarName(key))).By(codegen.Expr(
+			"&?{Key: ?, Code: ?, Msg: ?, CanBeTalkError: ?}",
+			codegen.Type(g.File.Use("github.com/go-courier/statuserror", "StatusErr")),
+			g.File.Val(statusErr.Key),
+			g.File.Val(statusErr.Code),
+			g.File.Val(statusErr.Msg),
+			g.File.Val(statusErr.CanBeTalkError),
+		)))
+	}
+
+	g.File.WriteBlock(codegen.DeclVar(specs...))
+}
+
+func (g *StatusErrGenerator) VarName(key string) string {
+	return codegen.UpperCamelCase("Err-" + key)
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/openapi/generator"
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusErrGenerator(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+
+	op := oas.NewOperation("GetByID")
+	op.AddResponse(200, oas.NewResponse(""))
+
+	notFound := oas.NewResponse("")
+	notFound.AddExtension(generator.XStatusErrs, []interface{}{
+		"@StatusErr[NotFound][404000001][not found]",
+		"invalid",
+	})
+	op.AddResponse(404, notFound)
+
+	unauthorized := oas.NewResponse("")
+	unauthorized.AddExtension(generator.XStatusErrs, []interface{}{
+		"@StatusErr[Unauthorized][401000001][unauthorized]!",
+	})
+	op.AddResponse(401, unauthorized)
+
+	openapi.AddOperation(oas.GET, "/items/{id}", op)
+
+	file := codegen.NewFile("client_demo", "errors.go")
+	NewStatusErrGenerator("demo", file).Scan(context.Background(), openapi)
+
+	require.Equal(t, `package client_demo
+
+import (
+	github_com_go_courier_statuserror "github.com/go-courier/statuserror"
+)
+
+var (
+	ErrNotFound     = &github_com_go_courier_statuserror.StatusErr{Key: "NotFound", Code: 404000001, Msg: "not found", CanBeTalkError: false}
+	ErrUnauthorized = &github_com_go_courier_statuserror.StatusErr{Key: "Unauthorized", Code: 401000001, Msg: "unauthorized", CanBeTalkError: true}
+)
+`, string(file.Bytes()))
+}
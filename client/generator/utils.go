@@ -38,6 +38,10 @@ func eachOperation(openapi *oas.OpenAPI, mapper func(method string, path string,
 				continue
 			}
 
+			if _, ok := op.Extensions[generator.XOperational]; ok {
+				continue
+			}
+
 			ops[op.OperationId] = struct {
 				Method string
 				Path   string
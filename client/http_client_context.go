@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type contextKeyCookieJar int
+
+// ContextWithCookieJar sets cookie jar of http.Client created by GetShortConnClientContext or GetHttp2ClientContext
+func ContextWithCookieJar(ctx context.Context, jar http.CookieJar) context.Context {
+	return context.WithValue(ctx, contextKeyCookieJar(1), jar)
+}
+
+func CookieJarFromContext(ctx context.Context) http.CookieJar {
+	if ctx == nil {
+		return nil
+	}
+	if jar, ok := ctx.Value(contextKeyCookieJar(1)).(http.CookieJar); ok {
+		return jar
+	}
+	return nil
+}
+
+// CheckRedirect is redirect policy of http.Client
+type CheckRedirect func(req *http.Request, via []*http.Request) error
+
+// NoRedirect returns redirect response as is, instead of following
+func NoRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// MaxRedirects follows at most n redirects, fails when more
+func MaxRedirects(n int) CheckRedirect {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > n {
+			return errors.Errorf("stopped after %d redirects", n)
+		}
+		return nil
+	}
+}
+
+type contextKeyCheckRedirect int
+
+// ContextWithCheckRedirect sets redirect policy of http.Client created by GetShortConnClientContext or GetHttp2ClientContext
+func ContextWithCheckRedirect(ctx context.Context, checkRedirect CheckRedirect) context.Context {
+	return context.WithValue(ctx, contextKeyCheckRedirect(1), checkRedirect)
+}
+
+func CheckRedirectFromContext(ctx context.Context) CheckRedirect {
+	if ctx == nil {
+		return nil
+	}
+	if checkRedirect, ok := ctx.Value(contextKeyCheckRedirect(1)).(CheckRedirect); ok {
+		return checkRedirect
+	}
+	return nil
+}
+
+// applyHttpClientContext sets cookie jar and redirect policy in ctx to client
+func applyHttpClientContext(ctx context.Context, client *http.Client) {
+	client.Jar = CookieJarFromContext(ctx)
+	client.CheckRedirect = CheckRedirectFromContext(ctx)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithCookieJar(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		session := "new"
+		if cookie, err := r.Cookie("session"); err == nil {
+			session = cookie.Value
+		} else {
+			http.SetCookie(rw, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + session + `"}`))
+	})
+
+	jar, _ := cookiejar.New(nil)
+	c.CookieJar = jar
+
+	for _, expect := range []string{"new", "s1", "s1"} {
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, expect, data.ID)
+	}
+}
+
+func TestClientWithCheckRedirect(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data":
+			http.Redirect(rw, r, "/moved", http.StatusFound)
+		case "/moved":
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"id":"moved"}`))
+		}
+	})
+
+	t.Run("follow by default", func(t *testing.T) {
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "moved", data.ID)
+	})
+
+	t.Run("no redirect", func(t *testing.T) {
+		c.CheckRedirect = NoRedirect
+		defer func() {
+			c.CheckRedirect = nil
+		}()
+
+		// redirect response returned as error, since not 2xx
+		meta, err := c.Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+		require.Equal(t, "/moved", meta.Get("Location"))
+	})
+
+	t.Run("max redirects", func(t *testing.T) {
+		c.CheckRedirect = MaxRedirects(0)
+		defer func() {
+			c.CheckRedirect = nil
+		}()
+
+		_, err := c.Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+}
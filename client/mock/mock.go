@@ -0,0 +1,207 @@
+package mock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/client"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// Responder returns value or error as operator of server
+type Responder interface {
+	Respond(ctx context.Context, req interface{}) (interface{}, error)
+}
+
+type ResponderFunc func(ctx context.Context, req interface{}) (interface{}, error)
+
+func (fn ResponderFunc) Respond(ctx context.Context, req interface{}) (interface{}, error) {
+	return fn(ctx, req)
+}
+
+// Return responds v for every request, v could be *httpx.Response or value with describers like operator returns
+func Return(v interface{}) Responder {
+	return ResponderFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return v, nil
+	})
+}
+
+// ReturnError responds err for every request, err should be *statuserror.StatusErr to control status code
+func ReturnError(err error) Responder {
+	return ResponderFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, err
+	})
+}
+
+// Call records request of Client
+type Call struct {
+	Route   string
+	Request interface{}
+	Metas   []courier.Metadata
+}
+
+// NewClient creates in-memory courier.Client for generated clients.
+//
+//	c := mock.NewClient()
+//	c.On(&client_demo.GetByID{}, mock.Return(&client_demo.Data{ID: "1"}))
+//	data, _, err := client_demo.NewClientDemo(c).GetByID(&client_demo.GetByID{ID: "1"})
+func NewClient() *Client {
+	return &Client{}
+}
+
+// Client matches requests by route of operation (MethodDescriber and PathDescriber),
+// responses will be encoded and decoded as real client, so status errors and metadata work the same.
+type Client struct {
+	// transformer mgr for encoding request and response
+	TransformerMgr transformers.TransformerMgr
+
+	mu         sync.Mutex
+	rtMgr      *httptransport.RequestTransformerMgr
+	responders map[string]Responder
+	calls      []*Call
+}
+
+func (c *Client) transformerMgr() transformers.TransformerMgr {
+	if c.TransformerMgr == nil {
+		return transformers.TransformerMgrDefault
+	}
+	return c.TransformerMgr
+}
+
+func (c *Client) requestTransformerMgr() *httptransport.RequestTransformerMgr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rtMgr == nil {
+		c.rtMgr = httptransport.NewRequestTransformerMgr(c.transformerMgr(), nil)
+		c.rtMgr.SetDefaults()
+	}
+	return c.rtMgr
+}
+
+// On registers responder for route of operation
+func (c *Client) On(op interface{}, responder Responder) *Client {
+	return c.OnRoute(methodOf(op), pathOf(op), responder)
+}
+
+// OnRoute registers responder for method and path pattern like /users/:id
+func (c *Client) OnRoute(method string, path string, responder Responder) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.responders == nil {
+		c.responders = map[string]Responder{}
+	}
+	c.responders[method+" "+path] = responder
+	return c
+}
+
+// Calls returns recorded calls in order
+func (c *Client) Calls() []*Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*Call{}, c.calls...)
+}
+
+func (c *Client) Do(ctx context.Context, req interface{}, metas ...courier.Metadata) courier.Result {
+	route := methodOf(req) + " " + pathOf(req)
+
+	c.mu.Lock()
+	c.calls = append(c.calls, &Call{Route: route, Request: req, Metas: metas})
+	responder, ok := c.responders[route]
+	c.mu.Unlock()
+
+	mgr := c.transformerMgr()
+
+	result := &client.Result{
+		TransformerMgr: mgr,
+		NewError: func(resp *http.Response) error {
+			return &statuserror.StatusErr{
+				Code: resp.StatusCode * 1e6,
+				Msg:  resp.Status,
+			}
+		},
+	}
+
+	if !ok {
+		result.Err = statuserror.Wrap(errors.Errorf("no responder for %s", route), http.StatusNotImplemented, "MockNotFound")
+		return result
+	}
+
+	httpReq, ok := req.(*http.Request)
+	if !ok {
+		r, err := c.requestTransformerMgr().NewRequestWithContext(ctx, methodOf(req), pathOf(req), req)
+		if err != nil {
+			result.Err = statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed")
+			return result
+		}
+		httpReq = r
+	}
+
+	for _, meta := range metas {
+		for k, vs := range meta {
+			for _, v := range vs {
+				httpReq.Header.Add(k, v)
+			}
+		}
+	}
+
+	v, err := responder.Respond(ctx, req)
+	if err != nil {
+		v = err
+	}
+
+	rw := httptest.NewRecorder()
+
+	if err := httpx.ResponseFrom(v).WriteTo(rw, httpReq, func(response *httpx.Response) (string, httpx.Encode, error) {
+		transformer, err := mgr.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(response.Value)), transformers.TransformerOption{
+			MIME: response.ContentType,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		return transformer.String(), func(w io.Writer, v interface{}) error {
+			_, err := transformer.EncodeToWriter(w, v)
+			return err
+		}, nil
+	}); err != nil {
+		result.Err = statuserror.Wrap(err, http.StatusInternalServerError, "WriteFailed")
+		return result
+	}
+
+	result.Response = rw.Result()
+	result.Response.Request = httpReq
+
+	return result
+}
+
+func methodOf(op interface{}) string {
+	if r, ok := op.(*http.Request); ok {
+		return r.Method
+	}
+	if methodDescriber, ok := op.(httptransport.MethodDescriber); ok {
+		return methodDescriber.Method()
+	}
+	return ""
+}
+
+func pathOf(op interface{}) string {
+	if r, ok := op.(*http.Request); ok {
+		return r.URL.Path
+	}
+	if pathDescriber, ok := op.(httptransport.PathDescriber); ok {
+		return pathDescriber.Path()
+	}
+	return ""
+}
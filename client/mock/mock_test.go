@@ -0,0 +1,79 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type GetByID struct {
+	ID string `in:"path" name:"id"`
+}
+
+func (GetByID) Path() string {
+	return "/items/:id"
+}
+
+func (GetByID) Method() string {
+	return "GET"
+}
+
+type Item struct {
+	ID string `json:"id"`
+}
+
+type RemoveByID struct {
+	ID string `in:"path" name:"id"`
+}
+
+func (RemoveByID) Path() string {
+	return "/items/:id"
+}
+
+func (RemoveByID) Method() string {
+	return "DELETE"
+}
+
+func TestClient(t *testing.T) {
+	c := NewClient()
+
+	c.On(&GetByID{}, ResponderFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &Item{ID: req.(*GetByID).ID}, nil
+	}))
+	c.On(&RemoveByID{}, ReturnError(statuserror.Wrap(errors.New("denied"), http.StatusForbidden, "Forbidden")))
+
+	t.Run("canned value", func(t *testing.T) {
+		item := &Item{}
+		meta, err := c.Do(context.Background(), &GetByID{ID: "1"}, courier.Metadata{"X-Token": {"t"}}).Into(item)
+		require.NoError(t, err)
+		require.Equal(t, "1", item.ID)
+		require.Contains(t, meta.Get("Content-Type"), "application/json")
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		_, err := c.Do(context.Background(), &RemoveByID{ID: "1"}).Into(nil)
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, statusErr.StatusCode())
+		require.Equal(t, "Forbidden", statusErr.Key)
+	})
+
+	t.Run("no responder", func(t *testing.T) {
+		_, err := c.Do(context.Background(), &struct{}{}).Into(nil)
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "MockNotFound", statusErr.Key)
+	})
+
+	calls := c.Calls()
+	require.Len(t, calls, 3)
+	require.Equal(t, "GET /items/:id", calls[0].Route)
+	require.Equal(t, []courier.Metadata{{"X-Token": {"t"}}}, calls[0].Metas)
+}
@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Exchange of request and response recorded
+type Exchange struct {
+	Request      *http.Request
+	RequestBody  []byte
+	Response     *http.Response
+	ResponseBody []byte
+}
+
+// NewRecorder creates http.RoundTripper serving requests by handler in memory,
+// handler could be http handler of a service, like httptransport.NewHttpRouteHandler.
+//
+//	c := &client.Client{Host: "srv", HttpTransports: []client.HttpTransport{recorder.Transport}}
+func NewRecorder(handler http.Handler) *Recorder {
+	return &Recorder{handler: handler}
+}
+
+type Recorder struct {
+	handler http.Handler
+
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+// Transport could be used as client.HttpTransport, next round tripper will never be called
+func (r *Recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	return r
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange := &Exchange{Request: req}
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		exchange.RequestBody = data
+	}
+
+	serverReq := req.Clone(req.Context())
+	serverReq.Body = ioutil.NopCloser(bytes.NewReader(exchange.RequestBody))
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = "127.0.0.1:0"
+
+	rw := httptest.NewRecorder()
+	r.handler.ServeHTTP(rw, serverReq)
+
+	exchange.ResponseBody = rw.Body.Bytes()
+
+	resp := rw.Result()
+	resp.Request = req
+	exchange.Response = resp
+
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, exchange)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Exchanges returns recorded exchanges in order
+func (r *Recorder) Exchanges() []*Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Exchange{}, r.exchanges...)
+}
+
+// Reset drops recorded exchanges
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = nil
+}
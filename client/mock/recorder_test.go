@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.URL.Path + string(data) + `"}`))
+	}))
+
+	c := &client.Client{
+		Host:           "srv",
+		HttpTransports: []client.HttpTransport{recorder.Transport},
+	}
+	c.SetDefaults()
+
+	req, _ := http.NewRequest(http.MethodPost, c.URL("/items"), bytes.NewBufferString("1"))
+
+	item := &Item{}
+	_, err := c.Do(context.Background(), req).Into(item)
+	require.NoError(t, err)
+	require.Equal(t, "/items1", item.ID)
+
+	exchanges := recorder.Exchanges()
+	require.Len(t, exchanges, 1)
+	require.Equal(t, "1", string(exchanges[0].RequestBody))
+	require.Equal(t, http.StatusOK, exchanges[0].Response.StatusCode)
+	require.Equal(t, `{"id":"/items1"}`, string(exchanges[0].ResponseBody))
+
+	recorder.Reset()
+	require.Len(t, recorder.Exchanges(), 0)
+}
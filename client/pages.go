@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+
+	"github.com/go-courier/courier"
+	"github.com/pkg/errors"
+)
+
+// PageTokenSetter could be implemented by request of list to be iterated page by page,
+// token of next page will be set before each request after the first one.
+//
+//	type ListUsers struct {
+//		Size      int    `name:"size,omitempty" in:"query"`
+//		PageToken string `name:"pageToken,omitempty" in:"query"`
+//	}
+//
+//	func (req *ListUsers) SetPageToken(token string) {
+//		req.PageToken = token
+//	}
+type PageTokenSetter interface {
+	SetPageToken(token string)
+}
+
+// NextPageTokenDescriber could be implemented by response of list,
+// empty token means the last page.
+type NextPageTokenDescriber interface {
+	NextPageToken() string
+}
+
+// NewPageIterator iterates pages of req, newPage creates response of each page to decode into.
+//
+//	pages := client.NewPageIterator(c, &ListUsers{Size: 100}, func() client.NextPageTokenDescriber {
+//		return &UserList{}
+//	})
+//	for pages.Next(ctx) {
+//		list := pages.Page().(*UserList)
+//	}
+//	if err := pages.Err(); err != nil {
+//	}
+func NewPageIterator(c courier.Client, req PageTokenSetter, newPage func() NextPageTokenDescriber, metas ...courier.Metadata) *PageIterator {
+	return &PageIterator{
+		c:       c,
+		req:     req,
+		newPage: newPage,
+		metas:   metas,
+	}
+}
+
+type PageIterator struct {
+	c       courier.Client
+	req     PageTokenSetter
+	newPage func() NextPageTokenDescriber
+	metas   []courier.Metadata
+
+	started bool
+	token   string
+	page    NextPageTokenDescriber
+	meta    courier.Metadata
+	err     error
+}
+
+// Next requests next page, false when no more pages or failed
+func (it *PageIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.started && it.token == "") {
+		return false
+	}
+
+	if it.started {
+		it.req.SetPageToken(it.token)
+	}
+	it.started = true
+
+	page := it.newPage()
+
+	meta, err := it.c.Do(ctx, it.req, it.metas...).Into(page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	next := page.NextPageToken()
+	if next != "" && next == it.token {
+		// same token returned, which will loop forever
+		it.err = errors.Errorf("next page token %q should not be same as current", next)
+		return false
+	}
+
+	it.token = next
+	it.page = page
+	it.meta = meta
+
+	return true
+}
+
+// Page of current
+func (it *PageIterator) Page() NextPageTokenDescriber {
+	return it.page
+}
+
+// Meta of response of current page
+func (it *PageIterator) Meta() courier.Metadata {
+	return it.meta
+}
+
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// ForEachPage calls each with every page of req until last page, stopped when each returns error
+func ForEachPage(ctx context.Context, c courier.Client, req PageTokenSetter, newPage func() NextPageTokenDescriber, each func(page NextPageTokenDescriber) error, metas ...courier.Metadata) error {
+	pages := NewPageIterator(c, req, newPage, metas...)
+
+	for pages.Next(ctx) {
+		if err := each(pages.Page()); err != nil {
+			return err
+		}
+	}
+
+	return pages.Err()
+}
@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"context"
+
+	"github.com/go-courier/courier"
+)
+
+// EachPage calls each with every page of req decoded into *TResp, stopped when each returns error
+//
+//	err := client.EachPage[UserList](ctx, c, &ListUsers{Size: 100}, func(list *UserList) error {
+//		return nil
+//	})
+func EachPage[TResp any, PResp interface {
+	*TResp
+	NextPageTokenDescriber
+}](ctx context.Context, c courier.Client, req PageTokenSetter, each func(page PResp) error, metas ...courier.Metadata) error {
+	return ForEachPage(ctx, c, req, func() NextPageTokenDescriber {
+		return PResp(new(TResp))
+	}, func(page NextPageTokenDescriber) error {
+		return each(page.(PResp))
+	}, metas...)
+}
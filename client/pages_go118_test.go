@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEachPage(t *testing.T) {
+	c := newPagesTestClient(t)
+
+	ids := make([]string, 0)
+
+	err := EachPage[ItemList](context.Background(), c, &ListItems{Size: 2}, func(list *ItemList) error {
+		for _, item := range list.Data {
+			ids = append(ids, item.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1", "2"}, ids)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+type ListItems struct {
+	httpx.MethodGet
+	Size      int    `name:"size,omitempty" in:"query"`
+	PageToken string `name:"pageToken,omitempty" in:"query"`
+}
+
+func (ListItems) Path() string {
+	return "/items"
+}
+
+func (req *ListItems) SetPageToken(token string) {
+	req.PageToken = token
+}
+
+type ItemList struct {
+	Data []Data `json:"data"`
+	Next string `json:"next,omitempty"`
+}
+
+func (l *ItemList) NextPageToken() string {
+	return l.Next
+}
+
+func newPagesTestClient(t *testing.T) *Client {
+	return newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		switch token := r.URL.Query().Get("pageToken"); token {
+		case "":
+			_, _ = rw.Write([]byte(`{"data":[{"id":"0"},{"id":"1"}],"next":"2"}`))
+		case "2":
+			_, _ = rw.Write([]byte(`{"data":[{"id":"2"}]}`))
+		case "loop":
+			_, _ = rw.Write([]byte(`{"data":[],"next":"loop"}`))
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"key":"InvalidPageToken","code":` + strconv.Itoa(http.StatusBadRequest*1e6) + `}`))
+		}
+	})
+}
+
+func TestForEachPage(t *testing.T) {
+	c := newPagesTestClient(t)
+
+	newPage := func() NextPageTokenDescriber {
+		return &ItemList{}
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		ids := make([]string, 0)
+
+		err := ForEachPage(context.Background(), c, &ListItems{Size: 2}, newPage, func(page NextPageTokenDescriber) error {
+			for _, item := range page.(*ItemList).Data {
+				ids = append(ids, item.ID)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2"}, ids)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		pages := NewPageIterator(c, &ListItems{PageToken: "invalid"}, newPage)
+		require.False(t, pages.Next(context.Background()))
+		require.Error(t, pages.Err())
+	})
+
+	t.Run("same token", func(t *testing.T) {
+		pages := NewPageIterator(c, &ListItems{PageToken: "loop"}, newPage)
+
+		n := 0
+		for pages.Next(context.Background()) {
+			n++
+		}
+		require.Equal(t, 1, n)
+		require.Error(t, pages.Err())
+	})
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc resolves Proxy of http.Transport by Proxy or ProxyFromEnvironment of client
+func (c *Client) proxyFunc() (func(req *http.Request) (*url.URL, error), error) {
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid proxy")
+		}
+
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, errors.Errorf("unsupported scheme of proxy %s", c.Proxy)
+		}
+
+		return http.ProxyURL(u), nil
+	}
+
+	if c.ProxyFromEnvironment {
+		// read environment when transport created, http.ProxyFromEnvironment caches at first call
+		proxyURL := httpproxy.FromEnvironment().ProxyFunc()
+
+		return func(req *http.Request) (*url.URL, error) {
+			return proxyURL(req.URL)
+		}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithProxy(t *testing.T) {
+	// upstream.test never resolved, requests are answered by proxies
+	upstream := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.Host + `"}`))
+	})
+
+	newClient := func(c *Client) *Client {
+		c.Host = "upstream.test"
+		c.Port = 8080
+		c.SetDefaults()
+		return c
+	}
+
+	t.Run("http proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(upstream)
+		defer proxy.Close()
+
+		data := &Data{}
+		_, err := newClient(&Client{Proxy: proxy.URL}).Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "upstream.test:8080", data.ID)
+	})
+
+	t.Run("socks5 proxy", func(t *testing.T) {
+		upstreamSrv := httptest.NewServer(upstream)
+		defer upstreamSrv.Close()
+
+		proxyAddr := serveSocks5(t, upstreamSrv.Listener.Addr().String())
+
+		for _, keepAlive := range []bool{false, true} {
+			data := &Data{}
+			_, err := newClient(&Client{Proxy: "socks5://" + proxyAddr, KeepAlive: keepAlive}).Do(context.Background(), &GetData{}).Into(data)
+			require.NoError(t, err)
+			require.Equal(t, "upstream.test:8080", data.ID)
+		}
+	})
+
+	t.Run("proxy from environment", func(t *testing.T) {
+		proxy := httptest.NewServer(upstream)
+		defer proxy.Close()
+
+		os.Setenv("HTTP_PROXY", proxy.URL)
+		defer os.Unsetenv("HTTP_PROXY")
+
+		data := &Data{}
+		_, err := newClient(&Client{ProxyFromEnvironment: true}).Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "upstream.test:8080", data.ID)
+
+		os.Setenv("NO_PROXY", "upstream.test")
+		defer os.Unsetenv("NO_PROXY")
+
+		_, err = newClient(&Client{ProxyFromEnvironment: true}).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid proxy", func(t *testing.T) {
+		_, err := newClient(&Client{Proxy: "ftp://127.0.0.1"}).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+
+		_, err = newClient(&Client{Proxy: "http://127.0.0.1", HTTP2: true}).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+}
+
+// serveSocks5 serves no auth CONNECT of socks5, connects to target whatever address requested
+func serveSocks5(t *testing.T, target string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ln.Close()
+	})
+
+	handle := func(conn net.Conn) {
+		defer conn.Close()
+
+		buf := make([]byte, 262)
+
+		// version, methods
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte{5, 0})
+
+		// version, cmd, rsv, atyp
+		if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+			return
+		}
+		addrLen := 0
+		switch buf[3] {
+		case 1:
+			addrLen = 4
+		case 4:
+			addrLen = 16
+		case 3:
+			if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+				return
+			}
+			addrLen = int(buf[0])
+		}
+		if _, err := io.ReadFull(conn, buf[:addrLen+2]); err != nil {
+			return
+		}
+
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer upstream.Close()
+
+		host, port, _ := net.SplitHostPort(upstream.LocalAddr().String())
+		p, _ := strconv.Atoi(port)
+		reply := append([]byte{5, 0, 0, 1}, net.ParseIP(host).To4()...)
+		reply = append(reply, 0, 0)
+		binary.BigEndian.PutUint16(reply[len(reply)-2:], uint16(p))
+		_, _ = conn.Write(reply)
+
+		go func() {
+			_, _ = io.Copy(upstream, conn)
+		}()
+		_, _ = io.Copy(conn, upstream)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestOption overrides options of single call without mutating the shared Client,
+// should be set by ContextWithRequestOptions, since Do of courier.Client only accepts metadata.
+//
+//	ctx = client.ContextWithRequestOptions(ctx, client.WithTimeout(time.Second), client.WithHost("canary.user-service"))
+//	c.Do(ctx, req)
+type RequestOption func(o *requestOptions)
+
+type requestOptions struct {
+	timeout   time.Duration
+	header    http.Header
+	query     url.Values
+	host      string
+	connectTo string
+}
+
+// WithTimeout limits the whole call, overwrites TimeoutDescriber of request
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader sets header values of request, overwrites header from request and metadata
+func WithHeader(key string, values ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.header[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+}
+
+// WithQuery sets query values of request, overwrites query from request
+func WithQuery(key string, values ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.query[key] = values
+	}
+}
+
+// WithHost sends request to host, could be host, host:port or url with scheme like https://canary:8443,
+// Port and Protocol of Client will be used when missing. no failover when set.
+func WithHost(host string) RequestOption {
+	return func(o *requestOptions) {
+		o.host = host
+	}
+}
+
+// WithConnectTo dials addr instead of host of request, overwrites ConnectTo of Client.
+// connections will not be reused, to keep calls to host unaffected.
+func WithConnectTo(addr string) RequestOption {
+	return func(o *requestOptions) {
+		o.connectTo = addr
+	}
+}
+
+type contextKeyRequestOptions int
+
+// ContextWithRequestOptions appends options for calls with ctx
+func ContextWithRequestOptions(ctx context.Context, options ...RequestOption) context.Context {
+	return context.WithValue(ctx, contextKeyRequestOptions(1), append(requestOptionsFromContext(ctx), options...))
+}
+
+func requestOptionsFromContext(ctx context.Context) []RequestOption {
+	if ctx == nil {
+		return nil
+	}
+	options, _ := ctx.Value(contextKeyRequestOptions(1)).([]RequestOption)
+	return options
+}
+
+func resolveRequestOptions(ctx context.Context) *requestOptions {
+	options := requestOptionsFromContext(ctx)
+	if len(options) == 0 {
+		return nil
+	}
+
+	o := &requestOptions{
+		header: http.Header{},
+		query:  url.Values{},
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// url of path on host of options
+func (o *requestOptions) url(c *Client, path string) string {
+	if strings.Contains(o.host, "://") {
+		return strings.TrimRight(o.host, "/") + path
+	}
+
+	port := c.Port
+	if _, _, err := net.SplitHostPort(o.host); err == nil {
+		port = 0
+	}
+
+	protocol := c.Protocol
+	if protocol == ProtocolUnix {
+		protocol = "http"
+	}
+
+	return toUrl(protocol, o.host, port, path)
+}
+
+func (o *requestOptions) apply(request *http.Request) {
+	for key, values := range o.header {
+		request.Header[key] = values
+	}
+
+	if len(o.query) > 0 {
+		query := request.URL.Query()
+		for key, values := range o.query {
+			query[key] = values
+		}
+		request.URL.RawQuery = query.Encode()
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithRequestOptions(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sleep") != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.Header.Get("X-Tenant") + `:` + r.URL.Query().Get("version") + `"}`))
+	})
+
+	t.Run("header and query", func(t *testing.T) {
+		ctx := ContextWithRequestOptions(context.Background(), WithHeader("x-tenant", "t1"))
+		ctx = ContextWithRequestOptions(ctx, WithQuery("version", "2"))
+
+		data := &Data{}
+		_, err := c.Do(ctx, &GetData{}, courier.Metadata{"X-Tenant": {"ignored"}}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "t1:2", data.ID)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx := ContextWithRequestOptions(context.Background(), WithTimeout(10*time.Millisecond), WithQuery("sleep", "1"))
+
+		_, err := c.Do(ctx, &GetDataWithCallDefaults{}).Into(nil)
+		require.Error(t, err)
+
+		_, err = c.Do(context.Background(), &GetData{}).Into(nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("host", func(t *testing.T) {
+		canary := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"id":"canary"}`))
+		}))
+		defer canary.Close()
+
+		data := &Data{}
+		_, err := c.Do(ContextWithRequestOptions(context.Background(), WithHost(canary.URL)), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "canary", data.ID)
+
+		_, err = c.Do(ContextWithRequestOptions(context.Background(), WithHost(canary.Listener.Addr().String())), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "canary", data.ID)
+
+		_, err = c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, ":", data.ID)
+	})
+
+	t.Run("host without resolving", func(t *testing.T) {
+		canary := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"id":"canary"}`))
+		}))
+		defer canary.Close()
+
+		c := &Client{Host: "srv://user-service"}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(ContextWithRequestOptions(context.Background(), WithHost(canary.URL)), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "canary", data.ID)
+	})
+}
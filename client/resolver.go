@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HostSchemeSRV marks Host of Client as service name, like srv://user-service,
+// which will be resolved by Resolver of Client at request time.
+const HostSchemeSRV = "srv://"
+
+// Resolver resolves service name to endpoint for each request,
+// empty scheme means Protocol of Client.
+type Resolver interface {
+	Resolve(ctx context.Context, serviceName string) (scheme string, host string, port uint16, err error)
+}
+
+// EndpointsResolver could be implemented by Resolver to fail over to other endpoints of service,
+// when dial or tls handshake failed. endpoints should be in order to try.
+type EndpointsResolver interface {
+	ResolveEndpoints(ctx context.Context, serviceName string) ([]Endpoint, error)
+}
+
+type ResolverFunc func(ctx context.Context, serviceName string) (string, string, uint16, error)
+
+func (fn ResolverFunc) Resolve(ctx context.Context, serviceName string) (string, string, uint16, error) {
+	return fn(ctx, serviceName)
+}
+
+type Endpoint struct {
+	Scheme string
+	Host   string
+	Port   uint16
+}
+
+// LookupEndpoints lists instances of service from registry like Consul, Kubernetes or DNS SRV
+type LookupEndpoints func(ctx context.Context, serviceName string) ([]Endpoint, error)
+
+// LookupSRV lists endpoints by DNS SRV records of service name, like _http._tcp.user-service.default.svc.cluster.local
+func LookupSRV(resolver *net.Resolver) LookupEndpoints {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return func(ctx context.Context, serviceName string) ([]Endpoint, error) {
+		_, addrs, err := resolver.LookupSRV(ctx, "", "", serviceName)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints := make([]Endpoint, len(addrs))
+		for i, addr := range addrs {
+			endpoints[i] = Endpoint{Host: strings.TrimSuffix(addr.Target, "."), Port: addr.Port}
+		}
+		return endpoints, nil
+	}
+}
+
+// NewCachedResolver caches endpoints of each service for ttl and balances requests across them by round robin.
+// Stale endpoints will be used when refreshing failed.
+func NewCachedResolver(lookup LookupEndpoints, ttl time.Duration) *CachedResolver {
+	if ttl == 0 {
+		ttl = 30 * time.Second
+	}
+
+	return &CachedResolver{
+		lookup:  lookup,
+		ttl:     ttl,
+		entries: map[string]*resolvedEntry{},
+	}
+}
+
+type CachedResolver struct {
+	lookup LookupEndpoints
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*resolvedEntry
+}
+
+type resolvedEntry struct {
+	endpoints  []Endpoint
+	resolvedAt time.Time
+	next       uint32
+}
+
+func (r *CachedResolver) Resolve(ctx context.Context, serviceName string) (string, string, uint16, error) {
+	e, err := r.entry(ctx, serviceName)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	endpoint := e.endpoints[e.pick()]
+
+	return endpoint.Scheme, endpoint.Host, endpoint.Port, nil
+}
+
+// ResolveEndpoints returns all endpoints of service, starts from the one picked by round robin
+func (r *CachedResolver) ResolveEndpoints(ctx context.Context, serviceName string) ([]Endpoint, error) {
+	e, err := r.entry(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := e.pick()
+
+	endpoints := make([]Endpoint, 0, len(e.endpoints))
+	endpoints = append(endpoints, e.endpoints[start:]...)
+	endpoints = append(endpoints, e.endpoints[:start]...)
+
+	return endpoints, nil
+}
+
+func (e *resolvedEntry) pick() int {
+	return int((atomic.AddUint32(&e.next, 1) - 1) % uint32(len(e.endpoints)))
+}
+
+func (r *CachedResolver) entry(ctx context.Context, serviceName string) (*resolvedEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[serviceName]
+	if ok && time.Since(e.resolvedAt) < r.ttl {
+		return e, nil
+	}
+
+	endpoints, err := r.lookup(ctx, serviceName)
+	if err == nil && len(endpoints) == 0 {
+		err = errors.Errorf("no endpoints of %s", serviceName)
+	}
+
+	if err != nil {
+		if ok {
+			// retry lookup after next ttl, not for each request
+			e.resolvedAt = time.Now()
+			return e, nil
+		}
+		return nil, errors.Wrapf(err, "resolve %s failed", serviceName)
+	}
+
+	e = &resolvedEntry{endpoints: endpoints, resolvedAt: time.Now()}
+	r.entries[serviceName] = e
+
+	return e, nil
+}
+
+// Refresh drops cached endpoints of services, all services when no names
+func (r *CachedResolver) Refresh(serviceNames ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(serviceNames) == 0 {
+		r.entries = map[string]*resolvedEntry{}
+		return
+	}
+
+	for _, name := range serviceNames {
+		delete(r.entries, name)
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedResolver(t *testing.T) {
+	lookups := 0
+	lookupErr := error(nil)
+	endpoints := []Endpoint{{Host: "10.0.0.1", Port: 80}, {Scheme: "https", Host: "10.0.0.2", Port: 443}}
+
+	r := NewCachedResolver(func(ctx context.Context, serviceName string) ([]Endpoint, error) {
+		lookups++
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return endpoints, nil
+	}, time.Hour)
+
+	t.Run("round robin", func(t *testing.T) {
+		hosts := make([]string, 0)
+		for i := 0; i < 3; i++ {
+			_, host, _, err := r.Resolve(context.Background(), "svc")
+			require.NoError(t, err)
+			hosts = append(hosts, host)
+		}
+		require.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"}, hosts)
+		require.Equal(t, 1, lookups)
+	})
+
+	t.Run("stale endpoints when refresh failed", func(t *testing.T) {
+		lookupErr = errors.New("registry down")
+		defer func() {
+			lookupErr = nil
+		}()
+
+		r.entries["svc"].resolvedAt = time.Now().Add(-2 * time.Hour)
+
+		_, _, _, err := r.Resolve(context.Background(), "svc")
+		require.NoError(t, err)
+
+		_, _, _, err = r.Resolve(context.Background(), "other")
+		require.Error(t, err)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		lookups = 0
+		endpoints = []Endpoint{{Host: "10.0.0.3", Port: 80}}
+
+		r.Refresh("svc")
+
+		scheme, host, port, err := r.Resolve(context.Background(), "svc")
+		require.NoError(t, err)
+		require.Equal(t, "", scheme)
+		require.Equal(t, "10.0.0.3", host)
+		require.Equal(t, uint16(80), port)
+		require.Equal(t, 1, lookups)
+	})
+
+	t.Run("no endpoints", func(t *testing.T) {
+		endpoints = nil
+		r.Refresh()
+
+		_, _, _, err := r.Resolve(context.Background(), "svc")
+		require.Error(t, err)
+	})
+}
+
+func TestClientWithResolver(t *testing.T) {
+	endpoints := make([]Endpoint, 0)
+
+	for _, id := range []string{"a", "b"} {
+		id := id
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"id":"` + id + `"}`))
+		}))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+		endpoints = append(endpoints, Endpoint{Host: u.Hostname(), Port: uint16(port)})
+	}
+
+	c := &Client{
+		Host: "srv://user-service",
+		Resolver: NewCachedResolver(func(ctx context.Context, serviceName string) ([]Endpoint, error) {
+			require.Equal(t, "user-service", serviceName)
+			return endpoints, nil
+		}, 0),
+	}
+	c.SetDefaults()
+
+	ids := make([]string, 0)
+
+	for i := 0; i < 4; i++ {
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		ids = append(ids, data.ID)
+	}
+
+	require.Equal(t, []string{"a", "b", "a", "b"}, ids)
+
+	t.Run("missing resolver", func(t *testing.T) {
+		c := &Client{Host: "srv://user-service"}
+		c.SetDefaults()
+
+		_, err := c.Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrResponseBodyTooLarge will be wrapped as status error ResponseBodyTooLarge,
+	// when body of response beyond MaxResponseBodyBytes
+	ErrResponseBodyTooLarge = errors.New("response body too large")
+	// ErrResponseReadTimeout will be wrapped as status error ResponseReadTimeout,
+	// when body of response not read in ReadTimeout
+	ErrResponseReadTimeout = errors.New("response read timeout")
+)
+
+// responseBodyStatusErr converts errors of limitedBody and deadlineBody into status error
+func responseBodyStatusErr(err error) *statuserror.StatusErr {
+	switch {
+	case errors.Is(err, ErrResponseBodyTooLarge):
+		return statuserror.Wrap(err, http.StatusInternalServerError, "ResponseBodyTooLarge")
+	case errors.Is(err, ErrResponseReadTimeout):
+		return statuserror.Wrap(err, http.StatusGatewayTimeout, "ResponseReadTimeout")
+	}
+	return nil
+}
+
+// limitedBody fails reading with ErrResponseBodyTooLarge instead of truncating silently like io.LimitReader
+type limitedBody struct {
+	io.Reader
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseBodyTooLarge
+	}
+	// one more byte to tell body ended or beyond limit
+	if int64(len(p)) > b.remaining+1 {
+		p = p[0 : b.remaining+1]
+	}
+	n, err := b.Reader.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrResponseBodyTooLarge
+	}
+	return n, err
+}
+
+// newDeadlineBody cancels request when body not fully read and closed in timeout,
+// reads blocked by slow upstreams will be interrupted with ErrResponseReadTimeout.
+// body not closed by timer, which could not be closed concurrently with reading by wrappers of round trippers.
+func newDeadlineBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) io.ReadCloser {
+	b := &deadlineBody{ReadCloser: body, cancel: cancel}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		b.timeout = true
+		b.mu.Unlock()
+
+		cancel()
+	})
+	return b
+}
+
+type deadlineBody struct {
+	io.ReadCloser
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	timeout bool
+}
+
+func (b *deadlineBody) isTimeout() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.timeout
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	if b.isTimeout() {
+		return 0, ErrResponseReadTimeout
+	}
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.isTimeout() {
+		return n, ErrResponseReadTimeout
+	}
+	return n, err
+}
+
+func (b *deadlineBody) Close() error {
+	b.timer.Stop()
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithMaxResponseBodyBytes(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("chunked") != "" {
+			// no Content-Length
+			rw.(http.Flusher).Flush()
+		}
+		_, _ = rw.Write([]byte(`{"id":"` + strings.Repeat("x", 100) + `"}`))
+	})
+
+	c.MaxResponseBodyBytes = 50
+
+	t.Run("by Content-Length", func(t *testing.T) {
+		_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "ResponseBodyTooLarge", statusErr.Key)
+	})
+
+	t.Run("by reading", func(t *testing.T) {
+		ctx := ContextWithRequestOptions(context.Background(), WithQuery("chunked", "1"))
+
+		buf := bytes.NewBuffer(nil)
+		_, err := c.Do(ctx, &GetData{}).Into(buf)
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "ResponseBodyTooLarge", statusErr.Key)
+		require.Equal(t, 50, buf.Len())
+	})
+
+	t.Run("in limit", func(t *testing.T) {
+		c.MaxResponseBodyBytes = 200
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Len(t, data.ID, 100)
+	})
+}
+
+func TestClientWithReadTimeout(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":`))
+		rw.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = rw.Write([]byte(`"1"}`))
+	})
+
+	t.Run("slow read", func(t *testing.T) {
+		c.ReadTimeout = 50 * time.Millisecond
+
+		_, err := c.Do(context.Background(), &GetData{}).Into(&Data{})
+
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "ResponseReadTimeout", statusErr.Key)
+		require.Equal(t, http.StatusGatewayTimeout, statusErr.StatusCode())
+	})
+
+	t.Run("read in time", func(t *testing.T) {
+		c.ReadTimeout = time.Second
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "1", data.ID)
+	})
+}
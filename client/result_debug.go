@@ -0,0 +1,43 @@
+//go:build debug
+// +build debug
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// LeakedResults counts results collected by gc without consumed
+var LeakedResults int64
+
+// trackResult reports result with the stack of Do when it collected without consumed
+func trackResult(r *Result) *Result {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := strings.Builder{}
+	for {
+		frame, more := frames.Next()
+		stack.WriteString(fmt.Sprintf("\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+
+	runtime.SetFinalizer(r, func(r *Result) {
+		if atomic.LoadInt32(&r.consumed) == 0 {
+			atomic.AddInt64(&LeakedResults, 1)
+			fmt.Fprintf(os.Stderr, "[Courier] body of result never consumed by Into, IntoReader or Discard, requested at:%s\n", stack.String())
+			if r.Response != nil && r.Response.Body != nil {
+				r.Response.Body.Close()
+			}
+		}
+	})
+
+	return r
+}
@@ -0,0 +1,33 @@
+//go:build debug
+// +build debug
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultLeakDetector(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	leaked := atomic.LoadInt64(&LeakedResults)
+
+	func() {
+		_ = c.Do(context.Background(), &GetData{})
+	}()
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		return atomic.LoadInt64(&LeakedResults) == leaked+1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,8 @@
+//go:build !debug
+// +build !debug
+
+package client
+
+func trackResult(r *Result) *Result {
+	return r
+}
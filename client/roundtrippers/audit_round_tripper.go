@@ -0,0 +1,127 @@
+package roundtrippers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-courier/metax"
+)
+
+// AuditRecord of outbound request
+type AuditRecord struct {
+	Method string
+	Host   string
+	Path   string
+	// operation id of outbound request
+	OperationID string
+	// caller from context, service@version/OperationID of the inbound request when called in operator
+	Caller        string
+	StatusCode    int
+	StartedAt     time.Time
+	Duration      time.Duration
+	RequestBytes  int64
+	ResponseBytes int64
+	Err           error
+}
+
+type AuditSink interface {
+	Record(ctx context.Context, record *AuditRecord)
+}
+
+type AuditSinkFunc func(ctx context.Context, record *AuditRecord)
+
+func (fn AuditSinkFunc) Record(ctx context.Context, record *AuditRecord) {
+	fn(ctx, record)
+}
+
+// NewAuditRoundTripper records every outbound request into sink,
+// record will be sent after response body closed, or request failed.
+func NewAuditRoundTripper(sink AuditSink) func(roundTripper http.RoundTripper) http.RoundTripper {
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &AuditRoundTripper{
+			nextRoundTripper: roundTripper,
+			sink:             sink,
+		}
+	}
+}
+
+type AuditRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	sink             AuditSink
+}
+
+func (rt *AuditRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	record := &AuditRecord{
+		Method:      req.Method,
+		Host:        req.URL.Host,
+		Path:        req.URL.Path,
+		OperationID: OperationIDFromContext(ctx),
+		Caller:      metax.MetaFromContext(ctx).Get("operator"),
+		StartedAt:   time.Now(),
+	}
+
+	var requestBody *countingReadCloser
+
+	if req.Body != nil && req.Body != http.NoBody {
+		requestBody = &countingReadCloser{ReadCloser: req.Body}
+		req = req.Clone(ctx)
+		req.Body = requestBody
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+	if requestBody != nil {
+		record.RequestBytes = atomic.LoadInt64(&requestBody.n)
+	}
+
+	if err != nil {
+		record.Err = err
+		record.Duration = time.Since(record.StartedAt)
+		rt.sink.Record(ctx, record)
+		return nil, err
+	}
+
+	record.StatusCode = resp.StatusCode
+
+	responseBody := &countingReadCloser{
+		ReadCloser: resp.Body,
+		onClose: func(n int64) {
+			record.ResponseBytes = n
+			record.Duration = time.Since(record.StartedAt)
+			rt.sink.Record(ctx, record)
+		},
+	}
+
+	resp.Body = responseBody
+
+	return resp, nil
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n       int64
+	once    sync.Once
+	onClose func(n int64)
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+func (r *countingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	if r.onClose != nil {
+		r.once.Do(func() {
+			r.onClose(atomic.LoadInt64(&r.n))
+		})
+	}
+	return err
+}
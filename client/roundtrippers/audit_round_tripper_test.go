@@ -0,0 +1,68 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/metax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte("12345"))
+	}))
+	defer srv.Close()
+
+	records := make([]*AuditRecord, 0)
+
+	rt := NewAuditRoundTripper(AuditSinkFunc(func(ctx context.Context, record *AuditRecord) {
+		records = append(records, record)
+	}))(http.DefaultTransport)
+
+	t.Run("recorded after body closed", func(t *testing.T) {
+		ctx := metax.ContextWithMeta(context.Background(), metax.Meta{"operator": {"srv@1.0.0/Caller"}})
+		ctx = ContextWithOperationID(ctx, "CreateItem")
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/items", bytes.NewBufferString("body"))
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Len(t, records, 0)
+
+		_, _ = ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		_ = resp.Body.Close()
+
+		require.Len(t, records, 1)
+
+		record := records[0]
+		require.Equal(t, http.MethodPost, record.Method)
+		require.Equal(t, strings.TrimPrefix(srv.URL, "http://"), record.Host)
+		require.Equal(t, "/items", record.Path)
+		require.Equal(t, "CreateItem", record.OperationID)
+		require.Equal(t, "srv@1.0.0/Caller", record.Caller)
+		require.Equal(t, http.StatusCreated, record.StatusCode)
+		require.Equal(t, int64(4), record.RequestBytes)
+		require.Equal(t, int64(5), record.ResponseBytes)
+		require.True(t, record.Duration > 0)
+	})
+
+	t.Run("recorded when request failed", func(t *testing.T) {
+		records = records[0:0]
+
+		req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:0", nil)
+		_, err := rt.RoundTrip(req)
+		require.Error(t, err)
+
+		require.Len(t, records, 1)
+		require.Error(t, records[0].Err)
+	})
+}
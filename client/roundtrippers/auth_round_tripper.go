@@ -0,0 +1,87 @@
+package roundtrippers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	AuthTypeBearer = "bearer"
+	AuthTypeBasic  = "basic"
+	AuthTypeHeader = "header"
+	AuthTypeQuery  = "query"
+)
+
+type AuthOptions struct {
+	// bearer, basic, header or query
+	Type string
+	// name of header or query, default Authorization when header
+	Name string
+	// token, or username:password when basic
+	Value string
+}
+
+func (opts *AuthOptions) SetDefaults() {
+	opts.Type = strings.ToLower(opts.Type)
+	if opts.Type == AuthTypeHeader && opts.Name == "" {
+		opts.Name = "Authorization"
+	}
+}
+
+// NewAuthRoundTripper sets credentials of third-party APIs into requests,
+// header or query already set by caller will not be overwritten.
+func NewAuthRoundTripper(opts AuthOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &AuthRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type AuthRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             AuthOptions
+}
+
+func (rt *AuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// clone to keep the request of caller untouched
+	r := req.Clone(req.Context())
+
+	if err := setAuth(r, rt.opts); err != nil {
+		return nil, err
+	}
+
+	return rt.nextRoundTripper.RoundTrip(r)
+}
+
+func setAuth(r *http.Request, opts AuthOptions) error {
+	switch opts.Type {
+	case AuthTypeBearer:
+		if r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+opts.Value)
+		}
+	case AuthTypeBasic:
+		if r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(opts.Value)))
+		}
+	case AuthTypeHeader:
+		if r.Header.Get(opts.Name) == "" {
+			r.Header.Set(opts.Name, opts.Value)
+		}
+	case AuthTypeQuery:
+		query := r.URL.Query()
+		if query.Get(opts.Name) == "" {
+			query.Set(opts.Name, opts.Value)
+			r.URL.RawQuery = query.Encode()
+		}
+	default:
+		return errors.Errorf("unsupported auth type %s", opts.Type)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthRoundTripper(t *testing.T) {
+	doWith := func(t *testing.T, opts AuthOptions, req *http.Request) *http.Request {
+		sent := (*http.Request)(nil)
+
+		rt := NewAuthRoundTripper(opts)(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+		}))
+
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		return sent
+	}
+
+	t.Run("bearer", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+
+		sent := doWith(t, AuthOptions{Type: "Bearer", Value: "token"}, req)
+		require.Equal(t, "Bearer token", sent.Header.Get("Authorization"))
+		require.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+
+		sent := doWith(t, AuthOptions{Type: AuthTypeBasic, Value: "user:pass"}, req)
+		username, password, ok := sent.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", username)
+		require.Equal(t, "pass", password)
+	})
+
+	t.Run("header set by caller", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+		req.Header.Set("X-Api-Key", "caller")
+
+		sent := doWith(t, AuthOptions{Type: AuthTypeHeader, Name: "X-Api-Key", Value: "key"}, req)
+		require.Equal(t, "caller", sent.Header.Get("X-Api-Key"))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items?size=10", nil)
+
+		sent := doWith(t, AuthOptions{Type: AuthTypeQuery, Name: "api_key", Value: "key"}, req)
+		require.Equal(t, "key", sent.URL.Query().Get("api_key"))
+		require.Equal(t, "10", sent.URL.Query().Get("size"))
+		require.Equal(t, "size=10", req.URL.RawQuery)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+
+		_, err := NewAuthRoundTripper(AuthOptions{Type: "digest"})(http.DefaultTransport).RoundTrip(req)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,325 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type CacheOptions struct {
+	// store of responses, default in-memory LRU store of MaxEntries
+	Store CacheStore
+	// max cached entries of default store, default 1024
+	MaxEntries int
+	// serve stale entries immediately while refreshing in background,
+	// in window of stale-while-revalidate directive (RFC 5861)
+	StaleWhileRevalidate bool
+	// serve stale entries when refreshing failed (errors or 5xx),
+	// in window of stale-if-error directive (RFC 5861)
+	StaleIfError bool
+	// timeout of background revalidation, default 10s
+	RevalidateTimeout time.Duration
+}
+
+func (o *CacheOptions) SetDefaults() {
+	if o.MaxEntries == 0 {
+		o.MaxEntries = 1024
+	}
+	if o.RevalidateTimeout == 0 {
+		o.RevalidateTimeout = 10 * time.Second
+	}
+	if o.Store == nil {
+		o.Store = NewLRUCacheStore(o.MaxEntries)
+	}
+}
+
+// NewCacheRoundTripper caches success responses of GET by Cache-Control max-age into CacheStore,
+// and revalidates by ETag or Last-Modified when stale, cached response will be returned on 304.
+// Responses without max-age or with no-cache will be cached only with ETag or Last-Modified, and revalidated before each reuse.
+// Responses with no-store or private will not be cached,
+// requests with Cache-Control no-cache will skip fresh entries.
+// Requests with credentials (Authorization or Cookie) only store and reuse responses with public.
+func NewCacheRoundTripper(opts CacheOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	// shared by round trippers wrapped for each request
+	c := &cacher{opts: opts}
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &CacheRoundTripper{
+			nextRoundTripper: roundTripper,
+			cacher:           c,
+		}
+	}
+}
+
+type CacheRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	*cacher
+}
+
+type cacher struct {
+	opts  CacheOptions
+	group singleflight.Group
+}
+
+// credentials of requests, responses of which are private unless public
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+func withCredentials(req *http.Request) bool {
+	for _, h := range credentialHeaders {
+		if req.Header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *CachedResponse) age(now time.Time) time.Duration {
+	return now.Sub(e.StoredAt)
+}
+
+func (e *CachedResponse) directives() cacheDirectives {
+	return parseCacheDirectives(e.Header)
+}
+
+func (e *CachedResponse) fresh(now time.Time) bool {
+	return e.age(now) < e.directives().maxAge
+}
+
+func (e *CachedResponse) staleWhileRevalidate(now time.Time) bool {
+	d := e.directives()
+	return e.age(now) < d.maxAge+d.staleWhileRevalidate
+}
+
+func (e *CachedResponse) staleIfError(now time.Time) bool {
+	d := e.directives()
+	return e.age(now) < d.maxAge+d.staleIfError
+}
+
+func (e *CachedResponse) matchVary(req *http.Request) bool {
+	for key, value := range e.Vary {
+		if req.Header.Get(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *CachedResponse) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(e.age(now)/time.Second)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (rt *CacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	now := time.Now()
+
+	entry := rt.load(key, req)
+
+	if entry != nil && !parseCacheDirectives(req.Header).noCache {
+		if entry.fresh(now) {
+			return entry.response(req, now), nil
+		}
+
+		if rt.opts.StaleWhileRevalidate && entry.staleWhileRevalidate(now) {
+			go rt.revalidate(key, req, entry)
+			return entry.response(req, now), nil
+		}
+	}
+
+	resp, err := rt.fetch(key, req, entry)
+
+	if entry != nil && rt.opts.StaleIfError && (err != nil || resp.StatusCode >= http.StatusInternalServerError) && entry.staleIfError(now) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return entry.response(req, now), nil
+	}
+
+	return resp, err
+}
+
+func (rt *CacheRoundTripper) revalidate(key string, req *http.Request, entry *CachedResponse) {
+	// one background revalidation for each key
+	_, _, _ = rt.group.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), rt.opts.RevalidateTimeout)
+		defer cancel()
+
+		resp, err := rt.fetch(key, req.Clone(ctx), entry)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		return nil, nil
+	})
+}
+
+// fetch requests with conditional headers of stale entry, and stores cacheable response
+func (rt *CacheRoundTripper) fetch(key string, req *http.Request, entry *CachedResponse) (*http.Response, error) {
+	conditional := req
+
+	if entry != nil {
+		etag, lastModified := entry.Header.Get("ETag"), entry.Header.Get("Last-Modified")
+
+		if etag != "" || lastModified != "" {
+			conditional = req.Clone(req.Context())
+			if etag != "" {
+				conditional.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				conditional.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil && conditional != req {
+		resp.Body.Close()
+
+		refreshed := *entry
+		refreshed.Header = entry.Header.Clone()
+		for k, vs := range resp.Header {
+			refreshed.Header[k] = vs
+		}
+		refreshed.StoredAt = now
+
+		rt.opts.Store.Set(key, &refreshed)
+
+		return refreshed.response(req, now), nil
+	}
+
+	directives := parseCacheDirectives(resp.Header)
+
+	// without max-age, cache only when could be revalidated
+	revalidatable := resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+
+	if resp.StatusCode != http.StatusOK || directives.noStore || directives.private || (directives.maxAge <= 0 && !revalidatable) || resp.Header.Get("Vary") == "*" {
+		return resp, nil
+	}
+
+	// shared cache, response of request with credentials could not be served to others
+	if withCredentials(req) && !directives.public {
+		return resp, nil
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       data,
+		StoredAt:   now,
+		Vary:       map[string]string{},
+	}
+
+	for _, v := range resp.Header.Values("Vary") {
+		for _, key := range strings.Split(v, ",") {
+			if key = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(key)); key != "" {
+				stored.Vary[key] = req.Header.Get(key)
+			}
+		}
+	}
+
+	rt.opts.Store.Set(key, stored)
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return resp, nil
+}
+
+func (rt *CacheRoundTripper) load(key string, req *http.Request) *CachedResponse {
+	if entry, ok := rt.opts.Store.Get(key); ok && entry.matchVary(req) {
+		if withCredentials(req) && !entry.directives().public {
+			return nil
+		}
+		return entry
+	}
+	return nil
+}
+
+type cacheDirectives struct {
+	noStore              bool
+	noCache              bool
+	public               bool
+	private              bool
+	maxAge               time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+}
+
+func parseCacheDirectives(header http.Header) cacheDirectives {
+	d := cacheDirectives{}
+
+	for _, v := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			kv := strings.SplitN(strings.TrimSpace(directive), "=", 2)
+
+			seconds := func() time.Duration {
+				if len(kv) == 2 {
+					if n, err := strconv.ParseInt(strings.Trim(kv[1], `"`), 10, 64); err == nil {
+						return time.Duration(n) * time.Second
+					}
+				}
+				return 0
+			}
+
+			switch strings.ToLower(kv[0]) {
+			case "no-store":
+				d.noStore = true
+			case "no-cache":
+				d.noCache = true
+			case "public":
+				d.public = true
+			case "private":
+				d.private = true
+			case "max-age":
+				d.maxAge = seconds()
+			case "stale-while-revalidate":
+				d.staleWhileRevalidate = seconds()
+			case "stale-if-error":
+				d.staleIfError = seconds()
+			}
+		}
+	}
+
+	if d.noCache {
+		// must revalidate before each reuse
+		d.maxAge = 0
+	}
+
+	return d
+}
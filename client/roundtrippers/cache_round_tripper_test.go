@@ -0,0 +1,262 @@
+package roundtrippers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheRoundTripper(t *testing.T) {
+	requests := int32(0)
+	notModified := int32(0)
+	failed := int32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+
+		if atomic.LoadInt32(&failed) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60, stale-if-error=600")
+		rw.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = rw.Write([]byte(strconv.Itoa(int(n))))
+	}))
+	defer srv.Close()
+
+	rt := NewCacheRoundTripper(CacheOptions{
+		StaleWhileRevalidate: true,
+		StaleIfError:         true,
+	})(http.DefaultTransport).(*CacheRoundTripper)
+
+	get := func(headers ...string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/items", nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	age := func(d time.Duration) {
+		key := srv.URL + "/items"
+		if e, ok := rt.opts.Store.Get(key); ok {
+			aged := *e
+			aged.StoredAt = e.StoredAt.Add(-d)
+			rt.opts.Store.Set(key, &aged)
+		}
+	}
+
+	_, body := get()
+	require.Equal(t, "1", body)
+
+	t.Run("fresh", func(t *testing.T) {
+		resp, body := get()
+		require.Equal(t, "1", body)
+		require.Equal(t, "0", resp.Header.Get("Age"))
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("stale while revalidate", func(t *testing.T) {
+		age(90 * time.Second)
+
+		resp, body := get()
+		require.Equal(t, "1", body)
+		require.Equal(t, "90", resp.Header.Get("Age"))
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&notModified) == 1
+		}, time.Second, 5*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			resp, _ := get()
+			return resp.Header.Get("Age") == "0"
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("stale if error", func(t *testing.T) {
+		atomic.StoreInt32(&failed, 1)
+		defer atomic.StoreInt32(&failed, 0)
+
+		age(300 * time.Second)
+
+		resp, body := get()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "1", body)
+	})
+
+	t.Run("request no-cache", func(t *testing.T) {
+		before := atomic.LoadInt32(&requests)
+
+		_, body := get("Cache-Control", "no-cache")
+		require.Equal(t, "1", body)
+		require.Equal(t, before+1, atomic.LoadInt32(&requests))
+	})
+}
+
+func TestCacheRoundTripperRevalidateWithoutMaxAge(t *testing.T) {
+	requests := int32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		rw.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+		if r.Header.Get("If-Modified-Since") != "" {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = rw.Write([]byte("countries"))
+	}))
+	defer srv.Close()
+
+	store := NewLRUCacheStore(10)
+
+	rt := NewCacheRoundTripper(CacheOptions{Store: store})(http.DefaultTransport)
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/countries", nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		data, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "countries", string(data))
+	}
+
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	require.Equal(t, 1, store.Len())
+}
+
+func TestCacheRoundTripperWithCredentials(t *testing.T) {
+	requests := int32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/public":
+			rw.Header().Set("Cache-Control", "public, max-age=60")
+		case "/private":
+			rw.Header().Set("Cache-Control", "private, max-age=60")
+		default:
+			rw.Header().Set("Cache-Control", "max-age=60")
+		}
+		_, _ = rw.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	rt := NewCacheRoundTripper(CacheOptions{})(http.DefaultTransport)
+
+	get := func(path string, token string) string {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	t.Run("different bearer tokens should not share entries", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		require.Equal(t, "Bearer a", get("/me", "a"))
+		require.Equal(t, "Bearer b", get("/me", "b"))
+		require.Equal(t, "", get("/me", ""))
+		require.Equal(t, "Bearer a", get("/me", "a"))
+		require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+		require.Equal(t, "", get("/me", ""))
+		require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("public could be shared", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		require.Equal(t, "Bearer a", get("/public", "a"))
+		require.Equal(t, "Bearer a", get("/public", "b"))
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("private should not be stored", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		get("/private", "")
+		get("/private", "")
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestCacheRoundTripperRevalidateOnceAcrossWraps(t *testing.T) {
+	revalidations := int32(0)
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60")
+		rw.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&revalidations, 1)
+			<-release
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = rw.Write([]byte("data"))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	store := NewLRUCacheStore(10)
+	newRoundTripper := NewCacheRoundTripper(CacheOptions{Store: store, StaleWhileRevalidate: true})
+
+	get := func() {
+		// wrapped for each request, like Client in short-conn mode
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/items", nil)
+		resp, err := newRoundTripper(http.DefaultTransport).RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	get()
+
+	e, ok := store.Get(srv.URL + "/items")
+	require.True(t, ok)
+	aged := *e
+	aged.StoredAt = e.StoredAt.Add(-90 * time.Second)
+	store.Set(srv.URL+"/items", &aged)
+
+	for i := 0; i < 5; i++ {
+		get()
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&revalidations) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&revalidations))
+}
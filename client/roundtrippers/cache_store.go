@@ -0,0 +1,96 @@
+package roundtrippers
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheStore stores responses for CacheRoundTripper,
+// stored responses should be treated as immutable
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+	Delete(key string)
+}
+
+// CachedResponse is entry of CacheStore, cache directives are parsed from Header
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+	// request header values of response Vary
+	Vary map[string]string
+}
+
+// NewLRUCacheStore creates in-memory CacheStore,
+// least recently used entry will be evicted when more than maxEntries
+func NewLRUCacheStore(maxEntries int) *LRUCacheStore {
+	return &LRUCacheStore{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		elements:   map[string]*list.Element{},
+	}
+}
+
+type LRUCacheStore struct {
+	maxEntries int
+
+	mu       sync.Mutex
+	ll       *list.List
+	elements map[string]*list.Element
+}
+
+type lruCacheItem struct {
+	key  string
+	resp *CachedResponse
+}
+
+func (s *LRUCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.elements[key]; ok {
+		s.ll.MoveToFront(el)
+		return el.Value.(*lruCacheItem).resp, true
+	}
+	return nil, false
+}
+
+func (s *LRUCacheStore) Set(key string, resp *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.elements[key]; ok {
+		el.Value.(*lruCacheItem).resp = resp
+		s.ll.MoveToFront(el)
+		return
+	}
+
+	s.elements[key] = s.ll.PushFront(&lruCacheItem{key: key, resp: resp})
+
+	for s.maxEntries > 0 && s.ll.Len() > s.maxEntries {
+		oldest := s.ll.Back()
+		s.ll.Remove(oldest)
+		delete(s.elements, oldest.Value.(*lruCacheItem).key)
+	}
+}
+
+func (s *LRUCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.elements[key]; ok {
+		s.ll.Remove(el)
+		delete(s.elements, key)
+	}
+}
+
+func (s *LRUCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ll.Len()
+}
@@ -0,0 +1,37 @@
+package roundtrippers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCacheStore(t *testing.T) {
+	s := NewLRUCacheStore(2)
+
+	s.Set("a", &CachedResponse{Body: []byte("a")})
+	s.Set("b", &CachedResponse{Body: []byte("b")})
+
+	_, ok := s.Get("a")
+	require.True(t, ok)
+
+	s.Set("c", &CachedResponse{Body: []byte("c")})
+	require.Equal(t, 2, s.Len())
+
+	_, ok = s.Get("b")
+	require.False(t, ok, "least recently used should be evicted")
+
+	resp, ok := s.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "a", string(resp.Body))
+
+	s.Set("a", &CachedResponse{Body: []byte("a2")})
+	resp, _ = s.Get("a")
+	require.Equal(t, "a2", string(resp.Body))
+	require.Equal(t, 2, s.Len())
+
+	s.Delete("a")
+	_, ok = s.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, s.Len())
+}
@@ -0,0 +1,271 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+type CircuitState int
+
+const (
+	CircuitStateClosed CircuitState = iota
+	CircuitStateOpen
+	CircuitStateHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitStateOpen:
+		return "open"
+	case CircuitStateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type CircuitBreakerOptions struct {
+	// consecutive failures to open circuit, default 5
+	FailureThreshold int
+	// duration of open state before half-open, default 10s
+	OpenTimeout time.Duration
+	// consecutive successes of half-open state to close circuit, default 1
+	SuccessThreshold int
+	// requests allowed concurrently in half-open state, default 1
+	HalfOpenMaxRequests int
+	// key of circuit, default CircuitKeyByOperation
+	KeyOf func(req *http.Request) string
+	// closed circuits idle longer than it will be evicted, default 5m
+	IdleTimeout time.Duration
+	// response treated as failure, default connection errors and 5xx
+	IsFailure func(resp *http.Response, err error) bool
+	// hook of state changes
+	OnStateChange func(key string, from CircuitState, to CircuitState)
+}
+
+func (o *CircuitBreakerOptions) SetDefaults() {
+	if o.FailureThreshold == 0 {
+		o.FailureThreshold = 5
+	}
+	if o.OpenTimeout == 0 {
+		o.OpenTimeout = 10 * time.Second
+	}
+	if o.SuccessThreshold == 0 {
+		o.SuccessThreshold = 1
+	}
+	if o.HalfOpenMaxRequests == 0 {
+		o.HalfOpenMaxRequests = 1
+	}
+	if o.KeyOf == nil {
+		o.KeyOf = CircuitKeyByOperation
+	}
+	if o.IdleTimeout == 0 {
+		o.IdleTimeout = 5 * time.Minute
+	}
+	if o.IsFailure == nil {
+		o.IsFailure = func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode >= http.StatusInternalServerError
+		}
+	}
+}
+
+// CircuitKeyByOperation keys circuit by host and operation id,
+// or host only when operation id not set, instead of path, which may contain ids of resources
+func CircuitKeyByOperation(req *http.Request) string {
+	if operationID := OperationIDFromContext(req.Context()); operationID != "" {
+		return req.URL.Host + "/" + operationID
+	}
+	return req.URL.Host
+}
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// NewCircuitBreakerRoundTripper fails fast with ErrCircuitOpen (wrapped as 503 status error)
+// when requests to same key failed continuously.
+// circuits are shared by all round trippers wrapped by the returned func,
+// as client.Client wraps HttpTransports for each request in short-conn mode.
+func NewCircuitBreakerRoundTripper(opts CircuitBreakerOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	breaker := &circuitBreaker{
+		opts:     opts,
+		circuits: map[string]*circuit{},
+	}
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &CircuitBreakerRoundTripper{
+			nextRoundTripper: roundTripper,
+			circuitBreaker:   breaker,
+		}
+	}
+}
+
+type CircuitBreakerRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	*circuitBreaker
+}
+
+type circuitBreaker struct {
+	opts CircuitBreakerOptions
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	changes  []stateChange
+	// at most one sweep of idle circuits in IdleTimeout
+	sweptAt time.Time
+}
+
+type stateChange struct {
+	key  string
+	from CircuitState
+	to   CircuitState
+}
+
+// unlock and emit state changes out of lock
+func (cb *circuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mu.Unlock()
+
+	if cb.opts.OnStateChange != nil {
+		for _, c := range changes {
+			cb.opts.OnStateChange(c.key, c.from, c.to)
+		}
+	}
+}
+
+// State returns current state of circuit of key
+func (cb *circuitBreaker) State(key string) CircuitState {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	if c, ok := cb.circuits[key]; ok {
+		cb.refresh(key, c, time.Now())
+		return c.state
+	}
+	return CircuitStateClosed
+}
+
+func (rt *CircuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := rt.opts.KeyOf(req)
+
+	if !rt.allow(key) {
+		return nil, statuserror.Wrap(errors.Wrap(ErrCircuitOpen, key), http.StatusServiceUnavailable, "CircuitOpen")
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+	rt.done(key, !rt.opts.IsFailure(resp, err))
+
+	return resp, err
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	successes int
+	inflight  int
+	openedAt  time.Time
+	usedAt    time.Time
+}
+
+func (cb *circuitBreaker) allow(key string) bool {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	now := time.Now()
+
+	cb.evictIdle(now)
+
+	c, ok := cb.circuits[key]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[key] = c
+	}
+
+	c.usedAt = now
+
+	cb.refresh(key, c, now)
+
+	switch c.state {
+	case CircuitStateOpen:
+		return false
+	case CircuitStateHalfOpen:
+		if c.inflight >= cb.opts.HalfOpenMaxRequests {
+			return false
+		}
+	}
+
+	c.inflight++
+	return true
+}
+
+func (cb *circuitBreaker) done(key string, success bool) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	c := cb.circuits[key]
+	c.inflight--
+
+	switch c.state {
+	case CircuitStateClosed:
+		if success {
+			c.failures = 0
+			return
+		}
+		c.failures++
+		if c.failures >= cb.opts.FailureThreshold {
+			cb.setState(key, c, CircuitStateOpen)
+		}
+	case CircuitStateHalfOpen:
+		if !success {
+			cb.setState(key, c, CircuitStateOpen)
+			return
+		}
+		c.successes++
+		if c.successes >= cb.opts.SuccessThreshold {
+			cb.setState(key, c, CircuitStateClosed)
+		}
+	}
+}
+
+// evictIdle drops closed circuits without requests in IdleTimeout,
+// swept once in IdleTimeout to keep cost of each request constant
+func (cb *circuitBreaker) evictIdle(now time.Time) {
+	if now.Sub(cb.sweptAt) < cb.opts.IdleTimeout {
+		return
+	}
+	cb.sweptAt = now
+
+	for key, c := range cb.circuits {
+		if c.state == CircuitStateClosed && c.inflight == 0 && now.Sub(c.usedAt) >= cb.opts.IdleTimeout {
+			delete(cb.circuits, key)
+		}
+	}
+}
+
+func (cb *circuitBreaker) refresh(key string, c *circuit, now time.Time) {
+	if c.state == CircuitStateOpen && now.Sub(c.openedAt) >= cb.opts.OpenTimeout {
+		cb.setState(key, c, CircuitStateHalfOpen)
+	}
+}
+
+func (cb *circuitBreaker) setState(key string, c *circuit, state CircuitState) {
+	from := c.state
+
+	c.state = state
+	c.failures = 0
+	c.successes = 0
+
+	if state == CircuitStateOpen {
+		c.openedAt = time.Now()
+	}
+
+	if from != state {
+		cb.changes = append(cb.changes, stateChange{key: key, from: from, to: state})
+	}
+}
@@ -0,0 +1,151 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerRoundTripper(t *testing.T) {
+	failing := int32(1)
+	calls := int32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	changes := make([]string, 0)
+
+	rt := NewCircuitBreakerRoundTripper(CircuitBreakerOptions{
+		FailureThreshold: 3,
+		OpenTimeout:      20 * time.Millisecond,
+		OnStateChange: func(key string, from CircuitState, to CircuitState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})(http.DefaultTransport).(*CircuitBreakerRoundTripper)
+
+	// paths with ids of same operation should share circuit
+	n := 0
+
+	do := func(operationID string) (*http.Response, error) {
+		n++
+		req, _ := http.NewRequestWithContext(ContextWithOperationID(context.Background(), operationID), http.MethodGet, srv.URL+"/items/"+strconv.Itoa(n), nil)
+		resp, err := rt.RoundTrip(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	key := srv.Listener.Addr().String() + "/GetItem"
+
+	for i := 0; i < 3; i++ {
+		_, err := do("GetItem")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, CircuitStateOpen, rt.State(key))
+
+	t.Run("fail fast when open", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		_, err := do("GetItem")
+		require.True(t, errors.Is(err, ErrCircuitOpen))
+		require.Equal(t, http.StatusServiceUnavailable, statuserror.FromErr(err).StatusCode())
+		require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("other keys not effected", func(t *testing.T) {
+		_, err := do("ListItems")
+		require.NoError(t, err)
+	})
+
+	t.Run("reopen when half-open request failed", func(t *testing.T) {
+		time.Sleep(20 * time.Millisecond)
+		require.Equal(t, CircuitStateHalfOpen, rt.State(key))
+
+		_, err := do("GetItem")
+		require.NoError(t, err)
+		require.Equal(t, CircuitStateOpen, rt.State(key))
+	})
+
+	t.Run("close when half-open request succeed", func(t *testing.T) {
+		atomic.StoreInt32(&failing, 0)
+		time.Sleep(20 * time.Millisecond)
+
+		_, err := do("GetItem")
+		require.NoError(t, err)
+		require.Equal(t, CircuitStateClosed, rt.State(key))
+	})
+
+	require.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, changes)
+}
+
+func TestCircuitBreakerRoundTripperKeyAndEviction(t *testing.T) {
+	t.Run("key by operation", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/users/123", nil)
+		require.Equal(t, "example.com", CircuitKeyByOperation(req))
+
+		req = req.WithContext(ContextWithOperationID(req.Context(), "GetUser"))
+		require.Equal(t, "example.com/GetUser", CircuitKeyByOperation(req))
+	})
+
+	t.Run("idle closed circuits evicted", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/failed" {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		rt := NewCircuitBreakerRoundTripper(CircuitBreakerOptions{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Hour,
+			IdleTimeout:      20 * time.Millisecond,
+			KeyOf: func(req *http.Request) string {
+				return req.URL.Path
+			},
+		})(http.DefaultTransport).(*CircuitBreakerRoundTripper)
+
+		do := func(path string) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+			if resp, err := rt.RoundTrip(req); err == nil {
+				_ = resp.Body.Close()
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			do("/items/" + strconv.Itoa(i))
+		}
+		do("/failed")
+		require.Len(t, rt.circuits, 11)
+
+		time.Sleep(30 * time.Millisecond)
+		do("/items/0")
+
+		require.Len(t, rt.circuits, 2)
+		require.Equal(t, CircuitStateOpen, rt.State("/failed"))
+	})
+}
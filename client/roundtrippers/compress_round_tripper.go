@@ -0,0 +1,132 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+type CompressOptions struct {
+	// request bodies smaller than MinSize will not be compressed, default 1024
+	MinSize int
+	// compression level of gzip, default gzip.DefaultCompression
+	Level int
+}
+
+func (opts *CompressOptions) SetDefaults() {
+	if opts.MinSize == 0 {
+		opts.MinSize = 1024
+	}
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+}
+
+// NewCompressRoundTripper gzips outgoing request bodies with Content-Encoding,
+// bodies already encoded will be sent as is.
+// Bodies of unknown length or not rewindable (without GetBody), like streams of multipart,
+// will be compressed while sending instead of buffered, and MinSize not checked.
+// Compressed responses are decompressed by http.Transport by default.
+func NewCompressRoundTripper(opts CompressOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &CompressRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type CompressRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             CompressOptions
+}
+
+func (rt *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get(httpx.HeaderContentEncoding) != "" {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	if req.ContentLength > 0 && req.ContentLength < int64(rt.opts.MinSize) {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	if req.ContentLength <= 0 || req.GetBody == nil {
+		return rt.roundTripStreaming(req)
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < rt.opts.MinSize {
+		return rt.nextRoundTripper.RoundTrip(withBody(req, data))
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	w, err := gzip.NewWriterLevel(buf, rt.opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	compressed := withBody(req, buf.Bytes())
+	compressed.Header.Set(httpx.HeaderContentEncoding, "gzip")
+
+	return rt.nextRoundTripper.RoundTrip(compressed)
+}
+
+// roundTripStreaming compresses body into pipe while transport reading
+func (rt *CompressRoundTripper) roundTripStreaming(req *http.Request) (*http.Response, error) {
+	pr, pw := io.Pipe()
+
+	w, err := gzip.NewWriterLevel(pw, rt.opts.Level)
+	if err != nil {
+		req.Body.Close()
+		return nil, err
+	}
+
+	body := req.Body
+
+	go func() {
+		_, err := io.Copy(w, body)
+		if err == nil {
+			err = w.Close()
+		}
+		body.Close()
+		// pipe closed by transport when request done, copying will be broken too
+		pw.CloseWithError(err)
+	}()
+
+	compressed := req.Clone(req.Context())
+	compressed.Body = pr
+	compressed.ContentLength = -1
+	compressed.GetBody = nil
+	compressed.Header.Del(httpx.HeaderContentLength)
+	compressed.Header.Set(httpx.HeaderContentEncoding, "gzip")
+
+	return rt.nextRoundTripper.RoundTrip(compressed)
+}
+
+func withBody(req *http.Request, data []byte) *http.Request {
+	r := req.Clone(req.Context())
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	r.ContentLength = int64(len(data))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	return r
+}
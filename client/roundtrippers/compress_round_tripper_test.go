@@ -0,0 +1,76 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressRoundTripper(t *testing.T) {
+	large := strings.Repeat(`{"name":"x"}`, 200)
+
+	var received *http.Request
+	var receivedBody []byte
+
+	rt := NewCompressRoundTripper(CompressOptions{})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		received = req
+		receivedBody, _ = ioutil.ReadAll(req.Body)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	t.Run("gzip large body", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", bytes.NewBufferString(large))
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.Equal(t, "gzip", received.Header.Get("Content-Encoding"))
+		require.Equal(t, int64(len(receivedBody)), received.ContentLength)
+
+		r, err := gzip.NewReader(bytes.NewBuffer(receivedBody))
+		require.NoError(t, err)
+		data, _ := ioutil.ReadAll(r)
+		require.Equal(t, large, string(data))
+
+		require.Equal(t, "", req.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("gzip stream of unknown length without buffering", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			for i := 0; i < 200; i++ {
+				_, _ = pw.Write([]byte(`{"name":"x"}`))
+			}
+			_ = pw.Close()
+		}()
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", pr)
+		require.Equal(t, int64(0), req.ContentLength)
+
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.Equal(t, "gzip", received.Header.Get("Content-Encoding"))
+		require.Equal(t, int64(-1), received.ContentLength)
+		require.Nil(t, received.GetBody)
+
+		r, err := gzip.NewReader(bytes.NewBuffer(receivedBody))
+		require.NoError(t, err)
+		data, _ := ioutil.ReadAll(r)
+		require.Equal(t, large, string(data))
+	})
+
+	t.Run("small body as is", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", bytes.NewBufferString(`{}`))
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.Equal(t, "", received.Header.Get("Content-Encoding"))
+		require.Equal(t, `{}`, string(receivedBody))
+	})
+}
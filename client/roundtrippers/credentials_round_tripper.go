@@ -0,0 +1,81 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type contextKeyCredential int
+
+// ContextWithCredential selects named credential of outbound request,
+// for NewCredentialsRoundTripper and key id of NewSignRoundTripper
+func ContextWithCredential(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKeyCredential(1), name)
+}
+
+func CredentialFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKeyCredential(1)).(string)
+	return v
+}
+
+type CredentialsOptions struct {
+	// credentials by name
+	Credentials map[string]AuthOptions
+	// name of credential when not selected by context, requests without credential are rejected when empty
+	Default string
+}
+
+func (opts *CredentialsOptions) SetDefaults() {
+	credentials := make(map[string]AuthOptions, len(opts.Credentials))
+	for name, auth := range opts.Credentials {
+		auth.SetDefaults()
+		credentials[name] = auth
+	}
+	opts.Credentials = credentials
+}
+
+// NewCredentialsRoundTripper sets credential selected by ContextWithCredential into requests,
+// so one client could call with credentials of different tenants, or with old and new keys while rotating.
+// header or query already set by caller will not be overwritten.
+func NewCredentialsRoundTripper(opts CredentialsOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &CredentialsRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type CredentialsRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             CredentialsOptions
+}
+
+func (rt *CredentialsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := CredentialFromContext(req.Context())
+	if name == "" {
+		name = rt.opts.Default
+	}
+
+	if name == "" {
+		return nil, errors.New("missing credential of request")
+	}
+
+	auth, ok := rt.opts.Credentials[name]
+	if !ok {
+		return nil, errors.Errorf("unknown credential %s", name)
+	}
+
+	// clone to keep the request of caller untouched
+	r := req.Clone(req.Context())
+
+	if err := setAuth(r, auth); err != nil {
+		return nil, err
+	}
+
+	return rt.nextRoundTripper.RoundTrip(r)
+}
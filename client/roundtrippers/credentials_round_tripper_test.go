@@ -0,0 +1,60 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsRoundTripper(t *testing.T) {
+	sent := (*http.Request)(nil)
+
+	rt := NewCredentialsRoundTripper(CredentialsOptions{
+		Credentials: map[string]AuthOptions{
+			"tenant-a": {Type: "Bearer", Value: "token-a"},
+			"tenant-b": {Type: AuthTypeHeader, Name: "X-Api-Key", Value: "key-b"},
+		},
+		Default: "tenant-a",
+	})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+	}))
+
+	t.Run("default", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token-a", sent.Header.Get("Authorization"))
+		require.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	t.Run("selected by context", func(t *testing.T) {
+		req, _ := http.NewRequestWithContext(ContextWithCredential(context.Background(), "tenant-b"), http.MethodGet, "http://api/items", nil)
+
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, "key-b", sent.Header.Get("X-Api-Key"))
+		require.Empty(t, sent.Header.Get("Authorization"))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		req, _ := http.NewRequestWithContext(ContextWithCredential(context.Background(), "tenant-c"), http.MethodGet, "http://api/items", nil)
+
+		_, err := rt.RoundTrip(req)
+		require.Error(t, err)
+	})
+
+	t.Run("missing without default", func(t *testing.T) {
+		rt := NewCredentialsRoundTripper(CredentialsOptions{
+			Credentials: map[string]AuthOptions{"tenant-a": {Type: AuthTypeBearer, Value: "token-a"}},
+		})(http.DefaultTransport)
+
+		req, _ := http.NewRequest(http.MethodGet, "http://api/items", nil)
+
+		_, err := rt.RoundTrip(req)
+		require.Error(t, err)
+	})
+}
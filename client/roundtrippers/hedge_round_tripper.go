@@ -0,0 +1,242 @@
+package roundtrippers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type HedgeOptions struct {
+	// delay before the hedged attempt, default 100ms.
+	// when Quantile set, used until enough latencies sampled.
+	Delay time.Duration
+	// quantile in (0, 1] of sampled latencies as delay, like 0.99, zero means fixed Delay
+	Quantile float64
+	// count of latest latencies to sample, default 200
+	Window int
+}
+
+func (o *HedgeOptions) SetDefaults() {
+	if o.Delay == 0 {
+		o.Delay = 100 * time.Millisecond
+	}
+	if o.Window == 0 {
+		o.Window = 200
+	}
+}
+
+// min count of sampled latencies to delay by Quantile
+const minHedgeSamples = 10
+
+// NewHedgeRoundTripper sends a second attempt of GET or HEAD request when no response after delay,
+// the first arrived response wins and the other attempt will be canceled.
+// Requests with body but no GetBody will not be hedged.
+// latencies and stats are shared by all round trippers wrapped by the returned func,
+// as client.Client wraps HttpTransports for each request in short-conn mode,
+// so Stats of any of them are totals.
+//
+//	hedge := roundtrippers.NewHedgeRoundTripper(roundtrippers.HedgeOptions{Quantile: 0.99})
+//	c.HttpTransports = append(c.HttpTransports, hedge)
+//
+//	stats := hedge(nil).(*roundtrippers.HedgeRoundTripper).Stats()
+func NewHedgeRoundTripper(opts HedgeOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	h := &hedger{
+		opts:      opts,
+		latencies: make([]time.Duration, 0, opts.Window),
+	}
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &HedgeRoundTripper{
+			nextRoundTripper: roundTripper,
+			hedger:           h,
+		}
+	}
+}
+
+type HedgeRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	*hedger
+}
+
+type hedger struct {
+	// first for 64-bit alignment of atomic operations
+	fired uint64
+	won   uint64
+
+	opts HedgeOptions
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+type HedgeStats struct {
+	// count of hedged attempts sent
+	Fired uint64
+	// count of hedged attempts won the first one
+	Won uint64
+}
+
+func (h *hedger) Stats() HedgeStats {
+	return HedgeStats{
+		Fired: atomic.LoadUint64(&h.fired),
+		Won:   atomic.LoadUint64(&h.won),
+	}
+}
+
+type hedgeResult struct {
+	resp    *http.Response
+	err     error
+	hedged  bool
+	latency time.Duration
+}
+
+func (rt *HedgeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.hedgeable(req) {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	results := make(chan *hedgeResult, 2)
+	cancels := map[bool]context.CancelFunc{}
+
+	send := func(hedged bool) error {
+		ctx, cancel := context.WithCancel(req.Context())
+
+		r := req.WithContext(ctx)
+
+		if hedged {
+			// cloned, headers could not be shared between attempts in flight
+			r = req.Clone(ctx)
+
+			if req.Body != nil && req.Body != http.NoBody {
+				body, err := req.GetBody()
+				if err != nil {
+					cancel()
+					return err
+				}
+				r.Body = body
+			}
+		}
+
+		cancels[hedged] = cancel
+
+		go func() {
+			startedAt := time.Now()
+			resp, err := rt.nextRoundTripper.RoundTrip(r)
+			results <- &hedgeResult{resp: resp, err: err, hedged: hedged, latency: time.Since(startedAt)}
+		}()
+
+		return nil
+	}
+
+	_ = send(false)
+
+	timer := time.NewTimer(rt.delay())
+	defer timer.Stop()
+
+	var lastErr error
+
+	for pending := 1; pending > 0; {
+		select {
+		case <-timer.C:
+			if err := send(true); err == nil {
+				atomic.AddUint64(&rt.fired, 1)
+				pending++
+			}
+		case result := <-results:
+			pending--
+
+			if result.err != nil {
+				cancels[result.hedged]()
+				lastErr = result.err
+				continue
+			}
+
+			rt.observe(result.latency)
+
+			if result.hedged {
+				atomic.AddUint64(&rt.won, 1)
+			}
+
+			if cancel, ok := cancels[!result.hedged]; ok {
+				cancel()
+			}
+
+			// release response of the loser arrived later
+			go func(pending int) {
+				for i := 0; i < pending; i++ {
+					if loser := <-results; loser.resp != nil {
+						discard(loser.resp)
+					}
+				}
+			}(pending)
+
+			// context of winner should be alive until body closed
+			result.resp.Body = &cancelOnCloseBody{ReadCloser: result.resp.Body, cancel: cancels[result.hedged]}
+			return result.resp, nil
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (rt *HedgeRoundTripper) hedgeable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead && req.Method != "" {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return true
+}
+
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < h.opts.Window {
+		h.latencies = append(h.latencies, latency)
+		return
+	}
+
+	h.latencies[h.next] = latency
+	h.next = (h.next + 1) % h.opts.Window
+}
+
+func (h *hedger) delay() time.Duration {
+	if h.opts.Quantile <= 0 {
+		return h.opts.Delay
+	}
+
+	h.mu.Lock()
+	if len(h.latencies) < minHedgeSamples {
+		h.mu.Unlock()
+		return h.opts.Delay
+	}
+	latencies := make([]time.Duration, len(h.latencies))
+	copy(latencies, h.latencies)
+	h.mu.Unlock()
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	return latencies[int(h.opts.Quantile*float64(len(latencies)-1))]
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
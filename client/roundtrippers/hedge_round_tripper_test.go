@@ -0,0 +1,88 @@
+package roundtrippers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgeRoundTripper(t *testing.T) {
+	t.Run("hedged attempt wins when first slow", func(t *testing.T) {
+		attempts := int32(0)
+		canceled := make(chan struct{}, 1)
+
+		rt := NewHedgeRoundTripper(HedgeOptions{Delay: 10 * time.Millisecond})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				<-req.Context().Done()
+				canceled <- struct{}{}
+				return nil, req.Context().Err()
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("hedged"))}, nil
+		})).(*HedgeRoundTripper)
+
+		req, _ := http.NewRequest(http.MethodGet, "http://svc/items", nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		data, _ := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.Equal(t, "hedged", string(data))
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("slow attempt should be canceled")
+		}
+
+		require.Equal(t, HedgeStats{Fired: 1, Won: 1}, rt.Stats())
+	})
+
+	t.Run("no hedge when first fast", func(t *testing.T) {
+		rt := NewHedgeRoundTripper(HedgeOptions{Delay: 50 * time.Millisecond})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		})).(*HedgeRoundTripper)
+
+		req, _ := http.NewRequest(http.MethodGet, "http://svc/items", nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		require.Equal(t, HedgeStats{}, rt.Stats())
+	})
+
+	t.Run("non idempotent not hedged", func(t *testing.T) {
+		attempts := int32(0)
+
+		rt := NewHedgeRoundTripper(HedgeOptions{Delay: time.Millisecond})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			time.Sleep(20 * time.Millisecond)
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		})).(*HedgeRoundTripper)
+
+		req, _ := http.NewRequest(http.MethodPost, "http://svc/items", strings.NewReader("{}"))
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+		require.Equal(t, HedgeStats{}, rt.Stats())
+	})
+
+	t.Run("delay by quantile", func(t *testing.T) {
+		rt := NewHedgeRoundTripper(HedgeOptions{Delay: time.Second, Quantile: 0.9, Window: 20})(nil).(*HedgeRoundTripper)
+
+		require.Equal(t, time.Second, rt.delay())
+
+		for i := 1; i <= 30; i++ {
+			rt.observe(time.Duration(i) * time.Millisecond)
+		}
+
+		// latest 20 latencies are 11ms..30ms
+		require.Equal(t, 28*time.Millisecond, rt.delay())
+	})
+}
@@ -0,0 +1,89 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/google/uuid"
+)
+
+type IdempotencyOptions struct {
+	// methods of requests to attach Idempotency-Key, default POST and PATCH
+	Methods []string
+	// generates key of request, default uuid
+	NewKey func() string
+}
+
+func (o *IdempotencyOptions) SetDefaults() {
+	if len(o.Methods) == 0 {
+		o.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if o.NewKey == nil {
+		o.NewKey = func() string {
+			return uuid.New().String()
+		}
+	}
+}
+
+// NewIdempotencyRoundTripper attaches Idempotency-Key to mutating requests without one,
+// key of ContextWithIdempotencyKey used first to keep same key across calls.
+// Should be wrapped outside of retry round tripper (after it in Client.HttpTransports),
+// then all attempts share one key and requests will be retried as idempotent,
+// and server could dedup them by handlers.IdempotencyHandler.
+func NewIdempotencyRoundTripper(opts IdempotencyOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &IdempotencyRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type IdempotencyRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             IdempotencyOptions
+}
+
+func (rt *IdempotencyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.match(req) || req.Header.Get(httpx.HeaderIdempotencyKey) != "" {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	key := IdempotencyKeyFromContext(req.Context())
+	if key == "" {
+		key = rt.opts.NewKey()
+	}
+
+	// round tripper should not modify request
+	req = req.Clone(req.Context())
+	req.Header.Set(httpx.HeaderIdempotencyKey, key)
+
+	return rt.nextRoundTripper.RoundTrip(req)
+}
+
+func (rt *IdempotencyRoundTripper) match(req *http.Request) bool {
+	for _, method := range rt.opts.Methods {
+		if strings.EqualFold(method, req.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKeyIdempotencyKey int
+
+// ContextWithIdempotencyKey sets Idempotency-Key of requests by NewIdempotencyRoundTripper
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKeyIdempotencyKey(1), key)
+}
+
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	if key, ok := ctx.Value(contextKeyIdempotencyKey(1)).(string); ok {
+		return key
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRoundTripper(t *testing.T) {
+	attempts := int32(0)
+	keys := make([]string, 0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(httpx.HeaderIdempotencyKey))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := int32(0)
+
+	rt := NewIdempotencyRoundTripper(IdempotencyOptions{
+		NewKey: func() string {
+			return "key-" + string(rune('0'+atomic.AddInt32(&n, 1)))
+		},
+	})(NewRetryRoundTripper(RetryOptions{
+		InitialBackoff: time.Millisecond,
+	})(http.DefaultTransport))
+
+	reset := func() {
+		atomic.StoreInt32(&attempts, 0)
+		keys = keys[0:0]
+	}
+
+	t.Run("retried with same key", func(t *testing.T) {
+		reset()
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("1"))
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{"key-1", "key-1"}, keys)
+		require.Empty(t, req.Header.Get(httpx.HeaderIdempotencyKey))
+	})
+
+	t.Run("key of context", func(t *testing.T) {
+		reset()
+
+		req, _ := http.NewRequestWithContext(ContextWithIdempotencyKey(context.Background(), "order-1"), http.MethodPatch, srv.URL, nil)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"order-1", "order-1"}, keys)
+	})
+
+	t.Run("key of request kept", func(t *testing.T) {
+		reset()
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, nil)
+		req.Header.Set(httpx.HeaderIdempotencyKey, "custom")
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"custom", "custom"}, keys)
+	})
+
+	t.Run("method not matched", func(t *testing.T) {
+		reset()
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"", ""}, keys)
+	})
+}
@@ -1,50 +1,195 @@
 package roundtrippers
 
 import (
-	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-courier/httptransport/httpx"
 	"github.com/go-courier/logr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func NewLogRoundTripper() func(roundTripper http.RoundTripper) http.RoundTripper {
+	return NewLogRoundTripperWithOptions(httpx.AccessLogOptions{})
+}
+
+// NewLogRoundTripperWithOptions logs structured access log of each outbound request by opts.Logger,
+// with credentials in headers redacted and bodies of sampled requests.
+// the log is emitted when response body closed, to count bytes received,
+// or when request failed.
+func NewLogRoundTripperWithOptions(opts httpx.AccessLogOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
 	return func(roundTripper http.RoundTripper) http.RoundTripper {
 		return &LogRoundTripper{
 			nextRoundTripper: roundTripper,
+			opts:             opts,
+			random:           rand.Float64,
 		}
 	}
 }
 
 type LogRoundTripper struct {
 	nextRoundTripper http.RoundTripper
+	opts             httpx.AccessLogOptions
+	random           func() float64
 }
 
 func (rt *LogRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	startedAt := time.Now()
 
 	ctx, logger := logr.Start(req.Context(), "Request")
-	defer logger.End()
 
-	resp, err := rt.nextRoundTripper.RoundTrip(req.WithContext(ctx))
+	sampled := rt.random()*100 < rt.opts.BodySampleRate
 
-	defer func() {
-		cost := time.Since(startedAt)
+	l := &httpx.AccessLog{
+		Kind:        "client",
+		OperationID: OperationIDFromContext(req.Context()),
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestID:   req.Header.Get(httpx.HeaderRequestID),
+		Header:      httpx.RedactHeader(req.Header, rt.opts.RedactHeaders...),
+	}
+
+	if req.ContentLength > 0 {
+		l.RequestBytes = req.ContentLength
+	}
 
-		logger := logger.WithValues(
-			"cost", fmt.Sprintf("%0.3fms", float64(cost/time.Millisecond)),
-			"method", req.Method,
-			"url", req.URL.String(),
-			"metadata", req.Header,
-		)
+	if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.IsValid() {
+		l.TraceID = spanContext.TraceID().String()
+		l.SpanID = spanContext.SpanID().String()
+	}
+
+	r := req.WithContext(ctx)
+
+	var requestBody *limitedBuffer
+
+	if sampled && req.Body != nil && req.Body != http.NoBody {
+		requestBody = &limitedBuffer{limit: rt.opts.BodyLimit}
+		r.Body = &teeBody{ReadCloser: req.Body, w: requestBody}
+	}
 
-		if err == nil {
-			logger.Info("success")
-		} else {
-			logger.Warn(errors.Wrap(err, "http request failed"))
+	done := func(l *httpx.AccessLog) {
+		if requestBody != nil {
+			l.RequestBody = httpx.Redact(requestBody.String(), rt.opts.RedactKeys...)
+			l.BodyTruncated = l.BodyTruncated || requestBody.truncated
 		}
-	}()
+		rt.opts.Logger.LogAccess(ctx, l)
+		logger.End()
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(r)
+
+	l.Latency = time.Since(startedAt)
+
+	if err != nil {
+		l.Err = errors.Wrap(err, "http request failed")
+		done(l)
+		return resp, err
+	}
+
+	l.StatusCode = resp.StatusCode
+
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		// body is the upgraded connection, which should not be wrapped
+		done(l)
+		return resp, nil
+	}
+
+	body := &accessLogBody{
+		ReadCloser: resp.Body,
+		done: func(received int64, data *limitedBuffer) {
+			l.ResponseBytes = received
+			if data != nil {
+				if resp.StatusCode >= http.StatusBadRequest && data.Len() > 0 {
+					l.Err = errors.New(httpx.Redact(data.String(), rt.opts.RedactKeys...))
+				}
+				if sampled {
+					l.ResponseBody = httpx.Redact(data.String(), rt.opts.RedactKeys...)
+					l.BodyTruncated = l.BodyTruncated || data.truncated
+				}
+			}
+			done(l)
+		},
+	}
+
+	if resp.Body == nil {
+		body.ReadCloser = http.NoBody
+	}
+
+	if sampled || resp.StatusCode >= http.StatusBadRequest {
+		// body of error responses kept for error of log
+		body.data = &limitedBuffer{limit: rt.opts.BodyLimit}
+	}
+
+	resp.Body = body
+
+	return resp, nil
+}
+
+// accessLogBody counts bytes received, and calls done once when closed,
+// Close could be called concurrently with Read
+type accessLogBody struct {
+	io.ReadCloser
+	mu       sync.Mutex
+	data     *limitedBuffer
+	received int64
+	once     sync.Once
+	done     func(received int64, data *limitedBuffer)
+}
+
+func (b *accessLogBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	b.received += int64(n)
+	if b.data != nil && n > 0 {
+		_, _ = b.data.Write(p[:n])
+	}
+	b.mu.Unlock()
+	return n, err
+}
 
-	return resp, err
+func (b *accessLogBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.done(b.received, b.data)
+	})
+	return err
+}
+
+type teeBody struct {
+	io.ReadCloser
+	w io.Writer
+}
+
+func (r *teeBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = r.w.Write(p[:n])
+	}
+	return n, err
+}
+
+type limitedBuffer struct {
+	strings.Builder
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rest := b.limit - b.Len(); rest < len(p) {
+		b.truncated = true
+		if rest > 0 {
+			b.Builder.Write(p[:rest])
+		}
+		return len(p), nil
+	}
+	return b.Builder.Write(p)
 }
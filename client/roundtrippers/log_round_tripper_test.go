@@ -1,10 +1,18 @@
 package roundtrippers
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLogRoundTripper(t *testing.T) {
@@ -15,3 +23,98 @@ func TestLogRoundTripper(t *testing.T) {
 
 	_, _ = NewLogRoundTripper()(http.DefaultTransport).RoundTrip(req)
 }
+
+func TestLogRoundTripperWithOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("fail") != "" {
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte(`{"key":"InternalError"}`))
+			return
+		}
+		data, _ := ioutil.ReadAll(req.Body)
+		_, _ = rw.Write(data)
+	}))
+	defer srv.Close()
+
+	logs := make([]*httpx.AccessLog, 0)
+
+	rt := NewLogRoundTripperWithOptions(httpx.AccessLogOptions{
+		BodySampleRate: 100,
+		Logger: httpx.AccessLoggerFunc(func(ctx context.Context, l *httpx.AccessLog) {
+			logs = append(logs, l)
+		}),
+	})(http.DefaultTransport)
+
+	do := func(url string, body string) *httpx.AccessLog {
+		req, _ := http.NewRequestWithContext(ContextWithOperationID(context.Background(), "CreateItem"), http.MethodPost, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer xxx")
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		// logged when body closed
+		require.Len(t, logs, 0)
+
+		_, _ = ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		_ = resp.Body.Close()
+
+		require.Len(t, logs, 1)
+		l := logs[0]
+		logs = logs[0:0]
+		return l
+	}
+
+	t.Run("success", func(t *testing.T) {
+		l := do(srv.URL, `{"token":"t","name":"x"}`)
+
+		require.Equal(t, "client", l.Kind)
+		require.Equal(t, "CreateItem", l.OperationID)
+		require.Equal(t, http.StatusOK, l.StatusCode)
+		require.Equal(t, int64(24), l.RequestBytes)
+		require.Equal(t, int64(24), l.ResponseBytes)
+		require.Equal(t, "***", l.Header.Get("Authorization"))
+		require.Equal(t, `{"token":"***","name":"x"}`, l.RequestBody)
+		require.Equal(t, `{"token":"***","name":"x"}`, l.ResponseBody)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		l := do(srv.URL+"?fail=1", `{}`)
+
+		require.Equal(t, http.StatusInternalServerError, l.StatusCode)
+		require.EqualError(t, l.Err, `{"key":"InternalError"}`)
+	})
+}
+
+func TestAccessLogBodyClosedWhileReading(t *testing.T) {
+	r, w := io.Pipe()
+
+	logged := make(chan int64, 1)
+
+	body := &accessLogBody{
+		ReadCloser: r,
+		data:       &limitedBuffer{limit: 10},
+		done: func(received int64, data *limitedBuffer) {
+			logged <- received
+		},
+	}
+
+	go func() {
+		for {
+			if _, err := w.Write([]byte("x")); err != nil {
+				return
+			}
+		}
+	}()
+
+	read := make(chan struct{})
+	go func() {
+		defer close(read)
+		_, _ = ioutil.ReadAll(body)
+	}()
+
+	time.Sleep(time.Millisecond)
+	_ = body.Close()
+	<-read
+
+	require.True(t, <-logged > 0)
+}
@@ -0,0 +1,74 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-courier/httptransport/internal/metricsx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type clientMetrics struct {
+	duration *prometheus.HistogramVec
+	inflight *prometheus.GaugeVec
+	requests *prometheus.CounterVec
+}
+
+func newClientMetrics(registerer prometheus.Registerer) *clientMetrics {
+	return &clientMetrics{
+		duration: metricsx.RegisterOrExisting(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_client_request_duration_seconds",
+			Help:    "Duration of outbound http requests.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "method", "host", "code"})).(*prometheus.HistogramVec),
+		inflight: metricsx.RegisterOrExisting(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_client_requests_in_flight",
+			Help: "Outbound http requests in flight.",
+		}, []string{"operation", "method", "host"})).(*prometheus.GaugeVec),
+		requests: metricsx.RegisterOrExisting(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_client_requests_total",
+			Help: "Outbound http requests by status code, code will be 0 when request failed.",
+		}, []string{"operation", "method", "host", "code"})).(*prometheus.CounterVec),
+	}
+}
+
+// NewMetricsRoundTripper records duration, in-flight requests and status codes of outbound requests
+// labeled by operation id, method and host.
+func NewMetricsRoundTripper(registerer prometheus.Registerer) func(roundTripper http.RoundTripper) http.RoundTripper {
+	metrics := newClientMetrics(registerer)
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &MetricsRoundTripper{
+			nextRoundTripper: roundTripper,
+			metrics:          metrics,
+		}
+	}
+}
+
+type MetricsRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	metrics          *clientMetrics
+}
+
+func (rt *MetricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := OperationIDFromContext(req.Context())
+
+	inflight := rt.metrics.inflight.WithLabelValues(operation, req.Method, req.URL.Host)
+	inflight.Inc()
+	defer inflight.Dec()
+
+	startedAt := time.Now()
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+	code := "0"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	rt.metrics.duration.WithLabelValues(operation, req.Method, req.URL.Host, code).Observe(time.Since(startedAt).Seconds())
+	rt.metrics.requests.WithLabelValues(operation, req.Method, req.URL.Host, code).Inc()
+
+	return resp, err
+}
@@ -0,0 +1,47 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	registry := prometheus.NewRegistry()
+
+	for i := 0; i < 2; i++ {
+		// shared collectors when created for each request
+		rt := NewMetricsRoundTripper(registry)(http.DefaultTransport)
+
+		req, _ := http.NewRequestWithContext(ContextWithOperationID(context.Background(), "GetItem"), http.MethodGet, srv.URL, nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP http_client_requests_total Outbound http requests by status code, code will be 0 when request failed.
+# TYPE http_client_requests_total counter
+http_client_requests_total{code="202",host="`+host+`",method="GET",operation="GetItem"} 2
+# HELP http_client_requests_in_flight Outbound http requests in flight.
+# TYPE http_client_requests_in_flight gauge
+http_client_requests_in_flight{host="`+host+`",method="GET",operation="GetItem"} 0
+`), "http_client_requests_total", "http_client_requests_in_flight"))
+
+	count, err := testutil.GatherAndCount(registry, "http_client_request_duration_seconds")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
@@ -0,0 +1,18 @@
+package roundtrippers
+
+import (
+	"context"
+)
+
+type contextKeyOperationID int
+
+// ContextWithOperationID sets operation id of outbound request,
+// Client will set it by name of request struct
+func ContextWithOperationID(ctx context.Context, operationID string) context.Context {
+	return context.WithValue(ctx, contextKeyOperationID(1), operationID)
+}
+
+func OperationIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKeyOperationID(1)).(string)
+	return v
+}
@@ -0,0 +1,83 @@
+package roundtrippers
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/go-courier/httptransport"
+
+type OtelOptions struct {
+	// default otel.GetTracerProvider()
+	TracerProvider trace.TracerProvider
+	// default W3C trace context
+	Propagator propagation.TextMapPropagator
+}
+
+func (o *OtelOptions) SetDefaults() {
+	if o.TracerProvider == nil {
+		o.TracerProvider = otel.GetTracerProvider()
+	}
+	if o.Propagator == nil {
+		o.Propagator = propagation.TraceContext{}
+	}
+}
+
+// NewOtelRoundTripper creates client span for each request and injects traceparent header.
+// Put it after LogRoundTripper in Client.HttpTransports to get trace id and span id in logs.
+func NewOtelRoundTripper(opts OtelOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &OtelRoundTripper{
+			nextRoundTripper: roundTripper,
+			tracer:           opts.TracerProvider.Tracer(instrumentationName),
+			propagator:       opts.Propagator,
+		}
+	}
+}
+
+type OtelRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	tracer           trace.Tracer
+	propagator       propagation.TextMapPropagator
+}
+
+func (rt *OtelRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	spanName := OperationIDFromContext(req.Context())
+	if spanName == "" {
+		spanName = "HTTP " + req.Method
+	}
+
+	ctx, span := rt.tracer.Start(
+		req.Context(),
+		spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(req.URL.String()),
+			semconv.HTTPHostKey.String(req.URL.Host),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	rt.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(resp.StatusCode))
+
+	return resp, nil
+}
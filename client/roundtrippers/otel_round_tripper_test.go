@@ -0,0 +1,49 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOtelRoundTripper(t *testing.T) {
+	traceparent := ""
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	rt := NewOtelRoundTripper(OtelOptions{TracerProvider: tp})(http.DefaultTransport)
+
+	req, _ := http.NewRequestWithContext(ContextWithOperationID(context.Background(), "GetItem"), http.MethodGet, srv.URL+"/items/1", nil)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	require.Equal(t, "GetItem", span.Name())
+	require.Equal(t, trace.SpanKindClient, span.SpanKind())
+
+	attrs := map[string]interface{}{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	require.Equal(t, http.MethodGet, attrs["http.method"])
+	require.Equal(t, int64(http.StatusNotFound), attrs["http.status_code"])
+
+	require.Equal(t, "00-"+span.SpanContext().TraceID().String()+"-"+span.SpanContext().SpanID().String()+"-01", traceparent)
+}
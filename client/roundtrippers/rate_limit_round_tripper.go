@@ -0,0 +1,218 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+type RateLimitOptions struct {
+	// requests per second of each key, no limit when 0
+	Rate float64
+	// max requests in burst, default Rate and at least 1
+	Burst int
+	// key of token bucket, default one global bucket,
+	// could be RateLimitKeyByHost or RateLimitKeyByOperation.
+	// compose round trippers for both global and per-key limits.
+	KeyOf func(req *http.Request) string
+	// fail fast with ErrRateLimited (wrapped as 429 status error) instead of waiting for token
+	FailFast bool
+	// pause requests of key until Retry-After of 429 responses
+	HonorRetryAfter bool
+}
+
+func (o *RateLimitOptions) SetDefaults() {
+	if o.Burst == 0 {
+		o.Burst = int(o.Rate)
+		if o.Burst < 1 {
+			o.Burst = 1
+		}
+	}
+	if o.KeyOf == nil {
+		o.KeyOf = func(req *http.Request) string {
+			return ""
+		}
+	}
+}
+
+func RateLimitKeyByHost(req *http.Request) string {
+	return req.URL.Host
+}
+
+func RateLimitKeyByOperation(req *http.Request) string {
+	return OperationIDFromContext(req.Context())
+}
+
+var ErrRateLimited = errors.New("rate limited")
+
+// NewRateLimitRoundTripper limits requests of each key by token bucket,
+// requests will wait for token until context done, or fail fast when FailFast.
+// buckets are shared by all round trippers wrapped by the returned func,
+// as client.Client wraps HttpTransports for each request in short-conn mode.
+func NewRateLimitRoundTripper(opts RateLimitOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	limiter := &rateLimiter{
+		opts:    opts,
+		buckets: map[string]*tokenBucket{},
+	}
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &RateLimitRoundTripper{
+			nextRoundTripper: roundTripper,
+			rateLimiter:      limiter,
+		}
+	}
+}
+
+type RateLimitRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	*rateLimiter
+}
+
+type rateLimiter struct {
+	opts RateLimitOptions
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens      float64
+	updatedAt   time.Time
+	pausedUntil time.Time
+}
+
+func (rt *RateLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.opts.Rate <= 0 {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	key := rt.opts.KeyOf(req)
+
+	wait, ok := rt.reserve(key, time.Now())
+	if !ok {
+		return nil, statuserror.Wrap(errors.Wrapf(ErrRateLimited, "%s, retry after %s", key, wait), http.StatusTooManyRequests, "RateLimited")
+	}
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			rt.cancel(key)
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+	if err == nil && rt.opts.HonorRetryAfter && resp.StatusCode == http.StatusTooManyRequests {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			rt.pause(key, time.Now().Add(d))
+		}
+	}
+
+	return resp, err
+}
+
+// reserve takes token and returns duration to wait for it,
+// returns false without taking token when FailFast and must wait.
+func (l *rateLimiter) reserve(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key, now)
+
+	wait := time.Duration(0)
+
+	if b.pausedUntil.After(now) {
+		wait = b.pausedUntil.Sub(now)
+	}
+
+	if b.tokens < 1 {
+		wait += time.Duration((1 - b.tokens) / l.opts.Rate * float64(time.Second))
+	}
+
+	if wait > 0 && l.opts.FailFast {
+		return wait, false
+	}
+
+	b.tokens--
+
+	return wait, true
+}
+
+// bucket of key with tokens refilled
+func (l *rateLimiter) bucket(key string, now time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.opts.Burst), updatedAt: now}
+		l.buckets[key] = b
+		return b
+	}
+
+	// no refill while paused
+	from := b.updatedAt
+	if b.pausedUntil.After(from) {
+		from = b.pausedUntil
+	}
+
+	if now.After(from) {
+		b.tokens += now.Sub(from).Seconds() * l.opts.Rate
+		if burst := float64(l.opts.Burst); b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+
+	if now.After(b.updatedAt) {
+		b.updatedAt = now
+	}
+
+	return b
+}
+
+// cancel gives back token reserved
+func (l *rateLimiter) cancel(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		b.tokens++
+	}
+}
+
+func (l *rateLimiter) pause(key string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key, time.Now())
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+}
+
+// retryAfter parses Retry-After of seconds or http date
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
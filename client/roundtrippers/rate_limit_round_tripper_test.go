@@ -0,0 +1,106 @@
+package roundtrippers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitRoundTripper(t *testing.T) {
+	statusCode := http.StatusOK
+
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: statusCode, Header: http.Header{}, Body: http.NoBody}
+		if statusCode == http.StatusTooManyRequests {
+			resp.Header.Set("Retry-After", "60")
+		}
+		return resp, nil
+	})
+
+	get := func(rt http.RoundTripper, ctx context.Context, url string) error {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		_, err := rt.RoundTrip(req)
+		return err
+	}
+
+	t.Run("fail fast", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 1, FailFast: true})(next)
+
+		require.NoError(t, get(rt, context.Background(), "http://a.com"))
+
+		err := get(rt, context.Background(), "http://b.com")
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode())
+		require.True(t, errors.Is(err, ErrRateLimited))
+	})
+
+	t.Run("per host", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 1, Burst: 2, FailFast: true, KeyOf: RateLimitKeyByHost})(next)
+
+		require.NoError(t, get(rt, context.Background(), "http://a.com"))
+		require.NoError(t, get(rt, context.Background(), "http://a.com"))
+		require.Error(t, get(rt, context.Background(), "http://a.com"))
+		require.NoError(t, get(rt, context.Background(), "http://b.com"))
+	})
+
+	t.Run("per operation", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 1, FailFast: true, KeyOf: RateLimitKeyByOperation})(next)
+
+		require.NoError(t, get(rt, ContextWithOperationID(context.Background(), "ListUser"), "http://a.com"))
+		require.Error(t, get(rt, ContextWithOperationID(context.Background(), "ListUser"), "http://a.com"))
+		require.NoError(t, get(rt, ContextWithOperationID(context.Background(), "GetUser"), "http://a.com"))
+	})
+
+	t.Run("blocking", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 50})(next)
+
+		startedAt := time.Now()
+		for i := 0; i < 60; i++ {
+			require.NoError(t, get(rt, context.Background(), "http://a.com"))
+		}
+		// 50 in burst, 10 more in 200ms
+		require.True(t, time.Since(startedAt) >= 150*time.Millisecond)
+	})
+
+	t.Run("blocking until context done", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 0.1})(next)
+
+		require.NoError(t, get(rt, context.Background(), "http://a.com"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.Equal(t, context.DeadlineExceeded, get(rt, ctx, "http://a.com"))
+	})
+
+	t.Run("pause by Retry-After", func(t *testing.T) {
+		rt := NewRateLimitRoundTripper(RateLimitOptions{Rate: 100, FailFast: true, HonorRetryAfter: true})(next)
+
+		statusCode = http.StatusTooManyRequests
+		require.NoError(t, get(rt, context.Background(), "http://a.com"))
+		statusCode = http.StatusOK
+
+		require.Error(t, get(rt, context.Background(), "http://a.com"))
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := retryAfter("120", now)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, d)
+
+	d, ok = retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, d)
+
+	_, ok = retryAfter("soon", now)
+	require.False(t, ok)
+}
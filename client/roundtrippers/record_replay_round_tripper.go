@@ -0,0 +1,268 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/pkg/errors"
+)
+
+type RecordMode int
+
+const (
+	// RecordModeReplay serves responses from cassette without sending, request not recorded will be failed
+	RecordModeReplay RecordMode = iota
+	// RecordModeRecord sends requests to upstream and records interactions into cassette, which will be overwritten
+	RecordModeRecord
+)
+
+// ErrInteractionNotRecorded returned in replay mode, when request not matched any interaction of cassette
+var ErrInteractionNotRecorded = errors.New("interaction not recorded")
+
+type RecordReplayOptions struct {
+	// file of cassette
+	Cassette string
+	Mode     RecordMode
+	// headers redacted in cassette, default httpx.DefaultRedactedHeaders
+	RedactHeaders []string
+	// keys of json or form bodies redacted in cassette, values of them not matched when replaying
+	RedactKeys []string
+}
+
+func (o *RecordReplayOptions) SetDefaults() {
+	if o.RedactHeaders == nil {
+		o.RedactHeaders = httpx.DefaultRedactedHeaders
+	}
+}
+
+// Cassette of recorded interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction of request and response recorded
+type Interaction struct {
+	// method, path with query and body hash of request
+	Key      string            `json:"key"`
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// NewRecordReplayRoundTripper records interactions with upstream into cassette in record mode,
+// and serves them back in replay mode for hermetic integration tests of client code.
+// requests keyed by method, path with query and hash of redacted body,
+// the same requests replayed in order of recorded.
+//
+//	mode := roundtrippers.RecordModeReplay
+//	if os.Getenv("RECORD") != "" {
+//		mode = roundtrippers.RecordModeRecord
+//	}
+//
+//	c.HttpTransports = append(c.HttpTransports, roundtrippers.NewRecordReplayRoundTripper(roundtrippers.RecordReplayOptions{
+//		Cassette: "testdata/cassettes/list_orders.json",
+//		Mode:     mode,
+//	}))
+func NewRecordReplayRoundTripper(opts RecordReplayOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	// shared by all round trippers wrapped by the returned func,
+	// as client.Client wraps HttpTransports for each request in short-conn mode
+	player := &cassettePlayer{opts: opts}
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &RecordReplayRoundTripper{
+			nextRoundTripper: roundTripper,
+			cassettePlayer:   player,
+		}
+	}
+}
+
+type RecordReplayRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	*cassettePlayer
+}
+
+type cassettePlayer struct {
+	opts RecordReplayOptions
+
+	mu       sync.Mutex
+	cassette *Cassette
+	// count of replayed by key
+	replayed map[string]int
+}
+
+func (rt *RecordReplayRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	redactedBody := httpx.Redact(string(body), rt.opts.RedactKeys...)
+	key := interactionKey(req, redactedBody)
+
+	if rt.opts.Mode == RecordModeRecord {
+		return rt.record(req, key, body, redactedBody)
+	}
+
+	return rt.replay(req, key)
+}
+
+func (rt *RecordReplayRoundTripper) record(req *http.Request, key string, body []byte, redactedBody string) (*http.Response, error) {
+	// round tripper should not modify request
+	upstreamReq := req.Clone(req.Context())
+	if body != nil {
+		upstreamReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := rt.nextRoundTripper.RoundTrip(upstreamReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Key: key,
+		Request: &RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: httpx.RedactHeader(req.Header, rt.opts.RedactHeaders...),
+			Body:   redactedBody,
+		},
+		Response: &RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     httpx.RedactHeader(resp.Header, rt.opts.RedactHeaders...),
+			Body:       respBody,
+		},
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.cassette == nil {
+		rt.cassette = &Cassette{}
+	}
+	rt.cassette.Interactions = append(rt.cassette.Interactions, interaction)
+
+	if err := rt.save(); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (rt *RecordReplayRoundTripper) replay(req *http.Request, key string) (*http.Response, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.cassette == nil {
+		cassette, err := loadCassette(rt.opts.Cassette)
+		if err != nil {
+			return nil, err
+		}
+		rt.cassette = cassette
+		rt.replayed = map[string]int{}
+	}
+
+	matched := make([]*Interaction, 0)
+	for _, interaction := range rt.cassette.Interactions {
+		if interaction.Key == key {
+			matched = append(matched, interaction)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, errors.Wrapf(ErrInteractionNotRecorded, "%s in %s", key, rt.opts.Cassette)
+	}
+
+	// the last one served for requests more than recorded
+	i := rt.replayed[key]
+	if i >= len(matched) {
+		i = len(matched) - 1
+	}
+	rt.replayed[key]++
+
+	recorded := matched[i].Response
+
+	header := http.Header{}
+	for k, values := range recorded.Header {
+		header[k] = append([]string{}, values...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (rt *RecordReplayRoundTripper) save() error {
+	data, err := json.MarshalIndent(rt.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(rt.opts.Cassette), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(rt.opts.Cassette, data, 0644)
+}
+
+func loadCassette(filename string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "load cassette")
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, errors.Wrapf(err, "invalid cassette %s", filename)
+	}
+	return cassette, nil
+}
+
+// readRequestBody reads and closes body of request, which should be closed by round tripper
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return ioutil.ReadAll(req.Body)
+}
+
+func interactionKey(req *http.Request, body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return req.Method + " " + req.URL.RequestURI() + " " + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,101 @@
+package roundtrippers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplayRoundTripper(t *testing.T) {
+	requests := int32(0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		rw.Header().Set("Set-Cookie", "session=1")
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"n":` + string(rune('0'+n)) + `,"body":` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassettes", "orders.json")
+
+	do := func(rt http.RoundTripper, body string) (*http.Response, string, error) {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/orders?size=1", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return resp, string(data), nil
+	}
+
+	t.Run("record", func(t *testing.T) {
+		rt := NewRecordReplayRoundTripper(RecordReplayOptions{
+			Cassette:   cassette,
+			Mode:       RecordModeRecord,
+			RedactKeys: []string{"token"},
+		})(http.DefaultTransport)
+
+		_, body, err := do(rt, `{"token":"t1","id":1}`)
+		require.NoError(t, err)
+		require.Equal(t, `{"n":1,"body":{"token":"t1","id":1}}`, body)
+
+		_, body, err = do(rt, `{"token":"t1","id":1}`)
+		require.NoError(t, err)
+		require.Equal(t, `{"n":2,"body":{"token":"t1","id":1}}`, body)
+
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+		data, err := ioutil.ReadFile(cassette)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "Bearer secret")
+		require.NotContains(t, string(data), "session=1")
+		require.NotContains(t, string(data), "t1")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		rt := NewRecordReplayRoundTripper(RecordReplayOptions{
+			Cassette:   cassette,
+			RedactKeys: []string{"token"},
+		})(http.DefaultTransport)
+
+		// redacted values not matched
+		resp, body, err := do(rt, `{"token":"t2","id":1}`)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		require.Equal(t, `{"n":1,"body":{"token":"t1","id":1}}`, body)
+
+		_, body, err = do(rt, `{"token":"t2","id":1}`)
+		require.NoError(t, err)
+		require.Equal(t, `{"n":2,"body":{"token":"t1","id":1}}`, body)
+
+		_, body, err = do(rt, `{"token":"t2","id":1}`)
+		require.NoError(t, err)
+		require.Equal(t, `{"n":2,"body":{"token":"t1","id":1}}`, body)
+
+		_, _, err = do(rt, `{"token":"t1","id":2}`)
+		require.True(t, errors.Is(err, ErrInteractionNotRecorded))
+
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		rt := NewRecordReplayRoundTripper(RecordReplayOptions{
+			Cassette: filepath.Join(t.TempDir(), "missing.json"),
+		})(http.DefaultTransport)
+
+		_, _, err := do(rt, `{}`)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,185 @@
+package roundtrippers
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RetryOptions struct {
+	// max attempts including the first request, default 3
+	MaxAttempts int
+	// backoff before first retry, doubled for each retry, default 100ms
+	InitialBackoff time.Duration
+	// max backoff, default 2s
+	MaxBackoff time.Duration
+	// status codes to retry besides 502, 503 and 504
+	RetryStatusCodes []int
+	// retry non-idempotent requests like POST and PATCH too,
+	// requests with header Idempotency-Key will be treated as idempotent always
+	RetryNonIdempotent bool
+}
+
+func (o *RetryOptions) SetDefaults() {
+	if o.MaxAttempts == 0 {
+		o.MaxAttempts = 3
+	}
+	if o.InitialBackoff == 0 {
+		o.InitialBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff == 0 {
+		o.MaxBackoff = 2 * time.Second
+	}
+}
+
+// NewRetryRoundTripper retries request on connection errors or retryable status codes
+// with exponential backoff and jitter.
+// Request body will be rewound by GetBody for each retry,
+// requests with body but no GetBody, like streaming uploads, will be sent once without retry.
+func NewRetryRoundTripper(opts RetryOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &RetryRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type RetryRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             RetryOptions
+}
+
+func (rt *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.retryable(req) || !rewindable(req) {
+		return rt.nextRoundTripper.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+		if attempt >= rt.opts.MaxAttempts || !rt.shouldRetry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		backoff := rt.backoff(attempt, resp)
+
+		if resp != nil {
+			discard(resp)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		// cloned, request of caller should not be modified
+		req = req.Clone(ctx)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+type contextKeyRetryable int
+
+// ContextWithRetryable marks whether request could be retried, overwrites idempotency by method
+func ContextWithRetryable(ctx context.Context, retryable bool) context.Context {
+	return context.WithValue(ctx, contextKeyRetryable(1), retryable)
+}
+
+func RetryableFromContext(ctx context.Context) (bool, bool) {
+	retryable, ok := ctx.Value(contextKeyRetryable(1)).(bool)
+	return retryable, ok
+}
+
+func (rt *RetryRoundTripper) retryable(req *http.Request) bool {
+	if retryable, ok := RetryableFromContext(req.Context()); ok {
+		return retryable
+	}
+	if rt.opts.RetryNonIdempotent {
+		return true
+	}
+	return isIdempotent(req)
+}
+
+func (rt *RetryRoundTripper) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	for _, code := range rt.opts.RetryStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rt *RetryRoundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if d := time.Duration(seconds) * time.Second; d < rt.opts.MaxBackoff {
+				return d
+			}
+			return rt.opts.MaxBackoff
+		}
+	}
+
+	backoff := rt.opts.InitialBackoff << uint(attempt-1)
+	if backoff <= 0 || backoff > rt.opts.MaxBackoff {
+		backoff = rt.opts.MaxBackoff
+	}
+
+	// jitter in [backoff/2, backoff)
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+	return false
+}
+
+// rewindable returns false when body could not be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func discard(resp *http.Response) {
+	if resp.Body != nil {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+	}
+}
@@ -0,0 +1,161 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	attempts := int32(0)
+	failures := int32(0)
+	bodies := make([]string, 0)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+
+		if atomic.AddInt32(&attempts, 1) <= atomic.LoadInt32(&failures) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rt := NewRetryRoundTripper(RetryOptions{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	})(http.DefaultTransport)
+
+	reset := func(n int32) {
+		atomic.StoreInt32(&attempts, 0)
+		atomic.StoreInt32(&failures, n)
+		bodies = bodies[0:0]
+	}
+
+	t.Run("retry until success", func(t *testing.T) {
+		reset(2)
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("stop when max attempts", func(t *testing.T) {
+		reset(5)
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("non-idempotent request should not be retried", func(t *testing.T) {
+		reset(1)
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString("data"))
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("request with idempotency key should be retried with body rewound", func(t *testing.T) {
+		reset(1)
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("data"))
+		req.Header.Set("Idempotency-Key", "1")
+		body := req.Body
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{"data", "data"}, bodies)
+		// request of caller not modified
+		require.Equal(t, body, req.Body)
+	})
+
+	t.Run("request with body but no GetBody should not be retried", func(t *testing.T) {
+		reset(1)
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, ioutil.NopCloser(strings.NewReader("data")))
+		req.Header.Set("Idempotency-Key", "1")
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Nil(t, req.GetBody)
+		require.Equal(t, []string{"data"}, bodies)
+	})
+
+	t.Run("retryable in context overwrites idempotency", func(t *testing.T) {
+		reset(1)
+
+		req, _ := http.NewRequestWithContext(ContextWithRetryable(context.Background(), true), http.MethodPost, srv.URL, bytes.NewBufferString("data"))
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+
+		reset(1)
+
+		req, _ = http.NewRequestWithContext(ContextWithRetryable(context.Background(), false), http.MethodGet, srv.URL, nil)
+		resp, err = rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("retry on connection errors", func(t *testing.T) {
+		rt := NewRetryRoundTripper(RetryOptions{
+			InitialBackoff: time.Millisecond,
+		})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return nil, context.DeadlineExceeded
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}))
+
+		reset(0)
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("stop when context canceled", func(t *testing.T) {
+		reset(5)
+
+		rt := NewRetryRoundTripper(RetryOptions{
+			InitialBackoff: time.Second,
+		})(http.DefaultTransport)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		_, err := rt.RoundTrip(req)
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
@@ -0,0 +1,97 @@
+package roundtrippers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+type SignOptions struct {
+	KeyID string
+	// key id of request, for key rotation or keys of tenants,
+	// default credential selected by ContextWithCredential, or KeyID
+	KeyIDOf func(ctx context.Context) string
+	Keys    httpx.SignatureKeyProvider
+	// algorithm of signature, default hmac-sha256
+	Algorithm string
+	// headers to sign, default host and content-type
+	SignedHeaders []string
+}
+
+func (opts *SignOptions) SetDefaults() {
+	if opts.Algorithm == "" {
+		opts.Algorithm = "hmac-sha256"
+	}
+	if len(opts.SignedHeaders) == 0 {
+		opts.SignedHeaders = []string{"host", httpx.HeaderContentType}
+	}
+	opts.SignedHeaders = httpx.NormalizeSignedHeaders(opts.SignedHeaders)
+	if opts.KeyIDOf == nil {
+		keyID := opts.KeyID
+		opts.KeyIDOf = func(ctx context.Context) string {
+			if name := CredentialFromContext(ctx); name != "" {
+				return name
+			}
+			return keyID
+		}
+	}
+}
+
+// NewSignRoundTripper signs method, path, query, signed headers and body hash of requests into X-Signature,
+// which could be verified by handlers.VerifySignatureHandler.
+func NewSignRoundTripper(opts SignOptions) func(roundTripper http.RoundTripper) http.RoundTripper {
+	opts.SetDefaults()
+
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &SignRoundTripper{
+			nextRoundTripper: roundTripper,
+			opts:             opts,
+		}
+	}
+}
+
+type SignRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	opts             SignOptions
+}
+
+func (rt *SignRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	keyID := rt.opts.KeyIDOf(req.Context())
+
+	key, err := rt.opts.Keys.SignatureKey(req.Context(), keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// clone before signing to keep the request of caller untouched
+	r := withBody(req, data)
+
+	sig := &httpx.Signature{
+		Algorithm: rt.opts.Algorithm,
+		KeyID:     keyID,
+		Timestamp: time.Now().Unix(),
+		Headers:   rt.opts.SignedHeaders,
+	}
+
+	sig.Signature, err = sig.Sum(key, r, httpx.BodyHash(data))
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set(httpx.HeaderSignature, sig.String())
+
+	return rt.nextRoundTripper.RoundTrip(r)
+}
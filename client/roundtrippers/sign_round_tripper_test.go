@@ -0,0 +1,96 @@
+package roundtrippers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/httptransport/handlers"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignRoundTripper(t *testing.T) {
+	keys := httpx.SignatureKeys{"svc-a": []byte("secret")}
+
+	srv := httptest.NewServer(handlers.VerifySignatureHandler(handlers.VerifySignatureOptions{
+		Keys:            keys,
+		RequiredHeaders: []string{"Host"},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(httpx.SignatureKeyIDFromContext(req.Context()) + ":" + string(data)))
+	})))
+	defer srv.Close()
+
+	t.Run("verified", func(t *testing.T) {
+		c := &http.Client{Transport: NewSignRoundTripper(SignOptions{KeyID: "svc-a", Keys: keys})(http.DefaultTransport)}
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/users?b=2&a=1", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		data, _ := ioutil.ReadAll(resp.Body)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, `svc-a:{"name":"x"}`, string(data))
+		require.Equal(t, "", req.Header.Get(httpx.HeaderSignature))
+	})
+
+	t.Run("signed with wrong key", func(t *testing.T) {
+		c := &http.Client{Transport: NewSignRoundTripper(SignOptions{
+			KeyID:     "svc-a",
+			Keys:      httpx.SignatureKeys{"svc-a": []byte("wrong")},
+			Algorithm: "hmac-sha512",
+		})(http.DefaultTransport)}
+
+		resp, err := c.Get(srv.URL + "/users")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("key of credential in context", func(t *testing.T) {
+		rotated := httpx.SignatureKeys{"svc-a": []byte("secret"), "svc-a-2": []byte("secret-2")}
+
+		srv := httptest.NewServer(handlers.VerifySignatureHandler(handlers.VerifySignatureOptions{
+			Keys: rotated,
+		})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = rw.Write([]byte(httpx.SignatureKeyIDFromContext(req.Context())))
+		})))
+		defer srv.Close()
+
+		c := &http.Client{Transport: NewSignRoundTripper(SignOptions{KeyID: "svc-a", Keys: rotated})(http.DefaultTransport)}
+
+		for _, keyID := range []string{"", "svc-a-2"} {
+			ctx := context.Background()
+			if keyID != "" {
+				ctx = ContextWithCredential(ctx, keyID)
+			}
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/users", nil)
+			resp, err := c.Do(req)
+			require.NoError(t, err)
+
+			data, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			if keyID == "" {
+				keyID = "svc-a"
+			}
+			require.Equal(t, keyID, string(data))
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		c := &http.Client{Transport: NewSignRoundTripper(SignOptions{KeyID: "svc-b", Keys: keys})(http.DefaultTransport)}
+
+		_, err := c.Get(srv.URL + "/users")
+		require.Error(t, err)
+	})
+}
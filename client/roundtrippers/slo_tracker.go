@@ -0,0 +1,269 @@
+package roundtrippers
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type SLOOptions struct {
+	// target of request, default operation id, or host when missing
+	TargetOf func(req *http.Request) string
+	// response treated as failure, default connection errors and 5xx
+	IsFailure func(resp *http.Response, err error) bool
+	// interval of exporting by Run, default 1m
+	Interval time.Duration
+	// max latencies sampled of each target in each interval, default 1024
+	SampleSize int
+	// callback of summaries, which aggregated since last exported
+	Export func(summaries []SLOSummary)
+}
+
+func (o *SLOOptions) SetDefaults() {
+	if o.TargetOf == nil {
+		o.TargetOf = func(req *http.Request) string {
+			if operationID := OperationIDFromContext(req.Context()); operationID != "" {
+				return operationID
+			}
+			return req.URL.Host
+		}
+	}
+	if o.IsFailure == nil {
+		o.IsFailure = func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode >= http.StatusInternalServerError
+		}
+	}
+	if o.Interval == 0 {
+		o.Interval = time.Minute
+	}
+	if o.SampleSize == 0 {
+		o.SampleSize = 1024
+	}
+}
+
+type SLOSummary struct {
+	Target   string
+	Requests uint64
+	Failures uint64
+	// ratio of succeeded requests, 1 when no requests
+	SuccessRate float64
+	// latencies until response header received
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// bytes of request bodies, by Content-Length
+	RequestBytes int64
+	// bytes of response bodies read
+	ResponseBytes int64
+}
+
+// NewSLOTracker aggregates success rate, latency percentiles and body sizes of outbound requests by target in process,
+// requests should be sent by round tripper of NewSLORoundTripper.
+// For batch jobs without prometheus.
+func NewSLOTracker(opts SLOOptions) *SLOTracker {
+	opts.SetDefaults()
+
+	return &SLOTracker{
+		opts:    opts,
+		targets: map[string]*sloTarget{},
+	}
+}
+
+type SLOTracker struct {
+	opts SLOOptions
+
+	mu      sync.Mutex
+	targets map[string]*sloTarget
+}
+
+type sloTarget struct {
+	requests      uint64
+	failures      uint64
+	observed      uint64
+	latencies     []time.Duration
+	max           time.Duration
+	requestBytes  int64
+	responseBytes int64
+}
+
+func (t *SLOTracker) target(name string) *sloTarget {
+	target, ok := t.targets[name]
+	if !ok {
+		target = &sloTarget{latencies: make([]time.Duration, 0)}
+		t.targets[name] = target
+	}
+	return target
+}
+
+func (t *SLOTracker) observe(name string, latency time.Duration, failed bool, requestBytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	target := t.target(name)
+
+	target.requests++
+	if failed {
+		target.failures++
+	}
+	if requestBytes > 0 {
+		target.requestBytes += requestBytes
+	}
+	if latency > target.max {
+		target.max = latency
+	}
+
+	// reservoir sampling, percentiles keep unbiased under heavy traffic
+	target.observed++
+	if len(target.latencies) < t.opts.SampleSize {
+		target.latencies = append(target.latencies, latency)
+	} else if i := rand.Int63n(int64(target.observed)); i < int64(t.opts.SampleSize) {
+		target.latencies[i] = latency
+	}
+}
+
+func (t *SLOTracker) addResponseBytes(name string, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.target(name).responseBytes += n
+}
+
+// Snapshot returns summaries sorted by target since last exported
+func (t *SLOTracker) Snapshot() []SLOSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.summaries()
+}
+
+func (t *SLOTracker) summaries() []SLOSummary {
+	summaries := make([]SLOSummary, 0, len(t.targets))
+
+	for name, target := range t.targets {
+		s := SLOSummary{
+			Target:        name,
+			Requests:      target.requests,
+			Failures:      target.failures,
+			SuccessRate:   1,
+			Max:           target.max,
+			RequestBytes:  target.requestBytes,
+			ResponseBytes: target.responseBytes,
+		}
+
+		if target.requests > 0 {
+			s.SuccessRate = float64(target.requests-target.failures) / float64(target.requests)
+		}
+
+		if n := len(target.latencies); n > 0 {
+			latencies := make([]time.Duration, n)
+			copy(latencies, target.latencies)
+			sort.Slice(latencies, func(i, j int) bool {
+				return latencies[i] < latencies[j]
+			})
+
+			s.P50 = latencies[int(0.5*float64(n-1))]
+			s.P90 = latencies[int(0.9*float64(n-1))]
+			s.P99 = latencies[int(0.99*float64(n-1))]
+		}
+
+		summaries = append(summaries, s)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Target < summaries[j].Target
+	})
+
+	return summaries
+}
+
+// Flush exports summaries since last exported, and resets them
+func (t *SLOTracker) Flush() {
+	t.mu.Lock()
+	summaries := t.summaries()
+	t.targets = map[string]*sloTarget{}
+	t.mu.Unlock()
+
+	if t.opts.Export != nil && len(summaries) > 0 {
+		t.opts.Export(summaries)
+	}
+}
+
+// Run flushes by Interval until ctx done, and flushes the rest before return
+func (t *SLOTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Flush()
+			return
+		case <-ticker.C:
+			t.Flush()
+		}
+	}
+}
+
+// NewSLORoundTripper tracks outbound requests into tracker
+func NewSLORoundTripper(tracker *SLOTracker) func(roundTripper http.RoundTripper) http.RoundTripper {
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &SLORoundTripper{
+			nextRoundTripper: roundTripper,
+			tracker:          tracker,
+		}
+	}
+}
+
+type SLORoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	tracker          *SLOTracker
+}
+
+func (rt *SLORoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := rt.tracker.opts.TargetOf(req)
+
+	startedAt := time.Now()
+
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+
+	rt.tracker.observe(target, time.Since(startedAt), rt.tracker.opts.IsFailure(resp, err), req.ContentLength)
+
+	if err == nil && resp.Body != nil {
+		resp.Body = &sloCountingBody{ReadCloser: resp.Body, done: func(n int64) {
+			rt.tracker.addResponseBytes(target, n)
+		}}
+	}
+
+	return resp, err
+}
+
+type sloCountingBody struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(n int64)
+}
+
+func (b *sloCountingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(&b.n, int64(n))
+	if err == io.EOF {
+		b.once.Do(func() {
+			b.done(atomic.LoadInt64(&b.n))
+		})
+	}
+	return n, err
+}
+
+func (b *sloCountingBody) Close() error {
+	b.once.Do(func() {
+		b.done(atomic.LoadInt64(&b.n))
+	})
+	return b.ReadCloser.Close()
+}
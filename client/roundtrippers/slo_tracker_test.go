@@ -0,0 +1,83 @@
+package roundtrippers
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLOTracker(t *testing.T) {
+	exported := make(chan []SLOSummary, 1)
+
+	tracker := NewSLOTracker(SLOOptions{
+		Interval: time.Hour,
+		Export: func(summaries []SLOSummary) {
+			exported <- summaries
+		},
+	})
+
+	rt := NewSLORoundTripper(tracker)(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/fail":
+			return &http.Response{StatusCode: http.StatusBadGateway, Body: ioutil.NopCloser(strings.NewReader("bad"))}, nil
+		case "/err":
+			return nil, errors.New("connection refused")
+		}
+		time.Sleep(time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("hello"))}, nil
+	}))
+
+	for i := 0; i < 7; i++ {
+		req, _ := http.NewRequest(http.MethodPost, "http://svc/items", strings.NewReader("{}"))
+		resp, err := rt.RoundTrip(req.WithContext(ContextWithOperationID(req.Context(), "CreateItem")))
+		require.NoError(t, err)
+		_, _ = ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://other/fail", nil)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	req, _ = http.NewRequest(http.MethodGet, "http://other/err", nil)
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+
+	summaries := tracker.Snapshot()
+	require.Len(t, summaries, 2)
+
+	created := summaries[0]
+	require.Equal(t, "CreateItem", created.Target)
+	require.Equal(t, uint64(7), created.Requests)
+	require.Equal(t, float64(1), created.SuccessRate)
+	require.Equal(t, int64(14), created.RequestBytes)
+	require.Equal(t, int64(35), created.ResponseBytes)
+	require.True(t, created.P50 >= time.Millisecond)
+	require.True(t, created.P99 <= created.Max)
+
+	other := summaries[1]
+	require.Equal(t, "other", other.Target)
+	require.Equal(t, uint64(2), other.Failures)
+	require.Equal(t, float64(0), other.SuccessRate)
+
+	t.Run("export by run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			tracker.Run(ctx)
+			close(done)
+		}()
+		cancel()
+		<-done
+
+		require.Len(t, <-exported, 2)
+		require.Empty(t, tracker.Snapshot())
+	})
+}
@@ -0,0 +1,58 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/logr"
+	"github.com/pkg/errors"
+)
+
+// NewSpecHashRoundTripper warns once per host when X-Spec-Hash of response
+// not matched spec hash which client generated by.
+func NewSpecHashRoundTripper(specHash string) func(roundTripper http.RoundTripper) http.RoundTripper {
+	return func(roundTripper http.RoundTripper) http.RoundTripper {
+		return &SpecHashRoundTripper{
+			nextRoundTripper: roundTripper,
+			specHash:         specHash,
+		}
+	}
+}
+
+type SpecHashRoundTripper struct {
+	nextRoundTripper http.RoundTripper
+	specHash         string
+	warned           sync.Map
+}
+
+func (rt *SpecHashRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.nextRoundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	served := resp.Header.Get(httpx.HeaderSpecHash)
+
+	if served != "" && rt.specHash != "" && served != rt.specHash {
+		if _, warned := rt.warned.LoadOrStore(req.URL.Host+"#"+served, true); !warned {
+			logr.FromContext(req.Context()).Warn(errors.Errorf("spec drift of %s, client generated by %s but server serves %s", req.URL.Host, rt.specHash, served))
+		}
+	}
+
+	return resp, nil
+}
+
+// SpecDrifted checks whether spec hash of host drifted ever
+func (rt *SpecHashRoundTripper) SpecDrifted(host string) bool {
+	drifted := false
+	rt.warned.Range(func(key, value interface{}) bool {
+		if strings.HasPrefix(key.(string), host+"#") {
+			drifted = true
+			return false
+		}
+		return true
+	})
+	return drifted
+}
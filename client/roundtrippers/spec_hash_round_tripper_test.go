@@ -0,0 +1,34 @@
+package roundtrippers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecHashRoundTripper(t *testing.T) {
+	served := "aaa"
+
+	rt := NewSpecHashRoundTripper("aaa")(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		resp.Header.Set(httpx.HeaderSpecHash, served)
+		return resp, nil
+	})).(*SpecHashRoundTripper)
+
+	do := func() {
+		req, _ := http.NewRequest(http.MethodGet, "http://svc/items", nil)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	do()
+	require.False(t, rt.SpecDrifted("svc"))
+
+	served = "bbb"
+	do()
+	do()
+	require.True(t, rt.SpecDrifted("svc"))
+	require.False(t, rt.SpecDrifted("sv"))
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// TLS options of client for https, with client certificate for mutual TLS
+type TLS struct {
+	// base config, options below overwrite it when set
+	Config *tls.Config
+	// skip verifying certificate chain and host name of server, for testing only
+	InsecureSkipVerify bool
+	// path of PEM encoded CA bundle to verify server, default system roots
+	RootCAs string
+	// paths of PEM encoded client certificate and private key
+	CertFile string
+	KeyFile  string
+	// server name to verify certificate of server, default host of request
+	ServerName string
+}
+
+// TLSConfig loads certificates from files and returns a new tls.Config
+func (opts *TLS) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if opts.Config != nil {
+		cfg = opts.Config.Clone()
+	}
+
+	if opts.InsecureSkipVerify {
+		cfg.InsecureSkipVerify = true
+	}
+
+	if opts.ServerName != "" {
+		cfg.ServerName = opts.ServerName
+	}
+
+	if opts.RootCAs != "" {
+		data, err := ioutil.ReadFile(opts.RootCAs)
+		if err != nil {
+			return nil, errors.Wrap(err, "read root CAs failed")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no valid certificate in %s", opts.RootCAs)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, errors.New("both CertFile and KeyFile are required for client certificate")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate failed")
+		}
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+
+	return cfg, nil
+}
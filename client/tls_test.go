@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	clientCert, certFile, keyFile := newTestCertificate(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}`))
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	rootCAs := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(rootCAs, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	newClient := func(opts *TLS, keepAlive bool) *Client {
+		c := &Client{
+			Protocol:  "https",
+			Host:      "127.0.0.1",
+			Port:      uint16(port),
+			TLS:       opts,
+			KeepAlive: keepAlive,
+		}
+		c.SetDefaults()
+		return c
+	}
+
+	t.Run("mutual tls", func(t *testing.T) {
+		for _, keepAlive := range []bool{false, true} {
+			c := newClient(&TLS{RootCAs: rootCAs, CertFile: certFile, KeyFile: keyFile}, keepAlive)
+
+			data := &Data{}
+			_, err := c.Do(context.Background(), &GetData{}).Into(data)
+			require.NoError(t, err)
+			require.Equal(t, "client", data.ID)
+
+			c.CloseIdleConnections()
+		}
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		_, err := newClient(&TLS{RootCAs: rootCAs}, false).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown authority", func(t *testing.T) {
+		_, err := newClient(&TLS{CertFile: certFile, KeyFile: keyFile}, false).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		_, err := newClient(&TLS{InsecureSkipVerify: true, CertFile: certFile, KeyFile: keyFile}, false).Do(context.Background(), &GetData{}).Into(nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("server name not matched", func(t *testing.T) {
+		_, err := newClient(&TLS{RootCAs: rootCAs, CertFile: certFile, KeyFile: keyFile, ServerName: "other.com"}, false).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := newClient(&TLS{CertFile: certFile}, false).Do(context.Background(), &GetData{}).Into(nil)
+		require.Error(t, err)
+
+		_, err = (&TLS{RootCAs: keyFile}).TLSConfig()
+		require.Error(t, err)
+	})
+}
+
+func newTestCertificate(t *testing.T, dir string, commonName string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, commonName+".pem")
+	keyFile := filepath.Join(dir, commonName+"-key.pem")
+
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return cert, certFile, keyFile
+}
@@ -0,0 +1,318 @@
+package transcoding
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/client"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// HttpRuleOf returns http rule defined by option google.api.http of method
+func HttpRuleOf(md protoreflect.MethodDescriptor) (*annotations.HttpRule, bool) {
+	opts := md.Options()
+	if opts == nil || !proto.HasExtension(opts, annotations.E_Http) {
+		return nil, false
+	}
+	rule, ok := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	return rule, ok && rule != nil
+}
+
+func methodAndPattern(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return strings.ToUpper(pattern.Custom.Kind), pattern.Custom.Path
+	}
+	return "", ""
+}
+
+// Invoke sends req of method transcoded by google.api.http rules, and unmarshal response into resp
+func Invoke(ctx context.Context, c *client.Client, md protoreflect.MethodDescriptor, req proto.Message, resp proto.Message, metas ...courier.Metadata) (courier.Metadata, error) {
+	request, err := NewRequest(ctx, c, md, req, metas...)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	meta, err := c.Do(ctx, request).Into(buf)
+	if err != nil {
+		return meta, err
+	}
+
+	if resp == nil || buf.Len() == 0 {
+		return meta, nil
+	}
+
+	target := resp.ProtoReflect()
+
+	if rule, _ := HttpRuleOf(md); rule != nil && rule.ResponseBody != "" {
+		fd := target.Descriptor().Fields().ByName(protoreflect.Name(rule.ResponseBody))
+		if fd == nil || fd.Message() == nil {
+			return meta, statuserror.Wrap(errors.Errorf("invalid response body field %s", rule.ResponseBody), http.StatusInternalServerError, "DecodeFailed")
+		}
+		target = target.Mutable(fd).Message()
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(buf.Bytes(), target.Interface()); err != nil {
+		return meta, statuserror.Wrap(err, http.StatusInternalServerError, "DecodeFailed")
+	}
+
+	return meta, nil
+}
+
+// NewRequest creates http request from req of method by google.api.http rules,
+// fields bound by path template will be filled into path,
+// field named by body (or all rest fields when body is *) will be sent as json body,
+// and the rest fields will be sent as query.
+func NewRequest(ctx context.Context, c *client.Client, md protoreflect.MethodDescriptor, req proto.Message, metas ...courier.Metadata) (*http.Request, error) {
+	rule, ok := HttpRuleOf(md)
+	if !ok {
+		return nil, statuserror.Wrap(errors.Errorf("missing google.api.http option of %s", md.FullName()), http.StatusBadRequest, "RequestTransformFailed")
+	}
+
+	tr, err := transcode(rule, req.ProtoReflect())
+	if err != nil {
+		return nil, statuserror.Wrap(err, http.StatusBadRequest, "RequestTransformFailed")
+	}
+
+	u := c.URL(tr.path)
+	if len(tr.query) > 0 {
+		u += "?" + tr.query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, tr.method, u, bytes.NewReader(tr.body))
+	if err != nil {
+		return nil, statuserror.Wrap(err, http.StatusBadRequest, "RequestTransformFailed")
+	}
+
+	if tr.body != nil {
+		request.Header.Set(httpx.HeaderContentType, httpx.MIME_JSON)
+	}
+
+	for k, vs := range courier.FromMetas(metas...) {
+		for _, v := range vs {
+			request.Header.Add(k, v)
+		}
+	}
+
+	return request, nil
+}
+
+type transcoded struct {
+	method string
+	path   string
+	query  url.Values
+	body   []byte
+}
+
+// {field.path} or {field.path=pattern}
+var reVariable = regexp.MustCompile(`{([^}=]+)(=([^}]*))?}`)
+
+func transcode(rule *annotations.HttpRule, msg protoreflect.Message) (*transcoded, error) {
+	method, pattern := methodAndPattern(rule)
+	if method == "" || pattern == "" {
+		return nil, errors.New("invalid http rule, missing pattern")
+	}
+
+	tr := &transcoded{method: method}
+
+	bound := map[string]bool{}
+
+	var err error
+
+	tr.path = reVariable.ReplaceAllStringFunc(pattern, func(variable string) string {
+		matched := reVariable.FindStringSubmatch(variable)
+		fieldPath, segments := matched[1], matched[3]
+
+		bound[fieldPath] = true
+
+		v, ok := fieldValueOf(msg, fieldPath)
+		if !ok || v == "" {
+			err = errors.Errorf("missing value of path variable %s", fieldPath)
+			return ""
+		}
+
+		// multi segments pattern like {name=messages/*} or {name=**} keeps slashes
+		if strings.Contains(segments, "/") || strings.Contains(segments, "**") {
+			parts := strings.Split(v, "/")
+			for i := range parts {
+				parts[i] = url.PathEscape(parts[i])
+			}
+			return strings.Join(parts, "/")
+		}
+
+		return url.PathEscape(v)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch rule.Body {
+	case "":
+	case "*":
+		m := proto.Clone(msg.Interface()).ProtoReflect()
+		for fieldPath := range bound {
+			clearField(m, fieldPath)
+		}
+		body, err := protojson.Marshal(m.Interface())
+		if err != nil {
+			return nil, err
+		}
+		tr.body = body
+		return tr, nil
+	default:
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(rule.Body))
+		if fd == nil {
+			return nil, errors.Errorf("invalid body field %s", rule.Body)
+		}
+
+		bound[rule.Body] = true
+
+		var body []byte
+
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			body, err = protojson.Marshal(msg.Get(fd).Message().Interface())
+		} else {
+			body, err = json.Marshal(msg.Get(fd).Interface())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		tr.body = body
+	}
+
+	tr.query = url.Values{}
+	addQuery(tr.query, msg, "", bound)
+
+	return tr, nil
+}
+
+func fieldDescriptorOf(msg protoreflect.Message, fieldPath string) (protoreflect.Message, protoreflect.FieldDescriptor) {
+	names := strings.Split(fieldPath, ".")
+
+	for i, name := range names {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil, nil
+		}
+		if i == len(names)-1 {
+			return msg, fd
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() || !msg.Has(fd) {
+			return nil, nil
+		}
+		msg = msg.Get(fd).Message()
+	}
+
+	return nil, nil
+}
+
+func fieldValueOf(msg protoreflect.Message, fieldPath string) (string, bool) {
+	m, fd := fieldDescriptorOf(msg, fieldPath)
+	if fd == nil || fd.IsList() || fd.IsMap() {
+		return "", false
+	}
+	return stringify(fd, m.Get(fd))
+}
+
+func clearField(msg protoreflect.Message, fieldPath string) {
+	if m, fd := fieldDescriptorOf(msg, fieldPath); fd != nil {
+		m.Clear(fd)
+	}
+}
+
+func addQuery(query url.Values, msg protoreflect.Message, prefix string, bound map[string]bool) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fieldPath := prefix + string(fd.Name())
+
+		if bound[fieldPath] || fd.IsMap() {
+			return true
+		}
+
+		key := prefix + fd.JSONName()
+
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if s, ok := stringify(fd, list.Get(i)); ok {
+					query.Add(key, s)
+				}
+			}
+			return true
+		}
+
+		if fd.Message() != nil && !isWellKnownType(fd.Message()) {
+			addQuery(query, v.Message(), fieldPath+".", bound)
+			return true
+		}
+
+		if s, ok := stringify(fd, v); ok {
+			query.Add(key, s)
+		}
+
+		return true
+	})
+}
+
+func isWellKnownType(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile() != nil && md.ParentFile().Package() == "google.protobuf"
+}
+
+func stringify(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, bool) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), true
+		}
+		return v.String(), true
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), true
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if !isWellKnownType(fd.Message()) {
+			return "", false
+		}
+		// well-known types like Timestamp, Duration and wrappers as their json value
+		data, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return "", false
+		}
+		s := string(data)
+		if unquoted, err := jsonUnquote(data); err == nil {
+			s = unquoted
+		}
+		return s, true
+	}
+	return v.String(), true
+}
+
+func jsonUnquote(data []byte) (string, error) {
+	s := ""
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
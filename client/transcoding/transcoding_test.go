@@ -0,0 +1,226 @@
+package transcoding
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/go-courier/httptransport/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func methodOptions(rule *annotations.HttpRule) *descriptorpb.MethodOptions {
+	opts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(opts, annotations.E_Http, rule)
+	return opts
+}
+
+func newService(t *testing.T) protoreflect.ServiceDescriptor {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("demo.proto"),
+		Package: proto.String("demo"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Filter"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("state", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name: proto.String("Message"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("text", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("size", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+					field("filter", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".demo.Filter"),
+					field("sub", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".demo.Message"),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Messaging"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("GetMessage"),
+						InputType:  proto.String(".demo.Message"),
+						OutputType: proto.String(".demo.Message"),
+						Options: methodOptions(&annotations.HttpRule{
+							Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=messages/*}"},
+						}),
+					},
+					{
+						Name:       proto.String("UpdateMessage"),
+						InputType:  proto.String(".demo.Message"),
+						OutputType: proto.String(".demo.Message"),
+						Options: methodOptions(&annotations.HttpRule{
+							Pattern: &annotations.HttpRule_Patch{Patch: "/v1/messages/{name}"},
+							Body:    "sub",
+						}),
+					},
+					{
+						Name:       proto.String("CreateMessage"),
+						InputType:  proto.String(".demo.Message"),
+						OutputType: proto.String(".demo.Message"),
+						Options: methodOptions(&annotations.HttpRule{
+							Pattern:      &annotations.HttpRule_Post{Post: "/v1/messages/{name}"},
+							Body:         "*",
+							ResponseBody: "sub",
+						}),
+					},
+					{
+						Name:       proto.String("Unbound"),
+						InputType:  proto.String(".demo.Message"),
+						OutputType: proto.String(".demo.Message"),
+					},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	require.NoError(t, err)
+
+	return fd.Services().Get(0)
+}
+
+func newMessage(md protoreflect.MessageDescriptor, values map[string]interface{}) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(md)
+	for name, v := range values {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		switch x := v.(type) {
+		case map[string]interface{}:
+			msg.Set(fd, protoreflect.ValueOfMessage(newMessage(fd.Message(), x)))
+		default:
+			msg.Set(fd, protoreflect.ValueOf(x))
+		}
+	}
+	return msg
+}
+
+type recorded struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   string
+}
+
+func TestInvoke(t *testing.T) {
+	service := newService(t)
+
+	var last recorded
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		last = recorded{Method: r.Method, Path: r.URL.EscapedPath(), Query: r.URL.Query(), Body: string(body)}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"name":"messages/1","text":"hello","sub":{"text":"world"}}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	c := &client.Client{Host: u.Hostname(), Port: uint16(port)}
+	c.SetDefaults()
+
+	msgType := service.Methods().ByName("GetMessage").Input()
+
+	t.Run("path and query", func(t *testing.T) {
+		md := service.Methods().ByName("GetMessage")
+
+		req := newMessage(msgType, map[string]interface{}{
+			"name":   "messages/a b",
+			"size":   int32(10),
+			"filter": map[string]interface{}{"state": "active"},
+		})
+		resp := dynamicpb.NewMessage(md.Output())
+
+		_, err := Invoke(context.Background(), c, md, req, resp)
+		require.NoError(t, err)
+
+		require.Equal(t, recorded{
+			Method: http.MethodGet,
+			Path:   "/v1/messages/a%20b",
+			Query:  url.Values{"size": {"10"}, "filter.state": {"active"}},
+		}, last)
+		require.Equal(t, "hello", resp.Get(md.Output().Fields().ByName("text")).String())
+	})
+
+	t.Run("body field", func(t *testing.T) {
+		md := service.Methods().ByName("UpdateMessage")
+
+		req := newMessage(msgType, map[string]interface{}{
+			"name": "1",
+			"text": "hello",
+			"sub":  map[string]interface{}{"text": "world"},
+		})
+
+		_, err := Invoke(context.Background(), c, md, req, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, http.MethodPatch, last.Method)
+		require.Equal(t, "/v1/messages/1", last.Path)
+		require.Equal(t, url.Values{"text": {"hello"}}, last.Query)
+		require.JSONEq(t, `{"text":"world"}`, last.Body)
+	})
+
+	t.Run("body * and response body", func(t *testing.T) {
+		md := service.Methods().ByName("CreateMessage")
+
+		req := newMessage(msgType, map[string]interface{}{
+			"name": "1",
+			"text": "hello",
+		})
+		resp := dynamicpb.NewMessage(md.Output())
+
+		_, err := Invoke(context.Background(), c, md, req, resp)
+		require.NoError(t, err)
+
+		require.Equal(t, http.MethodPost, last.Method)
+		require.Equal(t, "/v1/messages/1", last.Path)
+		require.Empty(t, last.Query)
+		require.JSONEq(t, `{"text":"hello"}`, last.Body)
+
+		// whole response body as value of field sub
+		sub := resp.Get(md.Output().Fields().ByName("sub")).Message()
+		require.Equal(t, "hello", sub.Get(md.Output().Fields().ByName("text")).String())
+		require.False(t, resp.Has(md.Output().Fields().ByName("text")))
+	})
+
+	t.Run("missing path variable", func(t *testing.T) {
+		_, err := Invoke(context.Background(), c, service.Methods().ByName("GetMessage"), dynamicpb.NewMessage(msgType), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("missing http rule", func(t *testing.T) {
+		_, err := Invoke(context.Background(), c, service.Methods().ByName("Unbound"), dynamicpb.NewMessage(msgType), nil)
+		require.Error(t, err)
+	})
+}
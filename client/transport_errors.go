@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// transportStatusErr classifies errors of round trip into status errors by keys,
+// so callers could branch and alert without parsing descriptions,
+//
+//	499 ClientClosedRequest     context canceled
+//	502 HostNotFound            dns no such host
+//	502 DNSFailed               dns failures others
+//	503 ConnectionRefused
+//	502 ConnectionReset
+//	502 TLSCertificateExpired   or not valid yet
+//	502 TLSCertificateUntrusted signed by unknown authority
+//	502 TLSHostnameMismatch
+//	502 TLSCertificateInvalid   others of certificate
+//	502 TLSHandshakeFailed      like server not speaking tls
+//	504 RequestTimeout          timeout of dialing, dns, tls handshake or waiting response
+//	500 RequestFailed           others
+//
+// status errors returned by round trippers, like ConnPoolExhausted, are kept.
+func transportStatusErr(err error) *statuserror.StatusErr {
+	statusErr := &statuserror.StatusErr{}
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return statuserror.Wrap(err, 499, "ClientClosedRequest")
+	}
+
+	dnsErr := &net.DNSError{}
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return statuserror.Wrap(err, http.StatusBadGateway, "HostNotFound")
+		case dnsErr.IsTimeout:
+			return statuserror.Wrap(err, http.StatusGatewayTimeout, "RequestTimeout")
+		}
+		return statuserror.Wrap(err, http.StatusBadGateway, "DNSFailed")
+	}
+
+	if key := tlsFailureKeyOf(err); key != "" {
+		return statuserror.Wrap(err, http.StatusBadGateway, key)
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return statuserror.Wrap(err, http.StatusServiceUnavailable, "ConnectionRefused")
+	case errors.Is(err, syscall.ECONNRESET):
+		return statuserror.Wrap(err, http.StatusBadGateway, "ConnectionReset")
+	case errors.Is(err, context.DeadlineExceeded):
+		return statuserror.Wrap(err, http.StatusGatewayTimeout, "RequestTimeout")
+	}
+
+	netErr := net.Error(nil)
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return statuserror.Wrap(err, http.StatusGatewayTimeout, "RequestTimeout")
+	}
+
+	return statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed")
+}
+
+func tlsFailureKeyOf(err error) string {
+	certInvalidErr := x509.CertificateInvalidError{}
+	if errors.As(err, &certInvalidErr) {
+		if certInvalidErr.Reason == x509.Expired {
+			return "TLSCertificateExpired"
+		}
+		return "TLSCertificateInvalid"
+	}
+
+	if errors.As(err, &x509.UnknownAuthorityError{}) {
+		return "TLSCertificateUntrusted"
+	}
+
+	if errors.As(err, &x509.HostnameError{}) {
+		return "TLSHostnameMismatch"
+	}
+
+	if errors.As(err, &tls.RecordHeaderError{}) {
+		return "TLSHandshakeFailed"
+	}
+
+	return ""
+}
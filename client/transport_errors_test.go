@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportStatusErr(t *testing.T) {
+	do := func(ctx context.Context, c *http.Client, url string) *statuserror.StatusErr {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		_, err := c.Do(req)
+		require.Error(t, err)
+		return transportStatusErr(err)
+	}
+
+	t.Run("connection refused", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		_ = l.Close()
+
+		statusErr := do(context.Background(), &http.Client{}, "http://"+addr)
+		require.Equal(t, "ConnectionRefused", statusErr.Key)
+		require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode())
+	})
+
+	t.Run("tls untrusted", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		statusErr := do(context.Background(), &http.Client{}, srv.URL)
+		require.Equal(t, "TLSCertificateUntrusted", statusErr.Key)
+	})
+
+	t.Run("tls handshake failed", func(t *testing.T) {
+		err := &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}}
+		require.Equal(t, "TLSHandshakeFailed", transportStatusErr(err).Key)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		statusErr := do(context.Background(), &http.Client{Timeout: 10 * time.Millisecond}, srv.URL)
+		require.Equal(t, "RequestTimeout", statusErr.Key)
+		require.Equal(t, http.StatusGatewayTimeout, statusErr.StatusCode())
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		statusErr := do(ctx, &http.Client{}, "http://127.0.0.1:1")
+		require.Equal(t, "ClientClosedRequest", statusErr.Key)
+	})
+
+	t.Run("dns", func(t *testing.T) {
+		require.Equal(t, "HostNotFound", transportStatusErr(&net.OpError{Op: "dial", Err: &net.DNSError{Name: "x.invalid", IsNotFound: true}}).Key)
+		require.Equal(t, "RequestTimeout", transportStatusErr(&net.OpError{Op: "dial", Err: &net.DNSError{Name: "x.invalid", IsTimeout: true}}).Key)
+		require.Equal(t, "DNSFailed", transportStatusErr(&net.OpError{Op: "dial", Err: &net.DNSError{Name: "x.invalid"}}).Key)
+	})
+
+	t.Run("status error of round tripper kept", func(t *testing.T) {
+		err := statuserror.Wrap(ErrConnPoolExhausted, http.StatusServiceUnavailable, "ConnPoolExhausted")
+		require.Equal(t, "ConnPoolExhausted", transportStatusErr(errors.Wrap(err, "Get")).Key)
+	})
+
+	t.Run("others", func(t *testing.T) {
+		require.Equal(t, "RequestFailed", transportStatusErr(errors.New("x")).Key)
+	})
+}
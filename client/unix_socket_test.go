@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"id":"` + r.URL.Path + `"}`))
+	})}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Close()
+
+	for _, keepAlive := range []bool{false, true} {
+		c := &Client{
+			Protocol:  ProtocolUnix,
+			Host:      socket,
+			KeepAlive: keepAlive,
+		}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "/data", data.ID)
+	}
+
+	t.Run("custom dial", func(t *testing.T) {
+		c := &Client{
+			Host: "sidecar",
+			DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}
+		c.SetDefaults()
+
+		data := &Data{}
+		_, err := c.Do(context.Background(), &GetData{}).Into(data)
+		require.NoError(t, err)
+		require.Equal(t, "/data", data.ID)
+	})
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// DialWebSocket upgrades req to websocket by Host, BasePath, TLS, dialing and HttpTransports of Client,
+// values of messages encoded and decoded by transformers of RequestTransformerMgr.
+// Timeout of Client not works for connection upgraded, and round trippers should keep body of 101 response unwrapped.
+//
+//	conn, err := c.DialWebSocket(ctx, &Subscribe{Topic: "orders"})
+//	if err != nil {
+//	}
+//	defer conn.Close()
+func (c *Client) DialWebSocket(ctx context.Context, req interface{}, metas ...courier.Metadata) (*httpx.WebSocketConn, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.HTTP2 || c.H2C {
+		return nil, statuserror.Wrap(errors.New("websocket is not supported with HTTP2 or H2C"), http.StatusInternalServerError, "RequestFailed")
+	}
+
+	request, _, err := c.newRequest(ctx, req, metas...)
+	if err != nil {
+		return nil, statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed")
+	}
+
+	if request.Method == "" {
+		request.Method = http.MethodGet
+	}
+
+	key := httpx.NewWebSocketKey()
+
+	request.Header.Set(httpx.HeaderConnection, "Upgrade")
+	request.Header.Set(httpx.HeaderUpgrade, "websocket")
+	request.Header.Set(httpx.HeaderSecWebSocketVersion, "13")
+	request.Header.Set(httpx.HeaderSecWebSocketKey, key)
+
+	httpClient := ClientFromContext(ctx)
+	if httpClient == nil {
+		hc, err := c.httpClientContext(ctx)
+		if err != nil {
+			return nil, statuserror.Wrap(err, http.StatusInternalServerError, "RequestFailed")
+		}
+		httpClient = hc
+	}
+
+	// timeout of http.Client wraps body, which should be kept as connection
+	withoutTimeout := *httpClient
+	withoutTimeout.Timeout = 0
+
+	resp, err := withoutTimeout.Do(request)
+	if err != nil {
+		return nil, transportStatusErr(err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		result := &Result{
+			NewError:       c.NewError,
+			ErrorBodies:    c.ErrorBodies,
+			TransformerMgr: c.RequestTransformerMgr.TransformerMgr,
+			Response:       resp,
+		}
+		if _, err := result.Into(nil); err != nil {
+			return nil, err
+		}
+		return nil, statuserror.Wrap(errors.Errorf("websocket handshake failed with status %d", resp.StatusCode), http.StatusBadGateway, "WebSocketHandshakeFailed")
+	}
+
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, statuserror.Wrap(errors.Errorf("body of 101 response should be io.ReadWriteCloser, but got %T", resp.Body), http.StatusInternalServerError, "WebSocketHandshakeFailed")
+	}
+
+	if resp.Header.Get(httpx.HeaderSecWebSocketAccept) != httpx.WebSocketAccept(key) {
+		_ = rwc.Close()
+		return nil, statuserror.Wrap(errors.Errorf("invalid %s", httpx.HeaderSecWebSocketAccept), http.StatusBadGateway, "WebSocketHandshakeFailed")
+	}
+
+	conn := httpx.NewWebSocketConn(context.Background(), rwc, true)
+	conn.Codec = transformers.NewWebSocketCodec(c.RequestTransformerMgr.TransformerMgr, "")
+
+	return conn, nil
+}
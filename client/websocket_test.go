@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/handlers"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDialWebSocket(t *testing.T) {
+	echo := httpx.WebSocketHandler(func(ctx context.Context, conn *httpx.WebSocketConn) error {
+		for {
+			data := Data{}
+			if err := conn.ReadValue(&data); err != nil {
+				return nil
+			}
+			if err := conn.WriteValue(Data{ID: "echo:" + data.ID}); err != nil {
+				return err
+			}
+		}
+	})
+
+	c := newTestClient(t, handlers.LogHandler()(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := echo.Upgrade(rw, r); err != nil {
+			statusErr, _ := statuserror.IsStatusErr(err)
+			rw.WriteHeader(statusErr.StatusCode())
+		}
+	})).ServeHTTP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conn, err := c.DialWebSocket(ctx, &GetData{})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// ctx only for handshake
+	cancel()
+
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, conn.WriteValue(Data{ID: id}))
+
+		data := Data{}
+		require.NoError(t, conn.ReadValue(&data))
+		require.Equal(t, "echo:"+id, data.ID)
+	}
+
+	t.Run("handshake failed", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = rw.Write([]byte(`{"key":"Unauthorized","code":401000000}`))
+		})
+
+		_, err := c.DialWebSocket(context.Background(), &GetData{})
+		statusErr, ok := statuserror.IsStatusErr(err)
+		require.True(t, ok)
+		require.Equal(t, "Unauthorized", statusErr.Key)
+	})
+}
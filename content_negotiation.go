@@ -0,0 +1,164 @@
+package httptransport
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// negotiateContentType picks content type of response by Accept of request.
+// the declared content type of response (or default of transformer) is kept when accepted with the highest q value, wildcards included,
+// otherwise the accepted media type with higher q value, which registered in TransformerMgr.
+// 406 when nothing could be matched.
+// responses written by themselves, like io.Reader or ResponseWriterFunc, are not negotiated.
+func (handler *HttpRouteHandler) negotiateContentType(rw http.ResponseWriter, r *http.Request, response *httpx.Response) error {
+	if !isNegotiable(response) {
+		return nil
+	}
+
+	ranges := parseAccept(r.Header.Get(httpx.HeaderAccept))
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	rw.Header().Add(httpx.HeaderVary, httpx.HeaderAccept)
+
+	typ := typesutil.FromRType(reflect.TypeOf(response.Value))
+
+	defaultMediaType := mediaTypeOf(response.ContentType)
+	if defaultMediaType == "" {
+		transformer, err := handler.TransformerMgr.NewTransformer(context.Background(), typ, transformers.TransformerOption{})
+		if err != nil {
+			return err
+		}
+		defaultMediaType = mediaTypeOf(transformer.String())
+	}
+
+	defaultQ := qualityOf(ranges, defaultMediaType)
+
+	// ranges ordered by q values, the default wins in tie
+	for _, ar := range ranges {
+		if ar.q <= defaultQ {
+			break
+		}
+		if strings.Contains(ar.mediaType, "*") {
+			continue
+		}
+		if _, err := handler.TransformerMgr.NewTransformer(context.Background(), typ, transformers.TransformerOption{
+			MIME: ar.mediaType,
+		}); err == nil {
+			response.ContentType = ar.mediaType
+			return nil
+		}
+	}
+
+	if defaultQ > 0 {
+		return nil
+	}
+
+	return statuserror.Wrap(
+		errors.Errorf("none of %q acceptable, could be %s", r.Header.Get(httpx.HeaderAccept), defaultMediaType),
+		http.StatusNotAcceptable,
+		"NotAcceptable",
+	)
+}
+
+func isNegotiable(response *httpx.Response) bool {
+	if response.Value == nil || response.Location != nil || response.StatusCode == http.StatusNoContent {
+		return false
+	}
+
+	switch response.Value.(type) {
+	case httpx.Upgrader, httpx.ResponseWriterFunc, *httpx.File, *httpx.PreEncoded, courier.Result, io.Reader, io.WriterTo, error:
+		return false
+	}
+
+	return true
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns lower cased media ranges ordered by q values, q=0 kept for excluding
+func parseAccept(accept string) []acceptRange {
+	list := make([]acceptRange, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+		kv := strings.Split(strings.TrimSpace(part), ";")
+
+		mediaType := strings.ToLower(strings.TrimSpace(kv[0]))
+		if mediaType == "" {
+			continue
+		}
+		if mediaType == "*" {
+			mediaType = "*/*"
+		}
+
+		q := 1.0
+		for _, param := range kv[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		list = append(list, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].q > list[j].q
+	})
+
+	return list
+}
+
+// qualityOf returns q value of the most specific range matched media type, 0 when not matched
+func qualityOf(ranges []acceptRange, mediaType string) float64 {
+	q := 0.0
+	specificity := -1
+
+	for _, ar := range ranges {
+		s := -1
+
+		switch {
+		case ar.mediaType == mediaType:
+			s = 2
+		case ar.mediaType == "*/*":
+			s = 0
+		case strings.HasSuffix(ar.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(ar.mediaType, "*")):
+			s = 1
+		}
+
+		if s > specificity {
+			specificity = s
+			q = ar.q
+		}
+	}
+
+	return q
+}
+
+func mediaTypeOf(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(contentType)
+}
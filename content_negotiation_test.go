@@ -0,0 +1,91 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+type GetNote struct {
+	httpx.MethodGet
+}
+
+type Note struct {
+	Title string `json:"title" xml:"title"`
+}
+
+func (GetNote) Output(ctx context.Context) (interface{}, error) {
+	return &Note{Title: "note"}, nil
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	route := NewHttpRouteMeta(courier.NewRouter(GetNote{}).Routes()[0])
+	handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, route, NewRequestTransformerMgr(nil, nil))
+
+	serve := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != "" {
+			req.Header.Set(httpx.HeaderAccept, accept)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("default without accept", func(t *testing.T) {
+		rw := serve("")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "application/json; charset=utf-8", rw.Header().Get(httpx.HeaderContentType))
+		require.Empty(t, rw.Header().Get(httpx.HeaderVary))
+	})
+
+	t.Run("default by wildcards", func(t *testing.T) {
+		for _, accept := range []string{"*/*", "application/*", "text/html, application/json;q=0.9"} {
+			rw := serve(accept)
+			require.Equal(t, http.StatusOK, rw.Code, accept)
+			require.Equal(t, "application/json; charset=utf-8", rw.Header().Get(httpx.HeaderContentType), accept)
+			require.Equal(t, httpx.HeaderAccept, rw.Header().Get(httpx.HeaderVary))
+		}
+	})
+
+	t.Run("picked by q values", func(t *testing.T) {
+		rw := serve("application/json;q=0.5, application/xml")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "application/xml; charset=utf-8", rw.Header().Get(httpx.HeaderContentType))
+		require.Contains(t, rw.Body.String(), "<title>note</title>")
+	})
+
+	t.Run("excluded by q=0", func(t *testing.T) {
+		rw := serve("application/json;q=0, */*")
+		require.Equal(t, http.StatusNotAcceptable, rw.Code)
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		rw := serve("image/png")
+		require.Equal(t, http.StatusNotAcceptable, rw.Code)
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), statusErr))
+		require.Equal(t, "NotAcceptable", statusErr.Key)
+	})
+}
+
+func TestParseAccept(t *testing.T) {
+	ranges := parseAccept("text/html;q=0.8, application/json, *;q=0.1")
+
+	require.Equal(t, []acceptRange{
+		{mediaType: "application/json", q: 1},
+		{mediaType: "text/html", q: 0.8},
+		{mediaType: "*/*", q: 0.1},
+	}, ranges)
+
+	require.Equal(t, 0.8, qualityOf(ranges, "text/html"))
+	require.Equal(t, 0.1, qualityOf(ranges, "image/png"))
+}
@@ -0,0 +1,92 @@
+package httptransport
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// contentTypesOfBody lists media types could be decoded by transformer of body parameter.
+// empty when body is default plain text without tag `mime`, which accepts any raw content, like file uploading.
+func contentTypesOfBody(transformer transformers.Transformer, declaredMIME string) []string {
+	names := transformer.Names()
+
+	contentTypes := make([]string, 0)
+
+	for _, name := range names {
+		if declaredMIME == "" && name == "text/plain" {
+			return nil
+		}
+		if strings.Contains(name, "/") {
+			contentTypes = append(contentTypes, name)
+		}
+	}
+
+	return contentTypes
+}
+
+// contentTypeCheckOf collects accepted content types of bodies of all operators of route,
+// nil when no body or any content accepted
+func contentTypeCheckOf(requestTransformers []*RequestTransformer) []string {
+	added := map[string]bool{}
+
+	for _, rt := range requestTransformers {
+		for _, param := range rt.Parameters {
+			for _, contentType := range param.ContentTypes {
+				added[contentType] = true
+			}
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	contentTypes := make([]string, 0, len(added))
+	for contentType := range added {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	return contentTypes
+}
+
+// checkContentType rejects request which Content-Type not accepted with 415 and Accept-Post (or Accept-Patch for PATCH),
+// requests without body and Content-Type will be passed for optional body.
+func checkContentType(rw http.ResponseWriter, contentTypes []string, r *http.Request) error {
+	if len(contentTypes) == 0 {
+		return nil
+	}
+
+	contentType := r.Header.Get(httpx.HeaderContentType)
+
+	if contentType == "" && (r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0) {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	for _, t := range contentTypes {
+		if strings.EqualFold(t, mediaType) {
+			return nil
+		}
+	}
+
+	acceptHeader := httpx.HeaderAcceptPost
+	if r.Method == http.MethodPatch {
+		acceptHeader = httpx.HeaderAcceptPatch
+	}
+	rw.Header().Set(acceptHeader, strings.Join(contentTypes, ", "))
+
+	return statuserror.Wrap(
+		errors.Errorf("unsupported Content-Type %q, should be one of %s", contentType, strings.Join(contentTypes, ", ")),
+		http.StatusUnsupportedMediaType,
+		"UnsupportedMediaType",
+	)
+}
@@ -0,0 +1,92 @@
+package httptransport_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+type ItemData struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type CreateItemWithJSON struct {
+	httpx.MethodPost
+	Data ItemData `in:"body"`
+}
+
+func (CreateItemWithJSON) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+type UpdateItemWithJSONOrXML struct {
+	httpx.MethodPatch
+	Data ItemData `in:"body" mime:"xml"`
+}
+
+func (UpdateItemWithJSONOrXML) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+type UploadRaw struct {
+	httpx.MethodPut
+	Data []byte `in:"body"`
+}
+
+func (UploadRaw) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestContentTypeCheck(t *testing.T) {
+	newHandler := func(op courier.Operator) http.Handler {
+		rootRouter := courier.NewRouter(httptransport.Group("/root"))
+		rootRouter.Register(courier.NewRouter(op))
+		return httptransport.NewHttpRouteHandler(serviceMeta, httptransport.NewHttpRouteMeta(rootRouter.Routes()[0]), rtMgr)
+	}
+
+	serve := func(handler http.Handler, method string, contentType string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+		if contentType != "" {
+			req.Header.Set(httpx.HeaderContentType, contentType)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("json body", func(t *testing.T) {
+		h := newHandler(CreateItemWithJSON{})
+
+		require.Equal(t, http.StatusNoContent, serve(h, http.MethodPost, "application/json; charset=utf-8", `{"name":"x"}`).Code)
+
+		rw := serve(h, http.MethodPost, "text/plain", `{"name":"x"}`)
+		require.Equal(t, http.StatusUnsupportedMediaType, rw.Code)
+		require.Equal(t, "application/json", rw.Header().Get(httpx.HeaderAcceptPost))
+		require.Contains(t, rw.Body.String(), "UnsupportedMediaType")
+
+		require.Equal(t, http.StatusUnsupportedMediaType, serve(h, http.MethodPost, "", `{"name":"x"}`).Code)
+	})
+
+	t.Run("declared mime on patch", func(t *testing.T) {
+		h := newHandler(UpdateItemWithJSONOrXML{})
+
+		require.Equal(t, http.StatusNoContent, serve(h, http.MethodPatch, "application/xml", `<ItemData><name>x</name></ItemData>`).Code)
+
+		rw := serve(h, http.MethodPatch, "application/json", `{"name":"x"}`)
+		require.Equal(t, http.StatusUnsupportedMediaType, rw.Code)
+		require.Equal(t, "application/xml", rw.Header().Get(httpx.HeaderAcceptPatch))
+	})
+
+	t.Run("raw body accepts any", func(t *testing.T) {
+		h := newHandler(UploadRaw{})
+
+		require.Equal(t, http.StatusNoContent, serve(h, http.MethodPut, "image/png", "png").Code)
+	})
+}
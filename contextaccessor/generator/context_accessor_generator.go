@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/packagesx"
+	"github.com/go-courier/reflectx/typesutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// NewContextAccessorGenerator generates typed accessors of values provided into context by operators of route group,
+// instead of type assertions of ctx.Value in operators.
+// type of value resolved from results of Output, key from ContextKey or type name as courier does.
+//
+//	type AuthProvider struct {
+//		Authorization string `name:"Authorization" in:"header"`
+//	}
+//
+//	func (AuthProvider) ContextKey() string {
+//		return "AuthProvider"
+//	}
+//
+//	func (p AuthProvider) Output(ctx context.Context) (interface{}, error) {
+//		return &Principal{}, nil
+//	}
+//
+// will generate
+//
+//	func AuthFromContext(ctx context.Context) (*Principal, bool)
+func NewContextAccessorGenerator(pkg *packagesx.Package) *ContextAccessorGenerator {
+	return &ContextAccessorGenerator{
+		pkg:       pkg,
+		accessors: map[string]*ContextAccessor{},
+	}
+}
+
+type ContextAccessorGenerator struct {
+	pkg       *packagesx.Package
+	accessors map[string]*ContextAccessor
+}
+
+// Scan context providers by type names
+func (g *ContextAccessorGenerator) Scan(names ...string) {
+	for _, name := range names {
+		typeName := g.pkg.TypeName(name)
+		if typeName == nil {
+			panic(fmt.Errorf("type `%s` not found", name))
+		}
+
+		valueType := g.scanValueType(typeName)
+		if valueType == nil {
+			panic(fmt.Errorf("type of value provided by `%s` could not be resolved from results of Output", name))
+		}
+
+		g.accessors[name] = &ContextAccessor{
+			Provider:  typeName,
+			ValueType: valueType,
+		}
+	}
+}
+
+func (g *ContextAccessorGenerator) scanValueType(typeName *types.TypeName) types.Type {
+	var valueType types.Type
+
+	for _, typ := range []types.Type{
+		typeName.Type(),
+		types.NewPointer(typeName.Type()),
+	} {
+		method, ok := typesutil.FromTType(typ).MethodByName("Output")
+		if !ok {
+			continue
+		}
+
+		results, n := g.pkg.FuncResultsOf(method.(*typesutil.TMethod).Func)
+		if n != 2 {
+			continue
+		}
+
+		for _, v := range results[0] {
+			if v.Type == nil || v.Type.String() == types.Typ[types.UntypedNil].String() {
+				continue
+			}
+			if valueType != nil && !types.Identical(valueType, v.Type) {
+				panic(fmt.Errorf("`%s` should provide values of same type, but got %s and %s", typeName.Name(), valueType, v.Type))
+			}
+			valueType = v.Type
+		}
+	}
+
+	return valueType
+}
+
+func getPkgDir(importPath string) string {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadFiles,
+	}, importPath)
+	if err != nil {
+		panic(err)
+	}
+	if len(pkgs) == 0 {
+		panic(fmt.Errorf("package `%s` not found", importPath))
+	}
+	return filepath.Dir(pkgs[0].GoFiles[0])
+}
+
+// Output writes accessors into context_accessors__generated.go of each package of providers
+func (g *ContextAccessorGenerator) Output(cwd string) {
+	accessorsByPkg := map[*types.Package][]*ContextAccessor{}
+
+	for _, accessor := range g.accessors {
+		pkg := accessor.Provider.Pkg()
+		accessorsByPkg[pkg] = append(accessorsByPkg[pkg], accessor)
+	}
+
+	for pkg, accessors := range accessorsByPkg {
+		sort.Slice(accessors, func(i, j int) bool {
+			return accessors[i].Name() < accessors[j].Name()
+		})
+
+		dir, _ := filepath.Rel(cwd, getPkgDir(pkg.Path()))
+		filename := codegen.GeneratedFileSuffix(path.Join(dir, "context_accessors.go"))
+
+		file := codegen.NewFile(pkg.Name(), filename)
+		for _, accessor := range accessors {
+			accessor.WriteToFile(file)
+		}
+
+		if _, err := file.WriteFile(); err != nil {
+			log.Printf("%s generated", file)
+		}
+	}
+}
+
+type ContextAccessor struct {
+	// operator provides value into context
+	Provider *types.TypeName
+	// type of value provided
+	ValueType types.Type
+}
+
+// Name of accessor, like AuthFromContext of AuthProvider
+func (a *ContextAccessor) Name() string {
+	name := strings.TrimSuffix(a.Provider.Name(), "Provider")
+	if name == "" {
+		name = a.Provider.Name()
+	}
+	return name + "FromContext"
+}
+
+// keyExpr same as ContextKey of courier.OperatorFactory
+func (a *ContextAccessor) keyExpr() string {
+	for _, typ := range []types.Type{
+		a.Provider.Type(),
+		types.NewPointer(a.Provider.Type()),
+	} {
+		if sel := types.NewMethodSet(typ).Lookup(a.Provider.Pkg(), "ContextKey"); sel != nil {
+			if _, isPtr := typ.(*types.Pointer); isPtr {
+				return fmt.Sprintf("(&%s{}).ContextKey()", a.Provider.Name())
+			}
+			return fmt.Sprintf("%s{}.ContextKey()", a.Provider.Name())
+		}
+	}
+
+	return fmt.Sprintf("%q", a.Provider.Pkg().Name()+"."+a.Provider.Name())
+}
+
+func (a *ContextAccessor) WriteToFile(file *codegen.File) {
+	valueType := typeSnippetOf(file, a.Provider.Pkg(), a.ValueType)
+
+	file.Write(codegen.Comments(fmt.Sprintf("%s returns value provided by %s", a.Name(), a.Provider.Name())).Bytes())
+
+	file.WriteBlock(
+		codegen.Func(codegen.Var(codegen.Type(file.Use("context", "Context")), "ctx")).
+			Named(a.Name()).
+			Return(codegen.Var(valueType), codegen.Var(codegen.Bool)).Do(
+			file.Expr(`v, ok := ctx.Value(`+a.keyExpr()+`).(?)
+return v, ok`, valueType),
+		),
+	)
+}
+
+func typeSnippetOf(file *codegen.File, pkg *types.Package, typ types.Type) codegen.SnippetType {
+	switch t := typ.(type) {
+	case *types.Pointer:
+		return codegen.Star(typeSnippetOf(file, pkg, t.Elem()))
+	case *types.Slice:
+		return codegen.Slice(typeSnippetOf(file, pkg, t.Elem()))
+	case *types.Map:
+		return codegen.Map(typeSnippetOf(file, pkg, t.Key()), typeSnippetOf(file, pkg, t.Elem()))
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil || obj.Pkg().Path() == pkg.Path() {
+			return codegen.Type(obj.Name())
+		}
+		return codegen.Type(file.Use(obj.Pkg().Path(), obj.Name()))
+	case *types.Basic:
+		return codegen.BuiltInType(t.Name())
+	}
+	return codegen.Type(types.TypeString(typ, nil))
+}
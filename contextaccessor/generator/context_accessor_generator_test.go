@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-courier/codegen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextAccessor(t *testing.T) {
+	pkg := types.NewPackage("github.com/go-courier/httptransport/__examples__/server/cmd/app/routes", "routes")
+
+	data := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Data", nil), types.NewStruct(nil, nil), nil)
+
+	dataProvider := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "DataProvider", nil), types.NewStruct(nil, nil), nil)
+	dataProvider.AddMethod(types.NewFunc(token.NoPos, pkg, "ContextKey", types.NewSignature(
+		types.NewVar(token.NoPos, pkg, "", dataProvider),
+		nil,
+		types.NewTuple(types.NewVar(token.NoPos, pkg, "", types.Typ[types.String])),
+		false,
+	)))
+
+	tenant := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Tenant", nil), types.NewStruct(nil, nil), nil)
+
+	file := codegen.NewFile("routes", "context_accessors__generated.go")
+
+	(&ContextAccessor{
+		Provider:  dataProvider.Obj(),
+		ValueType: types.NewPointer(data),
+	}).WriteToFile(file)
+
+	(&ContextAccessor{
+		Provider:  tenant.Obj(),
+		ValueType: types.NewSlice(types.Typ[types.String]),
+	}).WriteToFile(file)
+
+	require.Equal(t, `package routes
+
+import (
+	context "context"
+)
+
+// DataFromContext returns value provided by DataProvider
+func DataFromContext(ctx context.Context) (*Data, bool) {
+	v, ok := ctx.Value(DataProvider{}.ContextKey()).(*Data)
+	return v, ok
+}
+
+// TenantFromContext returns value provided by Tenant
+func TenantFromContext(ctx context.Context) ([]string, bool) {
+	v, ok := ctx.Value("routes.Tenant").([]string)
+	return v, ok
+}
+`, string(file.Bytes()))
+}
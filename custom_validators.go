@@ -0,0 +1,55 @@
+package httptransport
+
+import (
+	"context"
+
+	"github.com/go-courier/validator"
+	"github.com/pkg/errors"
+)
+
+// RegisterValidator registers custom named rules, like `validate:"@phone"`, into ValidatorMgr used when binding,
+// which is the shared validator.ValidatorMgrDefault by default.
+// should be registered before transformers created.
+func (mgr *RequestTransformerMgr) RegisterValidator(validators ...validator.ValidatorCreator) error {
+	registry, ok := mgr.ValidatorMgr.(interface {
+		Register(validators ...validator.ValidatorCreator)
+	})
+	if !ok {
+		return errors.Errorf("validators could not be registered into %T", mgr.ValidatorMgr)
+	}
+	registry.Register(validators...)
+	return nil
+}
+
+// NewStringValidator creates named rule of string implemented as go func
+//
+//	mgr.RegisterValidator(httptransport.NewStringValidator("phone", func(s string) error {
+//		...
+//	}))
+func NewStringValidator(name string, validate func(s string) error, aliases ...string) *validator.StrfmtValidator {
+	return validator.NewStrfmtValidator(func(v interface{}) error {
+		return validate(v.(string))
+	}, name, aliases...)
+}
+
+// NewPatternValidator creates named rule of string matched by pattern,
+// pattern will be documented in schema of openapi too
+func NewPatternValidator(name string, pattern string, aliases ...string) *PatternValidator {
+	return &PatternValidator{
+		StrfmtValidator: validator.NewRegexpStrfmtValidator(pattern, name, aliases...),
+		pattern:         pattern,
+	}
+}
+
+type PatternValidator struct {
+	*validator.StrfmtValidator
+	pattern string
+}
+
+func (v *PatternValidator) New(ctx context.Context, rule *validator.Rule) (validator.Validator, error) {
+	return v, v.TypeCheck(rule)
+}
+
+func (v *PatternValidator) Pattern() string {
+	return v.pattern
+}
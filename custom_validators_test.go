@@ -0,0 +1,63 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+func TestRequestTransformerMgr_RegisterValidator(t *testing.T) {
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+	err := mgr.RegisterValidator(
+		httptransport.NewPatternValidator("mobile", `^\+?[1-9]\d{1,14}$`),
+		httptransport.NewStringValidator("lowercase", func(s string) error {
+			if strings.ToLower(s) != s {
+				return errors.Errorf("should be lowercase")
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	type Contact struct {
+		Mobile string `json:"mobile" validate:"@mobile"`
+	}
+
+	type CreateContact struct {
+		Tag     string  `name:"tag" in:"query" validate:"@lowercase"`
+		Contact Contact `in:"body"`
+	}
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&CreateContact{}))
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		req, err := rt.NewRequest(http.MethodPost, "/", &CreateContact{Tag: "friend", Contact: Contact{Mobile: "+8613800000000"}})
+		require.NoError(t, err)
+		require.NoError(t, rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, &CreateContact{}))
+	})
+
+	t.Run("invalid with locations", func(t *testing.T) {
+		req, err := rt.NewRequest(http.MethodPost, "/", &CreateContact{Tag: "Friend", Contact: Contact{Mobile: "x"}})
+		require.NoError(t, err)
+
+		err = rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, &CreateContact{})
+		require.Error(t, err)
+
+		locations := map[string]string{}
+		for _, errField := range err.(*statuserror.StatusErr).ErrorFields {
+			locations[errField.Field] = errField.In
+		}
+		require.Equal(t, map[string]string{"tag": "query", "mobile": "body"}, locations)
+	})
+}
@@ -0,0 +1,19 @@
+package httptransport
+
+import (
+	"github.com/go-courier/httptransport/transformers"
+)
+
+// DecodeFallbackDescriber could be implemented by operators of route group
+// to decode request body by the fallback chain when Content-Type of request is missing or unknown,
+// instead of the transformer declared by `mime` tag.
+type DecodeFallbackDescriber interface {
+	DecodeFallback() transformers.FallbackChain
+}
+
+func (route *HttpRouteMeta) DecodeFallback() transformers.FallbackChain {
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return len(m.DecodeFallback) > 0 }); m != nil {
+		return m.DecodeFallback
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+// EarlyHints sends 103 Early Hints with links before final response
+type EarlyHints func(links ...string)
+
+type contextKeyEarlyHints int
+
+func ContextWithEarlyHints(ctx context.Context, earlyHints EarlyHints) context.Context {
+	return context.WithValue(ctx, contextKeyEarlyHints(1), earlyHints)
+}
+
+func EarlyHintsFromContext(ctx context.Context) EarlyHints {
+	if earlyHints, ok := ctx.Value(contextKeyEarlyHints(1)).(EarlyHints); ok {
+		return earlyHints
+	}
+	return func(links ...string) {}
+}
+
+// SendEarlyHints sends 103 Early Hints in operator,
+// will be ignored when not served by HttpRouteHandler
+func SendEarlyHints(ctx context.Context, links ...string) {
+	EarlyHintsFromContext(ctx)(links...)
+}
+
+func earlyHintsOf(rw http.ResponseWriter) EarlyHints {
+	return func(links ...string) {
+		httpx.WriteEarlyHints(rw, links...)
+	}
+}
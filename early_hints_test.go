@@ -0,0 +1,59 @@
+//go:build go1.19
+// +build go1.19
+
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+type GetPage struct {
+	httpx.MethodGet
+}
+
+func (GetPage) Output(ctx context.Context) (interface{}, error) {
+	httptransport.SendEarlyHints(ctx, httpx.PreloadLink("/style.css", "style"))
+	return httpx.NewHTML(), nil
+}
+
+func TestEarlyHints(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/"))
+	rootRouter.Register(courier.NewRouter(GetPage{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	srv := httptest.NewServer(httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr))
+	defer srv.Close()
+
+	codes := make([]int, 0)
+
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			codes = append(codes, code)
+			require.Equal(t, "</style.css>; rel=preload; as=style", header.Get(httpx.HeaderLink))
+			return nil
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, []int{http.StatusEarlyHints}, codes)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Run("ignored out of route handler", func(t *testing.T) {
+		httptransport.SendEarlyHints(context.Background(), httpx.PreloadLink("/style.css", "style"))
+	})
+}
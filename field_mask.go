@@ -0,0 +1,22 @@
+package httptransport
+
+import (
+	"context"
+
+	"github.com/go-courier/httptransport/transformers"
+)
+
+type contextKeyFieldMask int
+
+func ContextWithFieldMask(ctx context.Context, fieldMask transformers.FieldMask) context.Context {
+	return context.WithValue(ctx, contextKeyFieldMask(1), fieldMask)
+}
+
+// FieldMaskFromContext returns the field mask applied when decoding request body,
+// nil means all fields decoded.
+func FieldMaskFromContext(ctx context.Context) transformers.FieldMask {
+	if fieldMask, ok := ctx.Value(contextKeyFieldMask(1)).(transformers.FieldMask); ok {
+		return fieldMask
+	}
+	return nil
+}
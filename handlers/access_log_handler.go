@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/metax"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLogHandler logs structured access log of each request by httpx.AccessLogOptions.Logger,
+// with credentials in headers redacted and bodies of sampled requests.
+// like LogHandler, request id of X-Request-ID (created when missing) is put into meta of context.
+// operation id is resolved from X-Meta header written by HttpRouteHandler,
+// trace id and span id works when OtelHandler before AccessLogHandler.
+func AccessLogHandler(opts httpx.AccessLogOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &accessLogHandler{
+			nextHandler: handler,
+			opts:        opts,
+			random:      rand.Float64,
+		}
+	}
+}
+
+type accessLogHandler struct {
+	nextHandler http.Handler
+	opts        httpx.AccessLogOptions
+	random      func() float64
+}
+
+func (h *accessLogHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	startedAt := time.Now()
+
+	requestID := req.Header.Get(httpx.HeaderRequestID)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	sampled := h.random()*100 < h.opts.BodySampleRate
+
+	requestBody := &countedBuffer{limitedBuffer: limitedBuffer{limit: h.bodyLimit(sampled)}}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &teeBody{ReadCloser: req.Body, w: requestBody}
+	}
+
+	accessRw := &accessLogResponseWriter{
+		statusResponseWriter: statusResponseWriter{ResponseWriter: rw},
+		// body of error responses always kept for error of log
+		body: countedBuffer{limitedBuffer: limitedBuffer{limit: h.opts.BodyLimit}},
+	}
+
+	h.nextHandler.ServeHTTP(accessRw, req.WithContext(metax.ContextWithMeta(req.Context(), metax.ParseMeta(requestID))))
+
+	statusCode := accessRw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	l := &httpx.AccessLog{
+		Kind:          "server",
+		OperationID:   operationOf(rw.Header().Get("X-Meta")),
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		StatusCode:    statusCode,
+		Latency:       time.Since(startedAt),
+		RequestBytes:  requestBody.n,
+		ResponseBytes: accessRw.body.n,
+		RemoteIP:      httpx.ClientIP(req),
+		UserAgent:     req.Header.Get(httpx.HeaderUserAgent),
+		RequestID:     requestID,
+		Header:        httpx.RedactHeader(req.Header, h.opts.RedactHeaders...),
+	}
+
+	if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.IsValid() {
+		l.TraceID = spanContext.TraceID().String()
+		l.SpanID = spanContext.SpanID().String()
+	}
+
+	if statusCode >= http.StatusBadRequest && accessRw.body.Len() > 0 {
+		l.Err = errors.New(httpx.Redact(accessRw.body.String(), h.opts.RedactKeys...))
+	}
+
+	if sampled {
+		l.RequestBody = httpx.Redact(requestBody.String(), h.opts.RedactKeys...)
+		l.ResponseBody = httpx.Redact(accessRw.body.String(), h.opts.RedactKeys...)
+		l.BodyTruncated = requestBody.truncated || accessRw.body.truncated
+	}
+
+	h.opts.Logger.LogAccess(req.Context(), l)
+}
+
+func (h *accessLogHandler) bodyLimit(sampled bool) int {
+	if sampled {
+		return h.opts.BodyLimit
+	}
+	return 0
+}
+
+type accessLogResponseWriter struct {
+	statusResponseWriter
+	body countedBuffer
+}
+
+func (rw *accessLogResponseWriter) Write(data []byte) (int, error) {
+	n, err := rw.statusResponseWriter.Write(data)
+	_, _ = rw.body.Write(data[:n])
+	return n, err
+}
+
+// countedBuffer counts all bytes written, and keeps bytes in limit
+type countedBuffer struct {
+	limitedBuffer
+	n int64
+}
+
+func (b *countedBuffer) Write(p []byte) (int, error) {
+	b.n += int64(len(p))
+	return b.limitedBuffer.Write(p)
+}
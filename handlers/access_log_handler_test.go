@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/metax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogHandler(t *testing.T) {
+	logs := make([]*httpx.AccessLog, 0)
+
+	var handle http.HandlerFunc = func(rw http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+
+		require.NotEmpty(t, metax.MetaFromContext(req.Context()).Get("_id"))
+
+		rw.Header().Set("X-Meta", "srv@1.0.0/CreateItem")
+		if req.URL.Query().Get("fail") != "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"key":"BadRequest"}`))
+			return
+		}
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write(data)
+	}
+
+	handler := AccessLogHandler(httpx.AccessLogOptions{
+		RedactHeaders:  []string{"X-Api-Key"},
+		BodySampleRate: 50,
+		Logger: httpx.AccessLoggerFunc(func(ctx context.Context, l *httpx.AccessLog) {
+			logs = append(logs, l)
+		}),
+	})(handle).(*accessLogHandler)
+
+	serve := func(dice float64, url string, body string) *httpx.AccessLog {
+		handler.random = func() float64 {
+			return dice
+		}
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer xxx")
+		req.Header.Set("X-Api-Key", "key")
+		req.Header.Set(httpx.HeaderRequestID, "req-1")
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+		return logs[len(logs)-1]
+	}
+
+	t.Run("sampled", func(t *testing.T) {
+		l := serve(0.1, "http://example.com/items", `{"name":"x","password":"p"}`)
+
+		require.Equal(t, "server", l.Kind)
+		require.Equal(t, "CreateItem", l.OperationID)
+		require.Equal(t, http.StatusCreated, l.StatusCode)
+		require.Equal(t, "req-1", l.RequestID)
+		require.Equal(t, int64(27), l.RequestBytes)
+		require.Equal(t, int64(27), l.ResponseBytes)
+		require.Equal(t, "***", l.Header.Get("Authorization"))
+		require.Equal(t, "***", l.Header.Get("X-Api-Key"))
+		require.Equal(t, `{"name":"x","password":"***"}`, l.RequestBody)
+		require.Equal(t, `{"name":"x","password":"***"}`, l.ResponseBody)
+		require.NoError(t, l.Err)
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		l := serve(0.9, "http://example.com/items", `{"name":"x"}`)
+
+		require.Equal(t, int64(12), l.ResponseBytes)
+		require.Empty(t, l.RequestBody)
+		require.Empty(t, l.ResponseBody)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		l := serve(0.9, "http://example.com/items?fail=1", `{}`)
+
+		require.Equal(t, http.StatusBadRequest, l.StatusCode)
+		require.EqualError(t, l.Err, `{"key":"BadRequest"}`)
+	})
+}
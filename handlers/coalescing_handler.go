@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type CoalescingOptions struct {
+	// path prefixes of GET routes to coalesce
+	PathPrefixes []string
+	// ttl of micro-cache, when zero, only concurrent requests will be coalesced
+	TTL time.Duration
+	// request headers which response varies by.
+	// requests with credentials like Authorization and Cookie will not be coalesced,
+	// unless header of credentials in VaryHeaders
+	VaryHeaders []string
+	// max entries of micro-cache, least recently used ones will be evicted, default 1024
+	MaxEntries int
+}
+
+func (opts *CoalescingOptions) SetDefaults() {
+	if opts.MaxEntries == 0 {
+		opts.MaxEntries = 1024
+	}
+}
+
+// headers of credentials, responses of them may not be shared between callers
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// CoalescingHandler coalesces concurrent GET requests with same normalized url and vary headers into one,
+// and caches success responses for a short ttl to absorb thundering herds of hot endpoints.
+// Responses with Set-Cookie will not be cached or shared.
+// The shared execution is detached from context cancellation of the first request,
+// so followers not failed when the first one canceled.
+func CoalescingHandler(opts CoalescingOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &coalescingHandler{
+			nextHandler: handler,
+			opts:        opts,
+			cache:       newLRUCache(opts.MaxEntries),
+		}
+	}
+}
+
+type coalescingHandler struct {
+	nextHandler http.Handler
+	opts        CoalescingOptions
+
+	group singleflight.Group
+	mu    sync.Mutex
+	cache *lruCache
+}
+
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (resp *cachedResponse) shareable() bool {
+	return resp.header.Get("Set-Cookie") == ""
+}
+
+func (resp *cachedResponse) cacheable() bool {
+	return resp.shareable() && resp.statusCode >= http.StatusOK && resp.statusCode < http.StatusMultipleChoices
+}
+
+func (resp *cachedResponse) WriteTo(rw http.ResponseWriter) {
+	header := rw.Header()
+	for k, vs := range resp.header {
+		header[k] = append([]string(nil), vs...)
+	}
+	rw.WriteHeader(resp.statusCode)
+	_, _ = rw.Write(resp.body)
+}
+
+func (h *coalescingHandler) match(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for _, key := range credentialHeaders {
+		if _, ok := req.Header[key]; ok && !h.varyBy(key) {
+			return false
+		}
+	}
+	for _, prefix := range h.opts.PathPrefixes {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *coalescingHandler) varyBy(key string) bool {
+	for _, k := range h.opts.VaryHeaders {
+		if http.CanonicalHeaderKey(k) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *coalescingHandler) keyOf(req *http.Request) string {
+	b := strings.Builder{}
+	b.WriteString(req.URL.Path)
+	b.WriteString("?")
+	b.WriteString(req.URL.Query().Encode())
+
+	for _, key := range h.opts.VaryHeaders {
+		b.WriteString("\n")
+		b.WriteString(http.CanonicalHeaderKey(key))
+		b.WriteString(":")
+		b.WriteString(strings.Join(req.Header.Values(key), ","))
+	}
+
+	return b.String()
+}
+
+func (h *coalescingHandler) get(key string) (*cachedResponse, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	v, ok := h.cache.get(key, time.Now())
+	if !ok {
+		return nil, false
+	}
+	return v.(*cachedResponse), true
+}
+
+func (h *coalescingHandler) set(key string, resp *cachedResponse) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.cache.set(key, resp, now, now.Add(h.opts.TTL))
+}
+
+func (h *coalescingHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !h.match(req) {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	key := h.keyOf(req)
+
+	if resp, ok := h.get(key); ok {
+		resp.WriteTo(rw)
+		return
+	}
+
+	executed := false
+
+	v, _, _ := h.group.Do(key, func() (interface{}, error) {
+		executed = true
+
+		recorder := &responseRecorder{header: http.Header{}}
+		h.nextHandler.ServeHTTP(recorder, req.WithContext(withoutCancel{ctx: req.Context()}))
+
+		resp := recorder.Response()
+
+		if h.opts.TTL > 0 && resp.cacheable() {
+			h.set(key, resp)
+		}
+
+		return resp, nil
+	})
+
+	resp := v.(*cachedResponse)
+
+	if !executed && !resp.shareable() {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	resp.WriteTo(rw)
+}
+
+type responseRecorder struct {
+	header        http.Header
+	statusCode    int
+	headerWritten bool
+	body          bytes.Buffer
+}
+
+func (rw *responseRecorder) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseRecorder) WriteHeader(statusCode int) {
+	// informational responses could not be replayed, links of early hints kept in header of final response
+	if rw.headerWritten || informational(statusCode) {
+		return
+	}
+	rw.statusCode = statusCode
+	rw.headerWritten = true
+}
+
+func (rw *responseRecorder) Write(data []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	return rw.body.Write(data)
+}
+
+func (rw *responseRecorder) Response() *cachedResponse {
+	statusCode := rw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	return &cachedResponse{
+		statusCode: statusCode,
+		header:     rw.header.Clone(),
+		body:       rw.body.Bytes(),
+	}
+}
+
+// withoutCancel keeps values of ctx without deadline and cancellation
+type withoutCancel struct {
+	ctx context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (withoutCancel) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancel) Err() error {
+	return nil
+}
+
+func (c withoutCancel) Value(key interface{}) interface{} {
+	return c.ctx.Value(key)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/testify"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalescingHandler(t *testing.T) {
+	calls := int32(0)
+
+	var handle http.HandlerFunc = func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+
+		if req.URL.Path == "/dashboard/login" {
+			http.SetCookie(rw, &http.Cookie{Name: "token", Value: "1"})
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"lang":"` + req.Header.Get("Accept-Language") + `"}`))
+	}
+
+	handler := CoalescingHandler(CoalescingOptions{
+		PathPrefixes: []string{"/dashboard"},
+		TTL:          100 * time.Millisecond,
+		VaryHeaders:  []string{"Accept-Language"},
+	})(handle)
+
+	serve := func(method string, path string, lang string) *testify.MockResponseWriter {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Accept-Language", lang)
+		rw := testify.NewMockResponseWriter()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	concurrent := func(n int, method string, path string, lang string) {
+		statusCodes := make([]int, n)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				statusCodes[i] = serve(method, path, lang).StatusCode
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range statusCodes {
+			require.Equal(t, http.StatusOK, statusCodes[i])
+		}
+	}
+
+	t.Run("concurrent requests should be coalesced", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		concurrent(10, http.MethodGet, "/dashboard/stats?b=1&a=2", "en")
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		t.Run("and served from cache in ttl", func(t *testing.T) {
+			rw := serve(http.MethodGet, "/dashboard/stats?a=2&b=1", "en")
+			require.Equal(t, `{"lang":"en"}`, rw.String())
+			require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+			require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		})
+
+		t.Run("vary headers", func(t *testing.T) {
+			rw := serve(http.MethodGet, "/dashboard/stats?a=2&b=1", "zh")
+			require.Equal(t, `{"lang":"zh"}`, rw.String())
+			require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		})
+
+		t.Run("expired", func(t *testing.T) {
+			time.Sleep(100 * time.Millisecond)
+			serve(http.MethodGet, "/dashboard/stats?a=2&b=1", "en")
+			require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+		})
+	})
+
+	t.Run("responses with cookies should not be shared", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		concurrent(5, http.MethodGet, "/dashboard/login", "en")
+		require.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("unmatched requests should pass through", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		concurrent(5, http.MethodGet, "/other", "en")
+		concurrent(5, http.MethodPost, "/dashboard/stats", "en")
+		require.Equal(t, int32(10), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestCoalescingHandlerWithEarlyHints(t *testing.T) {
+	handler := CoalescingHandler(CoalescingOptions{
+		PathPrefixes: []string{"/"},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		httpx.WriteEarlyHints(rw, httpx.PreloadLink("/style.css", "style"))
+		rw.WriteHeader(http.StatusCreated)
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	rw := testify.NewMockResponseWriter()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusCreated, rw.StatusCode)
+	require.Equal(t, "</style.css>; rel=preload; as=style", rw.Header().Get(httpx.HeaderLink))
+}
+
+func TestCoalescingHandlerWithCredentials(t *testing.T) {
+	calls := int32(0)
+
+	handler := CoalescingHandler(CoalescingOptions{
+		PathPrefixes: []string{"/"},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = rw.Write([]byte(req.Header.Get("Authorization")))
+	}))
+
+	bodies := make([]string, 5)
+
+	wg := sync.WaitGroup{}
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+strconv.Itoa(i))
+			rw := testify.NewMockResponseWriter()
+			handler.ServeHTTP(rw, req)
+			bodies[i] = rw.String()
+		}(i)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	for i := range bodies {
+		require.Equal(t, "Bearer "+strconv.Itoa(i), bodies[i])
+	}
+}
+
+func TestCoalescingHandlerWithCanceledLeader(t *testing.T) {
+	started := make(chan struct{})
+
+	handler := CoalescingHandler(CoalescingOptions{
+		PathPrefixes: []string{"/"},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		if req.Context().Err() != nil {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte("ok"))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/stats", nil)
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+	}()
+
+	<-started
+
+	follower := make(chan *testify.MockResponseWriter)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
+		rw := testify.NewMockResponseWriter()
+		handler.ServeHTTP(rw, req)
+		follower <- rw
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	rw := <-follower
+	require.Equal(t, http.StatusOK, rw.StatusCode)
+	require.Equal(t, "ok", rw.String())
+}
+
+func TestCoalescingHandlerWithMaxEntries(t *testing.T) {
+	calls := int32(0)
+
+	handler := CoalescingHandler(CoalescingOptions{
+		PathPrefixes: []string{"/"},
+		TTL:          time.Minute,
+		MaxEntries:   2,
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path string) {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+	}
+
+	serve("/a")
+	serve("/b")
+	serve("/a")
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// least recently used /b evicted
+	serve("/c")
+	serve("/a")
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	serve("/b")
+	require.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
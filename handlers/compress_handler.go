@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+type CompressOptions struct {
+	// responses smaller than MinSize will not be compressed, default 1024
+	MinSize int
+	// compression level of gzip and deflate, default flate.DefaultCompression
+	Level int
+	// max bytes of decompressed request body, default 10MB,
+	// reading beyond will be failed with 413 status error, against zip bombs
+	MaxDecompressedBytes int64
+}
+
+func (opts *CompressOptions) SetDefaults() {
+	if opts.MinSize == 0 {
+		opts.MinSize = 1024
+	}
+	if opts.Level == 0 {
+		opts.Level = flate.DefaultCompression
+	}
+	if opts.MaxDecompressedBytes == 0 {
+		opts.MaxDecompressedBytes = 10 << 20
+	}
+}
+
+var ErrDecompressedBodyTooLarge = errors.New("decompressed request body too large")
+
+// CompressHandler decompresses gzip or deflate request bodies by Content-Encoding,
+// and compresses responses by Accept-Encoding,
+// so transformers always read and write plain bodies.
+func CompressHandler(opts CompressOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &compressHandler{
+			nextHandler: handler,
+			opts:        opts,
+		}
+	}
+}
+
+type compressHandler struct {
+	nextHandler http.Handler
+	opts        CompressOptions
+}
+
+func (h *compressHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if encoding := strings.ToLower(strings.TrimSpace(req.Header.Get(httpx.HeaderContentEncoding))); encoding != "" && encoding != "identity" && req.Body != nil {
+		body, err := decompressReader(encoding, req.Body)
+		if err != nil {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(err, http.StatusBadRequest, "InvalidContentEncoding"))
+			return
+		}
+		if body == nil {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(errors.Errorf("unsupported Content-Encoding %s", encoding), http.StatusUnsupportedMediaType, "UnsupportedContentEncoding"))
+			return
+		}
+
+		req.Body = &decompressedBody{ReadCloser: body, remaining: h.opts.MaxDecompressedBytes}
+		req.ContentLength = -1
+		req.Header.Del(httpx.HeaderContentEncoding)
+		req.Header.Del(httpx.HeaderContentLength)
+	}
+
+	encoding := negotiateEncoding(req.Header.Get(httpx.HeaderAcceptEncoding))
+	if encoding == "" || req.Method == http.MethodHead {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	compressRw := &compressResponseWriter{
+		ResponseWriter: rw,
+		encoding:       encoding,
+		opts:           &h.opts,
+	}
+	defer compressRw.Close()
+
+	h.nextHandler.ServeHTTP(compressRw, req)
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	for _, c := range r.closers {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decompressedBody fails reading once beyond limit
+type decompressedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	// one more byte to tell body ended or beyond limit
+	if int64(len(p)) > b.remaining+1 {
+		p = p[0 : b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, statuserror.Wrap(ErrDecompressedBodyTooLarge, http.StatusRequestEntityTooLarge, "RequestBodyTooLarge")
+	}
+	return n, err
+}
+
+// decompressReader returns nil without error when encoding is not supported
+func decompressReader(encoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{r, body}}, nil
+	case "deflate":
+		// deflate of http is zlib format (RFC 7230 4.2.2)
+		r, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{r, body}}, nil
+	}
+	return nil, nil
+}
+
+// negotiateEncoding picks gzip or deflate by q values of Accept-Encoding, gzip preferred when equal
+func negotiateEncoding(acceptEncoding string) string {
+	return negotiateEncodingOf(acceptEncoding, "gzip", "deflate")
+}
+
+// negotiateEncodingOf picks one of encodings by q values of Accept-Encoding,
+// the former preferred when equal
+func negotiateEncodingOf(acceptEncoding string, encodings ...string) string {
+	picked, pickedQ, pickedIdx := "", 0.0, len(encodings)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		kv := strings.Split(strings.TrimSpace(part), ";")
+
+		encoding := strings.ToLower(strings.TrimSpace(kv[0]))
+
+		idx := indexOfString(encodings, encoding)
+		if idx < 0 {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range kv[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q > pickedQ || (q == pickedQ && q > 0 && idx < pickedIdx) {
+			picked, pickedQ, pickedIdx = encoding, q, idx
+		}
+	}
+
+	return picked
+}
+
+func indexOfString(list []string, s string) int {
+	for i := range list {
+		if list[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressResponseWriter buffers body until MinSize reached,
+// then compresses rest of body, or writes plain body when closed before.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	opts     *CompressOptions
+
+	statusCode  int
+	wroteHeader bool
+	skip        bool
+	buf         bytes.Buffer
+	w           compressWriter
+}
+
+func (rw *compressResponseWriter) WriteHeader(statusCode int) {
+	if statusCode < http.StatusOK {
+		rw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	if rw.statusCode != 0 {
+		return
+	}
+
+	rw.statusCode = statusCode
+
+	header := rw.Header()
+	header.Add(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
+
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified || header.Get(httpx.HeaderContentEncoding) != "" {
+		rw.skip = true
+	}
+
+	// ranges are of identity body, which could not be compressed
+	if statusCode == http.StatusPartialContent || header.Get(httpx.HeaderContentRange) != "" {
+		rw.skip = true
+	}
+
+	if contentLength := header.Get(httpx.HeaderContentLength); contentLength != "" {
+		if n, err := strconv.Atoi(contentLength); err == nil && n < rw.opts.MinSize {
+			rw.skip = true
+		}
+	}
+
+	if rw.skip {
+		rw.writeHeader()
+	}
+}
+
+func (rw *compressResponseWriter) writeHeader() {
+	if !rw.wroteHeader {
+		rw.wroteHeader = true
+		rw.ResponseWriter.WriteHeader(rw.statusCode)
+	}
+}
+
+func (rw *compressResponseWriter) Write(data []byte) (int, error) {
+	if rw.statusCode == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	if rw.skip {
+		return rw.ResponseWriter.Write(data)
+	}
+
+	if rw.w == nil {
+		if rw.buf.Len()+len(data) < rw.opts.MinSize {
+			return rw.buf.Write(data)
+		}
+		if err := rw.startCompress(); err != nil {
+			return 0, err
+		}
+	}
+
+	return rw.w.Write(data)
+}
+
+func (rw *compressResponseWriter) startCompress() error {
+	header := rw.Header()
+
+	if header.Get(httpx.HeaderContentType) == "" && rw.buf.Len() > 0 {
+		header.Set(httpx.HeaderContentType, http.DetectContentType(rw.buf.Bytes()))
+	}
+
+	header.Set(httpx.HeaderContentEncoding, rw.encoding)
+	header.Del(httpx.HeaderContentLength)
+
+	rw.writeHeader()
+
+	switch rw.encoding {
+	case "gzip":
+		w, err := gzip.NewWriterLevel(rw.ResponseWriter, rw.opts.Level)
+		if err != nil {
+			return err
+		}
+		rw.w = w
+	default:
+		w, err := zlib.NewWriterLevel(rw.ResponseWriter, rw.opts.Level)
+		if err != nil {
+			return err
+		}
+		rw.w = w
+	}
+
+	if rw.buf.Len() > 0 {
+		if _, err := rw.w.Write(rw.buf.Bytes()); err != nil {
+			return err
+		}
+		rw.buf.Reset()
+	}
+
+	return nil
+}
+
+// Hijack for upgrading, should be called before anything written
+func (rw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hijack(rw.ResponseWriter)
+}
+
+// Flush starts compressing for streaming responses even smaller than MinSize
+func (rw *compressResponseWriter) Flush() {
+	if rw.statusCode == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	if !rw.skip {
+		if rw.w == nil {
+			if err := rw.startCompress(); err != nil {
+				return
+			}
+		}
+		_ = rw.w.Flush()
+	}
+
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rw *compressResponseWriter) Close() error {
+	if rw.statusCode == 0 {
+		return nil
+	}
+
+	if rw.w != nil {
+		return rw.w.Close()
+	}
+
+	if !rw.skip {
+		rw.writeHeader()
+		if rw.buf.Len() > 0 {
+			_, err := rw.ResponseWriter.Write(rw.buf.Bytes())
+			return err
+		}
+	}
+
+	return nil
+}
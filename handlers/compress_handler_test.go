@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressHandler(t *testing.T) {
+	large := strings.Repeat(`{"name":"x"}`, 200)
+
+	handler := CompressHandler(CompressOptions{})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write(data)
+	}))
+
+	serve := func(body []byte, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("compress response by gzip", func(t *testing.T) {
+		rw := serve([]byte(large), "Accept-Encoding", "deflate;q=0.5, gzip")
+
+		require.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+		require.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+		require.Less(t, rw.Body.Len(), len(large))
+
+		r, err := gzip.NewReader(rw.Body)
+		require.NoError(t, err)
+		data, _ := ioutil.ReadAll(r)
+		require.Equal(t, large, string(data))
+	})
+
+	t.Run("compress response by deflate", func(t *testing.T) {
+		rw := serve([]byte(large), "Accept-Encoding", "deflate, gzip;q=0")
+
+		require.Equal(t, "deflate", rw.Header().Get("Content-Encoding"))
+
+		r, err := zlib.NewReader(rw.Body)
+		require.NoError(t, err)
+		data, _ := ioutil.ReadAll(r)
+		require.Equal(t, large, string(data))
+	})
+
+	t.Run("small response in plain", func(t *testing.T) {
+		rw := serve([]byte(`{}`), "Accept-Encoding", "gzip")
+
+		require.Equal(t, "", rw.Header().Get("Content-Encoding"))
+		require.Equal(t, `{}`, rw.Body.String())
+	})
+
+	t.Run("range response in plain", func(t *testing.T) {
+		data := []byte(large)
+
+		handler := CompressHandler(CompressOptions{})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			http.ServeContent(rw, req, "data.json", time.Time{}, bytes.NewReader(data))
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("Range", "bytes=0-1999")
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusPartialContent, rw.Code)
+		require.Equal(t, "", rw.Header().Get("Content-Encoding"))
+		require.Equal(t, "2000", rw.Header().Get("Content-Length"))
+		require.Equal(t, "bytes 0-1999/2400", rw.Header().Get("Content-Range"))
+		require.Equal(t, large[0:2000], rw.Body.String())
+	})
+
+	t.Run("decompress request", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		w := gzip.NewWriter(buf)
+		_, _ = w.Write([]byte(`{"name":"x"}`))
+		_ = w.Close()
+
+		rw := serve(buf.Bytes(), "Content-Encoding", "gzip")
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, `{"name":"x"}`, rw.Body.String())
+	})
+
+	t.Run("decompressed request beyond limit", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		w := gzip.NewWriter(buf)
+		_, _ = w.Write(bytes.Repeat([]byte("0"), 1<<20))
+		_ = w.Close()
+
+		var readErr error
+
+		h := CompressHandler(CompressOptions{MaxDecompressedBytes: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, readErr = ioutil.ReadAll(req.Body)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/", buf)
+		req.Header.Set("Content-Encoding", "gzip")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.True(t, errors.Is(readErr, ErrDecompressedBodyTooLarge))
+		require.Equal(t, http.StatusRequestEntityTooLarge, statuserror.FromErr(readErr).StatusCode())
+	})
+
+	t.Run("invalid request encoding", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, serve([]byte("x"), "Content-Encoding", "gzip").Code)
+		require.Equal(t, http.StatusUnsupportedMediaType, serve([]byte("x"), "Content-Encoding", "br").Code)
+	})
+}
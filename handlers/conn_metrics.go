@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/go-courier/httptransport/internal/metricsx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConnMetrics exports listener-level connection metrics by ConnState hooks,
+// to distinguish capacity issues from handler latency.
+// Could be used as ServerModifier of HttpTransport.
+func ConnMetrics(registerer prometheus.Registerer) func(server *http.Server) error {
+	metrics := &connMetrics{
+		accepted: metricsx.RegisterOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "http_server_connections_accepted_total",
+			Help: "Accepted connections.",
+		})).(prometheus.Counter),
+		hijacked: metricsx.RegisterOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "http_server_connections_hijacked_total",
+			Help: "Hijacked connections, like websocket.",
+		})).(prometheus.Counter),
+		tlsHandshakeErrors: metricsx.RegisterOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "http_server_tls_handshake_errors_total",
+			Help: "TLS handshake errors.",
+		})).(prometheus.Counter),
+		connections: metricsx.RegisterOrExisting(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_server_connections",
+			Help: "Open connections by state.",
+		}, []string{"state"})).(*prometheus.GaugeVec),
+		states: map[net.Conn]http.ConnState{},
+	}
+
+	return func(server *http.Server) error {
+		connState := server.ConnState
+
+		server.ConnState = func(conn net.Conn, state http.ConnState) {
+			metrics.track(conn, state)
+			if connState != nil {
+				connState(conn, state)
+			}
+		}
+
+		errorLog := server.ErrorLog
+		if errorLog == nil {
+			errorLog = log.New(os.Stderr, "", log.LstdFlags)
+		}
+
+		// tls handshake errors only be reported to error log of http.Server
+		server.ErrorLog = log.New(&tlsHandshakeErrorCounter{writer: errorLog.Writer(), counter: metrics.tlsHandshakeErrors}, errorLog.Prefix(), errorLog.Flags())
+
+		return nil
+	}
+}
+
+type connMetrics struct {
+	accepted           prometheus.Counter
+	hijacked           prometheus.Counter
+	tlsHandshakeErrors prometheus.Counter
+	connections        *prometheus.GaugeVec
+
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+}
+
+func (m *connMetrics) track(conn net.Conn, state http.ConnState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if prev, ok := m.states[conn]; ok {
+		m.connections.WithLabelValues(prev.String()).Dec()
+	}
+
+	switch state {
+	case http.StateNew:
+		m.accepted.Inc()
+	case http.StateHijacked:
+		m.hijacked.Inc()
+	}
+
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(m.states, conn)
+	default:
+		m.states[conn] = state
+		m.connections.WithLabelValues(state.String()).Inc()
+	}
+}
+
+type tlsHandshakeErrorCounter struct {
+	writer  io.Writer
+	counter prometheus.Counter
+}
+
+func (w *tlsHandshakeErrorCounter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("TLS handshake error")) {
+		w.counter.Inc()
+	}
+	return w.writer.Write(p)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+
+	require.NoError(t, ConnMetrics(registry)(srv.Config))
+
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	defer client.CloseIdleConnections()
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// plain http to tls listener
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	require.NoError(t, err)
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	_, _ = ioutil.ReadAll(conn)
+	conn.Close()
+
+	expected := `
+# HELP http_server_connections Open connections by state.
+# TYPE http_server_connections gauge
+http_server_connections{state="active"} 0
+http_server_connections{state="idle"} 1
+http_server_connections{state="new"} 0
+# HELP http_server_connections_accepted_total Accepted connections.
+# TYPE http_server_connections_accepted_total counter
+http_server_connections_accepted_total 2
+# HELP http_server_tls_handshake_errors_total TLS handshake errors.
+# TYPE http_server_tls_handshake_errors_total counter
+http_server_tls_handshake_errors_total 1
+`
+
+	require.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(registry, strings.NewReader(expected),
+			"http_server_connections", "http_server_connections_accepted_total", "http_server_tls_handshake_errors_total",
+		) == nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+type IdempotencyOptions struct {
+	Store IdempotencyStore
+	// methods of requests to dedup, default POST and PATCH
+	Methods []string
+	// max bytes of request body to fingerprint, default 1MB,
+	// larger requests will be rejected with 413
+	MaxBodyBytes int64
+	// scope of keys, like principal of auth, so one caller could not replay response of another by same key.
+	// default hash of Authorization, all anonymous requests in same scope.
+	ScopeOf func(req *http.Request) string
+}
+
+func (o *IdempotencyOptions) SetDefaults() {
+	if o.Store == nil {
+		o.Store = NewMemoryIdempotencyStore(0, 0)
+	}
+	if len(o.Methods) == 0 {
+		o.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if o.MaxBodyBytes == 0 {
+		o.MaxBodyBytes = 1 << 20
+	}
+	if o.ScopeOf == nil {
+		o.ScopeOf = IdempotencyScopeByAuthorization
+	}
+}
+
+// IdempotencyScopeByAuthorization scopes keys by hash of Authorization, credentials not kept in store
+func IdempotencyScopeByAuthorization(req *http.Request) string {
+	authorization := req.Header.Get(httpx.HeaderAuthorization)
+	if authorization == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:])
+}
+
+// IdempotencyHandler dedups requests with same Idempotency-Key in scope of caller,
+// replays of completed request will get the original response with header Idempotent-Replayed,
+// replays during processing will be rejected with 409,
+// and same key of different method, path or body will be rejected with 422.
+// 5xx responses not recorded, so request could be retried.
+func IdempotencyHandler(opts IdempotencyOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &idempotencyHandler{
+			nextHandler: handler,
+			opts:        opts,
+		}
+	}
+}
+
+type idempotencyHandler struct {
+	nextHandler http.Handler
+	opts        IdempotencyOptions
+}
+
+func (h *idempotencyHandler) match(req *http.Request) bool {
+	for _, method := range h.opts.Methods {
+		if strings.EqualFold(method, req.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *idempotencyHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get(httpx.HeaderIdempotencyKey)
+
+	if key == "" || !h.match(req) {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	if scope := h.opts.ScopeOf(req); scope != "" {
+		key = scope + ":" + key
+	}
+
+	fingerprint, statusErr := h.fingerprint(req)
+	if statusErr != nil {
+		httpx.WriteStatusErr(rw, statusErr)
+		return
+	}
+
+	resp, reserved := h.opts.Store.Begin(key)
+	if !reserved {
+		if resp == nil {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(errors.Errorf("request of %s is being processed", httpx.HeaderIdempotencyKey), http.StatusConflict, "IdempotentRequestInProcessing"))
+			return
+		}
+		if resp.Fingerprint != fingerprint {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(errors.Errorf("%s is used by different request", httpx.HeaderIdempotencyKey), http.StatusUnprocessableEntity, "IdempotencyKeyReused"))
+			return
+		}
+
+		rw.Header().Set(httpx.HeaderIdempotentReplayed, "true")
+		(&cachedResponse{statusCode: resp.StatusCode, header: resp.Header, body: resp.Body}).WriteTo(rw)
+		return
+	}
+
+	completed := false
+
+	defer func() {
+		if !completed {
+			// panic or 5xx
+			h.opts.Store.Abort(key)
+		}
+	}()
+
+	recorder := &responseRecorder{header: http.Header{}}
+	h.nextHandler.ServeHTTP(recorder, req)
+
+	recorded := recorder.Response()
+
+	if recorded.statusCode < http.StatusInternalServerError {
+		h.opts.Store.Complete(key, &IdempotentResponse{
+			Fingerprint: fingerprint,
+			StatusCode:  recorded.statusCode,
+			Header:      recorded.header,
+			Body:        recorded.body,
+		})
+		completed = true
+	}
+
+	recorded.WriteTo(rw)
+}
+
+// fingerprint of method, path, query and body, body will be rewound
+func (h *idempotencyHandler) fingerprint(req *http.Request) (string, *statuserror.StatusErr) {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+
+	if req.Body != nil && req.Body != http.NoBody {
+		// one more byte to tell body beyond limit
+		data, err := ioutil.ReadAll(io.LimitReader(req.Body, h.opts.MaxBodyBytes+1))
+		req.Body.Close()
+		if err != nil {
+			return "", statuserror.Wrap(err, http.StatusBadRequest, "BadRequest")
+		}
+		if int64(len(data)) > h.opts.MaxBodyBytes {
+			return "", statuserror.Wrap(errors.Errorf("limit %d bytes", h.opts.MaxBodyBytes), http.StatusRequestEntityTooLarge, "RequestBodyTooLarge")
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		hash.Write(data)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyHandler(t *testing.T) {
+	calls := int32(0)
+	started, block := make(chan struct{}), make(chan struct{})
+
+	handler := IdempotencyHandler(IdempotencyOptions{})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		data, _ := ioutil.ReadAll(req.Body)
+
+		switch string(data) {
+		case "block":
+			close(started)
+			<-block
+		case "fail":
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		rw.Header().Set("X-Call", strconv.Itoa(int(n)))
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write(data)
+	}))
+
+	do := func(method string, key string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(httpx.HeaderIdempotencyKey, key)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("replay original response", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		rw := do(http.MethodPost, "1", "order")
+		require.Equal(t, http.StatusCreated, rw.Code)
+		require.Empty(t, rw.Header().Get(httpx.HeaderIdempotentReplayed))
+
+		rw = do(http.MethodPost, "1", "order")
+		require.Equal(t, http.StatusCreated, rw.Code)
+		require.Equal(t, "order", rw.Body.String())
+		require.Equal(t, "1", rw.Header().Get("X-Call"))
+		require.Equal(t, "true", rw.Header().Get(httpx.HeaderIdempotentReplayed))
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("same key of different request", func(t *testing.T) {
+		rw := do(http.MethodPost, "1", "other")
+		require.Equal(t, http.StatusUnprocessableEntity, rw.Code)
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), statusErr))
+		require.Equal(t, "IdempotencyKeyReused", statusErr.Key)
+	})
+
+	t.Run("keys scoped by authorization", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		doWithAuthorization := func(authorization string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("order"))
+			req.Header.Set(httpx.HeaderIdempotencyKey, "1")
+			req.Header.Set(httpx.HeaderAuthorization, authorization)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			return rw
+		}
+
+		require.Empty(t, doWithAuthorization("Bearer a").Header().Get(httpx.HeaderIdempotentReplayed))
+		require.Empty(t, doWithAuthorization("Bearer b").Header().Get(httpx.HeaderIdempotentReplayed))
+		require.Equal(t, "true", doWithAuthorization("Bearer a").Header().Get(httpx.HeaderIdempotentReplayed))
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("conflict when processing", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- do(http.MethodPost, "2", "block")
+		}()
+
+		<-started
+		require.Equal(t, http.StatusConflict, do(http.MethodPost, "2", "block").Code)
+
+		close(block)
+		require.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("5xx not recorded", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		require.Equal(t, http.StatusInternalServerError, do(http.MethodPost, "3", "fail").Code)
+		require.Equal(t, http.StatusInternalServerError, do(http.MethodPost, "3", "fail").Code)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("without key or method not matched", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		do(http.MethodPost, "", "order")
+		do(http.MethodPost, "", "order")
+		do(http.MethodPut, "4", "order")
+		do(http.MethodPut, "4", "order")
+		require.Equal(t, int32(4), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		rw := do(http.MethodPost, "5", strings.Repeat("x", 1<<20+1))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+		require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Minute, 2)
+
+	complete := func(key string) {
+		_, reserved := store.Begin(key)
+		require.True(t, reserved)
+		store.Complete(key, &IdempotentResponse{Fingerprint: key})
+	}
+
+	complete("a")
+	complete("b")
+
+	resp, reserved := store.Begin("a")
+	require.False(t, reserved)
+	require.Equal(t, "a", resp.Fingerprint)
+
+	t.Run("least recently used evicted beyond max entries", func(t *testing.T) {
+		complete("c")
+
+		_, reserved := store.Begin("b")
+		require.True(t, reserved)
+		store.Abort("b")
+	})
+
+	t.Run("processing keys not evicted", func(t *testing.T) {
+		_, reserved := store.Begin("d")
+		require.True(t, reserved)
+
+		complete("e")
+		complete("f")
+
+		_, reserved = store.Begin("d")
+		require.False(t, reserved)
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyStore records responses of requests by Idempotency-Key for IdempotencyHandler,
+// should be shared by all instances of service, like redis, to dedup across them.
+type IdempotencyStore interface {
+	// Begin reserves key for processing, reserved false when key being processed or completed,
+	// and recorded response returned when completed.
+	Begin(key string) (resp *IdempotentResponse, reserved bool)
+	// Complete records response of key reserved
+	Complete(key string, resp *IdempotentResponse)
+	// Abort releases key reserved without response recorded, then request could be retried
+	Abort(key string)
+}
+
+// IdempotentResponse is entry of IdempotencyStore, should be treated as immutable
+type IdempotentResponse struct {
+	// fingerprint of request, same key of different request will be rejected
+	Fingerprint string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+}
+
+// NewMemoryIdempotencyStore creates in-memory IdempotencyStore for single instance,
+// keys completed will be expired after ttl, default 24h,
+// and least recently used ones will be evicted beyond maxEntries, default 10000
+func NewMemoryIdempotencyStore(ttl time.Duration, maxEntries int) *MemoryIdempotencyStore {
+	if ttl == 0 {
+		ttl = 24 * time.Hour
+	}
+	if maxEntries == 0 {
+		maxEntries = 10000
+	}
+	return &MemoryIdempotencyStore{
+		ttl:        ttl,
+		processing: map[string]bool{},
+		completed:  newLRUCache(maxEntries),
+	}
+}
+
+type MemoryIdempotencyStore struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	// keys reserved, released by Complete or Abort
+	processing map[string]bool
+	completed  *lruCache
+}
+
+func (s *MemoryIdempotencyStore) Begin(key string) (*IdempotentResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.processing[key] {
+		return nil, false
+	}
+
+	if v, ok := s.completed.get(key, time.Now()); ok {
+		return v.(*IdempotentResponse), false
+	}
+
+	s.processing[key] = true
+	return nil, true
+}
+
+func (s *MemoryIdempotencyStore) Complete(key string, resp *IdempotentResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	delete(s.processing, key)
+	s.completed.set(key, resp, now, now.Add(s.ttl))
+}
+
+func (s *MemoryIdempotencyStore) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.processing, key)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -11,18 +13,27 @@ import (
 	"github.com/go-courier/metax"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func LogHandler() func(handler http.Handler) http.Handler {
+	return LogHandlerWithLevel(nil)
+}
+
+// LogHandlerWithLevel logs requests by level returned, which could be changed at runtime,
+// like HttpTransport.RuntimeConfig.LogLevel. Level of header x-log-level wins when valid.
+func LogHandlerWithLevel(level func() logr.Level) func(handler http.Handler) http.Handler {
 	return func(handler http.Handler) http.Handler {
 		return &loggerHandler{
 			nextHandler: handler,
+			level:       level,
 		}
 	}
 }
 
 type loggerHandler struct {
 	nextHandler http.Handler
+	level       func() logr.Level
 }
 
 type LoggerResponseWriter struct {
@@ -53,7 +64,27 @@ func (rw *LoggerResponseWriter) Write(data []byte) (int, error) {
 	return rw.rw.Write(data)
 }
 
+func (rw *LoggerResponseWriter) Flush() {
+	if flusher, ok := rw.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rw *LoggerResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, bufrw, err := hijack(rw.rw)
+	if err == nil && !rw.headerWritten {
+		rw.statusCode = http.StatusSwitchingProtocols
+		rw.headerWritten = true
+	}
+	return conn, bufrw, err
+}
+
 func (rw *LoggerResponseWriter) writeHeader(statusCode int) {
+	// informational responses like 103 Early Hints could be sent before final response
+	if informational(statusCode) {
+		rw.rw.WriteHeader(statusCode)
+		return
+	}
 	if !rw.headerWritten {
 		rw.rw.WriteHeader(statusCode)
 		rw.statusCode = statusCode
@@ -73,7 +104,10 @@ func (h *loggerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	logger := logr.FromContext(req.Context())
 
-	level, _ := logr.ParseLevel(strings.ToLower(req.Header.Get("x-log-level")))
+	level, err := logr.ParseLevel(strings.ToLower(req.Header.Get("x-log-level")))
+	if err != nil && h.level != nil {
+		level = h.level()
+	}
 	if level == logr.PanicLevel {
 		level = logr.TraceLevel
 	}
@@ -93,6 +127,14 @@ func (h *loggerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			"status", loggerRw.statusCode,
 		}
 
+		// works when OtelHandler before LogHandler
+		if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.IsValid() {
+			fields = append(fields,
+				"traceID", spanContext.TraceID().String(),
+				"spanID", spanContext.SpanID().String(),
+			)
+		}
+
 		if loggerRw.err != nil {
 			if loggerRw.statusCode >= http.StatusInternalServerError {
 				if level >= logr.ErrorLevel {
@@ -112,3 +154,8 @@ func (h *loggerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	h.nextHandler.ServeHTTP(loggerRw, req.WithContext(metax.ContextWithMeta(req.Context(), metax.ParseMeta(requestID))))
 }
+
+// informational returns true for 1xx statuses sent before final response, except 101 Switching Protocols
+func informational(statusCode int) bool {
+	return statusCode >= 100 && statusCode < http.StatusOK && statusCode != http.StatusSwitchingProtocols
+}
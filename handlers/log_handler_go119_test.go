@@ -0,0 +1,46 @@
+//go:build go1.19
+// +build go1.19
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogHandlerWithEarlyHints(t *testing.T) {
+	statusCodes := make(chan int, 1)
+
+	srv := httptest.NewServer(LogHandler()(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		httpx.WriteEarlyHints(rw, httpx.PreloadLink("/style.css", "style"))
+		rw.WriteHeader(http.StatusCreated)
+
+		statusCodes <- rw.(*LoggerResponseWriter).statusCode
+	})))
+	defer srv.Close()
+
+	codes := make([]int, 0)
+
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			codes = append(codes, code)
+			return nil
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, []int{http.StatusEarlyHints}, codes)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, http.StatusCreated, <-statusCodes)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"container/list"
+	"time"
+)
+
+// lruCache is least recently used cache with expiry, not safe for concurrent use,
+// callers should guard it by their own lock.
+type lruCache struct {
+	maxEntries int
+	ll         *list.List
+	elements   map[string]*list.Element
+}
+
+type lruCacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLRUCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		elements:   map[string]*list.Element{},
+	}
+}
+
+func (c *lruCache) get(key string, now time.Time) (interface{}, bool) {
+	e, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruCacheEntry)
+	if now.After(entry.expiresAt) {
+		c.remove(e)
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return entry.value, true
+}
+
+func (c *lruCache) set(key string, value interface{}, now time.Time, expiresAt time.Time) {
+	if e, ok := c.elements[key]; ok {
+		entry := e.Value.(*lruCacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.ll.MoveToFront(e)
+	} else {
+		c.elements[key] = c.ll.PushFront(&lruCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	}
+
+	// expired entries at back are dropped by the way, then least recently used ones beyond max entries
+	for e := c.ll.Back(); e != nil && c.ll.Len() > 1; e = c.ll.Back() {
+		if c.ll.Len() <= c.maxEntries && !now.After(e.Value.(*lruCacheEntry).expiresAt) {
+			break
+		}
+		c.remove(e)
+	}
+}
+
+func (c *lruCache) delete(key string) {
+	if e, ok := c.elements[key]; ok {
+		c.remove(e)
+	}
+}
+
+func (c *lruCache) len() int {
+	return c.ll.Len()
+}
+
+func (c *lruCache) remove(e *list.Element) {
+	c.ll.Remove(e)
+	delete(c.elements, e.Value.(*lruCacheEntry).key)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-courier/httptransport/internal/metricsx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type serverMetrics struct {
+	duration *prometheus.HistogramVec
+	inflight *prometheus.GaugeVec
+	requests *prometheus.CounterVec
+}
+
+// MetricsHandler records duration, in-flight requests and status codes of inbound requests
+// labeled by operation id and method.
+// Operation id is resolved from X-Meta header written by HttpRouteHandler.
+// Labels are bounded, not by Host or unknown methods from clients, to avoid unlimited series.
+func MetricsHandler(registerer prometheus.Registerer) func(handler http.Handler) http.Handler {
+	metrics := &serverMetrics{
+		duration: metricsx.RegisterOrExisting(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_server_request_duration_seconds",
+			Help:    "Duration of inbound http requests.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "method", "code"})).(*prometheus.HistogramVec),
+		inflight: metricsx.RegisterOrExisting(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_server_requests_in_flight",
+			Help: "Inbound http requests in flight.",
+		}, []string{"method"})).(*prometheus.GaugeVec),
+		requests: metricsx.RegisterOrExisting(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_server_requests_total",
+			Help: "Inbound http requests by status code.",
+		}, []string{"operation", "method", "code"})).(*prometheus.CounterVec),
+	}
+
+	return func(handler http.Handler) http.Handler {
+		return &metricsHandler{
+			nextHandler: handler,
+			metrics:     metrics,
+		}
+	}
+}
+
+type metricsHandler struct {
+	nextHandler http.Handler
+	metrics     *serverMetrics
+}
+
+func (h *metricsHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	method := methodOf(req.Method)
+
+	// operation is unknown before routing
+	inflight := h.metrics.inflight.WithLabelValues(method)
+	inflight.Inc()
+	defer inflight.Dec()
+
+	startedAt := time.Now()
+
+	statusRw := &statusResponseWriter{ResponseWriter: rw}
+
+	h.nextHandler.ServeHTTP(statusRw, req)
+
+	statusCode := statusRw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	operation := operationOf(rw.Header().Get("X-Meta"))
+	code := strconv.Itoa(statusCode)
+
+	h.metrics.duration.WithLabelValues(operation, method, code).Observe(time.Since(startedAt).Seconds())
+	h.metrics.requests.WithLabelValues(operation, method, code).Inc()
+}
+
+// methodOf returns method, or OTHER when not known by net/http
+func methodOf(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
+// X-Meta: service@version/OperationID
+func operationOf(meta string) string {
+	if i := strings.LastIndex(meta, "/"); i >= 0 {
+		return meta[i+1:]
+	}
+	return meta
+}
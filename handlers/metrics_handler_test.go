@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/httptransport/testify"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	var handle http.HandlerFunc = func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Meta", "srv@1.0.0/GetItem")
+		rw.WriteHeader(http.StatusNotFound)
+	}
+
+	handler := MetricsHandler(registry)(handle)
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+	}
+
+	// hosts and methods of clients should not create new series
+	for _, host := range []string{"a.example.com", "b.example.com"} {
+		req, _ := http.NewRequest("X-"+host, "http://"+host+"/items/1", nil)
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+	}
+
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP http_server_requests_total Inbound http requests by status code.
+# TYPE http_server_requests_total counter
+http_server_requests_total{code="404",method="GET",operation="GetItem"} 3
+http_server_requests_total{code="404",method="OTHER",operation="GetItem"} 2
+`), "http_server_requests_total"))
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/go-courier/httptransport"
+
+type OtelOptions struct {
+	// default otel.GetTracerProvider()
+	TracerProvider trace.TracerProvider
+	// default W3C trace context
+	Propagator propagation.TextMapPropagator
+}
+
+func (o *OtelOptions) SetDefaults() {
+	if o.TracerProvider == nil {
+		o.TracerProvider = otel.GetTracerProvider()
+	}
+	if o.Propagator == nil {
+		o.Propagator = propagation.TraceContext{}
+	}
+}
+
+// OtelHandler creates server span for each request with parent extracted from traceparent header,
+// span will be renamed by operation id in HttpRouteHandler.
+func OtelHandler(opts OtelOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &otelHandler{
+			nextHandler: handler,
+			tracer:      opts.TracerProvider.Tracer(instrumentationName),
+			propagator:  opts.Propagator,
+		}
+	}
+}
+
+type otelHandler struct {
+	nextHandler http.Handler
+	tracer      trace.Tracer
+	propagator  propagation.TextMapPropagator
+}
+
+func (h *otelHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := h.propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+	ctx, span := h.tracer.Start(
+		ctx,
+		"HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPTargetKey.String(req.URL.Path),
+			semconv.HTTPHostKey.String(req.Host),
+		),
+	)
+	defer span.End()
+
+	statusRw := &statusResponseWriter{ResponseWriter: rw}
+
+	h.nextHandler.ServeHTTP(statusRw, req.WithContext(ctx))
+
+	statusCode := statusRw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(statusCode))
+	// only 5xx as error of server span
+	if statusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *statusResponseWriter) WriteHeader(statusCode int) {
+	// skip informational responses like 103 Early Hints
+	if rw.statusCode == 0 && statusCode >= http.StatusOK {
+		rw.statusCode = statusCode
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *statusResponseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rw *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, bufrw, err := hijack(rw.ResponseWriter)
+	if err == nil && rw.statusCode == 0 {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, bufrw, err
+}
+
+// hijack of connection for upgrading like websocket
+func hijack(rw http.ResponseWriter) (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+func (rw *statusResponseWriter) Write(data []byte) (int, error) {
+	if rw.statusCode == 0 {
+		rw.statusCode = http.StatusOK
+	}
+	return rw.ResponseWriter.Write(data)
+}
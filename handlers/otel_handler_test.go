@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/testify"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOtelHandler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var handle http.HandlerFunc = func(rw http.ResponseWriter, req *http.Request) {
+		trace.SpanFromContext(req.Context()).SetName("GetItem")
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+
+	handler := OtelHandler(OtelOptions{TracerProvider: tp})(handle)
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/items/1", nil)
+	propagation.TraceContext{}.Inject(trace.ContextWithRemoteSpanContext(context.Background(), parent), propagation.HeaderCarrier(req.Header))
+
+	rw := testify.NewMockResponseWriter()
+	handler.ServeHTTP(rw, req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	require.Equal(t, "GetItem", span.Name())
+	require.Equal(t, trace.SpanKindServer, span.SpanKind())
+	require.Equal(t, parent.TraceID(), span.SpanContext().TraceID())
+	require.Equal(t, parent.SpanID(), span.Parent().SpanID())
+	require.Equal(t, codes.Error, span.Status().Code)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+type PrecompressedOptions struct {
+	// extensions of pre-compressed variants by encoding in Accept-Encoding,
+	// default br as .br and gzip as .gz
+	Extensions map[string]string
+	// encodings preferred in order when q values equal, default br, gzip
+	Encodings []string
+}
+
+func (opts *PrecompressedOptions) SetDefaults() {
+	if opts.Extensions == nil {
+		opts.Extensions = map[string]string{
+			"br":   ".br",
+			"gzip": ".gz",
+		}
+	}
+	if opts.Encodings == nil {
+		opts.Encodings = []string{"br", "gzip"}
+	}
+}
+
+// PrecompressedFileServer serves static files of root like http.FileServer,
+// but serves pre-compressed variant like app.js.br or app.js.gz when accepted by Accept-Encoding,
+// with Content-Type of the original file and Vary: Accept-Encoding,
+// so large bundles never be compressed per request.
+//
+// could be mounted as middleware, or used in operator by httpx.ResponseWriterFunc.
+func PrecompressedFileServer(root http.FileSystem, opts PrecompressedOptions) http.Handler {
+	opts.SetDefaults()
+
+	return &precompressedFileServer{
+		root:       root,
+		fileServer: http.FileServer(root),
+		opts:       opts,
+	}
+}
+
+type precompressedFileServer struct {
+	root       http.FileSystem
+	fileServer http.Handler
+	opts       PrecompressedOptions
+}
+
+func (s *precompressedFileServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	name := req.URL.Path
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	name = path.Clean(name)
+
+	f, info, ok := s.open(name)
+	if !ok {
+		// not found, directories and redirects
+		s.fileServer.ServeHTTP(rw, req)
+		return
+	}
+	defer f.Close()
+
+	rw.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
+
+	available := make([]string, 0, len(s.opts.Encodings))
+	for _, encoding := range s.opts.Encodings {
+		if _, ok := s.opts.Extensions[encoding]; ok {
+			available = append(available, encoding)
+		}
+	}
+
+	for _, encoding := range s.acceptedEncodings(req.Header.Get(httpx.HeaderAcceptEncoding), available) {
+		variant, variantInfo, ok := s.open(name + s.opts.Extensions[encoding])
+		if !ok {
+			continue
+		}
+		defer variant.Close()
+
+		if err := setContentType(rw.Header(), name, f); err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rw.Header().Set(httpx.HeaderContentEncoding, encoding)
+		http.ServeContent(rw, req, name, variantInfo.ModTime(), variant)
+		return
+	}
+
+	http.ServeContent(rw, req, name, info.ModTime(), f)
+}
+
+// acceptedEncodings returns encodings ordered by negotiation
+func (s *precompressedFileServer) acceptedEncodings(acceptEncoding string, encodings []string) []string {
+	accepted := make([]string, 0, len(encodings))
+
+	for len(encodings) > 0 {
+		encoding := negotiateEncodingOf(acceptEncoding, encodings...)
+		if encoding == "" {
+			break
+		}
+		accepted = append(accepted, encoding)
+
+		rest := make([]string, 0, len(encodings)-1)
+		for _, e := range encodings {
+			if e != encoding {
+				rest = append(rest, e)
+			}
+		}
+		encodings = rest
+	}
+
+	return accepted
+}
+
+func (s *precompressedFileServer) open(name string) (http.File, os.FileInfo, bool) {
+	f, err := s.root.Open(name)
+	if err != nil {
+		return nil, nil, false
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		_ = f.Close()
+		return nil, nil, false
+	}
+	return f, info, true
+}
+
+// setContentType by ext of the original file, or sniffed from its content
+func setContentType(header http.Header, name string, f io.ReadSeeker) error {
+	if header.Get(httpx.HeaderContentType) != "" {
+		return nil
+	}
+
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		header.Set(httpx.HeaderContentType, ctype)
+		return nil
+	}
+
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(f, buf)
+	header.Set(httpx.HeaderContentType, http.DetectContentType(buf[:n]))
+
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecompressedFileServer(t *testing.T) {
+	fs := fstest.MapFS{
+		"app.js":       {Data: []byte("console.log(1)")},
+		"app.js.br":    {Data: []byte("br")},
+		"app.js.gz":    {Data: []byte("gz")},
+		"style.css":    {Data: []byte("body{}")},
+		"style.css.gz": {Data: []byte("gz")},
+		"dir/a.txt":    {Data: []byte("a")},
+	}
+
+	handler := PrecompressedFileServer(http.FS(fs), PrecompressedOptions{})
+
+	serve := func(path string, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("brotli preferred", func(t *testing.T) {
+		rw := serve("/app.js", "gzip, deflate, br")
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "br", rw.Header().Get("Content-Encoding"))
+		require.Contains(t, rw.Header().Get("Content-Type"), "javascript")
+		require.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+		require.Equal(t, "br", rw.Body.String())
+	})
+
+	t.Run("by q values", func(t *testing.T) {
+		rw := serve("/app.js", "br;q=0.5, gzip")
+		require.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+		require.Equal(t, "gz", rw.Body.String())
+	})
+
+	t.Run("fallback to existed variant", func(t *testing.T) {
+		rw := serve("/style.css", "br, gzip")
+		require.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+		require.Contains(t, rw.Header().Get("Content-Type"), "text/css")
+	})
+
+	t.Run("plain when not accepted", func(t *testing.T) {
+		rw := serve("/app.js", "br;q=0, identity")
+		require.Empty(t, rw.Header().Get("Content-Encoding"))
+		require.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+		require.Equal(t, "console.log(1)", rw.Body.String())
+	})
+
+	t.Run("plain without variants", func(t *testing.T) {
+		rw := serve("/dir/a.txt", "gzip")
+		require.Empty(t, rw.Header().Get("Content-Encoding"))
+		require.Equal(t, "a", rw.Body.String())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, serve("/missing.js", "gzip").Code)
+	})
+}
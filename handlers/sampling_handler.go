@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+// Sample of redacted request/response pair
+type Sample struct {
+	Operation           string
+	Method              string
+	Path                string
+	StatusCode          int
+	RequestContentType  string
+	RequestBody         string
+	ResponseContentType string
+	ResponseBody        string
+	// request or response body truncated by limit
+	Truncated bool
+	SampledAt time.Time
+}
+
+// SampleSink should not block, Sample is called after response written
+type SampleSink interface {
+	Sample(ctx context.Context, sample *Sample)
+}
+
+type SampleSinkFunc func(ctx context.Context, sample *Sample)
+
+func (fn SampleSinkFunc) Sample(ctx context.Context, sample *Sample) {
+	fn(ctx, sample)
+}
+
+type SamplingOptions struct {
+	Sink SampleSink
+	// percentage of requests to sample, 0-100
+	Rate float64
+	// percentage by operation id, overwrite Rate
+	RateByOperation map[string]float64
+	// max bytes of each body to capture, default 4096
+	Limit int
+	// keys of json or form which values will be redacted, default password, secret, token
+	RedactKeys []string
+}
+
+func (opts *SamplingOptions) SetDefaults() {
+	if opts.Limit == 0 {
+		opts.Limit = 4096
+	}
+	if opts.RedactKeys == nil {
+		opts.RedactKeys = []string{"password", "secret", "token"}
+	}
+}
+
+func (opts *SamplingOptions) rateOf(operation string) float64 {
+	if rate, ok := opts.RateByOperation[operation]; ok {
+		return rate
+	}
+	return opts.Rate
+}
+
+func (opts *SamplingOptions) maxRate() float64 {
+	max := opts.Rate
+	for _, rate := range opts.RateByOperation {
+		if rate > max {
+			max = rate
+		}
+	}
+	return max
+}
+
+// SamplingHandler captures redacted request/response pairs of sampled requests per route into sink,
+// to drive schema-usage analytics and dead-field detection.
+// Operation id is resolved from X-Meta header written by HttpRouteHandler.
+func SamplingHandler(opts SamplingOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &samplingHandler{
+			nextHandler: handler,
+			opts:        opts,
+			random:      rand.Float64,
+		}
+	}
+}
+
+type samplingHandler struct {
+	nextHandler http.Handler
+	opts        SamplingOptions
+	random      func() float64
+}
+
+func (h *samplingHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// operation is unknown before routing,
+	// capture by max rate first, then check rate of operation with the same dice.
+	dice := h.random() * 100
+
+	if h.opts.Sink == nil || dice >= h.opts.maxRate() {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	var requestBody *limitedBuffer
+
+	if req.Body != nil {
+		requestBody = &limitedBuffer{limit: h.opts.Limit}
+		req.Body = &teeBody{ReadCloser: req.Body, w: requestBody}
+	}
+
+	sampleRw := &samplingResponseWriter{
+		statusResponseWriter: statusResponseWriter{ResponseWriter: rw},
+		body:                 limitedBuffer{limit: h.opts.Limit},
+	}
+
+	h.nextHandler.ServeHTTP(sampleRw, req)
+
+	operation := operationOf(rw.Header().Get("X-Meta"))
+	if dice >= h.opts.rateOf(operation) {
+		return
+	}
+
+	statusCode := sampleRw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	sample := &Sample{
+		Operation:           operation,
+		Method:              req.Method,
+		Path:                req.URL.Path,
+		StatusCode:          statusCode,
+		RequestContentType:  req.Header.Get(httpx.HeaderContentType),
+		ResponseContentType: rw.Header().Get(httpx.HeaderContentType),
+		ResponseBody:        httpx.Redact(sampleRw.body.String(), h.opts.RedactKeys...),
+		Truncated:           sampleRw.body.truncated,
+		SampledAt:           time.Now(),
+	}
+
+	if requestBody != nil {
+		sample.RequestBody = httpx.Redact(requestBody.String(), h.opts.RedactKeys...)
+		sample.Truncated = sample.Truncated || requestBody.truncated
+	}
+
+	h.opts.Sink.Sample(req.Context(), sample)
+}
+
+type samplingResponseWriter struct {
+	statusResponseWriter
+	body limitedBuffer
+}
+
+func (rw *samplingResponseWriter) Write(data []byte) (int, error) {
+	_, _ = rw.body.Write(data)
+	return rw.statusResponseWriter.Write(data)
+}
+
+type teeBody struct {
+	io.ReadCloser
+	w io.Writer
+}
+
+func (r *teeBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = r.w.Write(p[:n])
+	}
+	return n, err
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rest := b.limit - b.Len(); rest < len(p) {
+		b.truncated = true
+		if rest > 0 {
+			b.Buffer.Write(p[:rest])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/testify"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	samples := make([]*Sample, 0)
+
+	var handle http.HandlerFunc = func(rw http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+
+		if req.URL.Path == "/login" {
+			rw.Header().Set("X-Meta", "srv@1.0.0/Login")
+		} else {
+			rw.Header().Set("X-Meta", "srv@1.0.0/CreateItem")
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write(data)
+	}
+
+	handler := SamplingHandler(SamplingOptions{
+		Rate: 50,
+		RateByOperation: map[string]float64{
+			"Login": 0,
+		},
+		Limit: 32,
+		Sink: SampleSinkFunc(func(ctx context.Context, sample *Sample) {
+			samples = append(samples, sample)
+		}),
+	})(handle).(*samplingHandler)
+
+	serve := func(dice float64, path string, body string) {
+		handler.random = func() float64 {
+			return dice
+		}
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com"+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(testify.NewMockResponseWriter(), req)
+	}
+
+	serve(0.1, "/items", `{"name":"x","password":"p"}`)
+	serve(0.9, "/items", `{"name":"x"}`)
+	serve(0.1, "/login", `{"name":"x"}`)
+	serve(0.1, "/items", `{"name":"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"}`)
+
+	require.Len(t, samples, 2)
+
+	require.Equal(t, "CreateItem", samples[0].Operation)
+	require.Equal(t, http.StatusCreated, samples[0].StatusCode)
+	require.Equal(t, `{"name":"x","password":"***"}`, samples[0].RequestBody)
+	require.Equal(t, `{"name":"x","password":"***"}`, samples[0].ResponseBody)
+	require.False(t, samples[0].Truncated)
+
+	require.True(t, samples[1].Truncated)
+	require.Len(t, samples[1].RequestBody, 32)
+}
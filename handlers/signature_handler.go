@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+type VerifySignatureOptions struct {
+	Keys httpx.SignatureKeyProvider
+	// max clock skew between timestamp of signature and server, default 5m
+	MaxSkew time.Duration
+	// headers must be signed, like host
+	RequiredHeaders []string
+	// max bytes of request body buffered for verifying, default 10MB,
+	// larger requests will be rejected with 413 before verified
+	MaxBodyBytes int64
+}
+
+func (opts *VerifySignatureOptions) SetDefaults() {
+	if opts.MaxSkew == 0 {
+		opts.MaxSkew = 5 * time.Minute
+	}
+	opts.RequiredHeaders = httpx.NormalizeSignedHeaders(opts.RequiredHeaders)
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = 10 << 20
+	}
+}
+
+func invalidSignature(err error) *statuserror.StatusErr {
+	return statuserror.Wrap(err, http.StatusUnauthorized, "InvalidSignature")
+}
+
+// VerifySignatureHandler verifies X-Signature signed by roundtrippers.NewSignRoundTripper,
+// requests without valid signature will be rejected with 401,
+// key id of verified signature could be read by httpx.SignatureKeyIDFromContext.
+func VerifySignatureHandler(opts VerifySignatureOptions) func(handler http.Handler) http.Handler {
+	opts.SetDefaults()
+
+	return func(handler http.Handler) http.Handler {
+		return &verifySignatureHandler{
+			nextHandler: handler,
+			opts:        opts,
+		}
+	}
+}
+
+type verifySignatureHandler struct {
+	nextHandler http.Handler
+	opts        VerifySignatureOptions
+}
+
+func (h *verifySignatureHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s := req.Header.Get(httpx.HeaderSignature)
+	if s == "" {
+		httpx.WriteStatusErr(rw, invalidSignature(errors.Errorf("missing %s", httpx.HeaderSignature)))
+		return
+	}
+
+	sig, err := httpx.ParseSignature(s)
+	if err != nil {
+		httpx.WriteStatusErr(rw, invalidSignature(err))
+		return
+	}
+
+	if skew := time.Since(time.Unix(sig.Timestamp, 0)); skew > h.opts.MaxSkew || skew < -h.opts.MaxSkew {
+		httpx.WriteStatusErr(rw, invalidSignature(errors.New("signature expired")))
+		return
+	}
+
+	for _, name := range h.opts.RequiredHeaders {
+		if !containsString(sig.Headers, name) {
+			httpx.WriteStatusErr(rw, invalidSignature(errors.Errorf("header %s must be signed", name)))
+			return
+		}
+	}
+
+	key, err := h.opts.Keys.SignatureKey(req.Context(), sig.KeyID)
+	if err != nil {
+		httpx.WriteStatusErr(rw, invalidSignature(err))
+		return
+	}
+
+	data := make([]byte, 0)
+
+	if req.Body != nil {
+		// one more byte to tell body beyond limit
+		data, err = ioutil.ReadAll(io.LimitReader(req.Body, h.opts.MaxBodyBytes+1))
+		req.Body.Close()
+		if err != nil {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(err, http.StatusBadRequest, "BadRequest"))
+			return
+		}
+		if int64(len(data)) > h.opts.MaxBodyBytes {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(errors.Errorf("limit %d bytes", h.opts.MaxBodyBytes), http.StatusRequestEntityTooLarge, "RequestBodyTooLarge"))
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	if err := sig.Verify(key, req, httpx.BodyHash(data)); err != nil {
+		httpx.WriteStatusErr(rw, invalidSignature(err))
+		return
+	}
+
+	h.nextHandler.ServeHTTP(rw, req.WithContext(httpx.ContextWithSignatureKeyID(req.Context(), sig.KeyID)))
+}
+
+func containsString(list []string, s string) bool {
+	for i := range list {
+		if list[i] == s {
+			return true
+		}
+	}
+	return false
+}
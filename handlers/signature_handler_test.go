@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignatureHandler(t *testing.T) {
+	key := []byte("secret")
+
+	handler := VerifySignatureHandler(VerifySignatureOptions{
+		Keys: httpx.SignatureKeys{"svc-a": key},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+		_, _ = rw.Write(data)
+	}))
+
+	newSignedRequest := func(body string, timestamp time.Time) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/users/1?x=1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		sig := &httpx.Signature{
+			Algorithm: "hmac-sha256",
+			KeyID:     "svc-a",
+			Timestamp: timestamp.Unix(),
+			Headers:   []string{"content-type", "host"},
+		}
+		sig.Signature, _ = sig.Sum(key, req, httpx.BodyHash([]byte(body)))
+		req.Header.Set(httpx.HeaderSignature, sig.String())
+		return req
+	}
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("verified and body restored", func(t *testing.T) {
+		rw := serve(newSignedRequest(`{"name":"x"}`, time.Now()))
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, `{"name":"x"}`, rw.Body.String())
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		rw := serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), statusErr))
+		require.Equal(t, "InvalidSignature", statusErr.Key)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := newSignedRequest(`{"name":"x"}`, time.Now())
+		req.Body = ioutil.NopCloser(bytes.NewBufferString(`{"name":"y"}`))
+		require.Equal(t, http.StatusUnauthorized, serve(req).Code)
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		req := newSignedRequest(`{}`, time.Now())
+		req.Header.Set("Content-Type", "text/plain")
+		require.Equal(t, http.StatusUnauthorized, serve(req).Code)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		rw := serve(newSignedRequest(`{}`, time.Now().Add(-time.Hour)))
+		require.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+
+	t.Run("required header not signed", func(t *testing.T) {
+		h := VerifySignatureHandler(VerifySignatureOptions{
+			Keys:            httpx.SignatureKeys{"svc-a": key},
+			RequiredHeaders: []string{"X-Tenant"},
+		})(http.NotFoundHandler())
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(`{}`, time.Now()))
+		require.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		h := VerifySignatureHandler(VerifySignatureOptions{
+			Keys:         httpx.SignatureKeys{"svc-a": key},
+			MaxBodyBytes: 8,
+		})(http.NotFoundHandler())
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(`{"name":"x"}`, time.Now()))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-courier/metax"
 	"github.com/go-courier/reflectx/typesutil"
 	"github.com/go-courier/statuserror"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func NewHttpRouteHandler(serviceMeta *ServiceMeta, httpRoute *HttpRouteMeta, requestTransformerMgr *RequestTransformerMgr) *HttpRouteHandler {
@@ -34,12 +35,22 @@ func NewHttpRouteHandler(serviceMeta *ServiceMeta, httpRoute *HttpRouteMeta, req
 		requestTransformers[i] = rt
 	}
 
+	injectFields := make([][]injectField, len(operatorFactories))
+	for i := range operatorFactories {
+		injectFields[i] = injectFieldsOf(operatorFactories[i].Type)
+	}
+
 	return &HttpRouteHandler{
 		RequestTransformerMgr: requestTransformerMgr,
 		HttpRouteMeta:         httpRoute,
 
 		serviceMeta:         serviceMeta,
 		requestTransformers: requestTransformers,
+		decodeFallback:      httpRoute.DecodeFallback(),
+		policies:            httpRoute.Policies(),
+		pathParamChecks:     pathParamChecksOf(requestTransformers),
+		contentTypes:        contentTypeCheckOf(requestTransformers),
+		injectFields:        injectFields,
 	}
 }
 
@@ -48,6 +59,17 @@ type HttpRouteHandler struct {
 	*HttpRouteMeta
 	serviceMeta         *ServiceMeta
 	requestTransformers []*RequestTransformer
+	decodeFallback      transformers.FallbackChain
+	policies            RoutePolicies
+	policyMiddlewares   []RoutePolicyMiddleware
+	pathParamChecks     []pathParamCheck
+	contentTypes        []string
+	workerPool          *WorkerPool
+	runtimeConfig       *RuntimeConfig
+	profile             *TransportProfile
+	middlewares         []HttpMiddleware
+	injector            Injector
+	injectFields        [][]injectField
 }
 
 type contextKeyOperationID int
@@ -72,12 +94,34 @@ func OperatorFactoryFromContext(ctx context.Context) *courier.OperatorFactory {
 }
 
 func (handler *HttpRouteHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	operationID := handler.OperatorFactoryWithRouteMetas[len(handler.OperatorFactoryWithRouteMetas)-1].ID
+	handler.serveWithMiddlewares(rw, r, handler.serveInWorkerPool)
+}
+
+func (handler *HttpRouteHandler) operationID() string {
+	return handler.OperatorFactoryWithRouteMetas[len(handler.OperatorFactoryWithRouteMetas)-1].ID
+}
+
+func (handler *HttpRouteHandler) serveInWorkerPool(rw http.ResponseWriter, r *http.Request) {
+	if handler.workerPool != nil {
+		if err := handler.workerPool.Do(r.Context(), func() {
+			handler.serveHTTP(rw, r)
+		}); err != nil {
+			handler.writeErr(rw, r, err)
+		}
+		return
+	}
+
+	handler.serveHTTP(rw, r)
+}
+
+func (handler *HttpRouteHandler) serveHTTP(rw http.ResponseWriter, r *http.Request) {
+	operationID := handler.operationID()
 
 	ctx := r.Context()
 	ctx = ContextWithHttpRequest(ctx, r)
 	ctx = ContextWithServiceMeta(ctx, *handler.serviceMeta)
 	ctx = ContextWithOperationID(ctx, operationID)
+	ctx = ContextWithEarlyHints(ctx, earlyHintsOf(rw))
 
 	spanName := handler.serviceMeta.String() + "/" + operationID
 
@@ -87,11 +131,81 @@ func (handler *HttpRouteHandler) ServeHTTP(rw http.ResponseWriter, r *http.Reque
 
 	rw.Header().Set("X-Meta", spanName)
 
+	// rename span created by handlers.OtelHandler
+	trace.SpanFromContext(ctx).SetName(operationID)
+
+	var guardedBody *guardedBody
+
+	if maxBodyBytes, maxJSONDepth := handler.bodyLimits(); (maxBodyBytes > 0 || maxJSONDepth > 0) && r.Body != nil && r.Body != http.NoBody {
+		body, err := guardBody(r, maxBodyBytes, maxJSONDepth)
+		if err != nil {
+			handler.writeErr(rw, r, err)
+			return
+		}
+		guardedBody = body
+		r.Body = guardedBody
+	}
+
+	var teedBody *teeReadCloser
+
+	tee := RequestBodyTeeFromContext(ctx)
+	if tee != nil && r.Body != nil {
+		teedBody = tee.Tee(r.Body)
+		r.Body = teedBody
+	}
+
 	requestInfo := NewRequestInfo(r)
+	requestInfo.DecodeFallback = handler.decodeFallback
+
+	if fieldMask := requestInfo.FieldMask(); fieldMask != nil {
+		ctx = ContextWithFieldMask(ctx, fieldMask)
+	}
+
+	policies := handler.policies
+
+	if handler.runtimeConfig != nil {
+		p, timeout := handler.runtimeConfig.routePolicies(policies)
+		policies = p
+
+		if timeout > 0 {
+			c, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			ctx = c
+		}
+	}
+
+	for _, policyMiddleware := range handler.policyMiddlewares {
+		c, err := policyMiddleware(ctx, rw, r, policies)
+		if err != nil {
+			handler.writeErr(rw, r, err)
+			return
+		}
+		ctx = c
+	}
+
+	if err := checkPathParams(handler.pathParamChecks, requestInfo, transformers.ErrorLocationOf(handler.RequestTransformerMgr.TransformerMgr)); err != nil {
+		handler.writeErr(rw, r, err)
+		return
+	}
+
+	// fallback chain decodes missing or unknown Content-Type
+	if len(handler.decodeFallback) == 0 {
+		if err := checkContentType(rw, handler.contentTypes, r); err != nil {
+			handler.writeErr(rw, r, err)
+			return
+		}
+	}
 
 	for i := range handler.OperatorFactoryWithRouteMetas {
 		opFactory := handler.OperatorFactoryWithRouteMetas[i]
 
+		if !policies.PublicAccess {
+			if err := CheckScopes(ctx, opFactory.RequiredScopes...); err != nil {
+				handler.writeErr(rw, r, err)
+				return
+			}
+		}
+
 		if opFactory.NoOutput {
 			continue
 		}
@@ -100,10 +214,23 @@ func (handler *HttpRouteHandler) ServeHTTP(rw http.ResponseWriter, r *http.Reque
 
 		ctx = ContextWithOperatorFactory(ctx, opFactory.OperatorFactory)
 
+		if fields := handler.injectFields[i]; len(fields) > 0 {
+			if err := inject(ctx, handler.injector, fields, op); err != nil {
+				handler.writeErr(rw, r, err)
+				return
+			}
+		}
+
 		rt := handler.requestTransformers[i]
 		if rt != nil {
 			err := rt.DecodeFrom(requestInfo, opFactory.OperatorFactory, op)
 			if err != nil {
+				if guardedBody != nil && guardedBody.err != nil {
+					err = guardedBody.err
+				}
+				if tee != nil {
+					err = tee.Attach(err, teedBody)
+				}
 				handler.writeErr(rw, r, err)
 				return
 			}
@@ -130,6 +257,19 @@ func (handler *HttpRouteHandler) ServeHTTP(rw http.ResponseWriter, r *http.Reque
 	}
 }
 
+// checkInjector makes sure Injector set when operators of route have fields tagged `inject`
+func (handler *HttpRouteHandler) checkInjector() error {
+	if handler.injector != nil {
+		return nil
+	}
+	for i, fields := range handler.injectFields {
+		if len(fields) > 0 {
+			return errors.Errorf("missing Injector for field %s tagged `inject` of %s", fields[0].FieldName, handler.OperatorFactoryWithRouteMetas[i].Type)
+		}
+	}
+	return nil
+}
+
 func (handler *HttpRouteHandler) resolveTransformer(response *httpx.Response) (string, httpx.Encode, error) {
 	transformer, err := handler.TransformerMgr.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(response.Value)), transformers.TransformerOption{
 		MIME: response.ContentType,
@@ -145,7 +285,18 @@ func (handler *HttpRouteHandler) resolveTransformer(response *httpx.Response) (s
 }
 
 func (handler *HttpRouteHandler) writeResp(rw http.ResponseWriter, r *http.Request, resp interface{}) {
-	err := httpx.ResponseFrom(resp).WriteTo(rw, r, handler.resolveTransformer)
+	response := httpx.ResponseFrom(resp)
+
+	if err := handler.negotiateContentType(rw, r, response); err != nil {
+		handler.writeErr(rw, r, err)
+		return
+	}
+
+	if _, ok := response.Value.(httpx.WebSocketHandler); ok {
+		r = r.WithContext(httpx.ContextWithWebSocketCodec(r.Context(), transformers.NewWebSocketCodec(handler.TransformerMgr, "")))
+	}
+
+	err := response.WriteTo(rw, r, handler.resolveTransformer)
 	if err != nil {
 		handler.writeErr(rw, r, err)
 	}
@@ -164,7 +315,13 @@ func (handler *HttpRouteHandler) writeErr(rw http.ResponseWriter, r *http.Reques
 			rwe.WriteErrer(err)
 		}
 
-		resp.Value = err
+		localized, lang := LocalizeStatusErr(resp.Cause(), err, r.Header.Get(httpx.HeaderAcceptLanguage))
+		if lang != "" {
+			rw.Header().Set(httpx.HeaderContentLanguage, lang)
+			rw.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptLanguage)
+		}
+
+		resp.Value = localized
 	}
 
 	errForWrite := resp.WriteTo(rw, r, handler.resolveTransformer)
@@ -3,13 +3,19 @@ package httptransport_test
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"net/http/httputil"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/__examples__/server/cmd/app/routes"
+	"github.com/go-courier/httptransport/client"
+	"github.com/go-courier/httptransport/httpx"
 	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/stretchr/testify/require"
 
 	"github.com/go-courier/httptransport"
@@ -294,3 +300,99 @@ X-Meta: service-test@1.0.0/GetByID
 `, string(rw.MustDumpResponse()))
 	})
 }
+
+type WatchProgress struct {
+	httpx.MethodGet
+}
+
+func (WatchProgress) Output(ctx context.Context) (interface{}, error) {
+	events := make(chan *transformers.Event)
+
+	go func() {
+		defer close(events)
+		for i := 1; i <= 2; i++ {
+			select {
+			case events <- &transformers.Event{ID: strconv.Itoa(i), Data: "ok"}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return transformers.EventStream(events), nil
+}
+
+func TestHttpRouteHandlerWithEventStream(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/"))
+	rootRouter.Register(courier.NewRouter(WatchProgress{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	srv := httptest.NewServer(httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	ids := make([]string, 0)
+	err = transformers.ReadEvents(resp.Body, func(e *transformers.Event) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, ids)
+}
+
+type SubscribeTopic struct {
+	httpx.MethodGet
+	Topic string `name:"topic" in:"query"`
+}
+
+type TopicEvent struct {
+	Topic string `json:"topic"`
+	Seq   int    `json:"seq"`
+}
+
+func (req SubscribeTopic) Output(ctx context.Context) (interface{}, error) {
+	return httpx.WebSocketHandler(func(ctx context.Context, conn *httpx.WebSocketConn) error {
+		for {
+			event := TopicEvent{}
+			if err := conn.ReadValue(&event); err != nil {
+				return nil
+			}
+			event.Topic = req.Topic
+			if err := conn.WriteValue(event); err != nil {
+				return err
+			}
+		}
+	}), nil
+}
+
+func TestHttpRouteHandlerWithWebSocket(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/"))
+	rootRouter.Register(courier.NewRouter(SubscribeTopic{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	srv := httptest.NewServer(httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+
+	c := &client.Client{Host: u.Hostname(), Port: uint16(port)}
+	c.SetDefaults()
+
+	conn, err := c.DialWebSocket(context.Background(), &SubscribeTopic{Topic: "orders"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, conn.WriteValue(TopicEvent{Seq: i}))
+
+		event := TopicEvent{}
+		require.NoError(t, conn.ReadValue(&event))
+		require.Equal(t, TopicEvent{Topic: "orders", Seq: i}, event)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -80,6 +81,39 @@ func NewOperatorFactoryWithRouteMeta(op courier.Operator, last bool) *OperatorFa
 		m.Path = pathDescriber.Path()
 	}
 
+	if requiredScopesDescriber, ok := m.Operator.(RequiredScopesDescriber); ok {
+		m.RequiredScopes = requiredScopesDescriber.RequiredScopes()
+	}
+
+	if decodeFallbackDescriber, ok := m.Operator.(DecodeFallbackDescriber); ok {
+		m.DecodeFallback = decodeFallbackDescriber.DecodeFallback()
+	}
+
+	if workerPoolDescriber, ok := m.Operator.(WorkerPoolDescriber); ok {
+		m.WorkerPool = workerPoolDescriber.WorkerPool()
+	}
+
+	if publicAccessDescriber, ok := m.Operator.(PublicAccessDescriber); ok {
+		publicAccess := publicAccessDescriber.PublicAccess()
+		m.PublicAccess = &publicAccess
+	}
+
+	if rateLimitDescriber, ok := m.Operator.(RateLimitDescriber); ok {
+		m.RateLimit.Limit, m.RateLimit.Window = rateLimitDescriber.RateLimit()
+	}
+
+	if corsDescriber, ok := m.Operator.(CORSDescriber); ok {
+		m.CORSAllowedOrigins = corsDescriber.CORSAllowedOrigins()
+	}
+
+	if maxBodyBytesDescriber, ok := m.Operator.(MaxBodyBytesDescriber); ok {
+		m.MaxBodyBytes = maxBodyBytesDescriber.MaxBodyBytes()
+	}
+
+	if pathAliasesDescriber, ok := m.Operator.(PathAliasesDescriber); ok {
+		m.PathAliases = pathAliasesDescriber.PathAliases()
+	}
+
 	return m
 }
 
@@ -90,6 +124,22 @@ type RouteMeta struct {
 	BasePath   string
 	Summary    string
 	Deprecated bool
+	// scopes required by an operator
+	RequiredScopes []string
+	// transformers for decoding body of unknown or missing Content-Type
+	DecodeFallback transformers.FallbackChain
+	// name of worker pool to run route in
+	WorkerPool string
+	// accessible without auth when declared true
+	PublicAccess *bool
+	// max requests of each client in window
+	RateLimit RateLimit
+	// origins allowed of cross-origin requests
+	CORSAllowedOrigins []string
+	// max bytes of request body
+	MaxBodyBytes int64
+	// old paths of route renamed
+	PathAliases []string
 }
 
 type OperatorFactoryWithRouteMeta struct {
@@ -115,6 +165,18 @@ type HttpRouteMeta struct {
 	OperatorFactoryWithRouteMetas []*OperatorFactoryWithRouteMeta
 }
 
+// nearestRouteMeta resolves route meta declared by describers of operators of route,
+// the nearest one to the last operator of route wins,
+// so declarations of route group could be overwritten by inner groups or the operator itself.
+func (route *HttpRouteMeta) nearestRouteMeta(declared func(m *RouteMeta) bool) *RouteMeta {
+	for i := len(route.OperatorFactoryWithRouteMetas) - 1; i >= 0; i-- {
+		if m := &route.OperatorFactoryWithRouteMetas[i].RouteMeta; declared(m) {
+			return m
+		}
+	}
+	return nil
+}
+
 func (route *HttpRouteMeta) OperatorNames() string {
 	operatorTypeNames := make([]string, 0)
 
@@ -160,6 +222,10 @@ func (route *HttpRouteMeta) Log() {
 
 	courierPrintln(firstLine)
 	courierPrintln("\t%s", route.OperatorNames())
+
+	for _, alias := range last.PathAliases {
+		courierPrintln("\t%s %s", color.WhiteString("alias"), reHttpRouterPath.ReplaceAllString(alias, "/{$1}"))
+	}
 }
 
 var reHttpRouterPath = regexp.MustCompile("/:([^/]+)")
@@ -2,7 +2,10 @@ package httptransport
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,7 +19,9 @@ import (
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/handlers"
+	"github.com/go-courier/httptransport/httpx"
 	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/statuserror"
 	"github.com/go-courier/validator"
 	"github.com/julienschmidt/httprouter"
 )
@@ -59,7 +64,71 @@ type HttpTransport struct {
 	CertFile string
 	KeyFile  string
 
-	httpRouter *httprouter.Router
+	// port of admin listener for health, pprof and route debugging, disabled when 0
+	// admin handlers never registered on public router, so they will not be in openapi spec
+	AdminPort int
+	// extra handlers mounted on admin listener, like /metrics
+	AdminHandlers map[string]http.Handler
+
+	// ips or cidrs of gateways, which X-Forwarded-* headers trusted for resolving external url of Location.
+	TrustedProxies httpx.TrustedProxies
+
+	// debug mode, tee raw request bodies (bounded, redacted) into error when decoding or validation failed
+	DebugRequestBodyTee *RequestBodyTee
+
+	// worker pools for routes with WorkerPoolDescriber, by name
+	WorkerPools map[string]*WorkerPool
+
+	// json of SchemaReport will be written into when serving
+	SchemaReportWriter io.Writer
+
+	// run in order before accepting traffic, serving aborted when any failed
+	WarmUps []WarmUp
+
+	// consume policies declared by operators in order, like AuthPolicy, RateLimitPolicy and CORSPolicy
+	RoutePolicies []RoutePolicyMiddleware
+
+	// decoding of empty values of query
+	QueryOptions QueryOptions
+
+	// log level, request dumping, timeouts and rate limits of routes changed without restart,
+	// by /debug/config of admin listener or programmatically
+	RuntimeConfig *RuntimeConfig
+
+	// run in order around handling of each route after resolved, the first is outermost, before RoutePolicies.
+	// operation id and meta of route could be got by OperationIDFromContext and HttpRouteMetaFromContext,
+	// for auth, audit logging or request id without fake operators. see Use
+	RouteMiddlewares []HttpMiddleware
+
+	// timeout of reading request headers against slowloris, default 10s, overwritten by Profile when set
+	ReadHeaderTimeout time.Duration
+	// max requests in handling, requests beyond rejected with 503 and Retry-After, no limit when 0
+	MaxConcurrentRequests int
+
+	// grace period of draining in-flight requests when shutting down, default 10s
+	ShutdownTimeout time.Duration
+	// run in order when shutting down, see Shutdown and OnShutdown
+	ShutdownHooks []ShutdownHook
+
+	// hardening of body limits, timeouts, security headers, tls and decoding, like StrictProfile()
+	Profile *TransportProfile
+
+	// populates fields tagged `inject` of operators before request decoded, like NewContainer()
+	Injector Injector
+
+	// writes requests rejected by net/http before handling, like oversized headers or uri and malformed requests,
+	// default json of status error instead of raw text.
+	// when served with CertFile and KeyFile, only plain http requests sent to tls listener could be written
+	ProtocolErrorWriter ProtocolErrorWriter
+
+	// serving of old paths of renamed routes declared by PathAliasesDescriber, redirected by default
+	PathAliases PathAliasOptions
+
+	readiness        Readiness
+	lifecycle        lifecycle
+	concurrencyGuard *concurrencyGuard
+	httpRouter       *httprouter.Router
+	routeMetas       []*HttpRouteMeta
 }
 
 type ServerModifier func(server *http.Server) error
@@ -75,17 +144,83 @@ func (t *HttpTransport) SetDefaults() {
 		t.TransformerMgr = transformers.TransformerMgrDefault
 	}
 
+	if t.RuntimeConfig == nil {
+		t.RuntimeConfig = NewRuntimeConfig()
+		t.RuntimeConfig.SetRequestDump(t.DebugRequestBodyTee != nil)
+	}
+
 	if t.Middlewares == nil {
-		t.Middlewares = []HttpMiddleware{handlers.LogHandler()}
+		t.Middlewares = []HttpMiddleware{handlers.LogHandlerWithLevel(t.RuntimeConfig.LogLevel)}
 	}
 
 	if t.Port == 0 {
 		t.Port = 80
 	}
+
+	if t.ShutdownTimeout == 0 {
+		t.ShutdownTimeout = 10 * time.Second
+	}
+
+	if t.ReadHeaderTimeout == 0 {
+		t.ReadHeaderTimeout = 10 * time.Second
+	}
+
+	if t.MaxConcurrentRequests > 0 && t.concurrencyGuard == nil {
+		t.concurrencyGuard = newConcurrencyGuard(t.MaxConcurrentRequests)
+	}
+
+	if t.DebugRequestBodyTee != nil {
+		t.DebugRequestBodyTee.SetDefaults()
+	}
+
+	if t.ProtocolErrorWriter == nil {
+		source := t.ServiceMeta.String()
+
+		t.ProtocolErrorWriter = func(rw http.ResponseWriter, statusErr *statuserror.StatusErr) {
+			httpx.WriteStatusErr(rw, statusErr.AppendSource(source))
+		}
+	}
+}
+
+var requestBodyTeeDefault = func() *RequestBodyTee {
+	tee := &RequestBodyTee{}
+	tee.SetDefaults()
+	return tee
+}()
+
+// requestBodyTee returns tee when request dumping enabled
+func (t *HttpTransport) requestBodyTee() *RequestBodyTee {
+	if t.RuntimeConfig == nil {
+		return t.DebugRequestBodyTee
+	}
+	if !t.RuntimeConfig.RequestDump() {
+		return nil
+	}
+	if t.DebugRequestBodyTee != nil {
+		return t.DebugRequestBodyTee
+	}
+	return requestBodyTeeDefault
 }
 
 func (t *HttpTransport) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if t.concurrencyGuard != nil {
+		release, ok := t.concurrencyGuard.acquire(w)
+		if !ok {
+			return
+		}
+		defer release()
+	}
+
 	requestOverride(req)
+	if tee := t.requestBodyTee(); tee != nil {
+		req = req.WithContext(ContextWithRequestBodyTee(req.Context(), tee))
+	}
+	if t.TrustedProxies != nil {
+		req = req.WithContext(httpx.ContextWithTrustedProxies(req.Context(), t.TrustedProxies))
+	}
+	if t.Profile != nil {
+		t.Profile.setSecurityHeaders(w)
+	}
 	t.httpRouter.ServeHTTP(w, req)
 }
 
@@ -115,6 +250,11 @@ func (t *HttpTransport) ServeContext(ctx context.Context, router *courier.Router
 
 	srv.Addr = fmt.Sprintf(":%d", t.Port)
 	srv.Handler = MiddlewareChain(t.Middlewares...)(t)
+	srv.ReadHeaderTimeout = t.ReadHeaderTimeout
+
+	if t.Profile != nil {
+		t.Profile.modifyServer(srv)
+	}
 
 	for i := range t.ServerModifiers {
 		if err := t.ServerModifiers[i](srv); err != nil {
@@ -122,11 +262,40 @@ func (t *HttpTransport) ServeContext(ctx context.Context, router *courier.Router
 		}
 	}
 
+	var adminSrv *http.Server
+
+	// admin listener first, /readyz is not ready until warm up passed
+	if t.AdminPort > 0 {
+		adminSrv = t.newAdminServer()
+		t.lifecycle.serve(adminSrv)
+
+		go func() {
+			courierPrintln("%s admin listen on %s", t.ServiceMeta, adminSrv.Addr)
+
+			if err := adminSrv.ListenAndServe(); err != nil {
+				if err == http.ErrServerClosed {
+					logger.Error(err)
+				} else {
+					logger.Fatal(err)
+				}
+			}
+		}()
+	}
+
+	if err := t.warmUp(ctx, srv.Handler); err != nil {
+		if adminSrv != nil {
+			_ = adminSrv.Close()
+		}
+		return err
+	}
+
+	t.lifecycle.serve(srv)
+
 	go func() {
 		courierPrintln("%s listen on %s", t.ServiceMeta, srv.Addr)
 
 		if t.CertFile != "" && t.KeyFile != "" {
-			if err := srv.ListenAndServeTLS(t.CertFile, t.KeyFile); err != nil {
+			if err := t.listenAndServeTLS(srv, t.CertFile, t.KeyFile); err != nil {
 				if err == http.ErrServerClosed {
 					logger.Error(err)
 				} else {
@@ -136,7 +305,7 @@ func (t *HttpTransport) ServeContext(ctx context.Context, router *courier.Router
 			return
 		}
 
-		if err := srv.ListenAndServe(); err != nil {
+		if err := t.listenAndServe(srv); err != nil {
 			if err == http.ErrServerClosed {
 				logger.Error(err)
 			} else {
@@ -147,16 +316,40 @@ func (t *HttpTransport) ServeContext(ctx context.Context, router *courier.Router
 
 	stopCh := make(chan os.Signal, 1)
 	signal.Notify(stopCh, os.Interrupt, syscall.SIGTERM)
-	<-stopCh
-
-	timeout := 10 * time.Second
+	defer signal.Stop(stopCh)
+
+	select {
+	case <-stopCh:
+	case <-ctx.Done():
+	case <-t.lifecycle.doneCh():
+		// shut down by Shutdown
+		return t.lifecycle.err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	shutdownCtx, cancel := context.WithTimeout(logr.WithLogger(context.Background(), logger), t.ShutdownTimeout)
 	defer cancel()
 
-	logger.Info("shutdowning in %s", timeout)
+	logger.Info("shutdowning in %s", t.ShutdownTimeout)
+
+	return t.Shutdown(shutdownCtx)
+}
 
-	return srv.Shutdown(ctx)
+// listenAndServe likes srv.ListenAndServe, with protocol errors written by ProtocolErrorWriter
+func (t *HttpTransport) listenAndServe(srv *http.Server) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(ProtocolErrorListener(ln, t.ProtocolErrorWriter))
+}
+
+// listenAndServeTLS likes srv.ListenAndServeTLS, with protocol errors written by ProtocolErrorWriter
+func (t *HttpTransport) listenAndServeTLS(srv *http.Server, certFile string, keyFile string) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return srv.ServeTLS(ProtocolErrorListener(ln, t.ProtocolErrorWriter), certFile, keyFile)
 }
 
 func (t *HttpTransport) convertRouterToHttpRouter(router *courier.Router) *httprouter.Router {
@@ -166,31 +359,110 @@ func (t *HttpTransport) convertRouterToHttpRouter(router *courier.Router) *httpr
 		panic(errors.Errorf("need to register Operator to Router %#v before serve", router))
 	}
 
-	routeMetas := make([]*HttpRouteMeta, len(routes))
-	for i := range routes {
-		routeMetas[i] = NewHttpRouteMeta(routes[i])
-	}
+	routeMetas := t.sortedRouteMetas(routes)
 
 	httpRouter := httprouter.New()
 
-	sort.Slice(routeMetas, func(i, j int) bool {
-		return routeMetas[i].Key() < routeMetas[j].Key()
-	})
+	t.routeMetas = routeMetas
+
+	lintOperatorStates(routeMetas)
+
+	report := NewSchemaReport(t.ServiceMeta, routeMetas, t.requestTransformerMgr())
+
+	if t.SchemaReportWriter != nil {
+		encoder := json.NewEncoder(t.SchemaReportWriter)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			panic(errors.Wrap(err, "write schema report failed"))
+		}
+	}
+
+	// report all invalid operators at once
+	if err := report.Err(); err != nil {
+		panic(err)
+	}
+
+	preflights := map[string]*corsPreflight{}
+	optionsPaths := map[string]bool{}
 
 	for i := range routeMetas {
 		httpRoute := routeMetas[i]
 		httpRoute.Log()
 
+		if httpRoute.Method() == http.MethodOptions {
+			optionsPaths[httpRoute.Path()] = true
+		} else if origins := httpRoute.Policies().CORSAllowedOrigins; len(origins) > 0 {
+			if _, ok := preflights[httpRoute.Path()]; !ok {
+				preflights[httpRoute.Path()] = &corsPreflight{}
+			}
+			preflights[httpRoute.Path()].add(httpRoute.Method(), origins)
+		}
+
 		if err := TryCatch(func() {
+			handler := NewHttpRouteHandler(&t.ServiceMeta, httpRoute, t.requestTransformerMgr())
+
+			if name := httpRoute.WorkerPool(); name != "" {
+				pool, ok := t.WorkerPools[name]
+				if !ok {
+					panic(errors.Errorf("missing worker pool %s", name))
+				}
+				handler.workerPool = pool
+			}
+
+			handler.policyMiddlewares = t.RoutePolicies
+			handler.runtimeConfig = t.RuntimeConfig
+			handler.profile = t.Profile
+			handler.middlewares = t.RouteMiddlewares
+			handler.injector = t.Injector
+
+			if err := handler.checkInjector(); err != nil {
+				panic(err)
+			}
+
 			httpRouter.HandlerFunc(
 				httpRoute.Method(),
 				httpRoute.Path(),
-				NewHttpRouteHandler(&t.ServiceMeta, httpRoute, NewRequestTransformerMgr(t.TransformerMgr, t.ValidatorMgr)).ServeHTTP,
+				handler.ServeHTTP,
 			)
+
+			for _, alias := range httpRoute.PathAliases() {
+				httpRouter.Handler(httpRoute.Method(), alias, t.pathAliasHandler(httpRoute, alias, handler))
+			}
 		}); err != nil {
 			panic(errors.Errorf("register http route `%s` failed: %s", httpRoute, err))
 		}
 	}
 
+	for path, preflight := range preflights {
+		if !optionsPaths[path] {
+			httpRouter.Handler(http.MethodOptions, path, preflight)
+		}
+	}
+
 	return httpRouter
 }
+
+func (t *HttpTransport) requestTransformerMgr() *RequestTransformerMgr {
+	mgr := NewRequestTransformerMgr(t.TransformerMgr, t.ValidatorMgr)
+	mgr.QueryOptions = t.QueryOptions
+	return mgr
+}
+
+func (t *HttpTransport) sortedRouteMetas(routes []*courier.Route) []*HttpRouteMeta {
+	routeMetas := make([]*HttpRouteMeta, len(routes))
+	for i := range routes {
+		routeMetas[i] = NewHttpRouteMeta(routes[i])
+	}
+
+	sort.Slice(routeMetas, func(i, j int) bool {
+		return routeMetas[i].Key() < routeMetas[j].Key()
+	})
+
+	return routeMetas
+}
+
+// SchemaReport of router without serving, for diffing validation behavior in ci
+func (t *HttpTransport) SchemaReport(router *courier.Router) *SchemaReport {
+	t.SetDefaults()
+	return NewSchemaReport(t.ServiceMeta, t.sortedRouteMetas(router.Routes()), t.requestTransformerMgr())
+}
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-courier/logr"
+	"github.com/pkg/errors"
+)
+
+// AccessLog of inbound or outbound request, with headers redacted
+type AccessLog struct {
+	// server or client
+	Kind        string
+	OperationID string
+	Method      string
+	URL         string
+	StatusCode  int
+	Latency     time.Duration
+	// bytes of request body read or sent
+	RequestBytes int64
+	// bytes of response body written or received
+	ResponseBytes int64
+	RemoteIP      string
+	UserAgent     string
+	RequestID     string
+	TraceID       string
+	SpanID        string
+	// request header with values of credentials redacted
+	Header http.Header
+	// sampled body with values of credentials redacted, empty when not sampled
+	RequestBody  string
+	ResponseBody string
+	// sampled body truncated by limit
+	BodyTruncated bool
+	Err           error
+}
+
+// Fields of access log as key values
+func (l *AccessLog) Fields() []interface{} {
+	fields := []interface{}{
+		"tag", "access",
+		"kind", l.Kind,
+		"method", l.Method,
+		"url", l.URL,
+		"status", l.StatusCode,
+		"cost", fmt.Sprintf("%0.3fms", float64(l.Latency)/float64(time.Millisecond)),
+		"request_bytes", l.RequestBytes,
+		"response_bytes", l.ResponseBytes,
+	}
+
+	for _, kv := range [][2]string{
+		{"operationID", l.OperationID},
+		{"remote_ip", l.RemoteIP},
+		{"user_agent", l.UserAgent},
+		{"requestID", l.RequestID},
+		{"traceID", l.TraceID},
+		{"spanID", l.SpanID},
+		{"request_body", l.RequestBody},
+		{"response_body", l.ResponseBody},
+	} {
+		if kv[1] != "" {
+			fields = append(fields, kv[0], kv[1])
+		}
+	}
+
+	if l.Header != nil {
+		fields = append(fields, "metadata", l.Header)
+	}
+
+	if l.BodyTruncated {
+		fields = append(fields, "body_truncated", true)
+	}
+
+	return fields
+}
+
+// AccessLogOptions of access log of server and client
+type AccessLogOptions struct {
+	// default LogrAccessLogger
+	Logger AccessLogger
+	// headers which values will be redacted, default DefaultRedactedHeaders, extra ones appended
+	RedactHeaders []string
+	// percentage of requests to log bodies, 0-100
+	BodySampleRate float64
+	// max bytes of each body logged, default 1024
+	BodyLimit int
+	// keys of json or form which values of bodies will be redacted, default password, secret, token
+	RedactKeys []string
+}
+
+func (opts *AccessLogOptions) SetDefaults() {
+	if opts.Logger == nil {
+		opts.Logger = LogrAccessLogger
+	}
+	opts.RedactHeaders = append(append([]string{}, DefaultRedactedHeaders...), opts.RedactHeaders...)
+	if opts.BodyLimit == 0 {
+		opts.BodyLimit = 1024
+	}
+	if opts.RedactKeys == nil {
+		opts.RedactKeys = []string{"password", "secret", "token"}
+	}
+}
+
+// AccessLogger should not block, LogAccess is called after response done
+type AccessLogger interface {
+	LogAccess(ctx context.Context, l *AccessLog)
+}
+
+type AccessLoggerFunc func(ctx context.Context, l *AccessLog)
+
+func (fn AccessLoggerFunc) LogAccess(ctx context.Context, l *AccessLog) {
+	fn(ctx, l)
+}
+
+// LogrAccessLogger logs by logger of context,
+// as error when status code >= 500, as warning when error or status code >= 400
+var LogrAccessLogger AccessLogger = AccessLoggerFunc(func(ctx context.Context, l *AccessLog) {
+	logger := logr.FromContext(ctx).WithValues(l.Fields()...)
+
+	switch {
+	case l.StatusCode >= http.StatusInternalServerError:
+		logger.Error(errorOfAccessLog(l))
+	case l.Err != nil || l.StatusCode >= http.StatusBadRequest:
+		logger.Warn(errorOfAccessLog(l))
+	default:
+		logger.Info("")
+	}
+})
+
+func errorOfAccessLog(l *AccessLog) error {
+	if l.Err != nil {
+		return l.Err
+	}
+	return errors.Errorf("%d %s", l.StatusCode, http.StatusText(l.StatusCode))
+}
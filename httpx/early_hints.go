@@ -0,0 +1,30 @@
+package httpx
+
+import (
+	"net/http"
+)
+
+// PreloadLink returns value of Link header to preload resource
+// https://developer.mozilla.org/en-US/docs/Web/HTML/Link_types/preload
+func PreloadLink(uri string, as string) string {
+	link := "<" + uri + ">; rel=preload"
+	if as != "" {
+		link += "; as=" + as
+	}
+	return link
+}
+
+// WriteEarlyHints writes 103 Early Hints with links before final response.
+// Links will be kept in headers of final response too, which are the only hints when built before go1.19,
+// since net/http passes 1xx statuses through from go1.19.
+// Wrappers of ResponseWriter should pass 1xx statuses through without taking them as final status.
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/103
+func WriteEarlyHints(rw http.ResponseWriter, links ...string) {
+	if len(links) == 0 {
+		return
+	}
+	for _, link := range links {
+		rw.Header().Add(HeaderLink, link)
+	}
+	writeEarlyHints(rw)
+}
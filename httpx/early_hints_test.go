@@ -0,0 +1,12 @@
+package httpx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreloadLink(t *testing.T) {
+	require.Equal(t, "</style.css>; rel=preload; as=style", PreloadLink("/style.css", "style"))
+	require.Equal(t, "</font.woff2>; rel=preload", PreloadLink("/font.woff2", ""))
+}
@@ -0,0 +1,157 @@
+package httpx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TrustedProxies ips or cidrs of proxies, which forwarded headers could be trusted
+type TrustedProxies []string
+
+// Trust checks the remote addr (ip:port or ip) is one of trusted proxies
+func (proxies TrustedProxies) Trust(remoteAddr string) bool {
+	host := strings.TrimSpace(remoteAddr)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			if _, ipNet, err := net.ParseCIDR(proxy); err == nil && ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type contextKeyTrustedProxies int
+
+func ContextWithTrustedProxies(ctx context.Context, proxies TrustedProxies) context.Context {
+	return context.WithValue(ctx, contextKeyTrustedProxies(1), proxies)
+}
+
+func TrustedProxiesFromContext(ctx context.Context) TrustedProxies {
+	if proxies, ok := ctx.Value(contextKeyTrustedProxies(1)).(TrustedProxies); ok {
+		return proxies
+	}
+	return nil
+}
+
+// ExternalURL reconstructs external url (scheme, host and path prefix) of request behind gateways.
+// Forwarded or X-Forwarded-* headers only be honored when request from trusted proxies,
+// Forwarded takes precedence over X-Forwarded-*.
+func ExternalURL(r *http.Request, proxies TrustedProxies) *url.URL {
+	u := &url.URL{
+		Scheme: "http",
+		Host:   r.Host,
+	}
+
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+
+	if !proxies.Trust(r.RemoteAddr) {
+		return u
+	}
+
+	forwarded := parseForwarded(r.Header.Get(HeaderForwarded))
+
+	if proto := firstValue(forwarded["proto"], r.Header.Get(HeaderForwardedProto)); proto != "" {
+		u.Scheme = strings.ToLower(proto)
+	}
+
+	if host := firstValue(forwarded["host"], r.Header.Get(HeaderForwardedHost)); host != "" {
+		u.Host = host
+	}
+
+	if port := firstValue(r.Header.Get(HeaderForwardedPort)); port != "" {
+		hostname := u.Host
+		if h, _, err := net.SplitHostPort(u.Host); err == nil {
+			hostname = h
+		}
+		if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+			u.Host = hostname
+		} else {
+			u.Host = net.JoinHostPort(strings.Trim(hostname, "[]"), port)
+		}
+	}
+
+	if prefix := firstValue(r.Header.Get(HeaderForwardedPrefix)); prefix != "" {
+		u.Path = "/" + strings.Trim(prefix, "/")
+	}
+
+	return u
+}
+
+// ExternalLocation resolves path (with query) of service into external url,
+// for Location header or pagination links.
+func ExternalLocation(r *http.Request, proxies TrustedProxies, path string) *url.URL {
+	u := ExternalURL(r, proxies)
+
+	ref, err := url.Parse(path)
+	if err != nil {
+		return u
+	}
+
+	if ref.IsAbs() || ref.Host != "" {
+		return ref
+	}
+
+	p := ref.Path
+	if !strings.HasPrefix(p, "/") {
+		// relative to path of request
+		p = (&url.URL{Path: r.URL.Path}).ResolveReference(&url.URL{Path: p}).Path
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + p
+	u.RawQuery = ref.RawQuery
+	u.Fragment = ref.Fragment
+
+	return u
+}
+
+// first item of comma separated values of header
+func firstValue(values ...string) string {
+	for _, v := range values {
+		if i := strings.IndexByte(v, ','); i >= 0 {
+			v = v[:i]
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Forwarded: for=192.0.2.60;proto=http;by=203.0.113.43, for=198.51.100.17
+// https://datatracker.ietf.org/doc/html/rfc7239
+func parseForwarded(forwarded string) map[string]string {
+	values := map[string]string{}
+
+	if i := strings.IndexByte(forwarded, ','); i >= 0 {
+		forwarded = forwarded[:i]
+	}
+
+	for _, pair := range strings.Split(forwarded, ";") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 {
+			values[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalURL(t *testing.T) {
+	proxies := TrustedProxies{"10.0.0.0/8", "192.168.1.1"}
+
+	newRequest := func(remoteAddr string, headers map[string]string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "http://svc.local:8080/items?page=1", nil)
+		req.RemoteAddr = remoteAddr
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req
+	}
+
+	t.Run("trust", func(t *testing.T) {
+		require.True(t, proxies.Trust("10.1.2.3:1234"))
+		require.True(t, proxies.Trust("192.168.1.1"))
+		require.False(t, proxies.Trust("192.168.1.2:80"))
+		require.False(t, proxies.Trust("unknown"))
+	})
+
+	t.Run("x-forwarded-* from trusted proxy", func(t *testing.T) {
+		req := newRequest("10.0.0.1:1234", map[string]string{
+			HeaderForwardedProto:  "https",
+			HeaderForwardedHost:   "api.example.com, svc.local",
+			HeaderForwardedPort:   "8443",
+			HeaderForwardedPrefix: "/v1/",
+		})
+		require.Equal(t, "https://api.example.com:8443/v1", ExternalURL(req, proxies).String())
+		require.Equal(t, "https://api.example.com:8443/v1/items?page=2", ExternalLocation(req, proxies, "/items?page=2").String())
+		require.Equal(t, "https://api.example.com:8443/v1/items/1", ExternalLocation(req, proxies, "items/1").String())
+		require.Equal(t, "https://other.com/x", ExternalLocation(req, proxies, "https://other.com/x").String())
+	})
+
+	t.Run("forwarded takes precedence", func(t *testing.T) {
+		req := newRequest("10.0.0.1:1234", map[string]string{
+			HeaderForwarded:      `for=192.0.2.60;proto=https;host="api.example.com", for=10.0.0.2`,
+			HeaderForwardedProto: "http",
+			HeaderForwardedPort:  "443",
+		})
+		require.Equal(t, "https://api.example.com", ExternalURL(req, proxies).String())
+	})
+
+	t.Run("untrusted proxy", func(t *testing.T) {
+		req := newRequest("203.0.113.1:1234", map[string]string{
+			HeaderForwardedHost: "evil.com",
+		})
+		require.Equal(t, "http://svc.local:8080", ExternalURL(req, proxies).String())
+	})
+
+	t.Run("location of redirect", func(t *testing.T) {
+		req := newRequest("10.0.0.1:1234", map[string]string{
+			HeaderForwardedProto:  "https",
+			HeaderForwardedHost:   "api.example.com",
+			HeaderForwardedPrefix: "/v1",
+		})
+		req = req.WithContext(ContextWithTrustedProxies(context.Background(), proxies))
+
+		rw := httptest.NewRecorder()
+		err := RedirectWithStatusFound(&url.URL{Path: "/items/1"}).WriteTo(rw, req, nil)
+		require.NoError(t, err)
+		require.Equal(t, "https://api.example.com/v1/items/1", rw.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,90 @@
+package httpx
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/go-courier/courier"
+)
+
+// NewFile creates response of file served by seeking content instead of loading all into memory,
+// with Range requests as 206 Partial Content, conditional requests by If-Modified-Since or If-None-Match,
+// and Content-Disposition as attachment. content will be closed after served if it is io.Closer.
+//
+//	f, _ := os.Open(path)
+//	info, _ := f.Stat()
+//	return httpx.NewFile(info.Name(), info.ModTime(), f), nil
+func NewFile(filename string, modTime time.Time, content io.ReadSeeker) *File {
+	return &File{
+		filename: filename,
+		modTime:  modTime,
+		content:  content,
+	}
+}
+
+type File struct {
+	filename    string
+	contentType string
+	etag        string
+	inline      bool
+	modTime     time.Time
+	content     io.ReadSeeker
+}
+
+// WithContentType overwrites content type, which resolved by extension of filename by default
+func (f *File) WithContentType(contentType string) *File {
+	f.contentType = contentType
+	return f
+}
+
+// WithETag sets ETag for If-None-Match and If-Range, should be quoted like "v1" or W/"v1"
+func (f *File) WithETag(etag string) *File {
+	f.etag = etag
+	return f
+}
+
+// Inline marks file to be displayed by browser instead of downloaded
+func (f *File) Inline() *File {
+	f.inline = true
+	return f
+}
+
+func (f *File) ContentType() string {
+	if f.contentType != "" {
+		return f.contentType
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(f.filename)); contentType != "" {
+		return contentType
+	}
+	return MIME_OCTET_STREAM
+}
+
+func (f *File) Meta() courier.Metadata {
+	metadata := courier.Metadata{}
+
+	disposition := "attachment"
+	if f.inline {
+		disposition = "inline"
+	}
+	if f.filename != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": f.filename})
+	}
+	metadata.Add(HeaderContentDisposition, disposition)
+
+	if f.etag != "" {
+		metadata.Add(HeaderETag, f.etag)
+	}
+
+	return metadata
+}
+
+// ServeHTTP writes status code, Content-Length, Content-Range and Last-Modified by request
+func (f *File) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if c, ok := f.content.(io.Closer); ok {
+		defer c.Close()
+	}
+	http.ServeContent(rw, r, f.filename, f.modTime, f.content)
+}
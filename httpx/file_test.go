@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	modTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	serve := func(f *File, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		for key := range header {
+			req.Header.Set(key, header.Get(key))
+		}
+		rw := httptest.NewRecorder()
+		require.NoError(t, ResponseFrom(f).WriteTo(rw, req, nil))
+		return rw
+	}
+
+	t.Run("whole", func(t *testing.T) {
+		rw := serve(NewFile("report.json", modTime, strings.NewReader("0123456789")), nil)
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "0123456789", rw.Body.String())
+		require.Equal(t, "10", rw.Header().Get(HeaderContentLength))
+		require.Equal(t, "application/json", rw.Header().Get(HeaderContentType))
+		require.Equal(t, "attachment; filename=report.json", rw.Header().Get(HeaderContentDisposition))
+		require.Equal(t, "bytes", rw.Header().Get("Accept-Ranges"))
+		require.Equal(t, modTime.Format(http.TimeFormat), rw.Header().Get("Last-Modified"))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		rw := serve(NewFile("data.bin", modTime, strings.NewReader("0123456789")), http.Header{
+			"Range": {"bytes=2-5"},
+		})
+
+		require.Equal(t, http.StatusPartialContent, rw.Code)
+		require.Equal(t, "2345", rw.Body.String())
+		require.Equal(t, "bytes 2-5/10", rw.Header().Get("Content-Range"))
+		require.Equal(t, MIME_OCTET_STREAM, rw.Header().Get(HeaderContentType))
+	})
+
+	t.Run("range not satisfiable", func(t *testing.T) {
+		rw := serve(NewFile("data.bin", modTime, strings.NewReader("0123456789")), http.Header{
+			"Range": {"bytes=20-"},
+		})
+
+		require.Equal(t, http.StatusRequestedRangeNotSatisfiable, rw.Code)
+	})
+
+	t.Run("not modified since", func(t *testing.T) {
+		rw := serve(NewFile("data.bin", modTime, strings.NewReader("0123456789")), http.Header{
+			"If-Modified-Since": {modTime.Format(http.TimeFormat)},
+		})
+
+		require.Equal(t, http.StatusNotModified, rw.Code)
+		require.Empty(t, rw.Body.String())
+	})
+
+	t.Run("none match etag", func(t *testing.T) {
+		rw := serve(NewFile("data.bin", modTime, strings.NewReader("0123456789")).WithETag(`"v1"`), http.Header{
+			"If-None-Match": {`"v1"`},
+		})
+
+		require.Equal(t, http.StatusNotModified, rw.Code)
+		require.Equal(t, `"v1"`, rw.Header().Get(HeaderETag))
+	})
+
+	t.Run("if range with changed etag", func(t *testing.T) {
+		rw := serve(NewFile("data.bin", modTime, strings.NewReader("0123456789")).WithETag(`"v2"`), http.Header{
+			"Range":    {"bytes=2-5"},
+			"If-Range": {`"v1"`},
+		})
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "0123456789", rw.Body.String())
+	})
+
+	t.Run("inline with content type", func(t *testing.T) {
+		rw := serve(NewFile("报告.pdf", modTime, strings.NewReader("%PDF")).Inline().WithContentType("application/pdf"), nil)
+
+		require.Equal(t, "application/pdf", rw.Header().Get(HeaderContentType))
+		require.Equal(t, "inline; filename*=utf-8''%E6%8A%A5%E5%91%8A.pdf", rw.Header().Get(HeaderContentDisposition))
+	})
+}
@@ -3,8 +3,41 @@ package httpx
 const (
 	HeaderUserAgent          = "User-Agent"
 	HeaderContentType        = "Content-Type"
+	HeaderContentLength      = "Content-Length"
 	HeaderContentDisposition = "Content-Disposition"
+	HeaderContentEncoding    = "Content-Encoding"
+	HeaderAccept             = "Accept"
+	HeaderAcceptEncoding     = "Accept-Encoding"
+	HeaderAcceptLanguage     = "Accept-Language"
+	HeaderAcceptPost         = "Accept-Post"
+	HeaderAcceptPatch        = "Accept-Patch"
+	HeaderContentLanguage    = "Content-Language"
+	HeaderVary               = "Vary"
 	HeaderRequestID          = "X-Request-ID"
 	HeaderForwardedFor       = "X-Forwarded-For"
 	HeaderRealIP             = "X-Real-IP"
+	HeaderForwarded          = "Forwarded"
+	HeaderForwardedProto     = "X-Forwarded-Proto"
+	HeaderForwardedHost      = "X-Forwarded-Host"
+	HeaderForwardedPort      = "X-Forwarded-Port"
+	HeaderForwardedPrefix    = "X-Forwarded-Prefix"
+	HeaderLink               = "Link"
+	HeaderETag               = "ETag"
+	HeaderTotalCount         = "X-Total-Count"
+	HeaderFieldMask          = "X-Field-Mask"
+	HeaderSpecHash           = "X-Spec-Hash"
+	HeaderOrigin             = "Origin"
+	HeaderRetryAfter         = "Retry-After"
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	HeaderDeprecation        = "Deprecation"
+	HeaderSunset             = "Sunset"
+	HeaderAuthorization      = "Authorization"
+	HeaderContentRange       = "Content-Range"
+
+	HeaderAccessControlAllowOrigin    = "Access-Control-Allow-Origin"
+	HeaderAccessControlAllowMethods   = "Access-Control-Allow-Methods"
+	HeaderAccessControlAllowHeaders   = "Access-Control-Allow-Headers"
+	HeaderAccessControlRequestMethod  = "Access-Control-Request-Method"
+	HeaderAccessControlRequestHeaders = "Access-Control-Request-Headers"
 )
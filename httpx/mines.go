@@ -7,6 +7,7 @@ const (
 	MIME_XML               = "application/xml"
 	MIME_FORM_URLENCODED   = "application/x-www-form-urlencoded"
 	MIME_MULTIPART_FORMDAT = "multipart/form-data"
+	MIME_MULTIPART_MIXED   = "multipart/mixed"
 	MIME_PROTOBUF          = "application/x-protobuf"
 	MIME_MSGPACK           = "application/x-msgpack"
 )
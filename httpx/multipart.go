@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// NewMultipartMixed creates multipart/mixed response for document with attachments in one round trip,
+// parts will be streamed in order with flushing after each part.
+//
+//	m := httpx.NewMultipartMixed()
+//	m.AddJSON(doc)
+//	m.AddAttachment("a.pdf", "application/pdf", file)
+//	return m, nil
+func NewMultipartMixed() *MultipartMixed {
+	return &MultipartMixed{
+		boundary: multipart.NewWriter(ioutil.Discard).Boundary(),
+	}
+}
+
+type MultipartMixed struct {
+	boundary string
+	parts    []*multipartPart
+}
+
+type multipartPart struct {
+	header textproto.MIMEHeader
+	body   io.Reader
+	value  interface{}
+}
+
+func (m *MultipartMixed) ContentType() string {
+	return mime.FormatMediaType(MIME_MULTIPART_MIXED, map[string]string{"boundary": m.boundary})
+}
+
+// AddPart adds part with body, body will be closed after written when it is io.Closer
+func (m *MultipartMixed) AddPart(header textproto.MIMEHeader, body io.Reader) *MultipartMixed {
+	m.parts = append(m.parts, &multipartPart{header: header, body: body})
+	return m
+}
+
+// AddJSON adds part of v encoded as json
+func (m *MultipartMixed) AddJSON(v interface{}) *MultipartMixed {
+	header := textproto.MIMEHeader{}
+	header.Set(HeaderContentType, MIME_JSON)
+
+	m.parts = append(m.parts, &multipartPart{header: header, value: v})
+	return m
+}
+
+// AddAttachment adds part with Content-Disposition of filename
+func (m *MultipartMixed) AddAttachment(filename string, contentType string, body io.Reader) *MultipartMixed {
+	if contentType == "" {
+		contentType = MIME_OCTET_STREAM
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set(HeaderContentType, contentType)
+	header.Set(HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	return m.AddPart(header, body)
+}
+
+func (m *MultipartMixed) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{Writer: w}
+	flusher, _ := w.(http.Flusher)
+
+	mw := multipart.NewWriter(cw)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return cw.n, err
+	}
+
+	// close all bodies even when failed
+	defer func() {
+		for _, part := range m.parts {
+			if c, ok := part.body.(io.Closer); ok {
+				c.Close()
+			}
+		}
+	}()
+
+	for _, part := range m.parts {
+		pw, err := mw.CreatePart(part.header)
+		if err != nil {
+			return cw.n, err
+		}
+
+		if part.body != nil {
+			if _, err := io.Copy(pw, part.body); err != nil {
+				return cw.n, err
+			}
+		} else {
+			if err := json.NewEncoder(pw).Encode(part.value); err != nil {
+				return cw.n, err
+			}
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, nil
+}
+
+type countWriter struct {
+	io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
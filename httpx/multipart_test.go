@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMultipartMixed(t *testing.T) {
+	attachment := &closeRecorder{Reader: bytes.NewBufferString("%PDF")}
+
+	m := NewMultipartMixed().
+		AddJSON(map[string]string{"id": "1"}).
+		AddAttachment("a.pdf", "application/pdf", attachment)
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := ResponseFrom(m).WriteTo(rw, req, func(response *Response) (string, Encode, error) {
+		panic("should not be called")
+	})
+	require.NoError(t, err)
+	require.True(t, attachment.closed)
+	require.True(t, rw.Flushed)
+
+	mediaType, params, err := mime.ParseMediaType(rw.Header().Get(HeaderContentType))
+	require.NoError(t, err)
+	require.Equal(t, MIME_MULTIPART_MIXED, mediaType)
+
+	mr := multipart.NewReader(rw.Body, params["boundary"])
+
+	part, err := mr.NextPart()
+	require.NoError(t, err)
+	require.Equal(t, MIME_JSON, part.Header.Get(HeaderContentType))
+	data, _ := ioutil.ReadAll(part)
+	require.Equal(t, `{"id":"1"}`+"\n", string(data))
+
+	part, err = mr.NextPart()
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", part.Header.Get(HeaderContentType))
+	require.Equal(t, "a.pdf", part.FileName())
+	data, _ = ioutil.ReadAll(part)
+	require.Equal(t, "%PDF", string(data))
+
+	_, err = mr.NextPart()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,73 @@
+package httpx
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/courier"
+)
+
+// Pagination of list operations, should be embedded into operators,
+// openapi generator documents X-Total-Count and Link headers of their responses.
+//
+//	type ListPets struct {
+//		httpx.MethodGet
+//		httpx.Pagination
+//	}
+//
+//	func (req *ListPets) Output(ctx context.Context) (interface{}, error) {
+//		pets, total := listPets(req.Size, req.Offset)
+//		return httpx.WithPagination(requestURL, req.Pagination, total)(pets), nil
+//	}
+type Pagination struct {
+	// max count of items in page
+	Size int `name:"size,omitempty" in:"query" default:"10" validate:"@int[1,100]"`
+	// count of items skipped
+	Offset int `name:"offset,omitempty" in:"query" validate:"@int[0,]"`
+}
+
+// Links of next and prev pages by u, like <https://x/pets?offset=20&size=10>; rel="next"
+func (p Pagination) Links(u *url.URL, total int) string {
+	links := make([]string, 0, 2)
+
+	link := func(offset int, rel string) {
+		pageURL := *u
+		query := pageURL.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("size", strconv.Itoa(p.Size))
+		pageURL.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, pageURL.String(), rel))
+	}
+
+	if p.Size > 0 && p.Offset+p.Size < total {
+		link(p.Offset+p.Size, "next")
+	}
+
+	if p.Offset > 0 {
+		prev := p.Offset - p.Size
+		if prev < 0 {
+			prev = 0
+		}
+		link(prev, "prev")
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// WithPagination sets X-Total-Count and Link of page into metadata of response
+func WithPagination(u *url.URL, p Pagination, total int) ResponseWrapper {
+	return func(v interface{}) *Response {
+		resp := ResponseFrom(v)
+
+		metadata := courier.Metadata{}
+		metadata.Set(HeaderTotalCount, strconv.Itoa(total))
+		if links := p.Links(u, total); links != "" {
+			metadata.Set(HeaderLink, links)
+		}
+
+		resp.Metadata = courier.FromMetas(resp.Metadata, metadata)
+		return resp
+	}
+}
@@ -0,0 +1,31 @@
+package httpx
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagination(t *testing.T) {
+	u, _ := url.Parse("https://example.com/pets?kind=cat&offset=10&size=10")
+
+	t.Run("links of next and prev", func(t *testing.T) {
+		require.Equal(t,
+			`<https://example.com/pets?kind=cat&offset=20&size=10>; rel="next", <https://example.com/pets?kind=cat&offset=0&size=10>; rel="prev"`,
+			Pagination{Size: 10, Offset: 10}.Links(u, 25),
+		)
+	})
+
+	t.Run("no link for single page", func(t *testing.T) {
+		require.Equal(t, "", Pagination{Size: 10}.Links(u, 5))
+	})
+
+	t.Run("metadata of response", func(t *testing.T) {
+		resp := WithPagination(u, Pagination{Size: 10}, 25)(Compose(WithMetadata(Metadata("X-Custom", "1")))([]string{}))
+
+		require.Equal(t, "25", resp.Metadata.Get(HeaderTotalCount))
+		require.Equal(t, `<https://example.com/pets?kind=cat&offset=10&size=10>; rel="next"`, resp.Metadata.Get(HeaderLink))
+		require.Equal(t, "1", resp.Metadata.Get("X-Custom"))
+	})
+}
@@ -0,0 +1,32 @@
+package httpx
+
+// NewPreEncoded creates response of cached or pre-rendered payload,
+// which will be written as is without encoding by transformers.
+//
+//	return httpx.NewPreEncoded(httpx.MIME_JSON, cachedJSON), nil
+func NewPreEncoded(contentType string, data []byte) *PreEncoded {
+	return &PreEncoded{
+		contentType: contentType,
+		data:        data,
+	}
+}
+
+type PreEncoded struct {
+	contentType string
+	data        []byte
+}
+
+func (p *PreEncoded) ContentType() string {
+	if p.contentType == "" {
+		return MIME_OCTET_STREAM
+	}
+	return p.contentType
+}
+
+func (p *PreEncoded) Bytes() []byte {
+	return p.data
+}
+
+func (p *PreEncoded) Len() int {
+	return len(p.data)
+}
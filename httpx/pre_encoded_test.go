@@ -0,0 +1,17 @@
+package httpx
+
+import (
+	"fmt"
+)
+
+func ExampleNewPreEncoded() {
+	p := NewPreEncoded("", []byte("raw"))
+
+	fmt.Println(p.ContentType())
+	fmt.Println(p.Len())
+	fmt.Println(string(p.Bytes()))
+	// Output:
+	// application/octet-stream
+	// 3
+	// raw
+}
@@ -0,0 +1,52 @@
+package httpx
+
+import (
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+// Redact values of keys (case-insensitive, partial matched) in json or form body
+func Redact(raw string, keys ...string) string {
+	if len(keys) == 0 {
+		return raw
+	}
+
+	quoted := make([]string, len(keys))
+	for i := range keys {
+		quoted[i] = regexp.QuoteMeta(keys[i])
+	}
+
+	pattern := "(?i)(" + strings.Join(quoted, "|") + ")"
+
+	raw = regexp.MustCompile(`(?i)("[^"]*`+pattern+`[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`).ReplaceAllString(raw, `${1}"***"`)
+	raw = regexp.MustCompile(`(?i)((?:^|&)[^=&]*`+pattern+`[^=&]*=)[^&]*`).ReplaceAllString(raw, `${1}***`)
+
+	return raw
+}
+
+// DefaultRedactedHeaders of credentials, values of them should not be logged
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RedactHeader returns copy of header with values of keys (case-insensitive) replaced by ***
+func RedactHeader(header http.Header, keys ...string) http.Header {
+	redacted := make(http.Header, len(header))
+
+	for key, values := range header {
+		redacted[key] = values
+	}
+
+	for _, key := range keys {
+		key = textproto.CanonicalMIMEHeaderKey(key)
+		if values, ok := redacted[key]; ok {
+			masked := make([]string, len(values))
+			for i := range masked {
+				masked[i] = "***"
+			}
+			redacted[key] = masked
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,30 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	require.Equal(t, `{"user":"a","Password":"***"}`, Redact(`{"user":"a","Password":"pw"}`, "password"))
+	require.Equal(t, `user=a&client_secret=***`, Redact(`user=a&client_secret=s`, "secret"))
+	require.Equal(t, `{"password":"pw"}`, Redact(`{"password":"pw"}`))
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer xxx")
+	header.Set("X-Api-Key", "key")
+	header.Set("Accept", MIME_JSON)
+
+	redacted := RedactHeader(header, append(DefaultRedactedHeaders, "x-api-key")...)
+
+	require.Equal(t, http.Header{
+		"Authorization": {"***"},
+		"X-Api-Key":     {"***"},
+		"Accept":        {MIME_JSON},
+	}, redacted)
+	require.Equal(t, "Bearer xxx", header.Get("Authorization"))
+}
@@ -2,11 +2,13 @@ package httpx
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"mime"
 	"net/http"
 	"net/textproto"
 	"net/url"
+	"strconv"
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/reflectx/typesutil"
@@ -95,6 +97,7 @@ func ResponseFrom(v interface{}) *Response {
 				}
 			}
 			v = statusErr
+			response.cause = e
 		}
 	}
 
@@ -123,6 +126,16 @@ type Upgrader interface {
 	Upgrade(w http.ResponseWriter, r *http.Request) error
 }
 
+// ResponseWriterFunc could be returned by operator to write response by itself without transformers,
+// like proxying bytes of upstream.
+// Metadata and cookies of response will be set before called.
+//
+//	return httpx.ResponseWriterFunc(func(rw http.ResponseWriter, r *http.Request) {
+//		rw.WriteHeader(http.StatusOK)
+//		_, _ = io.Copy(rw, upstream)
+//	}), nil
+type ResponseWriterFunc func(rw http.ResponseWriter, r *http.Request)
+
 type Response struct {
 	// value of Body
 	Value       interface{}      `json:"-"`
@@ -131,6 +144,9 @@ type Response struct {
 	Location    *url.URL         `json:"-"`
 	ContentType string           `json:"-"`
 	StatusCode  int              `json:"-"`
+
+	// error before converted to status error
+	cause error
 }
 
 func (response *Response) Unwrap() error {
@@ -140,6 +156,14 @@ func (response *Response) Unwrap() error {
 	return nil
 }
 
+// Cause returns the original error which Value converted from
+func (response *Response) Cause() error {
+	if response.cause != nil {
+		return response.cause
+	}
+	return response.Unwrap()
+}
+
 func (response *Response) Error() string {
 	if err, ok := response.Value.(error); ok {
 		return err.Error()
@@ -210,8 +234,18 @@ func (response *Response) WriteTo(rw http.ResponseWriter, r *http.Request, resol
 		}
 	}
 
+	if writeResponse, ok := response.Value.(ResponseWriterFunc); ok {
+		writeResponse(rw, r)
+		return nil
+	}
+
 	if response.Location != nil {
-		http.Redirect(rw, r, response.Location.String(), response.StatusCode)
+		location := response.Location
+		// behind gateways, resolve location of service into external url
+		if proxies := TrustedProxiesFromContext(r.Context()); proxies != nil && !location.IsAbs() && location.Host == "" {
+			location = ExternalLocation(r, proxies, location.String())
+		}
+		http.Redirect(rw, r, location.String(), response.StatusCode)
 		return nil
 	}
 
@@ -225,6 +259,17 @@ func (response *Response) WriteTo(rw http.ResponseWriter, r *http.Request, resol
 	}
 
 	switch v := response.Value.(type) {
+	case *File:
+		// status code resolved by Range and conditional headers of request
+		v.ServeHTTP(rw, r)
+	case *PreEncoded:
+		// size known, so metrics and proxies could use Content-Length
+		rw.Header().Set(HeaderContentLength, strconv.Itoa(v.Len()))
+		rw.WriteHeader(response.StatusCode)
+
+		if _, err := rw.Write(v.Bytes()); err != nil {
+			return err
+		}
 	case courier.Result:
 		rw.WriteHeader(response.StatusCode)
 
@@ -243,6 +288,13 @@ func (response *Response) WriteTo(rw http.ResponseWriter, r *http.Request, resol
 		if _, err := io.Copy(rw, v); err != nil {
 			return err
 		}
+	case io.WriterTo:
+		// streams body by itself, like MultipartMixed
+		rw.WriteHeader(response.StatusCode)
+
+		if _, err := v.WriteTo(rw); err != nil {
+			return err
+		}
 	default:
 		contentType, encode, err := resolveEncode(response)
 		if err != nil {
@@ -264,3 +316,10 @@ func (response *Response) WriteTo(rw http.ResponseWriter, r *http.Request, resol
 
 	return nil
 }
+
+// WriteStatusErr writes status error as json, for responses of middlewares out of operators
+func WriteStatusErr(rw http.ResponseWriter, err *statuserror.StatusErr) {
+	rw.Header().Set(HeaderContentType, "application/json; charset=utf-8")
+	rw.WriteHeader(err.StatusCode())
+	_ = json.NewEncoder(rw).Encode(err)
+}
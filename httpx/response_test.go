@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/statuserror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,6 +65,19 @@ func TestResponseFrom(t *testing.T) {
 	}, resp)
 }
 
+func TestResponse_Cause(t *testing.T) {
+	err := errors.New("some error")
+
+	resp := ResponseFrom(err)
+
+	statusErr, ok := statuserror.IsStatusErr(resp.Unwrap())
+	require.True(t, ok)
+	require.Equal(t, "UnknownError", statusErr.Key)
+	require.Equal(t, err, resp.Cause())
+
+	require.Nil(t, ResponseFrom(&Data{}).Cause())
+}
+
 func TestResponse_WriteTo(t *testing.T) {
 	t.Run("redirect", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodPost, "/", nil)
@@ -169,6 +184,23 @@ Content-Type: application/json; charset=utf-8
 `, string(rw.MustDumpResponse()))
 	})
 
+	t.Run("return response writer func", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		rw := testify.NewMockResponseWriter()
+
+		_ = WithMetadata(Metadata("X", "xxx"))(ResponseWriterFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set(HeaderContentType, "text/plain")
+			rw.WriteHeader(http.StatusAccepted)
+			_, _ = rw.Write([]byte(r.Method))
+		})).WriteTo(rw, req, nil)
+
+		require.Equal(t, `HTTP/0.0 202 Accepted
+Content-Type: text/plain
+X: xxx
+
+GET`, string(rw.MustDumpResponse()))
+	})
+
 	t.Run("return attachment", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/", nil)
 		rw := testify.NewMockResponseWriter()
@@ -184,4 +216,34 @@ Content-Type: text/plain
 
 123123123`, string(rw.MustDumpResponse()))
 	})
+
+	t.Run("return pre encoded", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		rw := testify.NewMockResponseWriter()
+
+		_ = WithMetadata(Metadata("X-Cache", "HIT"))(NewPreEncoded(MIME_JSON, []byte(`{"ID":"1"}`))).WriteTo(rw, req, func(response *Response) (string, Encode, error) {
+			t.Fatal("should not encode pre encoded payload")
+			return "", nil, nil
+		})
+
+		require.Equal(t, "10", rw.Header().Get(HeaderContentLength))
+		require.Equal(t, `HTTP/0.0 200 OK
+Content-Type: application/json
+X-Cache: HIT
+
+{"ID":"1"}`, string(rw.MustDumpResponse()))
+	})
+}
+
+func TestWriteStatusErr(t *testing.T) {
+	rw := testify.NewMockResponseWriter()
+
+	WriteStatusErr(rw, statuserror.Wrap(errors.New("busy"), http.StatusServiceUnavailable, "Busy"))
+
+	require.Equal(t, http.StatusServiceUnavailable, rw.StatusCode)
+	require.Equal(t, "application/json; charset=utf-8", rw.Header().Get(HeaderContentType))
+
+	statusErr := &statuserror.StatusErr{}
+	require.NoError(t, json.Unmarshal(rw.Bytes(), statusErr))
+	require.Equal(t, "Busy", statusErr.Key)
 }
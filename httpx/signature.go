@@ -0,0 +1,197 @@
+package httpx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderSignature of signed request, like
+//
+//	X-Signature: algorithm=hmac-sha256,keyId=svc-a,timestamp=1600000000,headers=content-type;host,signature=<hex>
+const HeaderSignature = "X-Signature"
+
+var signatureAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha256": sha256.New,
+	"hmac-sha512": sha512.New,
+}
+
+// RegisterSignatureAlgorithm registers hash for hmac algorithm, should be called in init
+func RegisterSignatureAlgorithm(name string, newHash func() hash.Hash) {
+	signatureAlgorithms[name] = newHash
+}
+
+// SignatureKeyProvider provides shared key by key id for signing and verifying requests
+type SignatureKeyProvider interface {
+	SignatureKey(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// SignatureKeys is static SignatureKeyProvider of keys by key id
+type SignatureKeys map[string][]byte
+
+func (keys SignatureKeys) SignatureKey(ctx context.Context, keyID string) ([]byte, error) {
+	if key, ok := keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, errors.Errorf("unknown signature key %s", keyID)
+}
+
+type Signature struct {
+	Algorithm string
+	KeyID     string
+	// unix seconds
+	Timestamp int64
+	// lower cased names of signed headers
+	Headers   []string
+	Signature string
+}
+
+func ParseSignature(s string) (*Signature, error) {
+	sig := &Signature{}
+
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid signature %s", s)
+		}
+
+		switch parts[0] {
+		case "algorithm":
+			sig.Algorithm = parts[1]
+		case "keyId":
+			sig.KeyID = parts[1]
+		case "timestamp":
+			ts, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid timestamp of signature")
+			}
+			sig.Timestamp = ts
+		case "headers":
+			if parts[1] != "" {
+				sig.Headers = strings.Split(parts[1], ";")
+			}
+		case "signature":
+			sig.Signature = parts[1]
+		}
+	}
+
+	if sig.Algorithm == "" || sig.KeyID == "" || sig.Signature == "" {
+		return nil, errors.Errorf("incomplete signature %s", s)
+	}
+
+	return sig, nil
+}
+
+func (sig *Signature) String() string {
+	return "algorithm=" + sig.Algorithm +
+		",keyId=" + sig.KeyID +
+		",timestamp=" + strconv.FormatInt(sig.Timestamp, 10) +
+		",headers=" + strings.Join(sig.Headers, ";") +
+		",signature=" + sig.Signature
+}
+
+// Sum computes hex signature of request by key, headers of signature should be sorted
+func (sig *Signature) Sum(key []byte, r *http.Request, bodyHash string) (string, error) {
+	newHash, ok := signatureAlgorithms[sig.Algorithm]
+	if !ok {
+		return "", errors.Errorf("unsupported signature algorithm %s", sig.Algorithm)
+	}
+
+	canonicalRequestHash := sha256.Sum256([]byte(CanonicalRequest(r, sig.Headers, bodyHash)))
+
+	mac := hmac.New(newHash, key)
+	_, _ = mac.Write([]byte(sig.Algorithm + "\n" + strconv.FormatInt(sig.Timestamp, 10) + "\n" + hex.EncodeToString(canonicalRequestHash[:])))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify checks signature of request in constant time
+func (sig *Signature) Verify(key []byte, r *http.Request, bodyHash string) error {
+	expected, err := sig.Sum(key, r, bodyHash)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(sig.Signature)) {
+		return errors.New("signature not matched")
+	}
+	return nil
+}
+
+// BodyHash is hex sha256 of body
+func BodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalRequest joins method, escaped path, sorted query, signed headers and body hash by lines,
+// header host uses Host of request
+func CanonicalRequest(r *http.Request, headers []string, bodyHash string) string {
+	b := strings.Builder{}
+
+	b.WriteString(r.Method)
+	b.WriteString("\n")
+	b.WriteString(r.URL.EscapedPath())
+	b.WriteString("\n")
+	b.WriteString(r.URL.Query().Encode())
+	b.WriteString("\n")
+
+	for _, name := range headers {
+		b.WriteString(name)
+		b.WriteString(":")
+
+		if name == "host" {
+			host := r.Host
+			if host == "" {
+				host = r.URL.Host
+			}
+			b.WriteString(host)
+		} else {
+			values := r.Header.Values(name)
+			for i := range values {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				b.WriteString(strings.TrimSpace(values[i]))
+			}
+		}
+
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Join(headers, ";"))
+	b.WriteString("\n")
+	b.WriteString(bodyHash)
+
+	return b.String()
+}
+
+// NormalizeSignedHeaders lower cases and sorts names of headers
+func NormalizeSignedHeaders(headers []string) []string {
+	names := make([]string, len(headers))
+	for i := range headers {
+		names[i] = strings.ToLower(strings.TrimSpace(headers[i]))
+	}
+	sort.Strings(names)
+	return names
+}
+
+type contextKeySignatureKeyID int
+
+func ContextWithSignatureKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, contextKeySignatureKeyID(1), keyID)
+}
+
+// SignatureKeyIDFromContext returns key id of verified signature
+func SignatureKeyIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKeySignatureKeyID(1)).(string)
+	return v
+}
@@ -0,0 +1,42 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	t.Run("parse and format", func(t *testing.T) {
+		sig := &Signature{
+			Algorithm: "hmac-sha256",
+			KeyID:     "svc-a",
+			Timestamp: 1600000000,
+			Headers:   []string{"content-type", "host"},
+			Signature: "abc",
+		}
+
+		parsed, err := ParseSignature(sig.String())
+		require.NoError(t, err)
+		require.Equal(t, sig, parsed)
+
+		_, err = ParseSignature("algorithm=hmac-sha256")
+		require.Error(t, err)
+	})
+
+	t.Run("canonical request", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/a%20b?b=2&a=1", nil)
+		req.Header.Add("X-Tag", "x ")
+		req.Header.Add("X-Tag", "y")
+
+		require.Equal(t, "GET\n/a%20b\na=1&b=2\nhost:example.com\nx-tag:x,y\nhost;x-tag\n"+BodyHash(nil),
+			CanonicalRequest(req, NormalizeSignedHeaders([]string{"X-Tag", "Host"}), BodyHash(nil)))
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, err := (&Signature{Algorithm: "rsa"}).Sum([]byte("k"), req, BodyHash(nil))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,570 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderUpgrade             = "Upgrade"
+	HeaderConnection          = "Connection"
+	HeaderSecWebSocketKey     = "Sec-WebSocket-Key"
+	HeaderSecWebSocketAccept  = "Sec-WebSocket-Accept"
+	HeaderSecWebSocketVersion = "Sec-WebSocket-Version"
+)
+
+/*
+WebSocketHandler could be returned by operator to upgrade request to websocket,
+ctx canceled when connection closed by peer, and connection closed when returned,
+with close code 1011 and reason of error when error returned.
+
+	func (req *Subscribe) Output(ctx context.Context) (interface{}, error) {
+		return httpx.WebSocketHandler(func(ctx context.Context, conn *httpx.WebSocketConn) error {
+			for {
+				event := Event{}
+				if err := conn.ReadValue(&event); err != nil {
+					return err
+				}
+			}
+		}), nil
+	}
+
+values of messages encoded and decoded by WebSocketCodec in context, json by default.
+handshake of cross-site requests rejected by WebSocketCheckOrigin in context, WebSocketSameOrigin by default.
+*/
+type WebSocketHandler func(ctx context.Context, conn *WebSocketConn) error
+
+func (h WebSocketHandler) Upgrade(rw http.ResponseWriter, r *http.Request) error {
+	if err := checkWebSocketHandshake(r); err != nil {
+		if r.Header.Get(HeaderSecWebSocketVersion) != webSocketVersion {
+			rw.Header().Set(HeaderSecWebSocketVersion, webSocketVersion)
+		}
+		return err
+	}
+
+	if !WebSocketCheckOriginFromContext(r.Context())(r) {
+		return statuserror.Wrap(errors.Errorf("origin %s is not allowed", r.Header.Get(HeaderOrigin)), http.StatusForbidden, "WebSocketOriginForbidden")
+	}
+
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		return statuserror.Wrap(errors.New("response writer should be http.Hijacker for websocket"), http.StatusInternalServerError, "WebSocketUnsupported")
+	}
+
+	conn, bufrw, err := hijacker.Hijack()
+	if err != nil {
+		return statuserror.Wrap(err, http.StatusInternalServerError, "WebSocketUnsupported")
+	}
+
+	_, _ = bufrw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		HeaderUpgrade + ": websocket\r\n" +
+		HeaderConnection + ": Upgrade\r\n" +
+		HeaderSecWebSocketAccept + ": " + WebSocketAccept(r.Header.Get(HeaderSecWebSocketKey)) + "\r\n\r\n")
+
+	if err := bufrw.Flush(); err != nil {
+		_ = conn.Close()
+		// hijacked, nothing could be written
+		return nil
+	}
+
+	ws := newWebSocketConn(r.Context(), bufrw.Reader, conn, false)
+	ws.Codec = WebSocketCodecFromContext(r.Context())
+
+	if err := h(ws.Context(), ws); err != nil {
+		_ = ws.CloseWith(WebSocketCloseInternalError, err.Error())
+		return nil
+	}
+
+	_ = ws.Close()
+	return nil
+}
+
+const webSocketVersion = "13"
+
+func checkWebSocketHandshake(r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return statuserror.Wrap(errors.Errorf("websocket handshake should be GET, but got %s", r.Method), http.StatusMethodNotAllowed, "InvalidWebSocketHandshake")
+	}
+	if !headerContainsToken(r.Header, HeaderConnection, "upgrade") || !headerContainsToken(r.Header, HeaderUpgrade, "websocket") {
+		return statuserror.Wrap(errors.New("missing upgrade of websocket"), http.StatusUpgradeRequired, "InvalidWebSocketHandshake")
+	}
+	if r.Header.Get(HeaderSecWebSocketVersion) != webSocketVersion {
+		return statuserror.Wrap(errors.Errorf("websocket version should be %s", webSocketVersion), http.StatusUpgradeRequired, "InvalidWebSocketHandshake")
+	}
+	if r.Header.Get(HeaderSecWebSocketKey) == "" {
+		return statuserror.Wrap(errors.Errorf("missing %s", HeaderSecWebSocketKey), http.StatusBadRequest, "InvalidWebSocketHandshake")
+	}
+	return nil
+}
+
+func headerContainsToken(header http.Header, key string, token string) bool {
+	for _, v := range header.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebSocketAccept of Sec-WebSocket-Key, see https://www.rfc-editor.org/rfc/rfc6455#section-4.2.2
+func WebSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// NewWebSocketKey creates random Sec-WebSocket-Key for handshake
+func NewWebSocketKey() string {
+	p := make([]byte, 16)
+	_, _ = io.ReadFull(rand.Reader, p)
+	return base64.StdEncoding.EncodeToString(p)
+}
+
+// WebSocketCheckOrigin reports whether handshake of websocket allowed by Origin of request,
+// browsers send cookies of cross-site websocket, without checking, connections could be hijacked by other sites.
+type WebSocketCheckOrigin func(r *http.Request) bool
+
+// WebSocketSameOrigin allows requests without Origin, like clients not browsers, or Origin of same host of request
+func WebSocketSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get(HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+type contextKeyWebSocketCheckOrigin int
+
+func ContextWithWebSocketCheckOrigin(ctx context.Context, checkOrigin WebSocketCheckOrigin) context.Context {
+	return context.WithValue(ctx, contextKeyWebSocketCheckOrigin(1), checkOrigin)
+}
+
+func WebSocketCheckOriginFromContext(ctx context.Context) WebSocketCheckOrigin {
+	if checkOrigin, ok := ctx.Value(contextKeyWebSocketCheckOrigin(1)).(WebSocketCheckOrigin); ok {
+		return checkOrigin
+	}
+	return WebSocketSameOrigin
+}
+
+// WebSocketCodec encodes and decodes values of messages of WebSocketConn
+type WebSocketCodec interface {
+	EncodeMessage(v interface{}) (WebSocketMessageType, []byte, error)
+	DecodeMessage(data []byte, v interface{}) error
+}
+
+type contextKeyWebSocketCodec int
+
+func ContextWithWebSocketCodec(ctx context.Context, codec WebSocketCodec) context.Context {
+	return context.WithValue(ctx, contextKeyWebSocketCodec(1), codec)
+}
+
+func WebSocketCodecFromContext(ctx context.Context) WebSocketCodec {
+	if codec, ok := ctx.Value(contextKeyWebSocketCodec(1)).(WebSocketCodec); ok {
+		return codec
+	}
+	return nil
+}
+
+type jsonWebSocketCodec struct{}
+
+func (jsonWebSocketCodec) EncodeMessage(v interface{}) (WebSocketMessageType, []byte, error) {
+	data, err := json.Marshal(v)
+	return WebSocketMessageText, data, err
+}
+
+func (jsonWebSocketCodec) DecodeMessage(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type WebSocketMessageType int
+
+const (
+	WebSocketMessageText   WebSocketMessageType = 1
+	WebSocketMessageBinary WebSocketMessageType = 2
+)
+
+// close codes, see https://www.rfc-editor.org/rfc/rfc6455#section-7.4.1
+const (
+	WebSocketCloseNormal        = 1000
+	WebSocketCloseGoingAway     = 1001
+	WebSocketCloseProtocolError = 1002
+	WebSocketCloseNoStatus      = 1005
+	WebSocketCloseAbnormal      = 1006
+	WebSocketCloseTooLarge      = 1009
+	WebSocketCloseInternalError = 1011
+)
+
+// WebSocketCloseError returned by reading when connection closed by close frame
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *WebSocketCloseError) Error() string {
+	if e.Reason == "" {
+		return "websocket closed: " + strconv.Itoa(e.Code)
+	}
+	return "websocket closed: " + strconv.Itoa(e.Code) + " " + e.Reason
+}
+
+// ErrWebSocketMessageTooLarge when message beyond read limit of WebSocketConn
+var ErrWebSocketMessageTooLarge = errors.New("websocket message too large")
+
+var errWebSocketProtocol = errors.New("websocket protocol error")
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+type webSocketMessage struct {
+	messageType WebSocketMessageType
+	data        []byte
+}
+
+// NewWebSocketConn creates connection over rwc already upgraded, frames masked when as client.
+// ctx of connection canceled when closed.
+func NewWebSocketConn(ctx context.Context, rwc io.ReadWriteCloser, client bool) *WebSocketConn {
+	return newWebSocketConn(ctx, bufio.NewReader(rwc), rwc, client)
+}
+
+func newWebSocketConn(ctx context.Context, r *bufio.Reader, rwc io.ReadWriteCloser, client bool) *WebSocketConn {
+	c := &WebSocketConn{
+		r:         r,
+		rwc:       rwc,
+		client:    client,
+		readLimit: 32 << 20,
+		messages:  make(chan webSocketMessage, 16),
+	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+
+	go c.readLoop()
+
+	return c
+}
+
+// WebSocketConn of websocket, messages read by background,
+// ping answered and close frame replied even when not reading,
+// unread messages more than 16 block reading of connection.
+// writing could be concurrent, reading should not.
+type WebSocketConn struct {
+	// codec of ReadValue and WriteValue, json when nil
+	Codec WebSocketCodec
+
+	r      *bufio.Reader
+	rwc    io.ReadWriteCloser
+	client bool
+
+	readLimit int64
+	messages  chan webSocketMessage
+	err       error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wmu         sync.Mutex
+	closeSent   bool
+	closeOnce   sync.Once
+	closeCalled int32
+}
+
+// Context canceled when connection closed
+func (c *WebSocketConn) Context() context.Context {
+	return c.ctx
+}
+
+// SetReadLimit of bytes of each message, default 32MB
+func (c *WebSocketConn) SetReadLimit(n int64) {
+	atomic.StoreInt64(&c.readLimit, n)
+}
+
+// ReadMessage returns next data message
+func (c *WebSocketConn) ReadMessage() (WebSocketMessageType, []byte, error) {
+	m, ok := <-c.messages
+	if !ok {
+		return 0, nil, c.err
+	}
+	return m.messageType, m.data, nil
+}
+
+// WriteMessage writes data message
+func (c *WebSocketConn) WriteMessage(messageType WebSocketMessageType, data []byte) error {
+	return c.writeFrame(byte(messageType), data)
+}
+
+// ReadValue decodes next message into v by Codec
+func (c *WebSocketConn) ReadValue(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return c.codec().DecodeMessage(data, v)
+}
+
+// WriteValue encodes v by Codec and writes it as message
+func (c *WebSocketConn) WriteValue(v interface{}) error {
+	messageType, data, err := c.codec().EncodeMessage(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(messageType, data)
+}
+
+func (c *WebSocketConn) codec() WebSocketCodec {
+	if c.Codec == nil {
+		return jsonWebSocketCodec{}
+	}
+	return c.Codec
+}
+
+// Close with code 1000
+func (c *WebSocketConn) Close() error {
+	return c.CloseWith(WebSocketCloseNormal, "")
+}
+
+// CloseWith sends close frame with code and reason, and closes connection
+func (c *WebSocketConn) CloseWith(code int, reason string) error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		atomic.StoreInt32(&c.closeCalled, 1)
+
+		// reason should be less than 123 bytes of control frame
+		if len(reason) > 123 {
+			reason = reason[0:123]
+		}
+
+		payload := make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+
+		err = c.writeFrame(opClose, payload)
+		if e := c.rwc.Close(); err == nil {
+			err = e
+		}
+		c.cancel()
+	})
+
+	return err
+}
+
+func (c *WebSocketConn) readLoop() {
+	defer func() {
+		close(c.messages)
+		_ = c.rwc.Close()
+		c.cancel()
+	}()
+
+	for {
+		m, err := c.readMessage()
+		if err != nil {
+			c.err = c.closeErrOf(err)
+			return
+		}
+
+		select {
+		case c.messages <- *m:
+		case <-c.ctx.Done():
+			c.err = &WebSocketCloseError{Code: WebSocketCloseNormal}
+			return
+		}
+	}
+}
+
+func (c *WebSocketConn) closeErrOf(err error) error {
+	if _, ok := err.(*WebSocketCloseError); ok {
+		return err
+	}
+	if atomic.LoadInt32(&c.closeCalled) == 1 {
+		// closed by self
+		return &WebSocketCloseError{Code: WebSocketCloseNormal}
+	}
+	if errors.Is(err, ErrWebSocketMessageTooLarge) {
+		_ = c.CloseWith(WebSocketCloseTooLarge, err.Error())
+		return err
+	}
+	if errors.Is(err, errWebSocketProtocol) {
+		_ = c.CloseWith(WebSocketCloseProtocolError, err.Error())
+		return err
+	}
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		// closed without close frame
+		return &WebSocketCloseError{Code: WebSocketCloseAbnormal}
+	}
+	return err
+}
+
+func (c *WebSocketConn) readMessage() (*webSocketMessage, error) {
+	var m *webSocketMessage
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			closeErr := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			// reply close frame
+			_ = c.CloseWith(closeErr.Code, "")
+			return nil, closeErr
+		case opText, opBinary:
+			if m != nil {
+				return nil, errors.Wrap(errWebSocketProtocol, "message not finished before new one")
+			}
+			m = &webSocketMessage{messageType: WebSocketMessageType(opcode), data: payload}
+		case opContinuation:
+			if m == nil {
+				return nil, errors.Wrap(errWebSocketProtocol, "continuation without message")
+			}
+			if limit := atomic.LoadInt64(&c.readLimit); limit > 0 && int64(len(m.data)+len(payload)) > limit {
+				return nil, ErrWebSocketMessageTooLarge
+			}
+			m.data = append(m.data, payload...)
+		default:
+			return nil, errors.Wrapf(errWebSocketProtocol, "unknown opcode %d", opcode)
+		}
+
+		if fin && m != nil && opcode != opPing && opcode != opPong {
+			return m, nil
+		}
+	}
+}
+
+func (c *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, head); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0f
+	masked := head[1]&0x80 != 0
+
+	if !c.client && !masked {
+		return false, 0, nil, errors.Wrap(errWebSocketProtocol, "frames of client should be masked")
+	}
+
+	length := uint64(head[1] & 0x7f)
+
+	switch length {
+	case 126:
+		b := make([]byte, 2)
+		if _, err := io.ReadFull(c.r, b); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(b))
+	case 127:
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(c.r, b); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(b)
+	}
+
+	if limit := atomic.LoadInt64(&c.readLimit); limit > 0 && length > uint64(limit) {
+		return false, 0, nil, ErrWebSocketMessageTooLarge
+	}
+
+	var mask []byte
+	if masked {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(c.r, mask); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	if c.closeSent {
+		return &WebSocketCloseError{Code: WebSocketCloseNormal}
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	b := bytes.NewBuffer(make([]byte, 0, 14+len(payload)))
+	b.WriteByte(0x80 | opcode)
+
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n < 126:
+		b.WriteByte(maskBit | byte(n))
+	case n <= 0xffff:
+		b.WriteByte(maskBit | 126)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(maskBit | 127)
+		_ = binary.Write(b, binary.BigEndian, uint64(n))
+	}
+
+	if c.client {
+		mask := make([]byte, 4)
+		_, _ = io.ReadFull(rand.Reader, mask)
+		b.Write(mask)
+		for i, v := range payload {
+			b.WriteByte(v ^ mask[i%4])
+		}
+	} else {
+		b.Write(payload)
+	}
+
+	_, err := c.rwc.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,162 @@
+package httpx
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	disconnected := make(chan struct{})
+
+	handlers := map[string]WebSocketHandler{
+		"/echo": func(ctx context.Context, conn *WebSocketConn) error {
+			for {
+				messageType, data, err := conn.ReadMessage()
+				if err != nil {
+					return nil
+				}
+				if err := conn.WriteMessage(messageType, data); err != nil {
+					return err
+				}
+			}
+		},
+		"/failed": func(ctx context.Context, conn *WebSocketConn) error {
+			return errors.New("something wrong")
+		},
+		"/wait": func(ctx context.Context, conn *WebSocketConn) error {
+			<-ctx.Done()
+			close(disconnected)
+			return nil
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := handlers[r.URL.Path].Upgrade(rw, r); err != nil {
+			statusErr, _ := statuserror.IsStatusErr(err)
+			rw.WriteHeader(statusErr.StatusCode())
+		}
+	}))
+	defer srv.Close()
+
+	dial := func(t *testing.T, path string) *WebSocketConn {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+
+		key := NewWebSocketKey()
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set(HeaderConnection, "Upgrade")
+		req.Header.Set(HeaderUpgrade, "websocket")
+		req.Header.Set(HeaderSecWebSocketVersion, "13")
+		req.Header.Set(HeaderSecWebSocketKey, key)
+		require.NoError(t, req.Write(conn))
+
+		br := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(br, req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+		require.Equal(t, WebSocketAccept(key), resp.Header.Get(HeaderSecWebSocketAccept))
+
+		return newWebSocketConn(context.Background(), br, conn, true)
+	}
+
+	t.Run("echo", func(t *testing.T) {
+		conn := dial(t, "/echo")
+		defer conn.Close()
+
+		large := strings.Repeat("x", 70000)
+
+		for _, message := range []string{"hello", large} {
+			require.NoError(t, conn.WriteMessage(WebSocketMessageText, []byte(message)))
+
+			messageType, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			require.Equal(t, WebSocketMessageText, messageType)
+			require.Equal(t, message, string(data))
+		}
+
+		require.NoError(t, conn.WriteValue(map[string]string{"id": "1"}))
+		v := map[string]string{}
+		require.NoError(t, conn.ReadValue(&v))
+		require.Equal(t, "1", v["id"])
+	})
+
+	t.Run("closed with error of handler", func(t *testing.T) {
+		conn := dial(t, "/failed")
+		defer conn.Close()
+
+		_, _, err := conn.ReadMessage()
+		closeErr, ok := err.(*WebSocketCloseError)
+		require.True(t, ok, err)
+		require.Equal(t, WebSocketCloseInternalError, closeErr.Code)
+		require.Equal(t, "something wrong", closeErr.Reason)
+
+		<-conn.Context().Done()
+	})
+
+	t.Run("ctx canceled when disconnected", func(t *testing.T) {
+		conn := dial(t, "/wait")
+		require.NoError(t, conn.Close())
+
+		select {
+		case <-disconnected:
+		case <-time.After(time.Second):
+			t.Fatal("ctx of handler should be canceled")
+		}
+	})
+
+	t.Run("cross-site handshake", func(t *testing.T) {
+		handshake := func(origin string) *http.Response {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/echo", nil)
+			req.Header.Set(HeaderConnection, "Upgrade")
+			req.Header.Set(HeaderUpgrade, "websocket")
+			req.Header.Set(HeaderSecWebSocketVersion, "13")
+			req.Header.Set(HeaderSecWebSocketKey, NewWebSocketKey())
+			req.Header.Set(HeaderOrigin, origin)
+
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			return resp
+		}
+
+		require.Equal(t, http.StatusForbidden, handshake("https://evil.example.com").StatusCode)
+		require.Equal(t, http.StatusSwitchingProtocols, handshake("http://"+srv.Listener.Addr().String()).StatusCode)
+	})
+
+	t.Run("invalid handshake", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/echo")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+		require.Equal(t, "13", resp.Header.Get(HeaderSecWebSocketVersion))
+	})
+}
+
+func TestWebSocketCheckOrigin(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+	require.True(t, WebSocketSameOrigin(req))
+
+	req.Header.Set(HeaderOrigin, "https://EXAMPLE.com")
+	require.True(t, WebSocketSameOrigin(req))
+
+	req.Header.Set(HeaderOrigin, "https://app.example.com")
+	require.False(t, WebSocketSameOrigin(req))
+
+	t.Run("custom in context", func(t *testing.T) {
+		ctx := ContextWithWebSocketCheckOrigin(context.Background(), func(r *http.Request) bool {
+			return r.Header.Get(HeaderOrigin) == "https://app.example.com"
+		})
+		require.True(t, WebSocketCheckOriginFromContext(ctx)(req))
+	})
+}
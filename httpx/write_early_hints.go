@@ -0,0 +1,12 @@
+//go:build !go1.19
+// +build !go1.19
+
+package httpx
+
+import (
+	"net/http"
+)
+
+// 103 will be taken as final status before go1.19
+func writeEarlyHints(rw http.ResponseWriter) {
+}
@@ -0,0 +1,12 @@
+//go:build go1.19
+// +build go1.19
+
+package httpx
+
+import (
+	"net/http"
+)
+
+func writeEarlyHints(rw http.ResponseWriter) {
+	rw.WriteHeader(http.StatusEarlyHints)
+}
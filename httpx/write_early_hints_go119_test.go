@@ -0,0 +1,43 @@
+//go:build go1.19
+// +build go1.19
+
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEarlyHints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		WriteEarlyHints(rw, PreloadLink("/style.css", "style"), PreloadLink("/app.js", "script"))
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	earlyHints := make([][]string, 0)
+
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				earlyHints = append(earlyHints, header[HeaderLink])
+			}
+			return nil
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, [][]string{{"</style.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}}, earlyHints)
+	require.Len(t, resp.Header.Values(HeaderLink), 2)
+}
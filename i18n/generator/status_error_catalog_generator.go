@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"log"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/packagesx"
+	"golang.org/x/tools/go/packages"
+)
+
+// NewStatusErrorCatalogGenerator generates msg catalogs of status errors by languages from comments of constants,
+// key of status error is the name of constant, same as the generator of statuserror.
+//
+//	const (
+//		// @errTalk Unauthorized
+//		// @i18n zh 未授权
+//		// @i18n ja 認証されていません
+//		Unauthorized StatusError = http.StatusUnauthorized*1e6 + iota + 1
+//	)
+func NewStatusErrorCatalogGenerator(pkg *packagesx.Package) *StatusErrorCatalogGenerator {
+	return &StatusErrorCatalogGenerator{
+		pkg:      pkg,
+		catalogs: map[string]*StatusErrorCatalog{},
+	}
+}
+
+type StatusErrorCatalogGenerator struct {
+	pkg      *packagesx.Package
+	catalogs map[string]*StatusErrorCatalog
+}
+
+func (g *StatusErrorCatalogGenerator) Scan(names ...string) {
+	for _, name := range names {
+		typeName := g.pkg.TypeName(name)
+		if typeName == nil {
+			panic(fmt.Errorf("type `%s` not found", name))
+		}
+
+		g.catalogs[name] = &StatusErrorCatalog{
+			TypeName: typeName,
+			Messages: g.scanMessages(typeName),
+		}
+	}
+}
+
+func (g *StatusErrorCatalogGenerator) scanMessages(typeName *types.TypeName) map[string]map[string]string {
+	messages := map[string]map[string]string{}
+
+	pkgInfo := g.pkg.Pkg(typeName.Pkg().Path())
+	if pkgInfo == nil {
+		return messages
+	}
+
+	for ident, def := range pkgInfo.TypesInfo.Defs {
+		typeConst, ok := def.(*types.Const)
+		if !ok || typeConst.Type() != typeName.Type() {
+			continue
+		}
+
+		valueSpec, ok := ident.Obj.Decl.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for lang, msg := range ParseStatusErrI18nMsgs(valueSpec.Doc.Text()) {
+			if messages[lang] == nil {
+				messages[lang] = map[string]string{}
+			}
+			messages[lang][typeConst.Name()] = msg
+		}
+	}
+
+	return messages
+}
+
+// ParseStatusErrI18nMsgs picks msg by language from lines like `@i18n zh-CN 未授权`,
+// language tags are lower cased.
+func ParseStatusErrI18nMsgs(doc string) map[string]string {
+	msgs := map[string]string{}
+
+	prefix := "@i18n "
+
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(line[len(prefix):]), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if msg := strings.TrimSpace(parts[1]); msg != "" {
+			msgs[strings.ToLower(parts[0])] = msg
+		}
+	}
+
+	return msgs
+}
+
+func getPkgDir(importPath string) string {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadFiles,
+	}, importPath)
+	if err != nil {
+		panic(err)
+	}
+	if len(pkgs) == 0 {
+		panic(fmt.Errorf("package `%s` not found", importPath))
+	}
+	return filepath.Dir(pkgs[0].GoFiles[0])
+}
+
+func (g *StatusErrorCatalogGenerator) Output(cwd string) {
+	for _, catalog := range g.catalogs {
+		dir, _ := filepath.Rel(cwd, getPkgDir(catalog.TypeName.Pkg().Path()))
+		filename := codegen.GeneratedFileSuffix(path.Join(dir, codegen.LowerSnakeCase(catalog.Name())+"_i18n.go"))
+
+		file := codegen.NewFile(catalog.TypeName.Pkg().Name(), filename)
+		catalog.WriteToFile(file)
+
+		if _, err := file.WriteFile(); err != nil {
+			log.Printf("%s generated", file)
+		}
+	}
+}
+
+type StatusErrorCatalog struct {
+	TypeName *types.TypeName
+	// msg by key of status error by language
+	Messages map[string]map[string]string
+}
+
+func (c *StatusErrorCatalog) Name() string {
+	return c.TypeName.Name()
+}
+
+func (c *StatusErrorCatalog) CatalogName() string {
+	return c.Name() + "MsgCatalog"
+}
+
+func (c *StatusErrorCatalog) WriteToFile(file *codegen.File) {
+	file.WriteBlock(
+		file.Expr("var ? = ?", codegen.Id(c.CatalogName()), file.Val(c.Messages)),
+	)
+
+	file.WriteBlock(
+		codegen.Func(codegen.Var(codegen.String, "lang")).
+			MethodOf(codegen.Var(codegen.Type(c.Name()), "v")).
+			Named("LocalizedMsg").
+			Return(codegen.Var(codegen.String), codegen.Var(codegen.Bool)).Do(
+			file.Expr(`msg, ok := ?[lang][v.Key()]
+return msg, ok`, codegen.Id(c.CatalogName())),
+		),
+	)
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-courier/codegen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusErrorCatalog(t *testing.T) {
+	catalog := &StatusErrorCatalog{
+		TypeName: types.NewTypeName(token.NoPos, types.NewPackage("github.com/go-courier/httptransport/__examples__/server/pkg/errors", "errors"), "StatusError", nil),
+		Messages: map[string]map[string]string{
+			"zh": {
+				"InternalServerError": "服务器内部错误",
+				"Unauthorized":        "未授权",
+			},
+			"ja": {
+				"Unauthorized": "認証されていません",
+			},
+		},
+	}
+
+	file := codegen.NewFile("errors", "status_error__i18n_generated.go")
+	catalog.WriteToFile(file)
+
+	require.Equal(t, `package errors
+
+var StatusErrorMsgCatalog = map[string]map[string]string{
+	"ja": map[string]string{
+		"Unauthorized": "認証されていません",
+	},
+	"zh": map[string]string{
+		"InternalServerError": "服务器内部错误",
+		"Unauthorized":        "未授权",
+	},
+}
+
+func (v StatusError) LocalizedMsg(lang string) (string, bool) {
+	msg, ok := StatusErrorMsgCatalog[lang][v.Key()]
+	return msg, ok
+}
+`, string(file.Bytes()))
+}
+
+func TestParseStatusErrI18nMsgs(t *testing.T) {
+	require.Equal(t, map[string]string{
+		"zh-cn": "未授权",
+	}, ParseStatusErrI18nMsgs("@errTalk Unauthorized\n@i18n zh-CN 未授权\n@i18n en\n"))
+}
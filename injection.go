@@ -0,0 +1,149 @@
+package httptransport
+
+import (
+	"context"
+	"go/ast"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// TagInject marks fields of operator populated by Injector before request decoded,
+// named by value of tag, or by type when empty
+//
+//	type GetUser struct {
+//		httpx.MethodGet
+//		ID    string    `name:"id" in:"path"`
+//		Users UserStore `inject:""`
+//		DB    *sql.DB   `inject:"primary"`
+//	}
+const TagInject = "inject"
+
+// Injector provides values of fields tagged `inject` of operators for each request,
+// values could be shared per process or created per request by ctx of request.
+type Injector interface {
+	Inject(ctx context.Context, name string, typ reflect.Type) (interface{}, error)
+}
+
+// NewContainer creates Injector of values registered by Provide
+func NewContainer() *Container {
+	return &Container{
+		providers: map[providerKey]func(ctx context.Context) (interface{}, error){},
+	}
+}
+
+// Container is simple Injector for values provided by name and type
+type Container struct {
+	providers map[providerKey]func(ctx context.Context) (interface{}, error)
+}
+
+type providerKey struct {
+	name string
+	typ  reflect.Type
+}
+
+// Provide registers value for fields tagged `inject:"<name>"`, name empty for fields tagged `inject:""`,
+// value of `func(ctx context.Context) (T, error)` called for each request as factory.
+// value matched by type assignable to field.
+func (c *Container) Provide(name string, v interface{}) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() == reflect.Func && isFactory(rv.Type()) {
+		c.providers[providerKey{name: name, typ: rv.Type().Out(0)}] = func(ctx context.Context) (interface{}, error) {
+			results := rv.Call([]reflect.Value{reflect.ValueOf(ctx)})
+			if err, _ := results[1].Interface().(error); err != nil {
+				return nil, err
+			}
+			return results[0].Interface(), nil
+		}
+		return
+	}
+
+	c.providers[providerKey{name: name, typ: rv.Type()}] = func(ctx context.Context) (interface{}, error) {
+		return v, nil
+	}
+}
+
+func (c *Container) Inject(ctx context.Context, name string, typ reflect.Type) (interface{}, error) {
+	if provide, ok := c.providers[providerKey{name: name, typ: typ}]; ok {
+		return provide(ctx)
+	}
+	// like implementation for field of interface
+	for key, provide := range c.providers {
+		if key.name == name && key.typ.AssignableTo(typ) {
+			return provide(ctx)
+		}
+	}
+	return nil, errors.Errorf("missing provider `%s` of %s", name, typ)
+}
+
+// isFactory checks whether type is func(ctx context.Context) (T, error)
+func isFactory(typ reflect.Type) bool {
+	return typ.NumIn() == 1 && typ.In(0) == rtypeContext &&
+		typ.NumOut() == 2 && typ.Out(1) == rtypeError
+}
+
+var (
+	rtypeContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+	rtypeError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type injectField struct {
+	Index     []int
+	FieldName string
+	// value of tag `inject`
+	Name string
+	Type reflect.Type
+}
+
+// injectFieldsOf collects fields tagged `inject` of operator type, embedded structs included
+func injectFieldsOf(typ reflect.Type) []injectField {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	fields := make([]injectField, 0)
+
+	if typ.Kind() != reflect.Struct {
+		return fields
+	}
+
+	var collect func(t reflect.Type, index []int)
+
+	collect = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fieldIndex := append(append([]int{}, index...), i)
+
+			if name, ok := f.Tag.Lookup(TagInject); ok && ast.IsExported(f.Name) {
+				fields = append(fields, injectField{Index: fieldIndex, FieldName: f.Name, Name: name, Type: f.Type})
+				continue
+			}
+
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				collect(f.Type, fieldIndex)
+			}
+		}
+	}
+
+	collect(typ, nil)
+
+	return fields
+}
+
+// inject populates fields of operator by injector
+func inject(ctx context.Context, injector Injector, fields []injectField, op interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(op))
+
+	for _, f := range fields {
+		v, err := injector.Inject(ctx, f.Name, f.Type)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			rv.FieldByIndex(f.Index).Set(reflect.ValueOf(v))
+		}
+	}
+
+	return nil
+}
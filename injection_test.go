@@ -0,0 +1,86 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type greeter struct {
+	prefix string
+}
+
+func (g *greeter) Greet(name string) string {
+	return g.prefix + name
+}
+
+type requestScoped struct {
+	RequestID string
+}
+
+type Greet struct {
+	httpx.MethodGet
+	Name    string         `name:"name" in:"query"`
+	Greeter Greeter        `inject:""`
+	Scoped  *requestScoped `inject:"scoped"`
+}
+
+func (req *Greet) Output(ctx context.Context) (interface{}, error) {
+	return req.Greeter.Greet(req.Name) + " " + req.Scoped.RequestID, nil
+}
+
+func TestInjector(t *testing.T) {
+	container := NewContainer()
+	container.Provide("", &greeter{prefix: "hello "})
+	container.Provide("scoped", func(ctx context.Context) (*requestScoped, error) {
+		r := HttpRequestFromContext(ctx)
+		if r.Header.Get("X-Request-ID") == "" {
+			return nil, errors.New("missing request id")
+		}
+		return &requestScoped{RequestID: r.Header.Get("X-Request-ID")}, nil
+	})
+
+	route := NewHttpRouteMeta(courier.NewRouter(&Greet{}).Routes()[0])
+	handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, route, NewRequestTransformerMgr(nil, nil))
+
+	require.Error(t, handler.checkInjector())
+
+	handler.injector = container
+	require.NoError(t, handler.checkInjector())
+
+	t.Run("populated per process and per request", func(t *testing.T) {
+		for _, id := range []string{"1", "2"} {
+			req := httptest.NewRequest(http.MethodGet, "/?name=x", nil)
+			req.Header.Set("X-Request-ID", id)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, "hello x "+id, strings.TrimSpace(rw.Body.String()))
+		}
+	})
+
+	t.Run("failed of factory", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/?name=x", nil))
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+
+	t.Run("injected fields skipped by request transformer and lint", func(t *testing.T) {
+		rt, err := NewRequestTransformerMgr(nil, nil).NewRequestTransformer(context.Background(), route.OperatorFactoryWithRouteMetas[0].Type)
+		require.NoError(t, err)
+		require.Len(t, rt.Parameters, 1)
+		require.Empty(t, LintOperatorState(route.OperatorFactoryWithRouteMetas[0].Type))
+	})
+}
@@ -0,0 +1,17 @@
+package metricsx
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterOrExisting returns registered collector when already registered,
+// to make sure handlers or round trippers created more than once could share collectors.
+func RegisterOrExisting(registerer prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := registerer.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,91 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/go-courier/logr"
+)
+
+// ShutdownHook runs when shutting down, before draining in-flight requests,
+// like deregistering from service discovery
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown appends hooks, see ShutdownHooks of HttpTransport
+func (t *HttpTransport) OnShutdown(hooks ...ShutdownHook) {
+	t.ShutdownHooks = append(t.ShutdownHooks, hooks...)
+}
+
+// Shutdown stops serving gracefully, not ready for /readyz first, then runs ShutdownHooks in order,
+// and stops accepting connections and drains in-flight requests until ctx done.
+// ServeContext returns once shut down, called by ServeContext when SIGTERM or SIGINT trapped or ctx of serving done.
+// Shutdown only once, later calls wait for and return the result of the first.
+func (t *HttpTransport) Shutdown(ctx context.Context) error {
+	t.lifecycle.shutdownOnce.Do(func() {
+		t.lifecycle.err = t.shutdown(ctx)
+		close(t.lifecycle.doneCh())
+	})
+
+	<-t.lifecycle.doneCh()
+
+	return t.lifecycle.err
+}
+
+func (t *HttpTransport) shutdown(ctx context.Context) error {
+	logger := logr.FromContext(ctx)
+
+	t.readiness.set(false, nil)
+
+	var err error
+
+	for _, hook := range t.ShutdownHooks {
+		// requests still being served, hooks should not break draining
+		if e := hook(ctx); e != nil {
+			logger.Error(e)
+			if err == nil {
+				err = e
+			}
+		}
+	}
+
+	servers := t.lifecycle.serving()
+
+	// public server first, admin listener kept for probes until draining done
+	for i := len(servers) - 1; i >= 0; i-- {
+		if e := servers[i].Shutdown(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+type lifecycle struct {
+	initOnce     sync.Once
+	shutdownOnce sync.Once
+	done         chan struct{}
+	err          error
+
+	mu      sync.Mutex
+	servers []*http.Server
+}
+
+func (l *lifecycle) doneCh() chan struct{} {
+	l.initOnce.Do(func() {
+		l.done = make(chan struct{})
+	})
+	return l.done
+}
+
+func (l *lifecycle) serve(srv *http.Server) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.servers = append(l.servers, srv)
+}
+
+func (l *lifecycle) serving() []*http.Server {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*http.Server{}, l.servers...)
+}
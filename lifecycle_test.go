@@ -0,0 +1,115 @@
+package httptransport_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+var slowStarted = make(chan struct{}, 1)
+
+type SlowPing struct {
+	httpx.MethodGet
+}
+
+func (SlowPing) Output(ctx context.Context) (interface{}, error) {
+	slowStarted <- struct{}{}
+	time.Sleep(100 * time.Millisecond)
+	return "pong", nil
+}
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func waitReady(t *testing.T, ht *httptransport.HttpTransport, port int) {
+	for i := 0; i < 100; i++ {
+		if ready, _ := ht.Readiness().Ready(); ready {
+			if conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port)); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server not ready")
+}
+
+func TestShutdown(t *testing.T) {
+	router := courier.NewRouter(httptransport.Group("/root"))
+	router.Register(courier.NewRouter(SlowPing{}))
+
+	t.Run("drain in-flight requests", func(t *testing.T) {
+		port := freePort(t)
+		called := make([]string, 0)
+
+		ht := &httptransport.HttpTransport{Port: port}
+		ht.OnShutdown(func(ctx context.Context) error {
+			ready, _ := ht.Readiness().Ready()
+			require.False(t, ready)
+			called = append(called, "deregister")
+			return nil
+		})
+
+		served := make(chan error, 1)
+		go func() {
+			served <- ht.ServeContext(context.Background(), router)
+		}()
+
+		waitReady(t, ht, port)
+
+		responded := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/root", port))
+			if err != nil {
+				responded <- 0
+				return
+			}
+			_ = resp.Body.Close()
+			responded <- resp.StatusCode
+		}()
+
+		<-slowStarted
+
+		require.NoError(t, ht.Shutdown(context.Background()))
+		require.Equal(t, http.StatusOK, <-responded)
+		require.Equal(t, []string{"deregister"}, called)
+		require.NoError(t, <-served)
+
+		// shut down once
+		require.NoError(t, ht.Shutdown(context.Background()))
+		require.Len(t, called, 1)
+	})
+
+	t.Run("shutdown when context done", func(t *testing.T) {
+		port := freePort(t)
+
+		ht := &httptransport.HttpTransport{Port: port, ShutdownTimeout: time.Second}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		served := make(chan error, 1)
+		go func() {
+			served <- ht.ServeContext(ctx, router)
+		}()
+
+		waitReady(t, ht, port)
+		cancel()
+
+		require.NoError(t, <-served)
+
+		_, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		require.Error(t, err)
+	})
+}
@@ -12,9 +12,8 @@ import (
 	"github.com/go-courier/logr"
 	"github.com/pkg/errors"
 
-	"github.com/go-courier/enumeration/scanner"
-
 	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/go-courier/oas"
 	"github.com/go-courier/packagesx"
 	"github.com/go-courier/reflectx/typesutil"
@@ -22,16 +21,20 @@ import (
 
 func NewDefinitionScanner(pkg *packagesx.Package) *DefinitionScanner {
 	return &DefinitionScanner{
-		enumScanner:       scanner.NewScanner(pkg),
+		enumScanner:       NewEnumScanner(pkg),
 		pkg:               pkg,
 		ioWriterInterface: packagesx.NewPackage(pkg.Pkg("io")).TypeName("Writer").Type().Underlying().(*types.Interface),
 	}
 }
 
 type DefinitionScanner struct {
+	// appends human-readable constraints of validate tags into descriptions of schemas, like "Range: [1, 100]"
+	DescribeValidations bool
+
 	pkg               *packagesx.Package
-	enumScanner       *scanner.Scanner
+	enumScanner       *EnumScanner
 	definitions       map[*types.TypeName]*oas.Schema
+	instances         map[string]*types.TypeName
 	schemas           map[string]*oas.Schema
 	ioWriterInterface *types.Interface
 }
@@ -47,11 +50,36 @@ func addExtension(s *oas.Schema, key string, v interface{}) {
 	}
 }
 
+// descriptionOfSchema returns description set by setMetaFromDoc
+func descriptionOfSchema(s *oas.Schema) string {
+	if s == nil {
+		return ""
+	}
+	if len(s.AllOf) > 0 {
+		return s.AllOf[len(s.AllOf)-1].Description
+	}
+	return s.Description
+}
+
 func setMetaFromDoc(s *oas.Schema, doc string) {
 	if s == nil {
 		return
 	}
 
+	doc, extensions := vendorExtensionsFromDoc(doc)
+	for key, value := range extensions {
+		addExtension(s, key, value)
+	}
+
+	doc, example, ok := exampleFromDoc(doc)
+	if ok {
+		typ := s.Type
+		if len(s.AllOf) > 0 {
+			typ = s.AllOf[len(s.AllOf)-1].Type
+		}
+		setExample(s, exampleValueOf(typ == oas.TypeString, example))
+	}
+
 	lines := strings.Split(doc, "\n")
 
 	for i := range lines {
@@ -89,7 +117,7 @@ func (scanner *DefinitionScanner) Def(ctx context.Context, typeName *types.TypeN
 	logr.FromContext(ctx).Debug("scanning Type `%s.%s`", typeName.Pkg().Path(), typeName.Name())
 
 	if typeName.IsAlias() {
-		typeName = typeName.Type().(*types.Named).Obj()
+		typeName = scanner.typeNameOf(typeName.Type().(*types.Named))
 	}
 
 	doc := scanner.pkg.CommentsOf(scanner.pkg.IdentOf(typeName.Type().(*types.Named).Obj()))
@@ -194,6 +222,56 @@ func (scanner *DefinitionScanner) Def(ctx context.Context, typeName *types.TypeN
 	return scanner.setDef(typeName, s)
 }
 
+// typeNameOf returns TypeName of named type,
+// each generic instantiation will be a standalone TypeName named with its type arguments, like Paged[User] to PagedUser
+func (scanner *DefinitionScanner) typeNameOf(named *types.Named) *types.TypeName {
+	if len(typeArgsOf(named)) == 0 {
+		return named.Obj()
+	}
+
+	key := types.TypeString(named, nil)
+
+	if typeName, ok := scanner.instances[key]; ok {
+		return typeName
+	}
+
+	if scanner.instances == nil {
+		scanner.instances = map[string]*types.TypeName{}
+	}
+
+	origin := named.Obj()
+	typeName := types.NewTypeName(origin.Pos(), origin.Pkg(), instanceName(named), named)
+	scanner.instances[key] = typeName
+
+	return typeName
+}
+
+func instanceName(named *types.Named) string {
+	name := named.Obj().Name()
+	for _, typeArg := range typeArgsOf(named) {
+		name += typeArgName(typeArg)
+	}
+	return name
+}
+
+func typeArgName(typ types.Type) string {
+	switch t := typ.(type) {
+	case *types.Named:
+		return instanceName(t)
+	case *types.Basic:
+		return codegen.UpperCamelCase(t.Name())
+	case *types.Pointer:
+		return typeArgName(t.Elem())
+	case *types.Slice:
+		return typeArgName(t.Elem()) + "List"
+	case *types.Array:
+		return typeArgName(t.Elem()) + "List"
+	case *types.Map:
+		return "Map" + typeArgName(t.Key()) + typeArgName(t.Elem())
+	}
+	return "Any"
+}
+
 func (scanner *DefinitionScanner) isInternal(typeName *types.TypeName) bool {
 	return strings.HasPrefix(typeName.Pkg().Path(), scanner.pkg.PkgPath)
 }
@@ -223,8 +301,13 @@ func (scanner *DefinitionScanner) reformatSchemas() {
 		typeNameList = append(typeNameList, v)
 	}
 
+	// internal types first, then by full type name
 	sort.Slice(typeNameList, func(i, j int) bool {
-		return scanner.isInternal(typeNameList[i]) && fullTypeName(typeNameList[i]) < fullTypeName(typeNameList[j])
+		a, b := typeNameList[i], typeNameList[j]
+		if isInternalA, isInternalB := scanner.isInternal(a), scanner.isInternal(b); isInternalA != isInternalB {
+			return isInternalA
+		}
+		return fullTypeName(a) < fullTypeName(b)
 	})
 
 	schemas := map[string]*oas.Schema{}
@@ -279,7 +362,7 @@ func (scanner *DefinitionScanner) GetSchemaByType(ctx context.Context, typ types
 		if t.String() == "mime/multipart.FileHeader" {
 			return oas.Binary()
 		}
-		return oas.RefSchemaByRefer(NewSchemaRefer(scanner.Def(ctx, t.Obj())))
+		return oas.RefSchemaByRefer(NewSchemaRefer(scanner.Def(ctx, scanner.typeNameOf(t))))
 	case *types.Interface:
 		return &oas.Schema{}
 	case *types.Basic:
@@ -362,6 +445,10 @@ func (scanner *DefinitionScanner) GetSchemaByType(ctx context.Context, typ types
 			if hasOmitempty, ok := flags["omitempty"]; ok {
 				required = !hasOmitempty
 			}
+			if flags[transformers.FlagCheckbox] {
+				// unchecked checkbox is missing
+				required = false
+			}
 
 			structSchema.SetProperty(
 				name,
@@ -379,6 +466,20 @@ func (scanner *DefinitionScanner) GetSchemaByType(ctx context.Context, typ types
 	return nil
 }
 
+// bindCheckbox documents coercion of checkbox in body of form or multipart
+func bindCheckbox(s *oas.Schema) {
+	s.Default = false
+	s.AddExtension(XCheckbox, map[string]interface{}{
+		"trueValues":  transformers.CheckboxTrueValues,
+		"falseValues": transformers.CheckboxFalseValues,
+	})
+
+	if s.Description != "" {
+		s.Description += "\n"
+	}
+	s.Description += "checkbox, checked by " + strings.Join(transformers.CheckboxTrueValues, ", ") + ", unchecked when missing"
+}
+
 func (scanner *DefinitionScanner) propSchemaByField(
 	ctx context.Context,
 	fieldName string,
@@ -406,16 +507,23 @@ func (scanner *DefinitionScanner) propSchemaByField(
 	}
 
 	if defaultValue != "" {
-		propSchema.Default = defaultValue
+		// typed as example, like 10 of int
+		propSchema.Default = exampleValueOf(isStringType(fieldType), defaultValue)
+	}
+
+	// before binding validations, which may append constraints to description
+	setMetaFromDoc(propSchema, desc)
+
+	if flags != nil && flags[transformers.FlagCheckbox] {
+		bindCheckbox(propSchema)
 	}
 
 	if hasValidate {
-		if err := BindSchemaValidationByValidateBytes(propSchema, fieldType, []byte(validate)); err != nil {
+		if err := bindSchemaValidationByValidateBytes(propSchema, fieldType, []byte(validate), scanner.DescribeValidations); err != nil {
 			panic(err)
 		}
 	}
 
-	setMetaFromDoc(propSchema, desc)
 	propSchema.AddExtension(XGoFieldName, fieldName)
 
 	tagKeys := map[string]string{
@@ -432,6 +540,14 @@ func (scanner *DefinitionScanner) propSchemaByField(
 		}
 	}
 
+	if tag, ok := tags.Lookup(TagOpenAPI); ok {
+		bindVendorExtensions(propSchema, VendorExtensionsFromTag(tag))
+	}
+
+	if example, ok := tags.Lookup(TagExample); ok {
+		propSchema.Example = exampleValueOf(isStringType(fieldType), example)
+	}
+
 	if refSchema != nil {
 		return oas.AllOf(
 			refSchema,
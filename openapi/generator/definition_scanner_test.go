@@ -322,6 +322,19 @@ func TestDefinitionScanner(t *testing.T) {
 		})
 	}
 
+	t.Run("describe validations", func(t *testing.T) {
+		scanner := NewDefinitionScanner(pkg)
+		scanner.DescribeValidations = true
+
+		s := scanner.Def(context.Background(), pkg.TypeName("Struct"))
+
+		require.Equal(t, "Enum: ONE", descriptionOfSchema(s.Properties["enum"]))
+		require.Equal(t, "Pattern: \\d+", descriptionOfSchema(s.Properties["id"]))
+		require.Equal(t, "Properties: [0, 3]", descriptionOfSchema(s.Properties["map"]))
+		require.Equal(t, "name\nLength: [2, +∞)", descriptionOfSchema(s.Properties["name"]))
+		require.Equal(t, "Items: [1, 3]", descriptionOfSchema(s.Properties["slice"]))
+	})
+
 	t.Run("bind", func(t *testing.T) {
 		openAPI := oas.NewOpenAPI()
 		openAPI.AddOperation(oas.GET, "/", oas.NewOperation("test"))
@@ -349,3 +362,21 @@ func tryCatch(fn func()) (err error) {
 	fn()
 	return nil
 }
+
+func TestBindCheckbox(t *testing.T) {
+	s := oas.Boolean()
+	s.Description = "agree terms"
+
+	bindCheckbox(s)
+
+	data, _ := json.Marshal(s)
+	require.JSONEq(t, `{
+  "type": "boolean",
+  "default": false,
+  "description": "agree terms\ncheckbox, checked by on, true, 1, yes, unchecked when missing",
+  "x-checkbox": {
+    "trueValues": ["on", "true", "1", "yes"],
+    "falseValues": ["off", "false", "0", "no", ""]
+  }
+}`, string(data))
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/enumeration/scanner"
+	"github.com/go-courier/packagesx"
+)
+
+func NewEnumScanner(pkg *packagesx.Package) *EnumScanner {
+	return &EnumScanner{
+		pkg: pkg,
+	}
+}
+
+// EnumScanner scans enum options of named type from consts of the type in its package
+//
+//	string consts                     values are string literals
+//	int consts named TYPE_NAME__KEY   values are KEYs, as generated by `courier gen enum`, TYPE_NAME_UNKNOWN skipped
+//	int or float consts, like iota    values are numbers
+//
+// label of option is trailing comment of the const, or first line of its doc without leading name, or name of the const.
+type EnumScanner struct {
+	pkg     *packagesx.Package
+	results map[*types.TypeName]scanner.Options
+	specs   map[*ast.Ident]*constSpec
+}
+
+type constSpec struct {
+	spec    *ast.ValueSpec
+	genDecl *ast.GenDecl
+}
+
+func (s *EnumScanner) Options(typeName *types.TypeName) (scanner.Options, bool) {
+	if typeName == nil || typeName.Pkg() == nil {
+		return nil, false
+	}
+
+	if options, ok := s.results[typeName]; ok {
+		return options, len(options) > 0
+	}
+
+	if s.results == nil {
+		s.results = map[*types.TypeName]scanner.Options{}
+	}
+
+	options := s.scan(typeName)
+	s.results[typeName] = options
+
+	return options, len(options) > 0
+}
+
+func (s *EnumScanner) scan(typeName *types.TypeName) scanner.Options {
+	pkg := s.pkg.Pkg(typeName.Pkg().Path())
+	if pkg == nil {
+		return nil
+	}
+
+	prefix := codegen.UpperSnakeCase(typeName.Name())
+
+	options := scanner.Options{}
+
+	for ident, def := range pkg.TypesInfo.Defs {
+		c, ok := def.(*types.Const)
+		if !ok || !types.Identical(c.Type(), typeName.Type()) {
+			continue
+		}
+
+		name := c.Name()
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		label := s.labelOf(pkg.Syntax, ident)
+
+		switch val := c.Val(); val.Kind() {
+		case constant.String:
+			options = append(options, *scanner.NewStrOption(constant.StringVal(val), label))
+		case constant.Int:
+			i, ok := constant.Int64Val(val)
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(name, prefix+"_") {
+				// TYPE_NAME_UNKNOWN as zero value
+				values := strings.SplitN(name, "__", 2)
+				if len(values) != 2 {
+					continue
+				}
+				options = append(options, *scanner.NewIntStringerOption(i, values[1], label))
+				continue
+			}
+			if label == "" {
+				label = name
+			}
+			options = append(options, *scanner.NewIntOption(i, label))
+		case constant.Float:
+			f, _ := constant.Float64Val(val)
+			if label == "" {
+				label = name
+			}
+			options = append(options, *scanner.NewFloatOption(f, label))
+		default:
+			// bool or complex could not be enum
+			return nil
+		}
+	}
+
+	sort.Sort(options)
+
+	return options
+}
+
+func (s *EnumScanner) labelOf(files []*ast.File, ident *ast.Ident) string {
+	if s.specs == nil {
+		s.specs = map[*ast.Ident]*constSpec{}
+	}
+
+	cs, ok := s.specs[ident]
+	if !ok {
+		s.indexConstSpecs(files)
+		cs = s.specs[ident]
+	}
+
+	if cs == nil {
+		return ""
+	}
+
+	if cs.spec.Comment != nil {
+		if label := strings.TrimSpace(cs.spec.Comment.Text()); label != "" {
+			return label
+		}
+	}
+
+	doc := cs.spec.Doc
+	if doc == nil && !cs.genDecl.Lparen.IsValid() {
+		// single const declaration
+		doc = cs.genDecl.Doc
+	}
+
+	if doc != nil {
+		lines := filterMarkedLines(strings.Split(strings.TrimSpace(doc.Text()), "\n"))
+		if len(lines) > 0 {
+			// drop name of const leading doc by convention
+			return strings.TrimSpace(strings.TrimPrefix(lines[0], ident.Name+" "))
+		}
+	}
+
+	return ""
+}
+
+func (s *EnumScanner) indexConstSpecs(files []*ast.File) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for _, name := range valueSpec.Names {
+					s.specs[name] = &constSpec{spec: valueSpec, genDecl: genDecl}
+				}
+			}
+		}
+	}
+}
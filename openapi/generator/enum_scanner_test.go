@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-courier/enumeration/scanner"
+	"github.com/go-courier/packagesx"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func loadPackageFromSource(t *testing.T, src string) *packagesx.Package {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "enums.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("github.com/demo/enums", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	return packagesx.NewPackage(&packages.Package{
+		ID:        pkg.Path(),
+		PkgPath:   pkg.Path(),
+		Fset:      fset,
+		Syntax:    []*ast.File{file},
+		Types:     pkg,
+		TypesInfo: info,
+	})
+}
+
+func TestEnumScanner(t *testing.T) {
+	pkg := loadPackageFromSource(t, `package enums
+
+type Status int
+
+const (
+	// StatusPending waiting for review
+	StatusPending Status = iota + 1
+	StatusActive // active
+	StatusClosed
+)
+
+type Color string
+
+const (
+	ColorRed   Color = "red"   // Red
+	ColorGreen Color = "green"
+	_colorHidden Color = "hidden"
+)
+
+// ColorBlue blue
+const ColorBlue Color = "blue"
+
+type Level int
+
+const (
+	LEVEL_UNKNOWN Level = iota
+	LEVEL__LOW   // low
+	LEVEL__HIGH  // high
+)
+
+type Flag bool
+
+const FlagOn Flag = true
+
+type Plain string
+`)
+
+	s := NewEnumScanner(pkg)
+
+	t.Run("iota", func(t *testing.T) {
+		options, ok := s.Options(pkg.TypeName("Status"))
+		require.True(t, ok)
+		require.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, options.Values())
+		require.Equal(t, []string{"waiting for review", "active", "StatusClosed"}, labelsOf(options))
+	})
+
+	t.Run("string consts", func(t *testing.T) {
+		options, ok := s.Options(pkg.TypeName("Color"))
+		require.True(t, ok)
+		require.Equal(t, []interface{}{"blue", "green", "red"}, options.Values())
+		require.Equal(t, []string{"blue", "green", "Red"}, labelsOf(options))
+	})
+
+	t.Run("generated enum", func(t *testing.T) {
+		options, ok := s.Options(pkg.TypeName("Level"))
+		require.True(t, ok)
+		require.Equal(t, []interface{}{"LOW", "HIGH"}, options.Values())
+		require.Equal(t, []string{"low", "high"}, labelsOf(options))
+	})
+
+	t.Run("not enum", func(t *testing.T) {
+		_, ok := s.Options(pkg.TypeName("Flag"))
+		require.False(t, ok)
+
+		_, ok = s.Options(pkg.TypeName("Plain"))
+		require.False(t, ok)
+	})
+}
+
+func labelsOf(options scanner.Options) []string {
+	labels := make([]string, 0, len(options))
+	for _, o := range options {
+		labels = append(labels, o.Label)
+	}
+	return labels
+}
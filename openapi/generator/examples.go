@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"encoding/json"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// TagExample declares example of field or parameter,
+//
+//	`example:"2006-01-02"`
+//
+// example of type or field also could be declared by line in comments
+//
+//	openapi:example {"name":"x"}
+//
+// values of strings are used as is, others are json when valid, otherwise strings.
+const TagExample = "example"
+
+var reExample = regexp.MustCompile(`^\s*open-?api:example\s+(.+)$`)
+
+// exampleFromDoc parses example from line of doc, and returns doc without it
+func exampleFromDoc(doc string) (string, string, bool) {
+	lines := strings.Split(doc, "\n")
+	rest := make([]string, 0, len(lines))
+
+	example, ok := "", false
+
+	for _, line := range lines {
+		if matched := reExample.FindStringSubmatch(line); matched != nil {
+			example, ok = strings.TrimSpace(matched[1]), true
+			continue
+		}
+		rest = append(rest, line)
+	}
+
+	return strings.Join(rest, "\n"), example, ok
+}
+
+// exampleValueOf returns example value by type, raw string kept for string types
+func exampleValueOf(isString bool, example string) interface{} {
+	if isString {
+		return example
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(example), &v); err == nil {
+		return v
+	}
+
+	return example
+}
+
+func isStringType(typ types.Type) bool {
+	if pointer, ok := typ.(*types.Pointer); ok {
+		return isStringType(pointer.Elem())
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+func setExample(s *oas.Schema, example interface{}) {
+	if s == nil {
+		return
+	}
+	if len(s.AllOf) > 0 {
+		s.AllOf[len(s.AllOf)-1].Example = example
+	} else {
+		s.Example = example
+	}
+}
+
+// summaryOfDoc returns first line of doc without marked lines, for descriptions of responses
+func summaryOfDoc(doc string) string {
+	doc, _ = vendorExtensionsFromDoc(doc)
+	doc, _, _ = exampleFromDoc(doc)
+
+	for _, line := range filterMarkedLines(strings.Split(doc, "\n")) {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"context"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleFromDoc(t *testing.T) {
+	doc, example, ok := exampleFromDoc("user of account\nopenapi:example {\"name\":\"x\"}\n@deprecated")
+	require.True(t, ok)
+	require.Equal(t, `{"name":"x"}`, example)
+	require.Equal(t, "user of account\n@deprecated", doc)
+
+	_, _, ok = exampleFromDoc("user of account")
+	require.False(t, ok)
+}
+
+func TestExampleValueOf(t *testing.T) {
+	require.Equal(t, "123", exampleValueOf(true, "123"))
+	require.Equal(t, float64(123), exampleValueOf(false, "123"))
+	require.Equal(t, []interface{}{"a", "b"}, exampleValueOf(false, `["a","b"]`))
+	require.Equal(t, "not json", exampleValueOf(false, "not json"))
+}
+
+func TestExamplesOfSchema(t *testing.T) {
+	t.Run("by doc", func(t *testing.T) {
+		s := oas.ObjectOf(oas.Props{"name": oas.String()})
+		setMetaFromDoc(s, "user of account\nopenapi:example {\"name\":\"x\"}")
+
+		require.Equal(t, "user of account", s.Description)
+		require.Equal(t, map[string]interface{}{"name": "x"}, s.Example)
+
+		str := oas.String()
+		setMetaFromDoc(str, "openapi:example 001")
+		require.Equal(t, "001", str.Example)
+	})
+
+	t.Run("by tag", func(t *testing.T) {
+		scanner := &DefinitionScanner{}
+
+		s := scanner.propSchemaByField(context.Background(), "Age", types.Typ[types.Int], reflect.StructTag(`json:"age" example:"18"`), "age", nil, "")
+		require.Equal(t, float64(18), s.Example)
+
+		s = scanner.propSchemaByField(context.Background(), "Code", types.Typ[types.String], reflect.StructTag(`json:"code" example:"007"`), "code", nil, "")
+		require.Equal(t, "007", s.Example)
+	})
+}
+
+func TestSummaryOfDoc(t *testing.T) {
+	require.Equal(t, "User of account", summaryOfDoc("\nUser of account\nopenapi:x-internal\nmore details"))
+	require.Equal(t, "", summaryOfDoc("@deprecated"))
+}
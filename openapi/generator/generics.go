@@ -0,0 +1,12 @@
+//go:build !go1.18
+// +build !go1.18
+
+package generator
+
+import (
+	"go/types"
+)
+
+func typeArgsOf(named *types.Named) []types.Type {
+	return nil
+}
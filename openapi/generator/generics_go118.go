@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package generator
+
+import (
+	"go/types"
+)
+
+func typeArgsOf(named *types.Named) []types.Type {
+	typeArgs := named.TypeArgs()
+	if typeArgs == nil {
+		return nil
+	}
+	list := make([]types.Type, typeArgs.Len())
+	for i := range list {
+		list[i] = typeArgs.At(i)
+	}
+	return list
+}
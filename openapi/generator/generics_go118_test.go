@@ -0,0 +1,83 @@
+//go:build go1.18
+// +build go1.18
+
+package generator
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceName(t *testing.T) {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "generics.go", `package generics
+
+type User struct {}
+
+type Paged[T any] struct {
+	Data  []T
+	Total int
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+var (
+	PagedUser        Paged[User]
+	PagedUserPtr     Paged[*User]
+	PagedString      Paged[string]
+	PagedUserList    Paged[[]User]
+	PairStringUser   Pair[string, User]
+	PagedPagedUser   Paged[Paged[User]]
+	PagedMapStringID Paged[map[string]int]
+)
+`, 0)
+	require.NoError(t, err)
+
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("generics", fset, []*ast.File{f}, nil)
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"PagedUser":        "PagedUser",
+		"PagedUserPtr":     "PagedUser",
+		"PagedString":      "PagedString",
+		"PagedUserList":    "PagedUserList",
+		"PairStringUser":   "PairStringUser",
+		"PagedPagedUser":   "PagedPagedUser",
+		"PagedMapStringID": "PagedMapStringInt",
+	}
+
+	for varName, name := range cases {
+		t.Run(varName, func(t *testing.T) {
+			named := pkg.Scope().Lookup(varName).Type().(*types.Named)
+			require.Equal(t, name, instanceName(named))
+		})
+	}
+
+	t.Run("instances should be standalone type names", func(t *testing.T) {
+		scanner := &DefinitionScanner{}
+
+		pagedUser := scanner.typeNameOf(pkg.Scope().Lookup("PagedUser").Type().(*types.Named))
+		pagedString := scanner.typeNameOf(pkg.Scope().Lookup("PagedString").Type().(*types.Named))
+
+		require.Equal(t, "PagedUser", pagedUser.Name())
+		require.Equal(t, "PagedString", pagedString.Name())
+		require.NotEqual(t, pagedUser, pagedString)
+		require.Equal(t, pagedUser, scanner.typeNameOf(pkg.Scope().Lookup("PagedUser").Type().(*types.Named)))
+
+		_, ok := pagedUser.Type().Underlying().(*types.Struct).Field(0).Type().(*types.Slice).Elem().(*types.Named)
+		require.True(t, ok)
+
+		user := pkg.Scope().Lookup("User").(*types.TypeName)
+		require.Equal(t, user, scanner.typeNameOf(user.Type().(*types.Named)))
+	})
+}
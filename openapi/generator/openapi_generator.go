@@ -1,10 +1,14 @@
 package generator
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
 	"go/ast"
 	"go/types"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -13,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/go-courier/codegen"
 	"github.com/go-courier/oas"
 	"github.com/go-courier/packagesx"
 	"github.com/pkg/errors"
@@ -26,10 +31,50 @@ func NewOpenAPIGenerator(pkg *packagesx.Package) *OpenAPIGenerator {
 	}
 }
 
+const (
+	OutputFormatJSON = "json"
+	OutputFormatYAML = "yaml"
+)
+
+// modes of scanning when several root routers served in main, like public api and admin api on different ports
+const (
+	// operations of all root routers in one spec, by default
+	RootRoutersMerged = "merged"
+	// operations of all root routers in one spec, tagged by name of root router
+	RootRoutersTagged = "tagged"
+	// spec of each root router, output as openapi.<name>.json.
+	// name of root router is var name in lower snake case, like admin_router
+	RootRoutersSplit = "split"
+)
+
 type OpenAPIGenerator struct {
+	// output in canonical serialization, see MarshalCanonical
+	Canonical bool
+	// OutputFormatJSON or OutputFormatYAML, default by ext of Filename, or json
+	Format string
+	// filename of Output, relative to cwd when not absolute, "-" for stdout.
+	// default openapi.json or openapi.yaml by Format
+	Filename string
+	// RootRoutersMerged, RootRoutersTagged or RootRoutersSplit, default RootRoutersMerged
+	RootRouters string
+	// compare with spec previously written into Filename by Output, changes logged,
+	// and ErrBreakingChanges returned without writing when breaking changes found, to gate api breakage in ci
+	Diff bool
+	// visit spec in order once scanned, before output, see Plugin
+	Plugins []Plugin
+
 	pkg           *packagesx.Package
 	openapi       *oas.OpenAPI
+	splits        []*rootRouterSpec
 	routerScanner *RouterScanner
+
+	pluginsApplied bool
+	pluginsErr     error
+}
+
+type rootRouterSpec struct {
+	name    string
+	openapi *oas.OpenAPI
 }
 
 func rootRouter(pkgInfo *packagesx.Package, callExpr *ast.CallExpr) *types.Var {
@@ -55,10 +100,40 @@ func rootRouter(pkgInfo *packagesx.Package, callExpr *ast.CallExpr) *types.Var {
 }
 
 func (g *OpenAPIGenerator) Scan(ctx context.Context) {
+	rootRouterVars := g.rootRouterVars()
+
+	// operationID should be unique in each spec
+	operationIDs := map[string]*Route{}
+
 	defer func() {
 		g.routerScanner.operatorScanner.BindSchemas(g.openapi)
+
+		for i := range g.splits {
+			g.routerScanner.operatorScanner.BindSchemas(g.splits[i].openapi)
+			pruneSchemas(g.splits[i].openapi)
+		}
 	}()
 
+	for _, rootRouterVar := range rootRouterVars {
+		name := codegen.LowerSnakeCase(rootRouterVar.Name())
+
+		switch g.RootRouters {
+		case RootRoutersSplit:
+			split := &rootRouterSpec{name: name, openapi: oas.NewOpenAPI()}
+			g.scanRootRouter(split.openapi, map[string]*Route{}, rootRouterVar, "")
+			g.splits = append(g.splits, split)
+		case RootRoutersTagged:
+			g.scanRootRouter(g.openapi, operationIDs, rootRouterVar, name)
+		default:
+			g.scanRootRouter(g.openapi, operationIDs, rootRouterVar, "")
+		}
+	}
+}
+
+// rootRouterVars returns all root routers served or ran in main, in order of calls
+func (g *OpenAPIGenerator) rootRouterVars() []*types.Var {
+	rootRouterVars := make([]*types.Var, 0)
+
 	for ident, def := range g.pkg.TypesInfo.Defs {
 		if typFunc, ok := def.(*types.Func); ok {
 			if typFunc.Name() != "main" {
@@ -69,34 +144,67 @@ func (g *OpenAPIGenerator) Scan(ctx context.Context) {
 				switch n := node.(type) {
 				case *ast.CallExpr:
 					if rootRouterVar := rootRouter(g.pkg, n); rootRouterVar != nil {
-						router := g.routerScanner.Router(rootRouterVar)
+						for _, v := range rootRouterVars {
+							if v == rootRouterVar {
+								return true
+							}
+						}
+						rootRouterVars = append(rootRouterVars, rootRouterVar)
+					}
+				}
+				return true
+			})
 
-						routes := router.Routes()
+			break
+		}
+	}
 
-						operationIDs := map[string]*Route{}
+	return rootRouterVars
+}
 
-						for _, route := range routes {
-							method := route.Method()
+func (g *OpenAPIGenerator) scanRootRouter(openapi *oas.OpenAPI, operationIDs map[string]*Route, rootRouterVar *types.Var, tag string) {
+	router := g.routerScanner.Router(rootRouterVar)
 
-							operation := g.OperationByOperatorTypes(method, route.Operators...)
+	for _, route := range router.Routes() {
+		method := route.Method()
 
-							if _, exists := operationIDs[operation.OperationId]; exists {
-								panic(errors.Errorf("operationID %s should be unique", operation.OperationId))
-							}
+		operation := g.operationByOperatorTypes(openapi, method, route.Operators...)
 
-							operationIDs[operation.OperationId] = route
+		if tag != "" {
+			operation.Tags = append(operation.Tags, tag)
+		}
 
-							g.openapi.AddOperation(oas.HttpMethod(strings.ToLower(method)), g.patchPath(route.Path(), operation), operation)
-						}
-					}
-				}
-				return true
-			})
-			return
+		if _, exists := operationIDs[operation.OperationId]; exists {
+			panic(errors.Errorf("operationID %s should be unique", operation.OperationId))
+		}
+
+		operationIDs[operation.OperationId] = route
+
+		openapi.AddOperation(oas.HttpMethod(strings.ToLower(method)), g.patchPath(route.Path(), operation), operation)
+
+		if n := len(route.Operators); n > 0 {
+			addWebhooks(openapi, route.Operators[n-1].Webhooks)
 		}
 	}
 }
 
+// LoadServers adds servers from config file into openapi spec
+// which could be used for multi-environment (dev/staging/prod) portals
+func (g *OpenAPIGenerator) LoadServers(configFile string) error {
+	c, err := LoadServersConfig(configFile)
+	if err != nil {
+		return err
+	}
+	servers, err := c.OpenAPIServers()
+	if err != nil {
+		return err
+	}
+	for i := range servers {
+		g.openapi.AddServer(servers[i])
+	}
+	return nil
+}
+
 var reHttpRouterPath = regexp.MustCompile("/:([^/]+)")
 
 func (g *OpenAPIGenerator) patchPath(openapiPath string, operation *oas.Operation) string {
@@ -120,6 +228,10 @@ func (g *OpenAPIGenerator) patchPath(openapiPath string, operation *oas.Operatio
 }
 
 func (g *OpenAPIGenerator) OperationByOperatorTypes(method string, operatorTypes ...*OperatorWithTypeName) *oas.Operation {
+	return g.operationByOperatorTypes(g.openapi, method, operatorTypes...)
+}
+
+func (g *OpenAPIGenerator) operationByOperatorTypes(openapi *oas.OpenAPI, method string, operatorTypes ...*OperatorWithTypeName) *oas.Operation {
 	operation := &oas.Operation{}
 
 	length := len(operatorTypes)
@@ -128,15 +240,301 @@ func (g *OpenAPIGenerator) OperationByOperatorTypes(method string, operatorTypes
 		operatorTypes[idx].BindOperation(method, operation, idx == length-1)
 	}
 
+	bindSecuritySchemes(openapi, operation, operatorTypes...)
+
 	return operation
 }
 
-func (g *OpenAPIGenerator) Output(cwd string) {
-	file := filepath.Join(cwd, "openapi.json")
-	data, err := json.MarshalIndent(g.openapi, "", "  ")
+// OpenAPI returns spec of scanned routes visited by Plugins with x-spec-hash, should be called after Scan.
+// error of Plugins returned by Marshal and Output.
+func (g *OpenAPIGenerator) OpenAPI() *oas.OpenAPI {
+	_ = g.applyPlugins()
+
+	if specHash, err := SpecHash(g.openapi); err == nil {
+		g.openapi.AddExtension(XSpecHash, specHash)
+	}
+	return g.openapi
+}
+
+// OpenAPIs returns spec of each root router by name with servers and x-spec-hash,
+// only when RootRouters is RootRoutersSplit, should be called after Scan
+func (g *OpenAPIGenerator) OpenAPIs() map[string]*oas.OpenAPI {
+	_ = g.applyPlugins()
+
+	openapis := map[string]*oas.OpenAPI{}
+
+	for _, split := range g.splits {
+		openapi := split.openapi
+		openapi.Servers = g.openapi.Servers
+
+		if specHash, err := SpecHash(openapi); err == nil {
+			openapi.AddExtension(XSpecHash, specHash)
+		}
+
+		openapis[split.name] = openapi
+	}
+
+	return openapis
+}
+
+// Marshal spec of scanned routes with x-spec-hash in Format, should be called after Scan
+func (g *OpenAPIGenerator) Marshal() ([]byte, error) {
+	return g.marshal(g.OpenAPI())
+}
+
+func (g *OpenAPIGenerator) marshal(openapi *oas.OpenAPI) ([]byte, error) {
+	if err := g.applyPlugins(); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var err error
+
+	if g.Canonical {
+		data, err = MarshalCanonical(openapi)
+	} else {
+		data, err = json.MarshalIndent(openapi, "", "  ")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal openapi spec failed")
+	}
+
+	switch format := g.format(); format {
+	case OutputFormatJSON:
+		return data, nil
+	case OutputFormatYAML:
+		return JSONToYAML(data)
+	default:
+		return nil, errors.Errorf("unsupported output format %s", format)
+	}
+}
+
+// WriteTo writes marshaled spec into w, like os.Stdout
+func (g *OpenAPIGenerator) WriteTo(w io.Writer) (int64, error) {
+	data, err := g.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// Output writes spec into Filename under cwd, or stdout when Filename is "-".
+// when RootRouters is RootRoutersSplit, spec of each root router written into Filename with name before ext,
+// like openapi.admin_router.json
+func (g *OpenAPIGenerator) Output(cwd string) error {
+	if g.RootRouters == RootRoutersSplit {
+		if g.Filename == "-" {
+			return errors.New("split specs of root routers could not be written into stdout")
+		}
+
+		openapis := g.OpenAPIs()
+
+		for _, split := range g.splits {
+			data, err := g.marshal(openapis[split.name])
+			if err != nil {
+				return err
+			}
+			if err := g.diffAndWriteFile(cwd, splitFilename(g.filename(), split.name), openapis[split.name], data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if g.Filename == "-" {
+		_, err := g.WriteTo(os.Stdout)
+		return err
+	}
+
+	openapi := g.OpenAPI()
+
+	data, err := g.marshal(openapi)
+	if err != nil {
+		return err
+	}
+
+	return g.diffAndWriteFile(cwd, g.filename(), openapi, data)
+}
+
+func (g *OpenAPIGenerator) diffAndWriteFile(cwd string, file string, openapi *oas.OpenAPI, data []byte) error {
+	if g.Diff {
+		if err := g.diff(cwd, file, openapi); err != nil {
+			return err
+		}
+	}
+	return g.writeFile(cwd, file, data)
+}
+
+// diff compares openapi with previous spec in file, skipped when file not exists
+func (g *OpenAPIGenerator) diff(cwd string, file string, openapi *oas.OpenAPI) error {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(cwd, file)
+	}
+
+	prevData, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	prev, err := LoadOpenAPI(prevData, g.format())
+	if err != nil {
+		return errors.Wrapf(err, "load previous openapi spec %s failed", file)
+	}
+
+	// round trip to compare in same form of previous
+	nextData, err := json.Marshal(openapi)
 	if err != nil {
-		return
+		return err
 	}
-	_ = ioutil.WriteFile(file, data, os.ModePerm)
+	next, err := LoadOpenAPI(nextData, OutputFormatJSON)
+	if err != nil {
+		return err
+	}
+
+	changes := DiffOpenAPI(prev, next)
+
+	for _, change := range changes {
+		if change.Breaking {
+			log.Print(color.RedString("%s", change))
+		} else {
+			log.Print(change)
+		}
+	}
+
+	if breaking := changes.Breaking(); len(breaking) > 0 {
+		return errors.Wrapf(ErrBreakingChanges, "%d of %s", len(breaking), file)
+	}
+
+	return nil
+}
+
+func (g *OpenAPIGenerator) writeFile(cwd string, file string, data []byte) error {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(cwd, file)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return errors.Wrapf(err, "write openapi spec into %s failed", file)
+	}
+
 	log.Printf("generated openapi spec into %s", color.MagentaString(file))
+	return nil
+}
+
+func (g *OpenAPIGenerator) filename() string {
+	if g.Filename == "" {
+		return "openapi." + g.format()
+	}
+	return g.Filename
+}
+
+// splitFilename inserts name of root router before ext of filename
+func splitFilename(filename string, name string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + name + ext
+}
+
+var reSchemaRef = regexp.MustCompile(`"#/components/schemas/([^"]+)"`)
+
+// pruneSchemas drops schemas not referenced by operations of openapi,
+// schemas scanned are shared by all root routers.
+func pruneSchemas(openapi *oas.OpenAPI) {
+	schemas := openapi.Components.Schemas
+	used := map[string]*oas.Schema{}
+
+	data, _ := json.Marshal(openapi.Paths)
+
+	for refs := reSchemaRef.FindAllSubmatch(data, -1); len(refs) > 0; {
+		next := make([][][]byte, 0)
+
+		for _, ref := range refs {
+			name := string(ref[1])
+			if _, ok := used[name]; ok {
+				continue
+			}
+			s, ok := schemas[name]
+			if !ok {
+				continue
+			}
+			used[name] = s
+
+			d, _ := json.Marshal(s)
+			next = append(next, reSchemaRef.FindAllSubmatch(d, -1)...)
+		}
+
+		refs = next
+	}
+
+	openapi.Components.Schemas = used
+}
+
+func (g *OpenAPIGenerator) format() string {
+	if g.Format != "" {
+		return g.Format
+	}
+	switch filepath.Ext(g.Filename) {
+	case ".yaml", ".yml":
+		return OutputFormatYAML
+	}
+	return OutputFormatJSON
+}
+
+// SpecHash returns sha256 of canonical serialization of openapi spec without x-spec-hash,
+// for detecting drift between deployed server and client build.
+func SpecHash(openapi *oas.OpenAPI) (string, error) {
+	o := *openapi
+
+	if _, ok := o.Extensions[XSpecHash]; ok {
+		extensions := map[string]interface{}{}
+		for k, v := range o.Extensions {
+			if k != XSpecHash {
+				extensions[k] = v
+			}
+		}
+		o.Extensions = extensions
+	}
+
+	data, err := MarshalCanonical(o)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// MarshalCanonical marshals v as json with keys of all objects sorted,
+// two spaces indent and trailing newline, to make sure same spec always be same bytes.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var values interface{}
+	if err := d.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	e := json.NewEncoder(buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	if err := e.Encode(values); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
 }
@@ -1,12 +1,15 @@
 package generator
 
 import (
+	"bytes"
 	"context"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/go-courier/logr"
+	"github.com/go-courier/oas"
 
 	"github.com/go-courier/packagesx"
 	"github.com/stretchr/testify/require"
@@ -24,5 +27,155 @@ func TestOpenAPIGenerator(t *testing.T) {
 	g := NewOpenAPIGenerator(pkg)
 
 	g.Scan(ctx)
-	g.Output(dir)
+	require.NoError(t, g.Output(dir))
+}
+
+func TestOpenAPIGeneratorOutput(t *testing.T) {
+	newGenerator := func() *OpenAPIGenerator {
+		openapi := oas.NewOpenAPI()
+		openapi.AddOperation(oas.GET, "/a", oas.NewOperation("A"))
+		return &OpenAPIGenerator{openapi: openapi}
+	}
+
+	t.Run("json by default", func(t *testing.T) {
+		dir := t.TempDir()
+
+		require.NoError(t, newGenerator().Output(dir))
+
+		data, err := os.ReadFile(filepath.Join(dir, "openapi.json"))
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"operationId": "A"`)
+	})
+
+	t.Run("yaml by ext of filename", func(t *testing.T) {
+		dir := t.TempDir()
+
+		g := newGenerator()
+		g.Filename = "api/spec.yaml"
+		require.NoError(t, g.Output(dir))
+
+		data, err := os.ReadFile(filepath.Join(dir, "api/spec.yaml"))
+		require.NoError(t, err)
+		require.Contains(t, string(data), "operationId: A")
+	})
+
+	t.Run("write to", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+
+		g := newGenerator()
+		g.Format = OutputFormatYAML
+		_, err := g.WriteTo(buf)
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "openapi: 3.0.3")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		g := newGenerator()
+		g.Format = "toml"
+		require.Error(t, g.Output(t.TempDir()))
+	})
+}
+
+func TestOpenAPIGeneratorSplitOutput(t *testing.T) {
+	refSchema := func(name string) *oas.Schema {
+		return oas.RefSchemaByRefer(oas.NewComponentRefer("schemas", name))
+	}
+
+	newSpec := func(operationID string, schemaName string) *oas.OpenAPI {
+		openapi := oas.NewOpenAPI()
+		op := oas.NewOperation(operationID)
+		resp := oas.NewResponse("")
+		resp.AddContent("application/json", oas.NewMediaTypeWithSchema(refSchema(schemaName)))
+		op.AddResponse(http.StatusOK, resp)
+		openapi.AddOperation(oas.GET, "/"+operationID, op)
+
+		// schemas are shared by root routers
+		openapi.Components.Schemas = map[string]*oas.Schema{
+			"User":    oas.ObjectOf(oas.Props{"org": refSchema("Org")}),
+			"Org":     oas.String(),
+			"Setting": oas.String(),
+		}
+		return openapi
+	}
+
+	g := &OpenAPIGenerator{
+		RootRouters: RootRoutersSplit,
+		openapi:     oas.NewOpenAPI(),
+		splits: []*rootRouterSpec{
+			{name: "root_router", openapi: newSpec("ListUser", "User")},
+			{name: "admin_router", openapi: newSpec("GetSetting", "Setting")},
+		},
+	}
+	for _, split := range g.splits {
+		pruneSchemas(split.openapi)
+	}
+
+	g.openapi.AddServer(oas.NewServer("https://api.example.com"))
+
+	dir := t.TempDir()
+	require.NoError(t, g.Output(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "openapi.root_router.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"operationId": "ListUser"`)
+	require.Contains(t, string(data), `"Org"`)
+	require.NotContains(t, string(data), `"Setting"`)
+	require.Contains(t, string(data), `https://api.example.com`)
+	require.Contains(t, string(data), XSpecHash)
+
+	data, err = os.ReadFile(filepath.Join(dir, "openapi.admin_router.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"operationId": "GetSetting"`)
+	require.NotContains(t, string(data), `"User"`)
+
+	g.Filename = "-"
+	require.Error(t, g.Output(dir))
+
+	require.Equal(t, "api/spec.admin.yaml", splitFilename("api/spec.yaml", "admin"))
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+	openapi.AddOperation(oas.GET, "/b", oas.NewOperation("B"))
+	openapi.AddOperation(oas.GET, "/a", oas.NewOperation("A"))
+
+	data, err := MarshalCanonical(openapi)
+	require.NoError(t, err)
+
+	data2, err := MarshalCanonical(openapi)
+	require.NoError(t, err)
+
+	require.Equal(t, data, data2)
+
+	data3, err := MarshalCanonical(map[string]interface{}{"b": 1, "a": map[string]interface{}{"d": 1.10, "c": "<"}})
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "a": {
+    "c": "<",
+    "d": 1.1
+  },
+  "b": 1
+}
+`, string(data3))
+}
+
+func TestSpecHash(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+	openapi.AddOperation(oas.GET, "/a", oas.NewOperation("A"))
+
+	hash, err := SpecHash(openapi)
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	openapi.AddExtension(XSpecHash, hash)
+
+	hash2, err := SpecHash(openapi)
+	require.NoError(t, err)
+	require.Equal(t, hash, hash2)
+
+	openapi.AddOperation(oas.GET, "/b", oas.NewOperation("B"))
+
+	hash3, err := SpecHash(openapi)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, hash3)
 }
@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-courier/logr"
 	"github.com/pkg/errors"
@@ -26,9 +27,12 @@ import (
 )
 
 func NewOperatorScanner(pkg *packagesx.Package) *OperatorScanner {
+	definitionScanner := NewDefinitionScanner(pkg)
+	definitionScanner.DescribeValidations = true
+
 	return &OperatorScanner{
 		pkg:               pkg,
-		DefinitionScanner: NewDefinitionScanner(pkg),
+		DefinitionScanner: definitionScanner,
 		StatusErrScanner:  NewStatusErrScanner(pkg),
 	}
 }
@@ -75,7 +79,9 @@ func (scanner *OperatorScanner) Operator(ctx context.Context, typeName *types.Ty
 
 		scanner.scanRouteMeta(operator, typeName)
 		scanner.scanParameterOrRequestBody(ctx, operator, typeStruct)
+		operator.Paginated = isPaginated(typeStruct)
 		scanner.scanReturns(ctx, operator, typeName)
+		scanner.scanWebhooks(ctx, operator, typeName)
 
 		// cached scanned
 		if scanner.operators == nil {
@@ -111,6 +117,11 @@ func (scanner *OperatorScanner) singleReturnOf(typeName *types.TypeName, name st
 						}
 						return s, true
 					}
+					if v.Expr != nil {
+						if s, ok := StringValueOf(scanner.pkg, v.Expr); ok {
+							return s, true
+						}
+					}
 				}
 			}
 		}
@@ -119,6 +130,79 @@ func (scanner *OperatorScanner) singleReturnOf(typeName *types.TypeName, name st
 	return "", false
 }
 
+// constReturnsOf returns const values of each result of method, when all results are consts
+func (scanner *OperatorScanner) constReturnsOf(typeName *types.TypeName, name string) ([]constant.Value, bool) {
+	if typeName == nil {
+		return nil, false
+	}
+
+	for _, typ := range []types.Type{
+		typeName.Type(),
+		types.NewPointer(typeName.Type()),
+	} {
+		method, ok := typesutil.FromTType(typ).MethodByName(name)
+		if ok {
+			results, n := scanner.pkg.FuncResultsOf(method.(*typesutil.TMethod).Func)
+			if n == 0 {
+				continue
+			}
+
+			values := make([]constant.Value, n)
+
+			for i := 0; i < n; i++ {
+				for _, v := range results[i] {
+					if v.Value != nil {
+						values[i] = v.Value
+						break
+					}
+				}
+				if values[i] == nil {
+					return nil, false
+				}
+			}
+
+			return values, true
+		}
+	}
+
+	return nil, false
+}
+
+func (scanner *OperatorScanner) stringsReturnOf(typeName *types.TypeName, name string) ([]string, bool) {
+	if typeName == nil {
+		return nil, false
+	}
+
+	for _, typ := range []types.Type{
+		typeName.Type(),
+		types.NewPointer(typeName.Type()),
+	} {
+		method, ok := typesutil.FromTType(typ).MethodByName(name)
+		if ok {
+			results, n := scanner.pkg.FuncResultsOf(method.(*typesutil.TMethod).Func)
+			if n == 1 {
+				for _, v := range results[0] {
+					if compositeLit, ok := v.Expr.(*ast.CompositeLit); ok {
+						values := make([]string, 0)
+						for _, elt := range compositeLit.Elts {
+							tv, err := scanner.pkg.Eval(elt)
+							if err != nil {
+								continue
+							}
+							if s, ok := valueOf(tv.Value).(string); ok {
+								values = append(values, s)
+							}
+						}
+						return values, true
+					}
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
+
 func (scanner *OperatorScanner) tagFrom(pkgPath string) string {
 	tag := strings.TrimPrefix(pkgPath, scanner.pkg.PkgPath)
 	return strings.TrimPrefix(tag, "/")
@@ -156,11 +240,21 @@ func (scanner *OperatorScanner) scanRouteMeta(op *Operator, typeName *types.Type
 				op.Summary = summary
 			}
 
+			if tag, ok := tags.Lookup(TagOpenAPI); ok {
+				for key, value := range VendorExtensionsFromTag(tag) {
+					op.AddExtension(key, value)
+				}
+			}
+
 			break
 		}
 	}
 
-	lines := scanner.pkg.CommentsOf(scanner.pkg.IdentOf(typeName))
+	lines, extensions := vendorExtensionsFromDoc(scanner.pkg.CommentsOf(scanner.pkg.IdentOf(typeName)))
+	for key, value := range extensions {
+		op.AddExtension(key, value)
+	}
+
 	comments := strings.Split(lines, "\n")
 
 	for i := range comments {
@@ -191,6 +285,25 @@ func (scanner *OperatorScanner) scanRouteMeta(op *Operator, typeName *types.Type
 	if bathPath, ok := scanner.singleReturnOf(typeName, "BasePath"); ok {
 		op.BasePath = bathPath
 	}
+
+	if scopes, ok := scanner.stringsReturnOf(typeName, "RequiredScopes"); ok {
+		op.RequiredScopes = scopes
+	}
+
+	if values, ok := scanner.constReturnsOf(typeName, "PublicAccess"); ok && len(values) == 1 && values[0].Kind() == constant.Bool {
+		publicAccess := constant.BoolVal(values[0])
+		op.PublicAccess = &publicAccess
+	}
+
+	if values, ok := scanner.constReturnsOf(typeName, "RateLimit"); ok && len(values) == 2 {
+		limit, _ := constant.Int64Val(values[0])
+		window, _ := constant.Int64Val(values[1])
+		op.RateLimit = httptransport.RateLimit{Limit: int(limit), Window: time.Duration(window)}
+	}
+
+	if origins, ok := scanner.stringsReturnOf(typeName, "CORSAllowedOrigins"); ok {
+		op.CORSAllowedOrigins = origins
+	}
 }
 
 func (scanner *OperatorScanner) scanReturns(ctx context.Context, op *Operator, typeName *types.TypeName) {
@@ -303,7 +416,20 @@ func (scanner *OperatorScanner) getResponse(ctx context.Context, tpe types.Type,
 		tpe = pointer.Elem()
 	}
 
+	if isHttpxResponseWriterFunc(tpe) {
+		// response written by operator itself
+		response.AddContent("*", oas.NewMediaTypeWithSchema(oas.Binary()))
+		return
+	}
+
 	if named, ok := tpe.(*types.Named); ok {
+		// description of response by doc of its type
+		if pkg := named.Obj().Pkg(); pkg != nil && scanner.pkg.Pkg(pkg.Path()) != nil {
+			if ident := scanner.pkg.IdentOf(named.Obj()); ident != nil {
+				response.Description = summaryOfDoc(scanner.pkg.CommentsOf(ident))
+			}
+		}
+
 		if v, ok := scanner.firstValueOfFunc(named, "ContentType"); ok {
 			if s, ok := v.(string); ok {
 				contentType = s
@@ -330,6 +456,11 @@ func (scanner *OperatorScanner) getResponse(ctx context.Context, tpe types.Type,
 
 func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context, op *Operator, typeStruct *types.Struct) {
 	typesutil.EachField(typesutil.FromTType(typeStruct), "name", func(field typesutil.StructField, fieldDisplayName string, omitempty bool) bool {
+		if _, ok := field.Tag().Lookup(httptransport.TagInject); ok {
+			// dependencies of operator, not parameters
+			return true
+		}
+
 		location, _ := tagValueAndFlagsByTagString(field.Tag().Get("in"))
 
 		if location == "" {
@@ -338,6 +469,8 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 
 		name, flags := tagValueAndFlagsByTagString(field.Tag().Get("name"))
 
+		doc := scanner.pkg.CommentsOf(scanner.pkg.IdentOf(field.(*typesutil.TStructField).Var))
+
 		schema := scanner.DefinitionScanner.propSchemaByField(
 			ctx,
 			field.Name(),
@@ -345,9 +478,17 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 			field.Tag(),
 			name,
 			flags,
-			scanner.pkg.CommentsOf(scanner.pkg.IdentOf(field.(*typesutil.TStructField).Var)),
+			doc,
 		)
 
+		// vendor extensions of parameter, same as its schema
+		_, extensions := vendorExtensionsFromDoc(doc)
+		if tag, ok := field.Tag().Lookup(TagOpenAPI); ok {
+			for key, value := range VendorExtensionsFromTag(tag) {
+				extensions[key] = value
+			}
+		}
+
 		transformer, err := transformers.TransformerMgrDefault.NewTransformer(context.Background(), field.Type(), transformers.TransformerOption{
 			MIME: field.Tag().Get("mime"),
 		})
@@ -356,19 +497,32 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 			panic(err)
 		}
 
+		if tag, ok := field.Tag().Lookup(TagSecurity); ok {
+			securityName, securityScheme, err := SecuritySchemeFromTag(tag, fieldDisplayName, oas.Position(location))
+			if err != nil {
+				panic(errors.Wrapf(err, "invalid security of %s of %s", field.Name(), op.ID))
+			}
+			op.AddSecurityScheme(securityName, securityScheme)
+		}
+
 		switch location {
 		case "body":
 			reqBody := oas.NewRequestBody("", true)
 			reqBody.AddContent(transformer.Names()[0], oas.NewMediaTypeWithSchema(schema))
 			op.SetRequestBody(reqBody)
 		case "query":
-			op.AddNonBodyParameter(oas.QueryParameter(fieldDisplayName, schema, !omitempty))
+			parameter := oas.QueryParameter(fieldDisplayName, schema, !omitempty).WithDesc(descriptionOfSchema(schema))
+			bindParameterStyle(parameter, transformers.ParamStyleFromFlags(flags))
+			op.AddNonBodyParameter(withVendorExtensions(parameter, extensions))
 		case "cookie":
-			op.AddNonBodyParameter(oas.CookieParameter(fieldDisplayName, schema, !omitempty))
+			op.AddNonBodyParameter(withVendorExtensions(oas.CookieParameter(fieldDisplayName, schema, !omitempty).WithDesc(descriptionOfSchema(schema)), extensions))
 		case "header":
-			op.AddNonBodyParameter(oas.HeaderParameter(fieldDisplayName, schema, !omitempty))
+			op.AddNonBodyParameter(withVendorExtensions(oas.HeaderParameter(fieldDisplayName, schema, !omitempty).WithDesc(descriptionOfSchema(schema)), extensions))
 		case "path":
-			op.AddNonBodyParameter(oas.PathParameter(fieldDisplayName, schema))
+			if converter, ok := httptransport.PathParamConverterByName(field.Tag().Get("format")); ok && schema.Refer == nil {
+				schema.Format = converter.Format()
+			}
+			op.AddNonBodyParameter(withVendorExtensions(oas.PathParameter(fieldDisplayName, schema).WithDesc(descriptionOfSchema(schema)), extensions))
 		}
 
 		return true
@@ -383,6 +537,10 @@ type Operator struct {
 
 	NonBodyParameters map[string]*oas.Parameter
 	RequestBody       *oas.RequestBody
+	// security schemes declared by tag security of parameters
+	SecuritySchemes map[string]*oas.SecurityScheme
+	// vendor extensions of operation declared by tag openapi or comments
+	Extensions map[string]interface{}
 
 	StatusErrors      []*statuserror.StatusErr
 	StatusErrorSchema *oas.Schema
@@ -390,6 +548,12 @@ type Operator struct {
 	SuccessStatus   int
 	SuccessType     types.Type
 	SuccessResponse *oas.Response
+
+	// outbound notifications declared by httptransport.WebhookDescriber
+	Webhooks []*Webhook
+
+	// list operation with httpx.Pagination embedded
+	Paginated bool
 }
 
 func (operator *Operator) AddNonBodyParameter(parameter *oas.Parameter) {
@@ -403,6 +567,33 @@ func (operator *Operator) SetRequestBody(requestBody *oas.RequestBody) {
 	operator.RequestBody = requestBody
 }
 
+func (operator *Operator) AddExtension(key string, value interface{}) {
+	if operator.Extensions == nil {
+		operator.Extensions = map[string]interface{}{}
+	}
+	operator.Extensions[key] = value
+}
+
+// bindParameterStyle binds style and explode of parameter in query declared by flag of tag `name`.
+// explode:false of csv dropped by omitempty of oas, generated clients keep the style by x-tag-name
+func bindParameterStyle(parameter *oas.Parameter, style transformers.ParamStyle) {
+	switch style {
+	case transformers.ParamStyleCSV:
+		parameter.Style, parameter.Explode = oas.ParameterStyleForm, false
+	case transformers.ParamStylePipes:
+		parameter.Style, parameter.Explode = oas.ParameterStylePipeDelimited, false
+	case transformers.ParamStyleSSV:
+		parameter.Style, parameter.Explode = oas.ParameterStyleSpaceDelimited, false
+	case transformers.ParamStyleDeepObject:
+		parameter.Style, parameter.Explode = oas.ParameterStyleDeepObject, true
+	}
+}
+
+func withVendorExtensions(parameter *oas.Parameter, extensions map[string]interface{}) *oas.Parameter {
+	bindVendorExtensions(parameter, extensions)
+	return parameter
+}
+
 func (operator *Operator) BindOperation(method string, operation *oas.Operation, last bool) {
 	parameterNames := map[string]bool{}
 	for _, parameter := range operation.Parameters {
@@ -419,6 +610,27 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 		operation.SetRequestBody(operator.RequestBody)
 	}
 
+	for _, name := range sortedSecuritySchemeNames(operator.SecuritySchemes) {
+		addRequiredScopes(operation, name)
+	}
+
+	if operator.PublicAccess != nil {
+		operation.AddExtension(XPublicAccess, *operator.PublicAccess)
+	}
+
+	if operator.RateLimit.Limit > 0 {
+		operation.AddExtension(XRateLimit, map[string]interface{}{
+			"limit":  operator.RateLimit.Limit,
+			"window": operator.RateLimit.Window.String(),
+		})
+	}
+
+	if operator.CORSAllowedOrigins != nil {
+		operation.AddExtension(XCORSAllowedOrigins, operator.CORSAllowedOrigins)
+	}
+
+	bindVendorExtensions(operation, operator.Extensions)
+
 	for _, statusError := range operator.StatusErrors {
 		statusErrorList := make([]string, 0)
 
@@ -454,6 +666,16 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 			operation.Tags = []string{operator.Tag}
 		}
 
+		if _, ok := operator.Extensions[XOperational]; ok {
+			operation.Tags = []string{TagOperational}
+		}
+
+		for _, webhook := range operator.Webhooks {
+			if webhook.URL != "" {
+				operation.AddCallback(webhook.Name, oas.NewCallback(oas.HttpMethod(strings.ToLower(webhook.Method)), oas.RuntimeExpression(webhook.URL), webhook.Operation))
+			}
+		}
+
 		if operator.SuccessType == nil {
 			operation.Responses.AddResponse(http.StatusNoContent, &oas.Response{})
 		} else {
@@ -467,6 +689,9 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 			if status >= http.StatusMultipleChoices && status < http.StatusBadRequest {
 				operator.SuccessResponse = oas.NewResponse(operator.SuccessResponse.Description)
 			}
+			if operator.Paginated && status < http.StatusMultipleChoices {
+				bindPaginationHeaders(operator.SuccessResponse)
+			}
 			operation.Responses.AddResponse(status, operator.SuccessResponse)
 		}
 	}
@@ -480,6 +705,59 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 	}
 }
 
+// isPaginated checks whether httpx.Pagination embedded in parameters of operator
+func isPaginated(typeStruct *types.Struct) bool {
+	for i := 0; i < typeStruct.NumFields(); i++ {
+		f := typeStruct.Field(i)
+		if f.Anonymous() && isHttpxPagination(f.Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindPaginationHeaders documents headers set by httpx.WithPagination
+func bindPaginationHeaders(response *oas.Response) {
+	totalCount := oas.NewHeaderWithSchema(oas.Integer())
+	totalCount.Description = "count of all items"
+	response.AddHeader(httpx.HeaderTotalCount, totalCount)
+
+	link := oas.NewHeaderWithSchema(oas.String())
+	link.Description = "links of next and prev pages, like <https://example.com/items?offset=10&size=10>; rel=\"next\""
+	response.AddHeader(httpx.HeaderLink, link)
+}
+
+// addRequiredScopes adds security scheme into the requirement of operation,
+// all security schemes of operators are required together,
+// as separate requirement objects means any of them.
+func addRequiredScopes(operation *oas.Operation, securitySchemeName string, scopes ...string) {
+	if len(operation.Security) == 0 {
+		operation.AddSecurityRequirement(&oas.SecurityRequirement{})
+	}
+
+	sr := *operation.Security[0]
+
+	existed, ok := sr[securitySchemeName]
+	if !ok {
+		existed = []string{}
+	}
+	for _, scope := range scopes {
+		if !containsString(existed, scope) {
+			existed = append(existed, scope)
+		}
+	}
+	sr[securitySchemeName] = existed
+}
+
+func containsString(list []string, s string) bool {
+	for i := range list {
+		if list[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
 var positionOrders = map[oas.Position]string{
 	"path":   "1",
 	"header": "2",
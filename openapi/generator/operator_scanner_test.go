@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/types"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/go-courier/oas"
 	"github.com/go-courier/packagesx"
 	"github.com/stretchr/testify/require"
@@ -230,3 +235,86 @@ func TestOperatorScanner(t *testing.T) {
 		})
 	}
 }
+
+func TestOperatorPolicies(t *testing.T) {
+	pkg := loadPackageFromSource(t, `package routes
+
+import "time"
+
+type ListArticles struct{}
+
+func (ListArticles) PublicAccess() bool {
+	return true
+}
+
+func (ListArticles) RateLimit() (int, time.Duration) {
+	return 10, 30 * time.Second
+}
+
+func (ListArticles) CORSAllowedOrigins() []string {
+	return []string{"https://example.com"}
+}
+`)
+
+	// only route meta scanned
+	scanner := &OperatorScanner{pkg: pkg}
+
+	op := &Operator{}
+	scanner.scanRouteMeta(op, pkg.TypeName("ListArticles"))
+
+	require.NotNil(t, op.PublicAccess)
+	require.True(t, *op.PublicAccess)
+	require.Equal(t, 10, op.RateLimit.Limit)
+	require.Equal(t, 30*time.Second, op.RateLimit.Window)
+	require.Equal(t, []string{"https://example.com"}, op.CORSAllowedOrigins)
+
+	operation := &oas.Operation{}
+	op.BindOperation(http.MethodGet, operation, false)
+
+	require.Equal(t, true, operation.Extensions[XPublicAccess])
+	require.Equal(t, map[string]interface{}{"limit": 10, "window": "30s"}, operation.Extensions[XRateLimit])
+	require.Equal(t, []string{"https://example.com"}, operation.Extensions[XCORSAllowedOrigins])
+}
+
+func TestBindParameterStyle(t *testing.T) {
+	for style, expect := range map[transformers.ParamStyle]oas.ParameterStyle{
+		transformers.ParamStyleForm:       "",
+		transformers.ParamStyleCSV:        oas.ParameterStyleForm,
+		transformers.ParamStylePipes:      oas.ParameterStylePipeDelimited,
+		transformers.ParamStyleSSV:        oas.ParameterStyleSpaceDelimited,
+		transformers.ParamStyleDeepObject: oas.ParameterStyleDeepObject,
+	} {
+		parameter := oas.QueryParameter("q", oas.String(), false)
+		bindParameterStyle(parameter, style)
+		require.Equal(t, expect, parameter.Style)
+		require.Equal(t, style == transformers.ParamStyleDeepObject, parameter.Explode)
+	}
+}
+
+func TestPaginatedOperation(t *testing.T) {
+	op := &Operator{
+		Paginated:       true,
+		SuccessType:     types.NewSlice(types.Typ[types.String]),
+		SuccessResponse: oas.NewResponse("pets"),
+	}
+
+	operation := &oas.Operation{}
+	op.BindOperation(http.MethodGet, operation, true)
+
+	headers := operation.Responses.Responses[http.StatusOK].Headers
+	require.Equal(t, oas.Integer(), headers[httpx.HeaderTotalCount].Schema)
+	require.Equal(t, oas.String(), headers[httpx.HeaderLink].Schema)
+}
+
+func TestOperationalOperation(t *testing.T) {
+	op := &Operator{
+		Tag: "github.com/go-courier/httptransport/operators",
+	}
+	op.AddExtension(XOperational, true)
+
+	operation := &oas.Operation{}
+	op.BindOperation(http.MethodGet, operation, true)
+
+	require.Equal(t, []string{TagOperational}, operation.Tags)
+	require.Equal(t, true, operation.Extensions[XOperational])
+}
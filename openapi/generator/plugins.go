@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+)
+
+// Plugin of OpenAPIGenerator visits spec once scanned, before output, without forking the generator,
+// like enforcing naming conventions, injecting extensions or stripping fields.
+// Plugin could implement any of OperationVisitor, SchemaVisitor and OpenAPIVisitor,
+// operations and schemas visited in order of path, method and name, and OpenAPIVisitor last.
+// error returned by plugin aborts Marshal and Output.
+//
+//	g.Plugins = append(g.Plugins, generator.NewOperationPlugin("operation-id-camel-case", func(method string, path string, operation *oas.Operation) error {
+//		if strings.Contains(operation.OperationId, "_") {
+//			return errors.Errorf("operation id %s should be camel case", operation.OperationId)
+//		}
+//		return nil
+//	}))
+type Plugin interface {
+	PluginName() string
+}
+
+type OperationVisitor interface {
+	VisitOperation(method string, path string, operation *oas.Operation) error
+}
+
+// SchemaVisitor visits schemas of components by name, schema could be modified in place
+type SchemaVisitor interface {
+	VisitSchema(name string, schema *oas.Schema) error
+}
+
+type OpenAPIVisitor interface {
+	VisitOpenAPI(openapi *oas.OpenAPI) error
+}
+
+// NewOperationPlugin creates Plugin visiting each operation by visit
+func NewOperationPlugin(name string, visit func(method string, path string, operation *oas.Operation) error) Plugin {
+	return &operationPlugin{name: name, visit: visit}
+}
+
+type operationPlugin struct {
+	name  string
+	visit func(method string, path string, operation *oas.Operation) error
+}
+
+func (p *operationPlugin) PluginName() string {
+	return p.name
+}
+
+func (p *operationPlugin) VisitOperation(method string, path string, operation *oas.Operation) error {
+	return p.visit(method, path, operation)
+}
+
+// NewSchemaPlugin creates Plugin visiting each schema of components by visit
+func NewSchemaPlugin(name string, visit func(name string, schema *oas.Schema) error) Plugin {
+	return &schemaPlugin{name: name, visit: visit}
+}
+
+type schemaPlugin struct {
+	name  string
+	visit func(name string, schema *oas.Schema) error
+}
+
+func (p *schemaPlugin) PluginName() string {
+	return p.name
+}
+
+func (p *schemaPlugin) VisitSchema(name string, schema *oas.Schema) error {
+	return p.visit(name, schema)
+}
+
+// ApplyPlugins visits openapi by plugins in order
+func ApplyPlugins(openapi *oas.OpenAPI, plugins ...Plugin) error {
+	for _, plugin := range plugins {
+		if err := applyPlugin(openapi, plugin); err != nil {
+			return errors.Wrapf(err, "plugin %s", plugin.PluginName())
+		}
+	}
+	return nil
+}
+
+func applyPlugin(openapi *oas.OpenAPI, plugin Plugin) error {
+	if visitor, ok := plugin.(OperationVisitor); ok {
+		paths := make([]string, 0, len(openapi.Paths.Paths))
+		for path := range openapi.Paths.Paths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			operations := openapi.Paths.Paths[path].Operations.Operations
+
+			methods := make([]string, 0, len(operations))
+			for method := range operations {
+				methods = append(methods, string(method))
+			}
+			sort.Strings(methods)
+
+			for _, method := range methods {
+				if err := visitor.VisitOperation(strings.ToUpper(method), path, operations[oas.HttpMethod(method)]); err != nil {
+					return errors.Wrapf(err, "%s %s", strings.ToUpper(method), path)
+				}
+			}
+		}
+	}
+
+	if visitor, ok := plugin.(SchemaVisitor); ok && openapi.Components.Schemas != nil {
+		names := make([]string, 0, len(openapi.Components.Schemas))
+		for name := range openapi.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := visitor.VisitSchema(name, openapi.Components.Schemas[name]); err != nil {
+				return errors.Wrapf(err, "schema %s", name)
+			}
+		}
+	}
+
+	if visitor, ok := plugin.(OpenAPIVisitor); ok {
+		if err := visitor.VisitOpenAPI(openapi); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPlugins to specs once, before spec hash
+func (g *OpenAPIGenerator) applyPlugins() error {
+	if !g.pluginsApplied {
+		g.pluginsApplied = true
+
+		if err := ApplyPlugins(g.openapi, g.Plugins...); err != nil {
+			g.pluginsErr = err
+		}
+
+		for _, split := range g.splits {
+			if g.pluginsErr != nil {
+				break
+			}
+			if err := ApplyPlugins(split.openapi, g.Plugins...); err != nil {
+				g.pluginsErr = errors.Wrapf(err, "spec of %s", split.name)
+			}
+		}
+	}
+	return g.pluginsErr
+}
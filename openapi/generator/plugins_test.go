@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type stripInternalPlugin struct {
+	visited []string
+}
+
+func (stripInternalPlugin) PluginName() string {
+	return "strip-internal"
+}
+
+func (p *stripInternalPlugin) VisitOperation(method string, path string, operation *oas.Operation) error {
+	p.visited = append(p.visited, method+" "+path)
+	operation.AddExtension("x-team", "users")
+	return nil
+}
+
+func (p *stripInternalPlugin) VisitSchema(name string, schema *oas.Schema) error {
+	p.visited = append(p.visited, name)
+	delete(schema.Properties, "internalNote")
+	return nil
+}
+
+func (p *stripInternalPlugin) VisitOpenAPI(openapi *oas.OpenAPI) error {
+	p.visited = append(p.visited, "openapi")
+	openapi.Info.Title = "Users"
+	return nil
+}
+
+func newPluginsTestOpenAPI() *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+	openapi.AddOperation(oas.POST, "/users", oas.NewOperation("CreateUser"))
+	openapi.AddOperation(oas.GET, "/users", oas.NewOperation("ListUsers"))
+	openapi.AddOperation(oas.GET, "/users/{id}", oas.NewOperation("get_user"))
+	openapi.AddSchema("User", oas.ObjectOf(oas.Props{
+		"name":         oas.String(),
+		"internalNote": oas.String(),
+	}))
+	return openapi
+}
+
+func TestApplyPlugins(t *testing.T) {
+	openapi := newPluginsTestOpenAPI()
+
+	plugin := &stripInternalPlugin{}
+	require.NoError(t, ApplyPlugins(openapi, plugin))
+
+	require.Equal(t, []string{"GET /users", "POST /users", "GET /users/{id}", "User", "openapi"}, plugin.visited)
+	require.Equal(t, "users", openapi.Paths.Paths["/users"].Operations.Operations[oas.GET].Extensions["x-team"])
+	require.NotContains(t, openapi.Components.Schemas["User"].Properties, "internalNote")
+	require.Equal(t, "Users", openapi.Info.Title)
+
+	t.Run("error of plugin", func(t *testing.T) {
+		err := ApplyPlugins(newPluginsTestOpenAPI(), NewOperationPlugin("operation-id-camel-case", func(method string, path string, operation *oas.Operation) error {
+			if strings.Contains(operation.OperationId, "_") {
+				return errors.Errorf("operation id %s should be camel case", operation.OperationId)
+			}
+			return nil
+		}))
+		require.EqualError(t, err, "plugin operation-id-camel-case: GET /users/{id}: operation id get_user should be camel case")
+	})
+}
+
+func TestOpenAPIGeneratorWithPlugins(t *testing.T) {
+	g := &OpenAPIGenerator{openapi: newPluginsTestOpenAPI()}
+
+	visits := 0
+
+	g.Plugins = []Plugin{
+		NewSchemaPlugin("strip-internal", func(name string, schema *oas.Schema) error {
+			visits++
+			delete(schema.Properties, "internalNote")
+			return nil
+		}),
+	}
+
+	data, err := g.Marshal()
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "internalNote")
+
+	// spec hash of spec visited
+	specHash := g.OpenAPI().Extensions[XSpecHash]
+	delete(g.openapi.Extensions, XSpecHash)
+	expected, err := SpecHash(g.openapi)
+	require.NoError(t, err)
+	require.Equal(t, expected, specHash)
+
+	_, _ = g.Marshal()
+	require.Equal(t, 1, visits)
+
+	t.Run("error aborts output", func(t *testing.T) {
+		g := &OpenAPIGenerator{openapi: newPluginsTestOpenAPI()}
+		g.Plugins = []Plugin{
+			NewSchemaPlugin("forbidden", func(name string, schema *oas.Schema) error {
+				return errors.New("forbidden")
+			}),
+		}
+
+		_, err := g.Marshal()
+		require.EqualError(t, err, "plugin forbidden: schema User: forbidden")
+		require.Error(t, g.Output(t.TempDir()))
+	})
+}
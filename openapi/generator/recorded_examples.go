@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"encoding/json"
+	"mime"
+
+	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/oas"
+)
+
+// LoadRecordedExamples attaches recordings under dir, recorded by testify.Recorder in tests,
+// as examples of request bodies and responses of operations, should be called after Scan.
+// recordings of operations not in spec are ignored.
+func (g *OpenAPIGenerator) LoadRecordedExamples(dir string) error {
+	recordings, err := testify.LoadRecordings(dir)
+	if err != nil {
+		return err
+	}
+
+	addRecordedExamples(g.openapi, recordings)
+
+	for i := range g.splits {
+		addRecordedExamples(g.splits[i].openapi, recordings)
+	}
+
+	return nil
+}
+
+func addRecordedExamples(openapi *oas.OpenAPI, recordings []*testify.Recording) {
+	operations := map[string]*oas.Operation{}
+
+	for _, pathItem := range openapi.Paths.Paths {
+		if pathItem == nil {
+			continue
+		}
+		for _, operation := range pathItem.Operations.Operations {
+			operations[operation.OperationId] = operation
+		}
+	}
+
+	for _, recording := range recordings {
+		operation, ok := operations[recording.OperationID]
+		if !ok {
+			continue
+		}
+
+		if reqBody := operation.RequestBody; reqBody != nil && len(recording.Request.Body) > 0 {
+			if mediaType := mediaTypeOf(reqBody.Content, recording.Request.ContentType); mediaType != nil {
+				mediaType.AddExample(recording.Name, exampleOfRecorded(recording.Request.Body))
+			}
+		}
+
+		if response, ok := operation.Responses.Responses[recording.Response.StatusCode]; ok && response != nil && len(recording.Response.Body) > 0 {
+			if mediaType := mediaTypeOf(response.Content, recording.Response.ContentType); mediaType != nil {
+				mediaType.AddExample(recording.Name, exampleOfRecorded(recording.Response.Body))
+			}
+		}
+	}
+}
+
+// mediaTypeOf matches media type by content type recorded, or the only one declared
+func mediaTypeOf(content map[string]*oas.MediaType, contentType string) *oas.MediaType {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if m, ok := content[mediaType]; ok {
+			return m
+		}
+	}
+	if len(content) == 1 {
+		for _, m := range content {
+			return m
+		}
+	}
+	return nil
+}
+
+func exampleOfRecorded(body json.RawMessage) *oas.Example {
+	var v interface{}
+	_ = json.Unmarshal(body, &v)
+
+	e := oas.NewExample()
+	e.Value = v
+	return e
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRecordedExamples(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+
+	operation := oas.NewOperation("CreateUser")
+	operation.SetRequestBody(oas.NewRequestBody("", true))
+	operation.RequestBody.AddContent("application/json", oas.NewMediaTypeWithSchema(oas.ObjectOf(nil)))
+	resp := oas.NewResponse("")
+	resp.AddContent("application/json", oas.NewMediaTypeWithSchema(oas.ObjectOf(nil)))
+	operation.AddResponse(http.StatusCreated, resp)
+	openapi.AddOperation(oas.POST, "/users", operation)
+
+	addRecordedExamples(openapi, []*testify.Recording{
+		{
+			OperationID: "CreateUser",
+			Name:        "created",
+			Request:     testify.RecordedRequest{ContentType: "application/json; charset=utf-8", Body: []byte(`{"name":"x"}`)},
+			Response:    testify.RecordedResponse{StatusCode: http.StatusCreated, Body: []byte(`{"id":"1"}`)},
+		},
+		{
+			OperationID: "CreateUser",
+			Name:        "conflict",
+			Response:    testify.RecordedResponse{StatusCode: http.StatusConflict, Body: []byte(`{"key":"Conflict"}`)},
+		},
+		{
+			OperationID: "Unknown",
+			Name:        "ignored",
+		},
+	})
+
+	reqExamples := operation.RequestBody.Content["application/json"].Examples
+	require.Len(t, reqExamples, 1)
+	require.Equal(t, map[string]interface{}{"name": "x"}, reqExamples["created"].Value)
+
+	respExamples := resp.Content["application/json"].Examples
+	require.Len(t, respExamples, 1)
+	require.Equal(t, map[string]interface{}{"id": "1"}, respExamples["created"].Value)
+}
@@ -0,0 +1,264 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+)
+
+// RouteManifest of routes for api gateways, like templates of Kong or Envoy route configs,
+// derived from operations of spec, so same as what documented.
+//
+//	{
+//	  "specHash": "...",
+//	  "routes": [
+//	    {
+//	      "operationID": "GetUser",
+//	      "method": "GET",
+//	      "path": "/api/users/{id}",
+//	      "pathRegex": "^/api/users/[^/]+$",
+//	      "auth": {"schemes": ["oauth2"], "scopes": ["users:read"]},
+//	      "rateLimit": {"limit": 100, "window": "1m0s", "windowSeconds": 60},
+//	      "timeout": "5s"
+//	    }
+//	  ]
+//	}
+type RouteManifest struct {
+	SpecHash string           `json:"specHash,omitempty"`
+	Routes   []*ManifestRoute `json:"routes"`
+}
+
+type ManifestRoute struct {
+	OperationID string `json:"operationID"`
+	Method      string `json:"method"`
+	// path with params like /users/{id}
+	Path string `json:"path"`
+	// regex of path for gateways not support templates of path
+	PathRegex  string `json:"pathRegex"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+	// accessible without auth, declared by x-public-access
+	PublicAccess bool `json:"publicAccess,omitempty"`
+	// security requirements of operation, nil when public access or none
+	Auth *ManifestAuth `json:"auth,omitempty"`
+	// by x-rate-limit
+	RateLimit *ManifestRateLimit `json:"rateLimit,omitempty"`
+	// by x-timeout
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type ManifestAuth struct {
+	// names of security schemes, any of them accepted
+	Schemes []string `json:"schemes"`
+	Scopes  []string `json:"scopes,omitempty"`
+}
+
+type ManifestRateLimit struct {
+	Limit         int    `json:"limit"`
+	Window        string `json:"window"`
+	WindowSeconds int    `json:"windowSeconds"`
+}
+
+var reOpenAPIPathParam = regexp.MustCompile(`\{[^/}]+}`)
+
+// NewRouteManifest collects routes from operations of spec, sorted by path and method
+func NewRouteManifest(openapi *oas.OpenAPI) *RouteManifest {
+	manifest := &RouteManifest{
+		Routes: make([]*ManifestRoute, 0),
+	}
+
+	if specHash, ok := openapi.Extensions[XSpecHash].(string); ok {
+		manifest.SpecHash = specHash
+	}
+
+	for path, pathItem := range openapi.Paths.Paths {
+		for method, operation := range pathItem.Operations.Operations {
+			manifest.Routes = append(manifest.Routes, manifestRouteOf(path, strings.ToUpper(string(method)), operation, openapi.Security))
+		}
+	}
+
+	sort.Slice(manifest.Routes, func(i, j int) bool {
+		if manifest.Routes[i].Path == manifest.Routes[j].Path {
+			return manifest.Routes[i].Method < manifest.Routes[j].Method
+		}
+		return manifest.Routes[i].Path < manifest.Routes[j].Path
+	})
+
+	return manifest
+}
+
+func manifestRouteOf(path string, method string, operation *oas.Operation, globalSecurity []*oas.SecurityRequirement) *ManifestRoute {
+	route := &ManifestRoute{
+		OperationID: operation.OperationId,
+		Method:      method,
+		Path:        path,
+		PathRegex:   pathRegexOf(path),
+		Deprecated:  operation.Deprecated,
+	}
+
+	if publicAccess, ok := operation.Extensions[XPublicAccess].(bool); ok {
+		route.PublicAccess = publicAccess
+	}
+
+	if !route.PublicAccess {
+		security := operation.Security
+		if len(security) == 0 {
+			security = globalSecurity
+		}
+		route.Auth = manifestAuthOf(security)
+	}
+
+	if rateLimit, ok := operation.Extensions[XRateLimit]; ok {
+		route.RateLimit = manifestRateLimitOf(rateLimit)
+	}
+
+	if timeout := durationOf(operation.Extensions[XTimeout]); timeout > 0 {
+		route.Timeout = timeout.String()
+	}
+
+	return route
+}
+
+// pathRegexOf converts /users/{id} to ^/users/[^/]+$
+func pathRegexOf(path string) string {
+	b := strings.Builder{}
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range reOpenAPIPathParam.FindAllStringIndex(path, -1) {
+		b.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		b.WriteString("[^/]+")
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(path[last:]))
+
+	b.WriteString("$")
+	return b.String()
+}
+
+func manifestAuthOf(security []*oas.SecurityRequirement) *ManifestAuth {
+	if len(security) == 0 {
+		return nil
+	}
+
+	auth := &ManifestAuth{
+		Schemes: make([]string, 0),
+	}
+
+	scopes := map[string]bool{}
+
+	for _, sr := range security {
+		if sr == nil {
+			continue
+		}
+		for name, values := range *sr {
+			auth.Schemes = append(auth.Schemes, name)
+			for _, scope := range values {
+				scopes[scope] = true
+			}
+		}
+	}
+
+	if len(auth.Schemes) == 0 {
+		return nil
+	}
+
+	sort.Strings(auth.Schemes)
+
+	for scope := range scopes {
+		auth.Scopes = append(auth.Scopes, scope)
+	}
+	sort.Strings(auth.Scopes)
+
+	return auth
+}
+
+// manifestRateLimitOf parses x-rate-limit as {"limit":100,"window":"1m"} or limit in one minute
+func manifestRateLimitOf(v interface{}) *ManifestRateLimit {
+	limit, window := 0, time.Duration(0)
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		limit = int(numberOf(x["limit"]))
+		window = durationOf(x["window"])
+	default:
+		limit = int(numberOf(x))
+		window = time.Minute
+	}
+
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+
+	return &ManifestRateLimit{
+		Limit:         limit,
+		Window:        window.String(),
+		WindowSeconds: int((window + time.Second - 1) / time.Second),
+	}
+}
+
+// durationOf parses duration like 5s, or number of seconds
+func durationOf(v interface{}) time.Duration {
+	if s, ok := v.(string); ok {
+		d, _ := time.ParseDuration(s)
+		return d
+	}
+	return time.Duration(numberOf(v) * float64(time.Second))
+}
+
+func numberOf(v interface{}) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	}
+	return 0
+}
+
+// RouteManifest of scanned routes, should be called after Scan
+func (g *OpenAPIGenerator) RouteManifest() *RouteManifest {
+	return NewRouteManifest(g.OpenAPI())
+}
+
+// OutputRouteManifest writes RouteManifest as json into filename under cwd, or stdout when filename is "-"
+func (g *OpenAPIGenerator) OutputRouteManifest(cwd string, filename string) error {
+	if err := g.applyPlugins(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(g.RouteManifest(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal route manifest failed")
+	}
+
+	if filename == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(cwd, filename)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return errors.Wrapf(err, "write route manifest into %s failed", filename)
+	}
+
+	log.Printf("generated route manifest into %s", color.MagentaString(filename))
+	return nil
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRouteManifest(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+
+	getUser := oas.NewOperation("GetUser")
+	addRequiredScopes(getUser, SecuritySchemeOAuth2, "users:read")
+	getUser.AddExtension(XRateLimit, map[string]interface{}{"limit": 100, "window": "1m0s"})
+	getUser.AddExtension(XTimeout, "5s")
+	openapi.AddOperation(oas.GET, "/api/users/{id}", getUser)
+
+	login := oas.NewOperation("Login")
+	login.AddExtension(XPublicAccess, true)
+	login.AddExtension(XRateLimit, float64(10))
+	openapi.AddOperation(oas.POST, "/api/login", login)
+
+	listUsers := oas.NewOperation("ListUsers")
+	listUsers.Deprecated = true
+	listUsers.AddExtension(XTimeout, float64(30))
+	openapi.AddOperation(oas.GET, "/api/users", listUsers)
+
+	openapi.AddSecurityRequirement(&oas.SecurityRequirement{"apiKey": {}})
+
+	manifest := NewRouteManifest(openapi)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+  "routes": [
+    {
+      "operationID": "Login",
+      "method": "POST",
+      "path": "/api/login",
+      "pathRegex": "^/api/login$",
+      "publicAccess": true,
+      "rateLimit": {"limit": 10, "window": "1m0s", "windowSeconds": 60}
+    },
+    {
+      "operationID": "ListUsers",
+      "method": "GET",
+      "path": "/api/users",
+      "pathRegex": "^/api/users$",
+      "deprecated": true,
+      "auth": {"schemes": ["apiKey"]},
+      "timeout": "30s"
+    },
+    {
+      "operationID": "GetUser",
+      "method": "GET",
+      "path": "/api/users/{id}",
+      "pathRegex": "^/api/users/[^/]+$",
+      "auth": {"schemes": ["oauth2"], "scopes": ["users:read"]},
+      "rateLimit": {"limit": 100, "window": "1m0s", "windowSeconds": 60},
+      "timeout": "5s"
+    }
+  ]
+}`, string(data))
+}
+
+func TestPathRegexOf(t *testing.T) {
+	require.Equal(t, `^/files/[^/]+/v1\.0/[^/]+$`, pathRegexOf("/files/{dir}/v1.0/{name}"))
+}
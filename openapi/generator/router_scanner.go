@@ -6,7 +6,6 @@ import (
 	"go/ast"
 	"go/types"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/go-courier/packagesx"
@@ -131,15 +130,9 @@ func (scanner *RouterScanner) OperatorTypeNamesFromArgs(pkg *packagesx.Package,
 				if isFromHttpTransport(pkg.TypesInfo.ObjectOf(selectorExpr.Sel).Type()) {
 					switch selectorExpr.Sel.Name {
 					case "BasePath":
-						switch v := callExpr.Args[0].(type) {
-						case *ast.BasicLit:
-							opTypeName.BasePath, _ = strconv.Unquote(v.Value)
-						}
+						opTypeName.BasePath, _ = StringValueOf(pkg, callExpr.Args[0])
 					case "Group":
-						switch v := callExpr.Args[0].(type) {
-						case *ast.BasicLit:
-							opTypeName.Path, _ = strconv.Unquote(v.Value)
-						}
+						opTypeName.Path, _ = StringValueOf(pkg, callExpr.Args[0])
 					}
 				}
 			}
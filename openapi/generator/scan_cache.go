@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/go-courier/packagesx"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// version of cached scan results, should be bumped when scanning changed
+const scanCacheVersion = "1"
+
+// LoadOpenAPIGenerator loads package of dir by go/packages and scans routes.
+//
+// when cacheDir is not empty, scanned spec will be cached by fingerprint of go files, go.mod and go.sum of the module
+// and of local directories replaced in go.mod,
+// loading and scanning will be skipped when nothing changed, which take most time of generating in large repos.
+func LoadOpenAPIGenerator(ctx context.Context, dir string, cacheDir string) (*OpenAPIGenerator, error) {
+	fingerprint := ""
+
+	if cacheDir != "" {
+		fp, err := ScanFingerprint(dir)
+		if err != nil {
+			return nil, err
+		}
+		fingerprint = fp
+
+		if openapi, ok := loadCachedScan(cacheDir, fingerprint); ok {
+			return &OpenAPIGenerator{openapi: openapi}, nil
+		}
+	}
+
+	pkg, err := packagesx.Load(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load package %s failed", dir)
+	}
+
+	g := NewOpenAPIGenerator(pkg)
+	g.Scan(ctx)
+
+	if cacheDir != "" {
+		if err := storeCachedScan(cacheDir, fingerprint, g.openapi); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// ScanFingerprint of package dir, by path, size and modified time of go files, go.mod and go.sum of its module,
+// and of local directories replaced in go.mod, which are out of the module but loaded as dependencies.
+// other dependencies are pinned by versions of go.mod and go.sum.
+// only files stat, so it is cheap even for thousands of packages.
+func ScanFingerprint(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	root := moduleRootOf(dir)
+
+	entries, err := fingerprintEntriesOf(root, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "fingerprint of %s failed", dir)
+	}
+
+	replacedDirs, err := replacedDirsOf(root)
+	if err != nil {
+		return "", errors.Wrapf(err, "fingerprint of %s failed", dir)
+	}
+
+	for _, replacedDir := range replacedDirs {
+		replacedEntries, err := fingerprintEntriesOf(replacedDir, replacedDir+"!")
+		if err != nil {
+			return "", errors.Wrapf(err, "fingerprint of replaced %s failed", replacedDir)
+		}
+		entries = append(entries, replacedEntries...)
+	}
+
+	sort.Strings(entries)
+
+	rel, _ := filepath.Rel(root, dir)
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n", scanCacheVersion, filepath.ToSlash(rel))
+	for _, e := range entries {
+		_, _ = fmt.Fprintln(h, e)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fingerprintEntriesOf go files, go.mod and go.sum under root, paths relative to root with prefix
+func fingerprintEntriesOf(root string, prefix string) ([]string, error) {
+	entries := make([]string, 0)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := info.Name()
+		if strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum" {
+			rel, _ := filepath.Rel(root, path)
+			entries = append(entries, fmt.Sprintf("%s%s:%d:%d", prefix, filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano()))
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// replacedDirsOf returns absolute dirs of modules replaced by local directories in go.mod of root
+func replacedDirsOf(root string) ([]string, error) {
+	gomodfile := filepath.Join(root, "go.mod")
+
+	data, err := ioutil.ReadFile(gomodfile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	f, err := modfile.Parse(gomodfile, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0)
+
+	for _, r := range f.Replace {
+		if r.New.Version != "" || !modfile.IsDirectoryPath(r.New.Path) {
+			continue
+		}
+		d := r.New.Path
+		if !filepath.IsAbs(d) {
+			d = filepath.Join(root, d)
+		}
+		dirs = append(dirs, filepath.Clean(d))
+	}
+
+	return dirs, nil
+}
+
+// moduleRootOf returns nearest dir with go.mod, or dir self when not found
+func moduleRootOf(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+func cachedScanFile(cacheDir string, fingerprint string) string {
+	return filepath.Join(cacheDir, "openapi-scan-"+fingerprint+".json")
+}
+
+func loadCachedScan(cacheDir string, fingerprint string) (*oas.OpenAPI, bool) {
+	data, err := ioutil.ReadFile(cachedScanFile(cacheDir, fingerprint))
+	if err != nil {
+		return nil, false
+	}
+	openapi := oas.NewOpenAPI()
+	if err := json.Unmarshal(data, openapi); err != nil {
+		return nil, false
+	}
+	return openapi, true
+}
+
+func storeCachedScan(cacheDir string, fingerprint string, openapi *oas.OpenAPI) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(openapi)
+	if err != nil {
+		return err
+	}
+
+	// written into temp file then renamed, to avoid partial cache read by concurrent generating
+	file := cachedScanFile(cacheDir, fingerprint)
+	tmp := fmt.Sprintf("%s.%d.tmp", file, os.Getpid())
+
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrap(err, "write scan cache failed")
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanCache(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "cmd/app")
+
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/demo/app\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+
+	fingerprint, err := ScanFingerprint(dir)
+	require.NoError(t, err)
+
+	t.Run("fingerprint stable", func(t *testing.T) {
+		fingerprint2, err := ScanFingerprint(dir)
+		require.NoError(t, err)
+		require.Equal(t, fingerprint, fingerprint2)
+
+		fingerprintOfRoot, err := ScanFingerprint(root)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, fingerprintOfRoot)
+	})
+
+	t.Run("load from cache without loading package", func(t *testing.T) {
+		cacheDir := filepath.Join(root, ".cache")
+
+		openapi := oas.NewOpenAPI()
+		openapi.AddOperation(oas.GET, "/a", oas.NewOperation("A"))
+		require.NoError(t, storeCachedScan(cacheDir, fingerprint, openapi))
+
+		g, err := LoadOpenAPIGenerator(context.Background(), dir, cacheDir)
+		require.NoError(t, err)
+		require.NotNil(t, g.OpenAPI().Paths.Paths["/a"])
+	})
+
+	t.Run("changed when go files changed", func(t *testing.T) {
+		later := time.Now().Add(time.Hour)
+		require.NoError(t, os.WriteFile(filepath.Join(root, "routes.go"), []byte("package app\n"), 0644))
+		require.NoError(t, os.Chtimes(filepath.Join(root, "routes.go"), later, later))
+
+		fingerprint2, err := ScanFingerprint(dir)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, fingerprint2)
+	})
+
+	t.Run("changed when go files of replaced dir changed", func(t *testing.T) {
+		lib := filepath.Join(t.TempDir(), "lib")
+		require.NoError(t, os.MkdirAll(lib, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(lib, "go.mod"), []byte("module github.com/demo/lib\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(lib, "lib.go"), []byte("package lib\n"), 0644))
+
+		require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/demo/app\n\nrequire github.com/demo/lib v0.0.0\n\nreplace github.com/demo/lib => "+lib+"\n"), 0644))
+
+		fingerprint, err := ScanFingerprint(dir)
+		require.NoError(t, err)
+
+		later := time.Now().Add(2 * time.Hour)
+		require.NoError(t, os.WriteFile(filepath.Join(lib, "types.go"), []byte("package lib\n"), 0644))
+		require.NoError(t, os.Chtimes(filepath.Join(lib, "types.go"), later, later))
+
+		fingerprint2, err := ScanFingerprint(dir)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, fingerprint2)
+	})
+}
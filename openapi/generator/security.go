@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+)
+
+// TagSecurity marks header, query or cookie parameter of operator as credential,
+//
+//	`security:"NAME[,TYPE[,FORMAT]]"`
+//
+// TYPE could be
+//
+//	apiKey   (default) api key in position and name of the parameter
+//	bearer   http bearer, FORMAT as bearerFormat, like JWT
+//	basic    http basic
+//	oauth2   oauth2 client credentials flow, FORMAT as tokenUrl
+//
+// the security scheme will be added into components.securitySchemes,
+// and operations with the operator will require it.
+// RequiredScopes of operators bind to oauth2 security scheme declared by operators of the route,
+// or the one named SecuritySchemeOAuth2 in components, and skipped when none.
+const TagSecurity = "security"
+
+const (
+	SecurityTypeAPIKey = "apiKey"
+	SecurityTypeBearer = "bearer"
+	SecurityTypeBasic  = "basic"
+	SecurityTypeOAuth2 = "oauth2"
+)
+
+// SecuritySchemeFromTag parses security scheme from tag security of parameter
+func SecuritySchemeFromTag(tag string, parameterName string, in oas.Position) (string, *oas.SecurityScheme, error) {
+	values := strings.Split(tag, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	name := values[0]
+	if name == "" {
+		return "", nil, errors.Errorf("missing name of security scheme in tag `%s`", tag)
+	}
+
+	typ := SecurityTypeAPIKey
+	if len(values) > 1 && values[1] != "" {
+		typ = values[1]
+	}
+
+	format := ""
+	if len(values) > 2 {
+		format = values[2]
+	}
+
+	switch typ {
+	case SecurityTypeAPIKey:
+		return name, oas.NewAPIKeySecurityScheme(parameterName, in), nil
+	case SecurityTypeBearer:
+		return name, oas.NewHTTPSecurityScheme("bearer", format), nil
+	case SecurityTypeBasic:
+		return name, oas.NewHTTPSecurityScheme("basic", ""), nil
+	case SecurityTypeOAuth2:
+		if format == "" {
+			return "", nil, errors.Errorf("missing tokenUrl of oauth2 security scheme in tag `%s`", tag)
+		}
+		return name, oas.NewOAuth2SecurityScheme(oas.OAuthFlowsObject{
+			ClientCredentials: oas.NewOAuthFlow("", format, "", map[string]string{}),
+		}), nil
+	}
+
+	return "", nil, errors.Errorf("unsupported security type `%s` in tag `%s`", typ, tag)
+}
+
+func (operator *Operator) AddSecurityScheme(name string, s *oas.SecurityScheme) {
+	if operator.SecuritySchemes == nil {
+		operator.SecuritySchemes = map[string]*oas.SecurityScheme{}
+	}
+	operator.SecuritySchemes[name] = s
+}
+
+// bindSecuritySchemes adds security schemes of operators into components,
+// and scopes required by operations into flows of oauth2 security schemes
+func bindSecuritySchemes(openapi *oas.OpenAPI, operation *oas.Operation, operatorTypes ...*OperatorWithTypeName) {
+	scopes := make([]string, 0)
+
+	for _, op := range operatorTypes {
+		for name, s := range op.SecuritySchemes {
+			if _, ok := openapi.Components.SecuritySchemes[name]; !ok {
+				openapi.AddSecurityScheme(name, s)
+			}
+		}
+		scopes = append(scopes, op.RequiredScopes...)
+	}
+
+	if len(scopes) > 0 {
+		if name, ok := oauth2SecuritySchemeNameOf(openapi, operatorTypes...); ok {
+			addRequiredScopes(operation, name, scopes...)
+		}
+	}
+
+	for _, sr := range operation.Security {
+		for name, scopes := range *sr {
+			s, ok := openapi.Components.SecuritySchemes[name]
+			if !ok || s.Flows == nil {
+				continue
+			}
+
+			for _, flow := range []*oas.OAuthFlow{
+				s.Flows.Implicit,
+				s.Flows.Password,
+				s.Flows.ClientCredentials,
+				s.Flows.AuthorizationCode,
+			} {
+				if flow == nil {
+					continue
+				}
+				if flow.Scopes == nil {
+					flow.Scopes = map[string]string{}
+				}
+				for _, scope := range scopes {
+					if _, ok := flow.Scopes[scope]; !ok {
+						flow.Scopes[scope] = ""
+					}
+				}
+			}
+		}
+	}
+}
+
+func oauth2SecuritySchemeNameOf(openapi *oas.OpenAPI, operatorTypes ...*OperatorWithTypeName) (string, bool) {
+	for _, op := range operatorTypes {
+		for _, name := range sortedSecuritySchemeNames(op.SecuritySchemes) {
+			if op.SecuritySchemes[name].Type == oas.SecurityTypeOAuth2 {
+				return name, true
+			}
+		}
+	}
+	if s, ok := openapi.Components.SecuritySchemes[SecuritySchemeOAuth2]; ok && s.Type == oas.SecurityTypeOAuth2 {
+		return SecuritySchemeOAuth2, true
+	}
+	return "", false
+}
+
+func sortedSecuritySchemeNames(schemes map[string]*oas.SecurityScheme) []string {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
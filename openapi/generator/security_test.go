@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySchemeFromTag(t *testing.T) {
+	cases := map[string]string{
+		"key":                                  `{"type":"apiKey","name":"X-Api-Key","in":"header"}`,
+		"token,bearer,JWT":                     `{"type":"http","scheme":"bearer","bearerFormat":"JWT"}`,
+		"token,basic":                          `{"type":"http","scheme":"basic"}`,
+		"oauth2,oauth2,https://demo.com/token": `{"type":"oauth2","flows":{"clientCredentials":{"authorizationUrl":"","tokenUrl":"https://demo.com/token","scopes":{}}}}`,
+	}
+
+	for tag, expect := range cases {
+		t.Run(tag, func(t *testing.T) {
+			_, s, err := SecuritySchemeFromTag(tag, "X-Api-Key", oas.PositionHeader)
+			require.NoError(t, err)
+
+			data, _ := json.Marshal(s)
+			require.JSONEq(t, expect, string(data))
+		})
+	}
+
+	for _, tag := range []string{"", "token,digest", "oauth2,oauth2"} {
+		_, _, err := SecuritySchemeFromTag(tag, "Authorization", oas.PositionHeader)
+		require.Error(t, err)
+	}
+}
+
+func TestBindSecuritySchemes(t *testing.T) {
+	auth := &Operator{}
+	_, bearer, _ := SecuritySchemeFromTag("bearer,bearer", "Authorization", oas.PositionHeader)
+	auth.AddSecurityScheme("bearer", bearer)
+	_, oauth2, _ := SecuritySchemeFromTag(SecuritySchemeOAuth2+",oauth2,https://demo.com/token", "Authorization", oas.PositionHeader)
+	auth.AddSecurityScheme(SecuritySchemeOAuth2, oauth2)
+
+	op := &Operator{}
+	op.ID = "ListItems"
+	op.RequiredScopes = []string{"items:read"}
+
+	openapi := oas.NewOpenAPI()
+	operation := &oas.Operation{}
+
+	operatorTypes := []*OperatorWithTypeName{{Operator: auth}, {Operator: op}}
+	for i := range operatorTypes {
+		operatorTypes[i].BindOperation("GET", operation, i == len(operatorTypes)-1)
+	}
+	bindSecuritySchemes(openapi, operation, operatorTypes...)
+
+	data, _ := json.Marshal(operation.Security)
+	// required together
+	require.JSONEq(t, `[{"bearer":[],"oauth2":["items:read"]}]`, string(data))
+
+	require.Len(t, openapi.Components.SecuritySchemes, 2)
+	require.Equal(t, map[string]string{"items:read": ""}, openapi.Components.SecuritySchemes[SecuritySchemeOAuth2].Flows.ClientCredentials.Scopes)
+}
+
+func TestBindRequiredScopes(t *testing.T) {
+	bind := func(securitySchemes map[string]string) (*oas.OpenAPI, *oas.Operation) {
+		auth := &Operator{}
+		for name, tag := range securitySchemes {
+			_, s, _ := SecuritySchemeFromTag(tag, "Authorization", oas.PositionHeader)
+			auth.AddSecurityScheme(name, s)
+		}
+
+		op := &Operator{}
+		op.RequiredScopes = []string{"items:read"}
+
+		openapi := oas.NewOpenAPI()
+		operation := &oas.Operation{}
+
+		operatorTypes := []*OperatorWithTypeName{{Operator: auth}, {Operator: op}}
+		for i := range operatorTypes {
+			operatorTypes[i].BindOperation("GET", operation, i == len(operatorTypes)-1)
+		}
+		bindSecuritySchemes(openapi, operation, operatorTypes...)
+
+		return openapi, operation
+	}
+
+	t.Run("bind to declared oauth2 scheme", func(t *testing.T) {
+		openapi, operation := bind(map[string]string{"auth": "auth,oauth2,https://demo.com/token"})
+
+		data, _ := json.Marshal(operation.Security)
+		require.JSONEq(t, `[{"auth":["items:read"]}]`, string(data))
+		require.Equal(t, map[string]string{"items:read": ""}, openapi.Components.SecuritySchemes["auth"].Flows.ClientCredentials.Scopes)
+	})
+
+	t.Run("skipped without oauth2 scheme", func(t *testing.T) {
+		_, operation := bind(map[string]string{"key": "key"})
+
+		data, _ := json.Marshal(operation.Security)
+		require.JSONEq(t, `[{"key":[]}]`, string(data))
+	})
+}
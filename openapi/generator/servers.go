@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+)
+
+/*
+ServersConfig for openapi servers
+
+	{
+	  "servers": [
+	    {
+	      "url": "https://{env}.{region}.example.com/api",
+	      "description": "gateway",
+	      "variables": {
+	        "env": { "default": "dev", "enum": ["dev", "staging", "prod"] },
+	        "region": { "default": "cn" }
+	      }
+	    }
+	  ]
+	}
+*/
+type ServersConfig struct {
+	Servers []*ServerConfig `json:"servers"`
+}
+
+type ServerConfig struct {
+	URL         string                           `json:"url"`
+	Description string                           `json:"description,omitempty"`
+	Variables   map[string]*ServerVariableConfig `json:"variables,omitempty"`
+}
+
+type ServerVariableConfig struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+func LoadServersConfig(file string) (*ServersConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &ServersConfig{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, errors.Wrapf(err, "invalid servers config %s", file)
+	}
+	return c, nil
+}
+
+var reServerURLVariable = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (c *ServersConfig) OpenAPIServers() ([]*oas.Server, error) {
+	servers := make([]*oas.Server, 0, len(c.Servers))
+
+	for _, sc := range c.Servers {
+		s := oas.NewServer(sc.URL)
+		s.Description = sc.Description
+
+		for _, matched := range reServerURLVariable.FindAllStringSubmatch(sc.URL, -1) {
+			if _, ok := sc.Variables[matched[1]]; !ok {
+				return nil, errors.Errorf("missing variable `%s` of server %s", matched[1], sc.URL)
+			}
+		}
+
+		names := make([]string, 0, len(sc.Variables))
+		for name := range sc.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			vc := sc.Variables[name]
+
+			if len(vc.Enum) > 0 && !containsString(vc.Enum, vc.Default) {
+				return nil, errors.Errorf("default value `%s` of variable `%s` should be one of %v", vc.Default, name, vc.Enum)
+			}
+
+			v := oas.NewServerVariable(vc.Default)
+			v.Enum = vc.Enum
+			v.Description = vc.Description
+
+			s.AddVariable(name, v)
+		}
+
+		servers = append(servers, s)
+	}
+
+	return servers, nil
+}
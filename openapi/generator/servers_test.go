@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServersConfig(t *testing.T) {
+	t.Run("with variables", func(t *testing.T) {
+		c := &ServersConfig{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+  "servers": [
+    {
+      "url": "https://{env}.{region}.example.com/demo",
+      "variables": {
+        "env": { "default": "dev", "enum": ["dev", "staging", "prod"] },
+        "region": { "default": "cn" }
+      }
+    }
+  ]
+}`), c))
+
+		servers, err := c.OpenAPIServers()
+		require.NoError(t, err)
+
+		data, _ := json.Marshal(servers)
+		require.JSONEq(t, `[{
+  "url": "https://{env}.{region}.example.com/demo",
+  "variables": {
+    "env": { "default": "dev", "enum": ["dev", "staging", "prod"] },
+    "region": { "default": "cn" }
+  }
+}]`, string(data))
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		c := &ServersConfig{Servers: []*ServerConfig{{URL: "https://{env}.example.com"}}}
+		_, err := c.OpenAPIServers()
+		require.Error(t, err)
+	})
+
+	t.Run("default not in enum", func(t *testing.T) {
+		c := &ServersConfig{Servers: []*ServerConfig{{
+			URL: "https://{env}.example.com",
+			Variables: map[string]*ServerVariableConfig{
+				"env": {Default: "test", Enum: []string{"dev", "prod"}},
+			},
+		}}}
+		_, err := c.OpenAPIServers()
+		require.Error(t, err)
+	})
+}
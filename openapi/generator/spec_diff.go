@@ -0,0 +1,343 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrBreakingChanges returned by Output when Diff and breaking changes found
+var ErrBreakingChanges = errors.New("breaking changes")
+
+// SpecChange between previous and next spec
+type SpecChange struct {
+	// api consumers of previous spec may break
+	Breaking bool
+	// like GET /users/{id}, or #/components/schemas/User
+	Location string
+	Msg      string
+}
+
+func (c SpecChange) String() string {
+	if c.Breaking {
+		return "[breaking] " + c.Location + ": " + c.Msg
+	}
+	return c.Location + ": " + c.Msg
+}
+
+type SpecChanges []SpecChange
+
+func (changes SpecChanges) Breaking() SpecChanges {
+	breaking := SpecChanges{}
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// LoadOpenAPI loads spec in OutputFormatJSON or OutputFormatYAML
+func LoadOpenAPI(data []byte, format string) (*oas.OpenAPI, error) {
+	if format == OutputFormatYAML {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		d, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		data = d
+	}
+
+	openapi := &oas.OpenAPI{}
+	if err := json.Unmarshal(data, openapi); err != nil {
+		return nil, err
+	}
+	return openapi, nil
+}
+
+// DiffOpenAPI reports removed operations, changed types of parameters, bodies and schemas,
+// narrowed enums and new required fields or parameters as breaking changes
+func DiffOpenAPI(prev *oas.OpenAPI, next *oas.OpenAPI) SpecChanges {
+	d := &specDiffer{}
+
+	prevOperations, nextOperations := operationsOf(prev), operationsOf(next)
+
+	for location, prevOperation := range prevOperations {
+		nextOperation, ok := nextOperations[location]
+		if !ok {
+			d.add(true, location, "operation removed")
+			continue
+		}
+		d.diffOperation(location, prevOperation, nextOperation)
+	}
+
+	for location := range nextOperations {
+		if _, ok := prevOperations[location]; !ok {
+			d.add(false, location, "operation added")
+		}
+	}
+
+	for name, prevSchema := range prev.Components.Schemas {
+		nextSchema, ok := next.Components.Schemas[name]
+		if !ok {
+			// unused schemas dropped, operations using it are diffed already
+			continue
+		}
+		d.diffSchema(oas.NewComponentRefer("schemas", name).RefString(), prevSchema, nextSchema)
+	}
+
+	sort.Slice(d.changes, func(i, j int) bool {
+		if d.changes[i].Location == d.changes[j].Location {
+			return d.changes[i].Msg < d.changes[j].Msg
+		}
+		return d.changes[i].Location < d.changes[j].Location
+	})
+
+	return d.changes
+}
+
+type specDiffer struct {
+	changes SpecChanges
+}
+
+func (d *specDiffer) add(breaking bool, location string, format string, args ...interface{}) {
+	d.changes = append(d.changes, SpecChange{Breaking: breaking, Location: location, Msg: fmt.Sprintf(format, args...)})
+}
+
+func operationsOf(openapi *oas.OpenAPI) map[string]*oas.Operation {
+	operations := map[string]*oas.Operation{}
+	for path, pathItem := range openapi.Paths.Paths {
+		if pathItem == nil {
+			continue
+		}
+		for method, operation := range pathItem.Operations.Operations {
+			operations[strings.ToUpper(string(method))+" "+path] = operation
+		}
+	}
+	return operations
+}
+
+func (d *specDiffer) diffOperation(location string, prev *oas.Operation, next *oas.Operation) {
+	prevParameters, nextParameters := parametersOf(prev), parametersOf(next)
+
+	for key, prevParameter := range prevParameters {
+		nextParameter, ok := nextParameters[key]
+		if !ok {
+			d.add(false, location, "parameter %s removed", key)
+			continue
+		}
+
+		if !prevParameter.Required && nextParameter.Required {
+			d.add(true, location, "parameter %s required", key)
+		}
+
+		d.diffSchemaType(location, "parameter "+key, prevParameter.Schema, nextParameter.Schema)
+	}
+
+	for key, nextParameter := range nextParameters {
+		if _, ok := prevParameters[key]; !ok {
+			d.add(nextParameter.Required, location, "parameter %s added", key)
+		}
+	}
+
+	prevBody, nextBody := prev.RequestBody, next.RequestBody
+
+	switch {
+	case prevBody == nil && nextBody != nil:
+		d.add(nextBody.Required, location, "request body added")
+	case prevBody != nil && nextBody == nil:
+		d.add(false, location, "request body removed")
+	case prevBody != nil && nextBody != nil:
+		if !prevBody.Required && nextBody.Required {
+			d.add(true, location, "request body required")
+		}
+		d.diffSchemaType(location, "request body", schemaOfContent(prevBody.Content), schemaOfContent(nextBody.Content))
+	}
+
+	prevResponse, prevStatus := successResponseOf(prev)
+	nextResponse, nextStatus := successResponseOf(next)
+
+	if prevResponse != nil && nextResponse != nil {
+		if prevStatus != nextStatus {
+			d.add(true, location, "status of response changed from %d to %d", prevStatus, nextStatus)
+		}
+		d.diffSchemaType(location, "response", schemaOfContent(prevResponse.Content), schemaOfContent(nextResponse.Content))
+	}
+}
+
+func parametersOf(operation *oas.Operation) map[string]*oas.Parameter {
+	parameters := map[string]*oas.Parameter{}
+	for _, parameter := range operation.Parameters {
+		parameters[string(parameter.In)+"."+parameter.Name] = parameter
+	}
+	return parameters
+}
+
+func schemaOfContent(content map[string]*oas.MediaType) *oas.Schema {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	for _, contentType := range contentTypes {
+		if mediaType := content[contentType]; mediaType != nil {
+			return mediaType.Schema
+		}
+	}
+	return nil
+}
+
+func successResponseOf(operation *oas.Operation) (*oas.Response, int) {
+	for status, response := range operation.Responses.Responses {
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			return response, status
+		}
+	}
+	return nil, 0
+}
+
+func (d *specDiffer) diffSchema(location string, prev *oas.Schema, next *oas.Schema) {
+	d.diffSchemaType(location, "schema", prev, next)
+
+	prevProps, prevRequired := propsOf(prev)
+	nextProps, nextRequired := propsOf(next)
+
+	for name, prevProp := range prevProps {
+		nextProp, ok := nextProps[name]
+		if !ok {
+			d.add(true, location, "field %s removed", name)
+			continue
+		}
+		d.diffSchemaType(location, "field "+name, prevProp, nextProp)
+	}
+
+	for name := range nextRequired {
+		if !prevRequired[name] {
+			d.add(true, location, "field %s required", name)
+		}
+	}
+}
+
+func (d *specDiffer) diffSchemaType(location string, of string, prev *oas.Schema, next *oas.Schema) {
+	if prevType, nextType := typeOfSchema(prev), typeOfSchema(next); prevType != nextType {
+		d.add(true, location, "type of %s changed from %s to %s", of, prevType, nextType)
+		return
+	}
+
+	if removed := removedEnums(enumOf(prev), enumOf(next)); len(removed) > 0 {
+		d.add(true, location, "enum of %s narrowed, %s removed", of, strings.Join(removed, ", "))
+	}
+}
+
+// typeOfSchema returns type summary of schema, like integer/int64, []string, or ref of component
+func typeOfSchema(s *oas.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Refer != nil {
+		return s.Refer.RefString()
+	}
+	if len(s.AllOf) > 0 {
+		types := make([]string, 0, len(s.AllOf))
+		for _, sub := range s.AllOf {
+			if t := typeOfSchema(sub); t != "any" {
+				types = append(types, t)
+			}
+		}
+		if s.Type != "" {
+			types = append(types, string(s.Type))
+		}
+		return strings.Join(types, "&")
+	}
+	switch s.Type {
+	case oas.TypeArray:
+		return "[]" + typeOfSchema(s.Items)
+	case oas.TypeObject:
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			return "map[string]" + typeOfSchema(s.AdditionalProperties.Schema)
+		}
+		return "object"
+	case "":
+		return "any"
+	}
+	if s.Format != "" {
+		return string(s.Type) + "/" + s.Format
+	}
+	return string(s.Type)
+}
+
+func enumOf(s *oas.Schema) []interface{} {
+	if s == nil {
+		return nil
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum
+	}
+	for _, sub := range s.AllOf {
+		if enum := enumOf(sub); len(enum) > 0 {
+			return enum
+		}
+	}
+	return nil
+}
+
+func removedEnums(prev []interface{}, next []interface{}) []string {
+	if len(prev) == 0 {
+		return nil
+	}
+
+	values := map[string]bool{}
+	for _, v := range next {
+		values[fmt.Sprint(v)] = true
+	}
+
+	removed := make([]string, 0)
+
+	if len(next) == 0 {
+		// enum dropped means any value allowed
+		return removed
+	}
+
+	for _, v := range prev {
+		if s := fmt.Sprint(v); !values[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	return removed
+}
+
+// propsOf collects properties and required of schema, with ones of inline schemas in allOf
+func propsOf(s *oas.Schema) (map[string]*oas.Schema, map[string]bool) {
+	props, required := map[string]*oas.Schema{}, map[string]bool{}
+
+	var collect func(s *oas.Schema)
+	collect = func(s *oas.Schema) {
+		if s == nil || s.Refer != nil {
+			return
+		}
+		for name, prop := range s.Properties {
+			props[name] = prop
+		}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		for _, sub := range s.AllOf {
+			collect(sub)
+		}
+	}
+
+	collect(s)
+
+	return props, required
+}
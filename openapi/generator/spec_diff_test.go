@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func specForDiff(modify func(openapi *oas.OpenAPI)) *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+
+	status := oas.String()
+	status.Enum = []interface{}{"ACTIVE", "DISABLED"}
+	openapi.AddSchema("Status", status)
+
+	openapi.AddSchema("User", oas.ObjectOf(oas.Props{
+		"id":     oas.Long(),
+		"name":   oas.String(),
+		"status": openapi.RefSchema("Status"),
+	}, "id"))
+
+	list := oas.NewOperation("ListUsers")
+	list.AddParameter(oas.QueryParameter("size", oas.Integer(), false))
+	resp := oas.NewResponse("")
+	resp.AddContent("application/json", oas.NewMediaTypeWithSchema(oas.ItemsOf(openapi.RefSchema("User"))))
+	list.AddResponse(http.StatusOK, resp)
+	openapi.AddOperation(oas.GET, "/users", list)
+
+	openapi.AddOperation(oas.DELETE, "/users/{id}", oas.NewOperation("DeleteUser"))
+
+	if modify != nil {
+		modify(openapi)
+	}
+
+	return openapi
+}
+
+func TestDiffOpenAPI(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		require.Empty(t, DiffOpenAPI(specForDiff(nil), specForDiff(nil)))
+	})
+
+	t.Run("operation added", func(t *testing.T) {
+		changes := DiffOpenAPI(specForDiff(nil), specForDiff(func(openapi *oas.OpenAPI) {
+			openapi.AddOperation(oas.POST, "/users", oas.NewOperation("CreateUser"))
+		}))
+		require.Equal(t, SpecChanges{{Location: "POST /users", Msg: "operation added"}}, changes)
+		require.Empty(t, changes.Breaking())
+	})
+
+	cases := map[string]struct {
+		modify func(openapi *oas.OpenAPI)
+		change SpecChange
+	}{
+		"operation removed": {
+			func(openapi *oas.OpenAPI) {
+				delete(openapi.Paths.Paths, "/users/{id}")
+			},
+			SpecChange{Breaking: true, Location: "DELETE /users/{id}", Msg: "operation removed"},
+		},
+		"parameter type changed": {
+			func(openapi *oas.OpenAPI) {
+				openapi.Paths.Paths["/users"].Operations.Operations[oas.GET].Parameters[0].Schema = oas.String()
+			},
+			SpecChange{Breaking: true, Location: "GET /users", Msg: "type of parameter query.size changed from integer/int32 to string"},
+		},
+		"parameter required": {
+			func(openapi *oas.OpenAPI) {
+				openapi.Paths.Paths["/users"].Operations.Operations[oas.GET].Parameters[0].Required = true
+			},
+			SpecChange{Breaking: true, Location: "GET /users", Msg: "parameter query.size required"},
+		},
+		"required parameter added": {
+			func(openapi *oas.OpenAPI) {
+				openapi.Paths.Paths["/users"].Operations.Operations[oas.GET].AddParameter(oas.HeaderParameter("X-Tenant", oas.String(), true))
+			},
+			SpecChange{Breaking: true, Location: "GET /users", Msg: "parameter header.X-Tenant added"},
+		},
+		"enum narrowed": {
+			func(openapi *oas.OpenAPI) {
+				openapi.Components.Schemas["Status"].Enum = []interface{}{"ACTIVE"}
+			},
+			SpecChange{Breaking: true, Location: "#/components/schemas/Status", Msg: "enum of schema narrowed, DISABLED removed"},
+		},
+		"field required": {
+			func(openapi *oas.OpenAPI) {
+				user := openapi.Components.Schemas["User"]
+				user.Required = append(user.Required, "name")
+			},
+			SpecChange{Breaking: true, Location: "#/components/schemas/User", Msg: "field name required"},
+		},
+		"field type changed": {
+			func(openapi *oas.OpenAPI) {
+				openapi.Components.Schemas["User"].Properties["id"] = oas.String()
+			},
+			SpecChange{Breaking: true, Location: "#/components/schemas/User", Msg: "type of field id changed from integer/int64 to string"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			changes := DiffOpenAPI(specForDiff(nil), specForDiff(c.modify))
+			require.Equal(t, SpecChanges{c.change}, changes)
+			require.Len(t, changes.Breaking(), 1)
+		})
+	}
+
+	t.Run("enum widened", func(t *testing.T) {
+		changes := DiffOpenAPI(specForDiff(nil), specForDiff(func(openapi *oas.OpenAPI) {
+			openapi.Components.Schemas["Status"].Enum = []interface{}{"ACTIVE", "DISABLED", "DELETED"}
+		}))
+		require.Empty(t, changes)
+	})
+}
+
+func TestOpenAPIGeneratorDiff(t *testing.T) {
+	for _, format := range []string{OutputFormatJSON, OutputFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+
+			require.NoError(t, (&OpenAPIGenerator{Format: format, openapi: specForDiff(nil)}).Output(dir))
+
+			t.Run("compatible", func(t *testing.T) {
+				g := &OpenAPIGenerator{Format: format, Diff: true, openapi: specForDiff(func(openapi *oas.OpenAPI) {
+					openapi.AddOperation(oas.POST, "/users", oas.NewOperation("CreateUser"))
+				})}
+				require.NoError(t, g.Output(dir))
+			})
+
+			t.Run("breaking", func(t *testing.T) {
+				g := &OpenAPIGenerator{Format: format, Diff: true, openapi: specForDiff(func(openapi *oas.OpenAPI) {
+					delete(openapi.Paths.Paths, "/users/{id}")
+				})}
+				err := g.Output(dir)
+				require.Error(t, err)
+				require.Equal(t, ErrBreakingChanges, errors.Cause(err))
+			})
+		})
+	}
+
+	t.Run("without previous", func(t *testing.T) {
+		g := &OpenAPIGenerator{Diff: true, openapi: specForDiff(nil)}
+		require.NoError(t, g.Output(t.TempDir()))
+	})
+}
@@ -157,6 +157,9 @@ func (scanner *StatusErrScanner) appendStateErrs(typeFunc *types.Func, statusErr
 	}
 
 	sort.Slice(next, func(i, j int) bool {
+		if next[i].Code == next[j].Code {
+			return next[i].Key < next[j].Key
+		}
 		return next[i].Code < next[j].Code
 	})
 
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/go-courier/packagesx"
+)
+
+// StringValueOf resolves string value of expr statically, for paths of routers and operators, supports
+//
+//	string literals and consts
+//	concatenation by +, like basePath + "/users"
+//	fmt.Sprintf with format and args all resolvable
+//	package-level vars initialized by above
+func StringValueOf(pkg *packagesx.Package, expr ast.Expr) (string, bool) {
+	info := pkg.PkgInfoOf(expr)
+	if info == nil {
+		return "", false
+	}
+
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		if tv.Value.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(tv.Value), true
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return StringValueOf(pkg, e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := StringValueOf(pkg, e.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := StringValueOf(pkg, e.Y)
+		if !ok {
+			return "", false
+		}
+		return x + y, true
+	case *ast.CallExpr:
+		if !isFmtSprintf(info, e) || len(e.Args) == 0 || e.Ellipsis.IsValid() {
+			return "", false
+		}
+		format, ok := StringValueOf(pkg, e.Args[0])
+		if !ok {
+			return "", false
+		}
+		args := make([]interface{}, 0, len(e.Args)-1)
+		for _, arg := range e.Args[1:] {
+			v, ok := argValueOf(pkg, arg)
+			if !ok {
+				return "", false
+			}
+			args = append(args, v)
+		}
+		return fmt.Sprintf(format, args...), true
+	case *ast.Ident:
+		return varValueOf(pkg, info.ObjectOf(e))
+	case *ast.SelectorExpr:
+		return varValueOf(pkg, info.ObjectOf(e.Sel))
+	}
+
+	return "", false
+}
+
+func argValueOf(pkg *packagesx.Package, expr ast.Expr) (interface{}, bool) {
+	if info := pkg.PkgInfoOf(expr); info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+			return valueOf(tv.Value), true
+		}
+	}
+	return StringValueOf(pkg, expr)
+}
+
+func isFmtSprintf(info *types.Info, callExpr *ast.CallExpr) bool {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := info.ObjectOf(selectorExpr.Sel).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && fn.Name() == "Sprintf"
+}
+
+// varValueOf resolves value of package-level var by its initializer
+func varValueOf(pkg *packagesx.Package, obj types.Object) (string, bool) {
+	typeVar, ok := obj.(*types.Var)
+	if !ok || typeVar.Pkg() == nil || typeVar.Parent() != typeVar.Pkg().Scope() {
+		return "", false
+	}
+
+	p := pkg.Pkg(typeVar.Pkg().Path())
+	if p == nil {
+		return "", false
+	}
+
+	for ident, def := range p.TypesInfo.Defs {
+		if def != obj || ident.Obj == nil {
+			continue
+		}
+		valueSpec, ok := ident.Obj.Decl.(*ast.ValueSpec)
+		if !ok || len(valueSpec.Values) != len(valueSpec.Names) {
+			return "", false
+		}
+		for i, name := range valueSpec.Names {
+			if name == ident {
+				return StringValueOf(pkg, valueSpec.Values[i])
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringValueOf(t *testing.T) {
+	pkg := loadPackageFromSource(t, `package routes
+
+import "fmt"
+
+const basePath = "/api"
+
+const version = 2
+
+var prefix = basePath + "/v1"
+
+var dynamic = fmt.Sprint(version)
+
+var (
+	literal   = "/users"
+	concat    = basePath + "/users"
+	nested    = (prefix + "/users") + "/:id"
+	sprintf   = fmt.Sprintf("%s/v%d/users", basePath, version)
+	sprintfs  = fmt.Sprintf("%s/orgs", prefix)
+	unknown   = dynamic + "/users"
+	notString = version
+)
+`)
+
+	values := map[string]ast.Expr{}
+	for ident, def := range pkg.TypesInfo.Defs {
+		if def == nil || ident.Obj == nil {
+			continue
+		}
+		if spec, ok := ident.Obj.Decl.(*ast.ValueSpec); ok && len(spec.Values) == 1 {
+			values[ident.Name] = spec.Values[0]
+		}
+	}
+
+	cases := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{"literal", "/users", true},
+		{"concat", "/api/users", true},
+		{"nested", "/api/v1/users/:id", true},
+		{"sprintf", "/api/v2/users", true},
+		{"sprintfs", "/api/v1/orgs", true},
+		{"unknown", "", false},
+		{"notString", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, ok := StringValueOf(pkg, values[c.name])
+			require.Equal(t, c.ok, ok)
+			require.Equal(t, c.expected, s)
+		})
+	}
+}
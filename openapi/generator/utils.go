@@ -28,6 +28,31 @@ const (
 	// Deprecated  use XEnumLabels
 	XEnumOptions = `x-enum-options`
 	XStatusErrs  = `x-status-errors`
+	XSpecHash    = `x-spec-hash`
+	// timeout of operation for generated clients, duration like 5s, or number of seconds
+	XTimeout = `x-timeout`
+	// whether operation could be retried for generated clients
+	XRetry = `x-retry`
+	// whether operation accessible without auth, declared by httptransport.PublicAccessDescriber
+	XPublicAccess = `x-public-access`
+	// limit and window of requests of each client, declared by httptransport.RateLimitDescriber
+	XRateLimit = `x-rate-limit`
+	// origins allowed of cross-origin requests, declared by httptransport.CORSDescriber
+	XCORSAllowedOrigins = `x-cors-allowed-origins`
+	// values coerced as true or false of bool field flagged checkbox in body of form or multipart
+	XCheckbox = `x-checkbox`
+	// webhooks subscribed out of band by name, declared by httptransport.WebhookDescriber, like webhooks of openapi 3.1
+	XWebhooks = `x-webhooks`
+	// operations for probes and metadata of service, like operators of httptransport/operators,
+	// tagged TagOperational and skipped by generated clients
+	XOperational = `x-operational`
+
+	// tag of operations flagged XOperational
+	TagOperational = "operational"
+
+	// name of security scheme in components which required scopes of operators bind to,
+	// when no oauth2 security scheme declared by operators of the route
+	SecuritySchemeOAuth2 = "oauth2"
 )
 
 var (
@@ -44,6 +69,14 @@ func isHttpxResponse(typ types.Type) bool {
 	return strings.HasSuffix(typ.String(), pkgImportPathHttpx+".Response")
 }
 
+func isHttpxPagination(typ types.Type) bool {
+	return strings.HasSuffix(typ.String(), pkgImportPathHttpx+".Pagination")
+}
+
+func isHttpxResponseWriterFunc(typ types.Type) bool {
+	return strings.HasSuffix(typ.String(), pkgImportPathHttpx+".ResponseWriterFunc")
+}
+
 func isFromHttpTransport(typ types.Type) bool {
 	return strings.Contains(typ.String(), pkgImportPathHttpTransport+".")
 }
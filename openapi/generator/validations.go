@@ -3,41 +3,91 @@ package generator
 import (
 	"context"
 	"go/types"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/transformers"
 	"github.com/go-courier/oas"
 	"github.com/go-courier/ptr"
 	"github.com/go-courier/reflectx/typesutil"
 	"github.com/go-courier/validator"
+	"github.com/go-courier/validator/rules"
 )
 
 func BindSchemaValidationByValidateBytes(s *oas.Schema, typ types.Type, validateBytes []byte) error {
+	return bindSchemaValidationByValidateBytes(s, typ, validateBytes, true)
+}
+
+func bindSchemaValidationByValidateBytes(s *oas.Schema, typ types.Type, validateBytes []byte, describe bool) error {
 	ttype := typesutil.FromTType(typ)
 
 	fieldValidator, err := validator.ValidatorMgrDefault.Compile(context.Background(), validateBytes, ttype, func(rule *validator.Rule) {
 		rule.DefaultValue = nil
 	})
 	if err != nil {
+		// custom rules registered by services at runtime, unknown for generator
+		if rule, e := rules.ParseRule(validateBytes); e == nil && strings.HasSuffix(err.Error(), "not match any validator") {
+			bindSchemaOfCustomRule(s, rule.Name)
+			return nil
+		}
 		return err
 	}
 
 	if fieldValidator != nil {
-		BindSchemaValidationByValidator(s, fieldValidator)
+		bindSchemaValidation(s, fieldValidator, describe)
 	}
 
 	return nil
 }
 
+// KnownCustomRules are patterns of custom rules commonly registered by services, like @phone,
+// patterns documented in schema of string, and other custom rules documented as format only.
+var KnownCustomRules = map[string]string{
+	// E.164
+	"phone": `^\+?[1-9]\d{1,14}$`,
+}
+
+func bindSchemaOfCustomRule(s *oas.Schema, name string) {
+	s.Type = oas.TypeString
+	s.Format = name
+	s.Pattern = KnownCustomRules[name]
+}
+
+// BindSchemaValidationByValidator binds constraints of validator into keywords of schema,
+// and appends human-readable constraints into description of schema
 func BindSchemaValidationByValidator(s *oas.Schema, v validator.Validator) {
+	bindSchemaValidation(s, v, true)
+}
+
+func bindSchemaValidation(s *oas.Schema, v validator.Validator, describe bool) {
+	if s == nil {
+		return
+	}
 	if validatorLoader, ok := v.(*validator.ValidatorLoader); ok {
 		v = validatorLoader.Validator
 	}
-	if s == nil {
-		*s = oas.Schema{}
+
+	bindSchemaValidationByValidator(s, v, describe)
+
+	if !describe {
+		return
+	}
+
+	if desc := DescribeValidator(v); desc != "" {
+		if s.Description != "" {
+			s.Description += "\n"
+		}
+		s.Description += desc
 	}
+}
+
+func bindSchemaValidationByValidator(s *oas.Schema, v validator.Validator, describe bool) {
 	switch vt := v.(type) {
 	case *validator.UintValidator:
 		if len(vt.Enums) > 0 {
-			for v := range vt.Enums {
+			for _, v := range sortedUint64s(vt.Enums) {
 				s.Enum = append(s.Enum, v)
 			}
 			return
@@ -52,7 +102,7 @@ func BindSchemaValidationByValidator(s *oas.Schema, v validator.Validator) {
 		}
 	case *validator.IntValidator:
 		if len(vt.Enums) > 0 {
-			for v := range vt.Enums {
+			for _, v := range sortedInt64s(vt.Enums) {
 				s.Enum = append(s.Enum, v)
 			}
 			return
@@ -72,7 +122,7 @@ func BindSchemaValidationByValidator(s *oas.Schema, v validator.Validator) {
 		}
 	case *validator.FloatValidator:
 		if len(vt.Enums) > 0 {
-			for v := range vt.Enums {
+			for _, v := range sortedFloat64s(vt.Enums) {
 				s.Enum = append(s.Enum, v)
 			}
 			return
@@ -90,14 +140,22 @@ func BindSchemaValidationByValidator(s *oas.Schema, v validator.Validator) {
 		if vt.MultipleOf > 0 {
 			s.MultipleOf = ptr.Float64(float64(vt.MultipleOf))
 		}
+	case *httptransport.PatternValidator:
+		s.Type = oas.TypeString
+		s.Format = vt.Names()[0]
+		s.Pattern = vt.Pattern()
 	case *validator.StrfmtValidator:
 		s.Type = oas.TypeString // force to type string for TextMarshaler
 		s.Format = vt.Names()[0]
+	case *transformers.DecimalValidator:
+		s.Type = oas.TypeString
+		s.Format = "decimal"
+		s.Pattern = vt.Pattern()
 	case *validator.StringValidator:
 		s.Type = oas.TypeString // force to type string for TextMarshaler
 
 		if len(vt.Enums) > 0 {
-			for v := range vt.Enums {
+			for _, v := range sortedStrings(vt.Enums) {
 				s.Enum = append(s.Enum, v)
 			}
 			return
@@ -116,16 +174,204 @@ func BindSchemaValidationByValidator(s *oas.Schema, v validator.Validator) {
 			s.MaxItems = ptr.Uint64(*vt.MaxItems)
 		}
 
-		if vt.ElemValidator != nil {
-			BindSchemaValidationByValidator(s.Items, vt.ElemValidator)
+		if vt.ElemValidator != nil && s.Items != nil {
+			bindSchemaValidation(s.Items, vt.ElemValidator, describe)
 		}
 	case *validator.MapValidator:
 		s.MinProperties = ptr.Uint64(vt.MinProperties)
 		if vt.MaxProperties != nil {
 			s.MaxProperties = ptr.Uint64(*vt.MaxProperties)
 		}
-		if vt.ElemValidator != nil {
-			BindSchemaValidationByValidator(s.AdditionalProperties.Schema, vt.ElemValidator)
+		if vt.ElemValidator != nil && s.AdditionalProperties != nil {
+			bindSchemaValidation(s.AdditionalProperties.Schema, vt.ElemValidator, describe)
+		}
+	}
+}
+
+// DescribeValidator renders constraints of validator as lines, like
+//
+//	Range: [1, 100]
+//	Enum: A, B, C
+//	Pattern: \d+
+//
+// constraints of elements are not included, which should be described on schema of items
+func DescribeValidator(v validator.Validator) string {
+	if validatorLoader, ok := v.(*validator.ValidatorLoader); ok {
+		v = validatorLoader.Validator
+	}
+
+	lines := make([]string, 0)
+
+	add := func(name string, value string) {
+		if value != "" {
+			lines = append(lines, name+": "+value)
+		}
+	}
+
+	switch vt := v.(type) {
+	case *validator.UintValidator:
+		if len(vt.Enums) > 0 {
+			values := make([]string, 0, len(vt.Enums))
+			for _, v := range sortedUint64s(vt.Enums) {
+				values = append(values, strconv.FormatUint(v, 10))
+			}
+			add("Enum", strings.Join(values, ", "))
+			break
+		}
+
+		max := ""
+		if vt.BitSize == 0 || vt.Maximum != validator.MaxUint(vt.BitSize) || vt.ExclusiveMaximum {
+			max = strconv.FormatUint(vt.Maximum, 10)
+		}
+		if max != "" || vt.Minimum > 0 || vt.ExclusiveMinimum {
+			add("Range", describeRange(strconv.FormatUint(vt.Minimum, 10), max, vt.ExclusiveMinimum, vt.ExclusiveMaximum))
+		}
+		if vt.MultipleOf > 0 {
+			add("Multiple of", strconv.FormatUint(vt.MultipleOf, 10))
+		}
+	case *validator.IntValidator:
+		if len(vt.Enums) > 0 {
+			values := make([]string, 0, len(vt.Enums))
+			for _, v := range sortedInt64s(vt.Enums) {
+				values = append(values, strconv.FormatInt(v, 10))
+			}
+			add("Enum", strings.Join(values, ", "))
+			break
+		}
+
+		min, max := "", ""
+		if vt.Minimum != nil && (vt.BitSize == 0 || *vt.Minimum != validator.MinInt(vt.BitSize) || vt.ExclusiveMinimum) {
+			min = strconv.FormatInt(*vt.Minimum, 10)
+		}
+		if vt.Maximum != nil && (vt.BitSize == 0 || *vt.Maximum != validator.MaxInt(vt.BitSize) || vt.ExclusiveMaximum) {
+			max = strconv.FormatInt(*vt.Maximum, 10)
+		}
+		add("Range", describeRange(min, max, vt.ExclusiveMinimum, vt.ExclusiveMaximum))
+		if vt.MultipleOf > 0 {
+			add("Multiple of", strconv.FormatInt(vt.MultipleOf, 10))
+		}
+	case *validator.FloatValidator:
+		if len(vt.Enums) > 0 {
+			values := make([]string, 0, len(vt.Enums))
+			for _, v := range sortedFloat64s(vt.Enums) {
+				values = append(values, formatFloat(v))
+			}
+			add("Enum", strings.Join(values, ", "))
+			break
+		}
+
+		min, max := "", ""
+		if vt.Minimum != nil {
+			min = formatFloat(*vt.Minimum)
+		}
+		if vt.Maximum != nil {
+			max = formatFloat(*vt.Maximum)
+		}
+		add("Range", describeRange(min, max, vt.ExclusiveMinimum, vt.ExclusiveMaximum))
+		if vt.MultipleOf > 0 {
+			add("Multiple of", formatFloat(vt.MultipleOf))
+		}
+	case *validator.StringValidator:
+		if len(vt.Enums) > 0 {
+			add("Enum", strings.Join(sortedStrings(vt.Enums), ", "))
+			break
+		}
+
+		add("Length", describeSize(vt.MinLength, vt.MaxLength))
+		if vt.Pattern != nil {
+			add("Pattern", vt.Pattern.String())
+		}
+	case *transformers.DecimalValidator:
+		digits := "Digits: " + strconv.Itoa(vt.MaxDigits)
+		if vt.Scale != nil {
+			digits += ", Scale: " + strconv.Itoa(*vt.Scale)
+		}
+		lines = append(lines, digits)
+		add("Range", describeRange(string(vt.Minimum), string(vt.Maximum), vt.ExclusiveMinimum, vt.ExclusiveMaximum))
+	case *validator.SliceValidator:
+		add("Items", describeSize(vt.MinItems, vt.MaxItems))
+	case *validator.MapValidator:
+		add("Properties", describeSize(vt.MinProperties, vt.MaxProperties))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// describeRange renders range in interval notation, empty bound as infinity
+func describeRange(min string, max string, exclusiveMin bool, exclusiveMax bool) string {
+	if min == "" && max == "" {
+		return ""
+	}
+
+	left, right := "[", "]"
+
+	if min == "" {
+		min = "-∞"
+		left = "("
+	} else if exclusiveMin {
+		left = "("
+	}
+
+	if max == "" {
+		max = "+∞"
+		right = ")"
+	} else if exclusiveMax {
+		right = ")"
+	}
+
+	return left + min + ", " + max + right
+}
+
+func describeSize(min uint64, max *uint64) string {
+	if max == nil {
+		if min == 0 {
+			return ""
 		}
+		return describeRange(strconv.FormatUint(min, 10), "", false, false)
+	}
+	return describeRange(strconv.FormatUint(min, 10), strconv.FormatUint(*max, 10), false, false)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func sortedUint64s(m map[uint64]string) []uint64 {
+	list := make([]uint64, 0, len(m))
+	for v := range m {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+	return list
+}
+
+func sortedInt64s(m map[int64]string) []int64 {
+	list := make([]int64, 0, len(m))
+	for v := range m {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+	return list
+}
+
+func sortedFloat64s(m map[float64]string) []float64 {
+	list := make([]float64, 0, len(m))
+	for v := range m {
+		list = append(list, v)
+	}
+	sort.Float64s(list)
+	return list
+}
+
+func sortedStrings(m map[string]string) []string {
+	list := make([]string, 0, len(m))
+	for v := range m {
+		list = append(list, v)
 	}
+	sort.Strings(list)
+	return list
 }
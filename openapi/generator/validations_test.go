@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/oas"
+	"github.com/go-courier/validator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindSchemaValidationByValidatorWithEnums(t *testing.T) {
+	t.Run("int enums should be sorted", func(t *testing.T) {
+		s := oas.Integer()
+		BindSchemaValidationByValidator(s, &validator.IntValidator{
+			Enums: map[int64]string{3: "3", 1: "1", 2: "2"},
+		})
+		require.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, s.Enum)
+	})
+
+	t.Run("string enums should be sorted", func(t *testing.T) {
+		s := oas.String()
+		BindSchemaValidationByValidator(s, &validator.StringValidator{
+			Enums: map[string]string{"C": "C", "A": "A", "B": "B"},
+		})
+		require.Equal(t, []interface{}{"A", "B", "C"}, s.Enum)
+	})
+}
+
+func TestBindSchemaValidationByValidateBytes(t *testing.T) {
+	compile := func(t *testing.T, s *oas.Schema, typ types.Type, rule string) {
+		require.NoError(t, BindSchemaValidationByValidateBytes(s, typ, []byte(rule)))
+	}
+
+	t.Run("int range", func(t *testing.T) {
+		s := oas.Integer()
+		compile(t, s, types.Typ[types.Int], "@int[1,100]")
+
+		require.Equal(t, float64(1), *s.Minimum)
+		require.Equal(t, float64(100), *s.Maximum)
+		require.Equal(t, "Range: [1, 100]", s.Description)
+	})
+
+	t.Run("exclusive and open range", func(t *testing.T) {
+		s := oas.Float()
+		compile(t, s, types.Typ[types.Float64], "@float64(0,]")
+
+		require.True(t, s.ExclusiveMinimum)
+		require.Nil(t, s.Maximum)
+		require.Equal(t, "Range: (0, +∞)", s.Description)
+	})
+
+	t.Run("decimal", func(t *testing.T) {
+		s := oas.String()
+		compile(t, s, types.Typ[types.String], "@decimal<10,2>[0,]")
+
+		require.Equal(t, "decimal", s.Format)
+		require.Equal(t, `^[+-]?\d+(\.\d{1,2})?$`, s.Pattern)
+		require.Equal(t, "Digits: 10, Scale: 2\nRange: [0, +∞)", s.Description)
+	})
+
+	t.Run("uint without range", func(t *testing.T) {
+		s := oas.Integer()
+		compile(t, s, types.Typ[types.Uint32], "@uint32")
+
+		require.Equal(t, "", s.Description)
+	})
+
+	t.Run("string pattern appends to doc", func(t *testing.T) {
+		s := oas.String().WithDesc("code")
+		compile(t, s, types.Typ[types.String], "@string/^\\d+$/")
+
+		require.Equal(t, `^\d+$`, s.Pattern)
+		require.Equal(t, "code\nPattern: ^\\d+$", s.Description)
+	})
+
+	t.Run("string enum", func(t *testing.T) {
+		s := oas.String()
+		compile(t, s, types.Typ[types.String], "@string{B,A}")
+
+		require.Equal(t, []interface{}{"A", "B"}, s.Enum)
+		require.Equal(t, "Enum: A, B", s.Description)
+	})
+
+	t.Run("known custom rule", func(t *testing.T) {
+		s := oas.String()
+		compile(t, s, types.Typ[types.String], "@phone")
+
+		require.Equal(t, "phone", s.Format)
+		require.Equal(t, KnownCustomRules["phone"], s.Pattern)
+	})
+
+	t.Run("unknown custom rule as format", func(t *testing.T) {
+		s := oas.String()
+		compile(t, s, types.Typ[types.String], "@sku")
+
+		require.Equal(t, "sku", s.Format)
+		require.Equal(t, "", s.Pattern)
+	})
+
+	t.Run("registered pattern validator", func(t *testing.T) {
+		validator.ValidatorMgrDefault.Register(httptransport.NewPatternValidator("orderNo", `^O\d+$`))
+
+		s := oas.String()
+		compile(t, s, types.Typ[types.String], "@orderNo")
+
+		require.Equal(t, "orderNo", s.Format)
+		require.Equal(t, `^O\d+$`, s.Pattern)
+	})
+
+	t.Run("items of slice", func(t *testing.T) {
+		s := oas.ItemsOf(oas.Integer())
+		compile(t, s, types.NewSlice(types.Typ[types.Int]), "@slice<@int[0,10]>[1,3]")
+
+		require.Equal(t, "Items: [1, 3]", s.Description)
+		require.Equal(t, float64(10), *s.Items.Maximum)
+		require.Equal(t, "Range: [0, 10]", s.Items.Description)
+	})
+
+	t.Run("nil items should be skipped", func(t *testing.T) {
+		s := &oas.Schema{}
+		compile(t, s, types.NewSlice(types.Typ[types.Int]), "@slice<@int[0,10]>[1,]")
+
+		require.Nil(t, s.Items)
+		require.Equal(t, "Items: [1, +∞)", s.Description)
+	})
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// TagOpenAPI declares vendor extensions of parameter, field, or operator when tagged on embedded httpx.Method*,
+//
+//	`openapi:"x-internal,x-kong-plugin=rate-limiting,x-rate-limit=100"`
+//
+// also could be declared by lines in comments of operator, type or field
+//
+//	openapi:x-rate-limit 100
+//
+// values are json when valid, like 100, true or {"a":1}, otherwise strings, true when missing.
+// keys not prefixed by x- are ignored, and values of tag should not contain comma.
+// extensions are copied into operations, parameters and schemas, for gateways consuming the spec directly.
+const TagOpenAPI = "openapi"
+
+// VendorExtensionsFromTag parses vendor extensions from tag openapi
+func VendorExtensionsFromTag(tag string) map[string]interface{} {
+	extensions := map[string]interface{}{}
+
+	for _, kv := range strings.Split(tag, ",") {
+		kv = strings.TrimSpace(kv)
+
+		key, value := kv, ""
+		if i := strings.Index(kv, "="); i >= 0 {
+			key, value = strings.TrimSpace(kv[0:i]), strings.TrimSpace(kv[i+1:])
+		}
+
+		if strings.HasPrefix(key, "x-") {
+			extensions[key] = vendorExtensionValueOf(value)
+		}
+	}
+
+	return extensions
+}
+
+var reVendorExtension = regexp.MustCompile(`^\s*open-?api:(x-\S+)(\s+(.+))?$`)
+
+// vendorExtensionsFromDoc parses vendor extensions from lines of doc, and returns doc without them
+func vendorExtensionsFromDoc(doc string) (string, map[string]interface{}) {
+	extensions := map[string]interface{}{}
+
+	lines := strings.Split(doc, "\n")
+	rest := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if matched := reVendorExtension.FindStringSubmatch(line); matched != nil {
+			extensions[matched[1]] = vendorExtensionValueOf(strings.TrimSpace(matched[3]))
+			continue
+		}
+		rest = append(rest, line)
+	}
+
+	return strings.Join(rest, "\n"), extensions
+}
+
+func vendorExtensionValueOf(value string) interface{} {
+	if value == "" {
+		return true
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func bindVendorExtensions(vendorExtensible VendorExtensible, extensions map[string]interface{}) {
+	for key, value := range extensions {
+		vendorExtensible.AddExtension(key, value)
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorExtensionsFromTag(t *testing.T) {
+	require.Equal(t, map[string]interface{}{
+		"x-internal":    true,
+		"x-kong-plugin": "rate-limiting",
+		"x-rate-limit":  float64(100),
+	}, VendorExtensionsFromTag("x-internal, x-kong-plugin=rate-limiting,x-rate-limit=100,name=ignored"))
+}
+
+func TestVendorExtensionsFromDoc(t *testing.T) {
+	doc, extensions := vendorExtensionsFromDoc(`list users
+openapi:x-rate-limit 100
+open-api:x-apisix-plugins {"limit-count":{"count":2}}
+openapi:x-internal
+description`)
+
+	require.Equal(t, "list users\ndescription", doc)
+	require.Equal(t, map[string]interface{}{
+		"x-rate-limit":     float64(100),
+		"x-apisix-plugins": map[string]interface{}{"limit-count": map[string]interface{}{"count": float64(2)}},
+		"x-internal":       true,
+	}, extensions)
+
+	t.Run("into schema", func(t *testing.T) {
+		s := oas.String()
+		setMetaFromDoc(s, "name of user\nopenapi:x-sensitive true")
+
+		require.Equal(t, "name of user", s.Description)
+		require.Equal(t, true, s.Extensions["x-sensitive"])
+	})
+
+	t.Run("into operation", func(t *testing.T) {
+		pkg := loadPackageFromSource(t, `package routes
+
+// ListUsers list users
+// openapi:x-kong-plugin rate-limiting
+type ListUsers struct{}
+`)
+
+		scanner := &OperatorScanner{pkg: pkg}
+
+		op := &Operator{}
+		scanner.scanRouteMeta(op, pkg.TypeName("ListUsers"))
+		require.Equal(t, "ListUsers list users", op.Summary)
+
+		operation := &oas.Operation{}
+		op.BindOperation("GET", operation, true)
+		require.Equal(t, "rate-limiting", operation.Extensions["x-kong-plugin"])
+	})
+}
@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/oas"
+	"github.com/go-courier/reflectx/typesutil"
+)
+
+// Webhook declared by httptransport.WebhookDescriber of operator
+type Webhook struct {
+	Name   string
+	Method string
+	// runtime expression of url, webhook subscribed out of band when empty
+	URL       string
+	Operation *oas.Operation
+}
+
+// scanWebhooks scans composite literals returned by method Webhooks
+func (scanner *OperatorScanner) scanWebhooks(ctx context.Context, op *Operator, typeName *types.TypeName) {
+	for _, typ := range []types.Type{
+		typeName.Type(),
+		types.NewPointer(typeName.Type()),
+	} {
+		method, ok := typesutil.FromTType(typ).MethodByName("Webhooks")
+		if !ok {
+			continue
+		}
+
+		results, n := scanner.pkg.FuncResultsOf(method.(*typesutil.TMethod).Func)
+		if n != 1 {
+			return
+		}
+
+		for _, v := range results[0] {
+			compositeLit, ok := v.Expr.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			for _, elt := range compositeLit.Elts {
+				if webhookLit, ok := elt.(*ast.CompositeLit); ok {
+					if webhook := scanner.webhookOf(ctx, op, webhookLit); webhook != nil {
+						op.Webhooks = append(op.Webhooks, webhook)
+					}
+				}
+			}
+
+			return
+		}
+	}
+}
+
+func (scanner *OperatorScanner) webhookOf(ctx context.Context, op *Operator, webhookLit *ast.CompositeLit) *Webhook {
+	webhook := &Webhook{Method: http.MethodPost}
+
+	var payloadType types.Type
+
+	for _, elt := range webhookLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Name":
+			webhook.Name, _ = StringValueOf(scanner.pkg, kv.Value)
+		case "Method":
+			if method, ok := StringValueOf(scanner.pkg, kv.Value); ok && method != "" {
+				webhook.Method = strings.ToUpper(method)
+			}
+		case "URL":
+			webhook.URL, _ = StringValueOf(scanner.pkg, kv.Value)
+		case "Payload":
+			if tv, err := scanner.pkg.Eval(kv.Value); err == nil {
+				payloadType = tv.Type
+			}
+		}
+	}
+
+	if webhook.Name == "" {
+		return nil
+	}
+
+	operation := &oas.Operation{}
+	operation.OperationId = op.ID + codegen.UpperCamelCase(webhook.Name)
+
+	if payloadType != nil && payloadType.String() != types.Typ[types.UntypedNil].String() {
+		if pointer, ok := payloadType.(*types.Pointer); ok {
+			payloadType = pointer.Elem()
+		}
+
+		if named, ok := payloadType.(*types.Named); ok {
+			if pkg := named.Obj().Pkg(); pkg != nil && scanner.pkg.Pkg(pkg.Path()) != nil {
+				if ident := scanner.pkg.IdentOf(named.Obj()); ident != nil {
+					operation.Summary = summaryOfDoc(scanner.pkg.CommentsOf(ident))
+				}
+			}
+		}
+
+		reqBody := oas.NewRequestBody("", true)
+		reqBody.AddContent(httpx.MIME_JSON, oas.NewMediaTypeWithSchema(scanner.DefinitionScanner.GetSchemaByType(ctx, payloadType)))
+		operation.SetRequestBody(reqBody)
+	}
+
+	// any 2xx acknowledged by subscriber
+	operation.Responses.AddResponse(http.StatusNoContent, &oas.Response{})
+
+	webhook.Operation = operation
+
+	return webhook
+}
+
+// addWebhooks adds webhooks subscribed out of band into XWebhooks of openapi, like webhooks of openapi 3.1
+func addWebhooks(openapi *oas.OpenAPI, webhooks []*Webhook) {
+	for _, webhook := range webhooks {
+		if webhook.URL != "" {
+			continue
+		}
+
+		pathItems, _ := openapi.Extensions[XWebhooks].(map[string]*oas.PathItem)
+		if pathItems == nil {
+			pathItems = map[string]*oas.PathItem{}
+			openapi.AddExtension(XWebhooks, pathItems)
+		}
+
+		if pathItems[webhook.Name] == nil {
+			pathItems[webhook.Name] = &oas.PathItem{}
+		}
+
+		pathItems[webhook.Name].AddOperation(oas.HttpMethod(strings.ToLower(webhook.Method)), webhook.Operation)
+	}
+}
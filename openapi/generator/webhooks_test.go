@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"context"
+	"go/types"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	pkg := loadPackageFromSource(t, `package routes
+
+import "io"
+
+var _ io.Writer
+
+type Webhook struct {
+	Name    string
+	Method  string
+	URL     string
+	Payload interface{}
+}
+
+const callbackURL = "{$request.body#/callbackUrl}"
+
+// PaymentPaid notified when payment paid
+type PaymentPaid struct {
+	ID string `+"`json:\"id\"`"+`
+}
+
+type CreatePayment struct{}
+
+func (CreatePayment) Webhooks() []Webhook {
+	return []Webhook{
+		{Name: "onPaid", URL: callbackURL, Payload: &PaymentPaid{}},
+		{Name: "paymentPaid", Method: "put", Payload: PaymentPaid{}},
+		{URL: "ignored without name"},
+	}
+}
+`)
+
+	// io imported only, not in all packages
+	ioWriter := pkg.Types.Imports()[0].Scope().Lookup("Writer")
+
+	scanner := &OperatorScanner{
+		pkg: pkg,
+		DefinitionScanner: &DefinitionScanner{
+			pkg:               pkg,
+			enumScanner:       NewEnumScanner(pkg),
+			ioWriterInterface: ioWriter.Type().Underlying().(*types.Interface),
+		},
+	}
+
+	op := &Operator{}
+	op.ID = "CreatePayment"
+	scanner.scanWebhooks(context.Background(), op, pkg.TypeName("CreatePayment"))
+
+	require.Len(t, op.Webhooks, 2)
+
+	onPaid := op.Webhooks[0]
+	require.Equal(t, "onPaid", onPaid.Name)
+	require.Equal(t, http.MethodPost, onPaid.Method)
+	require.Equal(t, "{$request.body#/callbackUrl}", onPaid.URL)
+	require.Equal(t, "CreatePaymentOnPaid", onPaid.Operation.OperationId)
+	require.Equal(t, "PaymentPaid notified when payment paid", onPaid.Operation.Summary)
+	require.NotNil(t, onPaid.Operation.RequestBody)
+
+	require.Equal(t, http.MethodPut, op.Webhooks[1].Method)
+
+	t.Run("callbacks of operation", func(t *testing.T) {
+		operation := &oas.Operation{}
+		op.BindOperation(http.MethodPost, operation, true)
+
+		require.Len(t, operation.Callbacks, 1)
+		require.Equal(t, onPaid.Operation, (*operation.Callbacks["onPaid"]).CallbackObject["{$request.body#/callbackUrl}"].Operations.Operations["post"])
+	})
+
+	t.Run("webhooks of openapi", func(t *testing.T) {
+		openapi := oas.NewOpenAPI()
+		addWebhooks(openapi, op.Webhooks)
+
+		pathItems := openapi.Extensions[XWebhooks].(map[string]*oas.PathItem)
+		require.Len(t, pathItems, 1)
+		require.Equal(t, op.Webhooks[1].Operation, pathItems["paymentPaid"].Operations.Operations["put"])
+	})
+}
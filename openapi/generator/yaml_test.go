@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONToYAML(t *testing.T) {
+	data, err := JSONToYAML([]byte(`{"b":1,"a":{"d":1.10,"c":"<","e":[true,null,"1"]},"f":[],"g":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, `b: 1
+a:
+  d: 1.10
+  c: <
+  e:
+    - true
+    - null
+    - "1"
+f: []
+g: {}
+`, string(data))
+
+	_, err = JSONToYAML([]byte(`{"a":`))
+	require.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+const (
+	DocsUISwagger = "swagger-ui"
+	DocsUIRedoc   = "redoc"
+)
+
+type HandlerOptions struct {
+	// spec served, default openapi.json under working dir
+	Spec []byte
+	// default /openapi.json
+	SpecPath string
+	// default /docs
+	DocsPath string
+	// DocsUISwagger or DocsUIRedoc, default DocsUISwagger
+	DocsUI string
+	// title of docs page, default API Docs
+	Title string
+}
+
+func (opts *HandlerOptions) SetDefaults() {
+	if opts.Spec == nil {
+		opts.Spec = openAPIJSONData.Bytes()
+	}
+	if opts.SpecPath == "" {
+		opts.SpecPath = "/openapi.json"
+	}
+	if opts.DocsPath == "" {
+		opts.DocsPath = "/docs"
+	}
+	if opts.DocsUI == "" {
+		opts.DocsUI = DocsUISwagger
+	}
+	if opts.Title == "" {
+		opts.Title = "API Docs"
+	}
+}
+
+// Handler serves spec at SpecPath and docs page of DocsUI at DocsPath, with ETag,
+// requests with If-None-Match matched will be responded 304.
+// paths matched by suffix, so it could be mounted under prefix, like admin listener.
+// assets of docs ui loaded from cdn by the embedded page.
+func Handler(opts HandlerOptions) http.Handler {
+	opts.SetDefaults()
+
+	return &handler{
+		opts:     opts,
+		specETag: etagOf(opts.Spec, specHashOf(opts.Spec)),
+	}
+}
+
+type handler struct {
+	opts     HandlerOptions
+	specETag string
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(req.URL.Path, h.opts.SpecPath):
+		serveWithETag(rw, req, httpx.MIME_JSON, h.specETag, h.opts.Spec)
+	case strings.HasSuffix(req.URL.Path, h.opts.DocsPath):
+		specURL := strings.TrimSuffix(req.URL.Path, h.opts.DocsPath) + h.opts.SpecPath
+		serveDocs(rw, req, h.opts.DocsUI, h.opts.Title, specURL)
+	default:
+		http.NotFound(rw, req)
+	}
+}
+
+func serveDocs(rw http.ResponseWriter, req *http.Request, docsUI string, title string, specURL string) {
+	tpl := swaggerUITemplate
+	if docsUI == DocsUIRedoc {
+		tpl = redocTemplate
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tpl.Execute(buf, map[string]string{"Title": title, "SpecURL": specURL}); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	serveWithETag(rw, req, "text/html; charset=utf-8", etagOf(buf.Bytes(), ""), buf.Bytes())
+}
+
+// serveWithETag writes data, or 304 when If-None-Match matched etag.
+// clients always revalidate by no-cache, so docs updated once deployed
+func serveWithETag(rw http.ResponseWriter, req *http.Request, contentType string, etag string, data []byte) {
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatched(req.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.Header().Set(httpx.HeaderContentType, contentType)
+	rw.WriteHeader(http.StatusOK)
+
+	if req.Method != http.MethodHead {
+		_, _ = rw.Write(data)
+	}
+}
+
+// etagOf returns quoted specHash, or quoted prefix of sha256 of data when specHash empty
+func etagOf(data []byte, specHash string) string {
+	if specHash == "" {
+		sum := sha256.Sum256(data)
+		specHash = hex.EncodeToString(sum[:])[0:16]
+	}
+	return `"` + specHash + `"`
+}
+
+func etagMatched(ifNoneMatch string, etag string) bool {
+	for _, v := range strings.Split(ifNoneMatch, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{ .Title }}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@4/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: {{ .SpecURL }}, dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`))
+
+var redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{ .Title }}</title>
+</head>
+<body>
+  <redoc spec-url="{{ .SpecURL }}"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	h := Handler(HandlerOptions{
+		Spec: []byte(`{"openapi":"3.0.3","x-spec-hash":"abc"}`),
+	})
+
+	serve := func(method string, path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("spec with etag of spec hash", func(t *testing.T) {
+		rw := serve(http.MethodGet, "/openapi.json", "")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, `"abc"`, rw.Header().Get("ETag"))
+		require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+		require.Equal(t, `{"openapi":"3.0.3","x-spec-hash":"abc"}`, rw.Body.String())
+
+		require.Equal(t, http.StatusNotModified, serve(http.MethodGet, "/openapi.json", `W/"x", "abc"`).Code)
+		require.Equal(t, http.StatusOK, serve(http.MethodGet, "/openapi.json", `"x"`).Code)
+	})
+
+	t.Run("docs under prefix", func(t *testing.T) {
+		rw := serve(http.MethodGet, "/api/docs", "")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.True(t, strings.HasPrefix(rw.Header().Get("Content-Type"), "text/html"))
+		require.Contains(t, rw.Body.String(), `"/api/openapi.json"`)
+
+		etag := rw.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		require.Equal(t, http.StatusNotModified, serve(http.MethodGet, "/api/docs", etag).Code)
+	})
+
+	t.Run("redoc", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		Handler(HandlerOptions{DocsUI: DocsUIRedoc}).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		require.Contains(t, rw.Body.String(), `<redoc spec-url="/openapi.json">`)
+	})
+
+	t.Run("head without body", func(t *testing.T) {
+		rw := serve(http.MethodHead, "/openapi.json", "")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Empty(t, rw.Body.String())
+	})
+
+	require.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, "/openapi.json", "").Code)
+	require.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/other", "").Code)
+}
@@ -3,7 +3,10 @@ package openapi
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"strings"
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/httpx"
@@ -11,6 +14,8 @@ import (
 
 var openAPIJSONData = bytes.NewBuffer(nil)
 
+var specHash = ""
+
 func init() {
 	data, err := ioutil.ReadFile("./openapi.json")
 	if err == nil {
@@ -18,14 +23,69 @@ func init() {
 	} else {
 		openAPIJSONData.Write([]byte("{}"))
 	}
+
+	specHash = specHashOf(openAPIJSONData.Bytes())
+}
+
+func specHashOf(data []byte) string {
+	spec := struct {
+		SpecHash string `json:"x-spec-hash"`
+	}{}
+	if err := json.Unmarshal(data, &spec); err == nil {
+		return spec.SpecHash
+	}
+	return ""
+}
+
+// SpecHash returns x-spec-hash of served openapi.json
+func SpecHash() string {
+	return specHash
+}
+
+// SpecHashHandler writes X-Spec-Hash on every response,
+// generated clients could verify it by roundtrippers.NewSpecHashRoundTripper
+func SpecHashHandler() func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if specHash != "" {
+				rw.Header().Set(httpx.HeaderSpecHash, specHash)
+			}
+			handler.ServeHTTP(rw, req)
+		})
+	}
 }
 
 var OpenAPIRouter = courier.NewRouter(OpenAPI{})
 
+// OpenAPIDocsRouter serves docs page of spec served by OpenAPI,
+// should be registered into OpenAPIRouter, like OpenAPIRouter.Register(openapi.OpenAPIDocsRouter)
+var OpenAPIDocsRouter = courier.NewRouter(OpenAPIDocs{})
+
 type OpenAPI struct {
 	httpx.MethodGet
 }
 
 func (s OpenAPI) Output(c context.Context) (interface{}, error) {
-	return httpx.WithContentType(httpx.MIME_JSON)(bytes.NewBuffer(openAPIJSONData.Bytes())), nil
+	return httpx.ResponseWriterFunc(func(rw http.ResponseWriter, r *http.Request) {
+		serveWithETag(rw, r, httpx.MIME_JSON, etagOf(openAPIJSONData.Bytes(), specHash), openAPIJSONData.Bytes())
+	}), nil
+}
+
+// OpenAPIDocs page by swagger-ui
+type OpenAPIDocs struct {
+	httpx.MethodGet
+}
+
+func (OpenAPIDocs) Path() string {
+	return "/docs"
+}
+
+func (OpenAPIDocs) Output(c context.Context) (interface{}, error) {
+	return httpx.ResponseWriterFunc(func(rw http.ResponseWriter, r *http.Request) {
+		specURL := strings.TrimSuffix(r.URL.Path, "/docs")
+		if specURL == "" {
+			specURL = "/"
+		}
+		serveDocs(rw, r, DocsUISwagger, "API Docs", specURL)
+	}), nil
 }
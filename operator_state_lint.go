@@ -0,0 +1,102 @@
+package httptransport
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+
+	"github.com/fatih/color"
+)
+
+// OperatorStateWarning of field of operator, which is mutable but not injected by request,
+// state in it may leak across concurrent requests, like map or pointer copied by InitFrom from the registered operator.
+// fields tagged `context` considered deliberate, like clients shared or state from context,
+//
+//	type ListUsers struct {
+//		httpx.MethodGet
+//		Size int `name:"size" in:"query"`
+//		// shared, safe for concurrent use
+//		Cache *Cache `name:"-" context:""`
+//	}
+type OperatorStateWarning struct {
+	Operator string
+	Field    string
+	Type     string
+}
+
+func (w OperatorStateWarning) String() string {
+	return fmt.Sprintf("field %s %s of operator %s is mutable and not injected, state may leak across concurrent requests. tag `context` if shared deliberately", w.Field, w.Type, w.Operator)
+}
+
+// LintOperatorState reports fields of operator type, which are unexported or `name:"-"`,
+// typed map, slice, pointer or chan, and without tag `context`.
+func LintOperatorState(typ reflect.Type) []OperatorStateWarning {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	warnings := make([]OperatorStateWarning, 0)
+
+	if typ.Kind() != reflect.Struct {
+		return warnings
+	}
+
+	var lint func(t reflect.Type, prefix string)
+
+	lint = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			if _, ok := f.Tag.Lookup("context"); ok {
+				continue
+			}
+
+			if _, ok := f.Tag.Lookup(TagInject); ok {
+				// populated by Injector for each request
+				continue
+			}
+
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				// like httpx.MethodGet
+				lint(f.Type, prefix+f.Name+".")
+				continue
+			}
+
+			injected := ast.IsExported(f.Name) && f.Tag.Get("name") != "-"
+			if injected {
+				continue
+			}
+
+			switch f.Type.Kind() {
+			case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Chan:
+				warnings = append(warnings, OperatorStateWarning{
+					Operator: typ.String(),
+					Field:    prefix + f.Name,
+					Type:     f.Type.String(),
+				})
+			}
+		}
+	}
+
+	lint(typ, "")
+
+	return warnings
+}
+
+// lintOperatorStates prints warnings of operators of routes, each operator type once
+func lintOperatorStates(routeMetas []*HttpRouteMeta) {
+	linted := map[reflect.Type]bool{}
+
+	for _, routeMeta := range routeMetas {
+		for _, opFactory := range routeMeta.OperatorFactoryWithRouteMetas {
+			if linted[opFactory.Type] {
+				continue
+			}
+			linted[opFactory.Type] = true
+
+			for _, w := range LintOperatorState(opFactory.Type) {
+				courierPrintln(color.YellowString("WARNING %s", w))
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package httptransport_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+type Cache struct{}
+
+type ListUsersWithState struct {
+	httpx.MethodGet
+	Size  int             `name:"size,omitempty" in:"query"`
+	IDs   []string        `name:"id,omitempty" in:"query"`
+	Cache *Cache          `name:"-" context:""`
+	Seen  map[string]bool `name:"-"`
+
+	mu      sync.Mutex
+	results []string
+	counter *int
+	total   int
+}
+
+func (*ListUsersWithState) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestLintOperatorState(t *testing.T) {
+	warnings := httptransport.LintOperatorState(reflect.TypeOf(&ListUsersWithState{}))
+
+	fields := make([]string, 0)
+	for _, w := range warnings {
+		fields = append(fields, w.Field)
+	}
+
+	require.Equal(t, []string{"Seen", "results", "counter"}, fields)
+	require.Contains(t, warnings[0].String(), "httptransport_test.ListUsersWithState")
+}
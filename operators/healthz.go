@@ -0,0 +1,24 @@
+package operators
+
+import (
+	"context"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+type HealthStatus struct {
+	Status string `json:"status"`
+}
+
+// Healthz for liveness probe, ok whenever serving
+type Healthz struct {
+	httpx.MethodGet `path:"/healthz" openapi:"x-operational"`
+}
+
+func (Healthz) PublicAccess() bool {
+	return true
+}
+
+func (Healthz) Output(ctx context.Context) (interface{}, error) {
+	return &HealthStatus{Status: StatusOK}, nil
+}
@@ -0,0 +1,32 @@
+// Package operators provides built-in operators for probes and build metadata of service,
+//
+//	GET /healthz  liveness
+//	GET /readyz   readiness by checkers registered
+//	GET /version  build metadata
+//
+// register them into root router, like RootRouter.Register(operators.Router).
+// operations of them flagged x-operational and tagged operational in openapi, skipped by generated clients.
+package operators
+
+import (
+	"github.com/go-courier/courier"
+)
+
+var Router = courier.NewRouter()
+
+var HealthzRouter = courier.NewRouter(&Healthz{})
+
+var ReadyzRouter = courier.NewRouter(&Readyz{})
+
+var VersionRouter = courier.NewRouter(&ServiceVersion{})
+
+func init() {
+	Router.Register(HealthzRouter)
+	Router.Register(ReadyzRouter)
+	Router.Register(VersionRouter)
+}
+
+const (
+	StatusOK       = "ok"
+	StatusNotReady = "not ready"
+)
@@ -0,0 +1,80 @@
+package operators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/httptransport"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, path string, v interface{}) int {
+	serviceMeta := &httptransport.ServiceMeta{Name: "service-test", Version: "1.0.0"}
+	rtMgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+	for _, route := range Router.Routes() {
+		routeMeta := httptransport.NewHttpRouteMeta(route)
+		if routeMeta.Path() != path {
+			continue
+		}
+
+		rw := httptest.NewRecorder()
+		httptransport.NewHttpRouteHandler(serviceMeta, routeMeta, rtMgr).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), v))
+		return rw.Code
+	}
+
+	t.Fatalf("route %s not found", path)
+	return 0
+}
+
+func TestRouter(t *testing.T) {
+	t.Run("healthz", func(t *testing.T) {
+		status := &HealthStatus{}
+		require.Equal(t, http.StatusOK, serve(t, "/healthz", status))
+		require.Equal(t, StatusOK, status.Status)
+	})
+
+	t.Run("readyz", func(t *testing.T) {
+		failing := false
+
+		RegisterChecker("db", func(ctx context.Context) error {
+			if failing {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+		defer func() {
+			DefaultCheckers = &Checkers{}
+		}()
+
+		status := &ReadinessStatus{}
+		require.Equal(t, http.StatusOK, serve(t, "/readyz", status))
+		require.Equal(t, &ReadinessStatus{Status: StatusOK, Checks: map[string]string{"db": StatusOK}}, status)
+
+		failing = true
+
+		status = &ReadinessStatus{}
+		require.Equal(t, http.StatusServiceUnavailable, serve(t, "/readyz", status))
+		require.Equal(t, &ReadinessStatus{Status: StatusNotReady, Checks: map[string]string{"db": "connection refused"}}, status)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		Commit, BuiltAt = "abc", "2026-10-14T00:00:00Z"
+		defer func() {
+			Commit, BuiltAt = "", ""
+		}()
+
+		info := &BuildInfo{}
+		require.Equal(t, http.StatusOK, serve(t, "/version", info))
+		require.Equal(t, "service-test", info.Name)
+		require.Equal(t, "1.0.0", info.Version)
+		require.Equal(t, "abc", info.Commit)
+		require.Equal(t, "2026-10-14T00:00:00Z", info.BuiltAt)
+		require.NotEmpty(t, info.GoVersion)
+	})
+}
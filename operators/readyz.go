@@ -0,0 +1,153 @@
+package operators
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/pkg/errors"
+)
+
+// Checker checks dependency of service, like database or downstream service
+type Checker func(ctx context.Context) error
+
+// Pinger like *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingChecker creates Checker by PingContext of pinger
+func PingChecker(pinger Pinger) Checker {
+	return pinger.PingContext
+}
+
+// ClientChecker creates Checker calling req by c, like request of generated client of downstream service,
+// failed when request failed or response status code not 2xx.
+func ClientChecker(c courier.Client, req interface{}) Checker {
+	return func(ctx context.Context) error {
+		_, err := c.Do(ctx, req).Into(nil)
+		return err
+	}
+}
+
+// Checkers of readiness by name
+type Checkers struct {
+	// timeout of each checker, default 5s
+	Timeout time.Duration
+
+	mu       sync.RWMutex
+	names    []string
+	checkers map[string]Checker
+}
+
+// Register checker by name, checker of same name will be replaced
+func (c *Checkers) Register(name string, checker Checker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checkers == nil {
+		c.checkers = map[string]Checker{}
+	}
+	if _, ok := c.checkers[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checkers[name] = checker
+}
+
+// Check runs all checkers concurrently, returns results by name, nil when passed
+func (c *Checkers) Check(ctx context.Context) map[string]error {
+	c.mu.RLock()
+	names := append([]string{}, c.names...)
+	checkers := make([]Checker, len(names))
+	for i, name := range names {
+		checkers[i] = c.checkers[name]
+	}
+	c.mu.RUnlock()
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	errs := make([]error, len(names))
+
+	wg := sync.WaitGroup{}
+	for i := range checkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			errs[i] = runChecker(ctx, checkers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	results := make(map[string]error, len(names))
+	for i, err := range errs {
+		results[names[i]] = err
+	}
+	return results
+}
+
+func runChecker(ctx context.Context, checker Checker) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("panic: %v", e)
+		}
+	}()
+	return checker(ctx)
+}
+
+// DefaultCheckers used by Readyz
+var DefaultCheckers = &Checkers{}
+
+// RegisterChecker registers checker into DefaultCheckers
+//
+//	operators.RegisterChecker("db", operators.PingChecker(db))
+//	operators.RegisterChecker("user-service", operators.ClientChecker(userClient, &client_user.Healthz{}))
+func RegisterChecker(name string, checker Checker) {
+	DefaultCheckers.Register(name, checker)
+}
+
+type ReadinessStatus struct {
+	Status string `json:"status"`
+	// result of each checker by name, ok or error message
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Readyz for readiness probe, 503 when any checker of DefaultCheckers failed
+type Readyz struct {
+	httpx.MethodGet `path:"/readyz" openapi:"x-operational"`
+}
+
+func (Readyz) PublicAccess() bool {
+	return true
+}
+
+func (Readyz) Output(ctx context.Context) (interface{}, error) {
+	status := &ReadinessStatus{Status: StatusOK}
+
+	for name, err := range DefaultCheckers.Check(ctx) {
+		if status.Checks == nil {
+			status.Checks = map[string]string{}
+		}
+		if err != nil {
+			status.Status = StatusNotReady
+			status.Checks[name] = err.Error()
+			continue
+		}
+		status.Checks[name] = StatusOK
+	}
+
+	if status.Status != StatusOK {
+		return httpx.WithStatusCode(http.StatusServiceUnavailable)(status), nil
+	}
+
+	return status, nil
+}
@@ -0,0 +1,66 @@
+package operators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type pinger func(ctx context.Context) error
+
+func (p pinger) PingContext(ctx context.Context) error {
+	return p(ctx)
+}
+
+type clientFunc func(ctx context.Context, req interface{}) error
+
+func (fn clientFunc) Do(ctx context.Context, req interface{}, metas ...courier.Metadata) courier.Result {
+	return resultErr{err: fn(ctx, req)}
+}
+
+type resultErr struct {
+	err error
+}
+
+func (r resultErr) Into(v interface{}) (courier.Metadata, error) {
+	return nil, r.err
+}
+
+func TestCheckers(t *testing.T) {
+	checkers := &Checkers{Timeout: 10 * time.Millisecond}
+
+	checkers.Register("db", PingChecker(pinger(func(ctx context.Context) error {
+		return nil
+	})))
+	checkers.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	checkers.Register("panic", func(ctx context.Context) error {
+		panic("boom")
+	})
+	checkers.Register("downstream", ClientChecker(clientFunc(func(ctx context.Context, req interface{}) error {
+		return errors.Errorf("%s unavailable", req)
+	}), "user-service"))
+
+	results := checkers.Check(context.Background())
+
+	require.Len(t, results, 4)
+	require.NoError(t, results["db"])
+	require.True(t, errors.Is(results["slow"], context.DeadlineExceeded))
+	require.EqualError(t, results["panic"], "panic: boom")
+	require.EqualError(t, results["downstream"], "user-service unavailable")
+
+	t.Run("replaced by name", func(t *testing.T) {
+		checkers.Register("slow", func(ctx context.Context) error {
+			return nil
+		})
+		results := checkers.Check(context.Background())
+		require.Len(t, results, 4)
+		require.NoError(t, results["slow"])
+	})
+}
@@ -0,0 +1,8 @@
+//go:build !go1.18
+// +build !go1.18
+
+package operators
+
+func vcsStamp() (revision string, time string) {
+	return "", ""
+}
@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package operators
+
+import (
+	"runtime/debug"
+)
+
+// vcsStamp returns revision and time of vcs stamped by go build
+func vcsStamp() (revision string, time string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			time = setting.Value
+		}
+	}
+	return
+}
@@ -0,0 +1,54 @@
+package operators
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+)
+
+// build metadata set by ldflags, like
+//
+//	-X github.com/go-courier/httptransport/operators.Commit=$(git rev-parse HEAD)
+//
+// revision and time of vcs stamped by go build used when empty.
+var (
+	Commit  = ""
+	BuiltAt = ""
+)
+
+type BuildInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuiltAt   string `json:"builtAt,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// ServiceVersion exposes build metadata, name and version of ServiceMeta
+type ServiceVersion struct {
+	httpx.MethodGet `path:"/version" openapi:"x-operational"`
+}
+
+func (ServiceVersion) PublicAccess() bool {
+	return true
+}
+
+func (ServiceVersion) Output(ctx context.Context) (interface{}, error) {
+	serviceMeta := httptransport.ServerMetaFromContext(ctx)
+
+	info := &BuildInfo{
+		Name:      serviceMeta.Name,
+		Version:   serviceMeta.Version,
+		Commit:    Commit,
+		BuiltAt:   BuiltAt,
+		GoVersion: runtime.Version(),
+	}
+
+	if info.Commit == "" {
+		info.Commit, info.BuiltAt = vcsStamp()
+	}
+
+	return info, nil
+}
@@ -0,0 +1,186 @@
+package httptransport
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-courier/httptransport/transformers"
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/statuserror"
+	verrors "github.com/go-courier/validator/errors"
+	"github.com/pkg/errors"
+)
+
+// PathParamConverter pre-validates raw value of path parameter at routing layer,
+// before any operator of route runs.
+//
+//	type GetItem struct {
+//		httpx.MethodGet `path:"/items/:id"`
+//		ID string `name:"id" in:"path" format:"uuid"`
+//	}
+//
+// Path parameters of int or uint kinds use converter named by kind without tag `format`.
+type PathParamConverter interface {
+	// Format of parameter in openapi
+	Format() string
+	// Check returns ErrPathParamNotMatched when raw value could not be the type of parameter,
+	// which responds 404, other errors respond 400.
+	Check(raw string) error
+}
+
+var ErrPathParamNotMatched = errors.New("path param not matched")
+
+var pathParamConverters = map[string]PathParamConverter{
+	"int":    &intPathParamConverter{bitSize: 64},
+	"int8":   &intPathParamConverter{bitSize: 8},
+	"int16":  &intPathParamConverter{bitSize: 16},
+	"int32":  &intPathParamConverter{bitSize: 32},
+	"int64":  &intPathParamConverter{bitSize: 64},
+	"uint":   &intPathParamConverter{bitSize: 64, unsigned: true},
+	"uint8":  &intPathParamConverter{bitSize: 8, unsigned: true},
+	"uint16": &intPathParamConverter{bitSize: 16, unsigned: true},
+	"uint32": &intPathParamConverter{bitSize: 32, unsigned: true},
+	"uint64": &intPathParamConverter{bitSize: 64, unsigned: true},
+	"uuid":   &uuidPathParamConverter{},
+	"date":   &datePathParamConverter{},
+}
+
+// RegisterPathParamConverter should be called in init, before route handlers created
+func RegisterPathParamConverter(name string, converter PathParamConverter) {
+	pathParamConverters[name] = converter
+}
+
+func PathParamConverterByName(name string) (PathParamConverter, bool) {
+	converter, ok := pathParamConverters[name]
+	return converter, ok
+}
+
+// pathParamConverterOf resolves converter by tag `format` or int kinds of field type
+func pathParamConverterOf(field typesutil.StructField) (PathParamConverter, error) {
+	name := field.Tag().Get("format")
+
+	if name == "" {
+		switch kind := typesutil.Deref(field.Type()).Kind(); kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			name = kind.String()
+		default:
+			return nil, nil
+		}
+	}
+
+	converter, ok := PathParamConverterByName(name)
+	if !ok {
+		return nil, errors.Errorf("unknown path param format %s", name)
+	}
+	return converter, nil
+}
+
+type pathParamCheck struct {
+	name      string
+	converter PathParamConverter
+}
+
+func pathParamChecksOf(requestTransformers []*RequestTransformer) []pathParamCheck {
+	checks := make([]pathParamCheck, 0)
+	added := map[string]bool{}
+
+	for _, rt := range requestTransformers {
+		for _, param := range rt.Parameters {
+			if param.PathParamConverter == nil || added[param.Name] {
+				continue
+			}
+			added[param.Name] = true
+			checks = append(checks, pathParamCheck{name: param.Name, converter: param.PathParamConverter})
+		}
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].name < checks[j].name
+	})
+
+	return checks
+}
+
+func checkPathParams(checks []pathParamCheck, info *RequestInfo, errorLocation transformers.ErrorLocation) error {
+	badRequest := &BadRequest{errorLocation: errorLocation}
+
+	for _, check := range checks {
+		if err := check.converter.Check(info.Param(check.name)); err != nil {
+			if errors.Cause(err) == ErrPathParamNotMatched {
+				return statuserror.Wrap(err, http.StatusNotFound, "NotFound").AppendErrorField("path", errorLocation(verrors.KeyPath{check.name}), err.Error())
+			}
+			badRequest.AddErr(err, "path", check.name)
+		}
+	}
+
+	return badRequest.Err()
+}
+
+type intPathParamConverter struct {
+	bitSize  int
+	unsigned bool
+}
+
+func (c *intPathParamConverter) Format() string {
+	if c.unsigned {
+		return "uint" + strconv.Itoa(c.bitSize)
+	}
+	return "int" + strconv.Itoa(c.bitSize)
+}
+
+var reInt = regexp.MustCompile(`^[-+]?[0-9]+$`)
+
+func (c *intPathParamConverter) Check(raw string) error {
+	if !reInt.MatchString(raw) || (c.unsigned && raw[0] == '-') {
+		return ErrPathParamNotMatched
+	}
+
+	var err error
+	if c.unsigned {
+		_, err = strconv.ParseUint(raw, 10, c.bitSize)
+	} else {
+		_, err = strconv.ParseInt(raw, 10, c.bitSize)
+	}
+	if err != nil {
+		return errors.Errorf("%s out of range of %s", raw, c.Format())
+	}
+	return nil
+}
+
+type uuidPathParamConverter struct{}
+
+func (uuidPathParamConverter) Format() string {
+	return "uuid"
+}
+
+var reUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (uuidPathParamConverter) Check(raw string) error {
+	if !reUUID.MatchString(raw) {
+		return ErrPathParamNotMatched
+	}
+	return nil
+}
+
+type datePathParamConverter struct{}
+
+func (datePathParamConverter) Format() string {
+	return "date"
+}
+
+var reDate = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+
+func (datePathParamConverter) Check(raw string) error {
+	if !reDate.MatchString(raw) {
+		return ErrPathParamNotMatched
+	}
+	if _, err := time.Parse("2006-01-02", raw); err != nil {
+		return errors.Errorf("invalid date %s", raw)
+	}
+	return nil
+}
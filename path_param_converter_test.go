@@ -0,0 +1,85 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+type GetByTypedPathParams struct {
+	httpx.MethodGet `path:"/:id/:uuid/:date"`
+	ID              uint8  `name:"id" in:"path"`
+	UUID            string `name:"uuid" in:"path" format:"uuid"`
+	Date            string `name:"date" in:"path" format:"date"`
+}
+
+func (GetByTypedPathParams) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+type GetByUnknownFormat struct {
+	httpx.MethodGet `path:"/:id"`
+	ID              string `name:"id" in:"path" format:"unknown"`
+}
+
+func (GetByUnknownFormat) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestPathParamConverter(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/root"))
+	rootRouter.Register(courier.NewRouter(GetByTypedPathParams{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	handler := httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr)
+
+	serve := func(id string, uuid string, date string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httptransport.ParamsFromMap(map[string]string{
+			"id":   id,
+			"uuid": uuid,
+			"date": date,
+		})))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	uuid := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	t.Run("matched", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, serve("1", uuid, "2021-01-01").Code)
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, serve("a", uuid, "2021-01-01").Code)
+		require.Equal(t, http.StatusNotFound, serve("-1", uuid, "2021-01-01").Code)
+		require.Equal(t, http.StatusNotFound, serve("1", "x", "2021-01-01").Code)
+		require.Equal(t, http.StatusNotFound, serve("1", uuid, "20210101").Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rw := serve("256", uuid, "2021-02-30")
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), `"field":"date"`)
+		require.Contains(t, rw.Body.String(), `"field":"id"`)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := rtMgr.NewRequestTransformer(context.Background(), httptransport.NewOperatorFactoryWithRouteMeta(GetByUnknownFormat{}, true).Type)
+		require.Error(t, err)
+	})
+
+	t.Run("format", func(t *testing.T) {
+		converter, ok := httptransport.PathParamConverterByName("uuid")
+		require.True(t, ok)
+		require.Equal(t, "uuid", converter.Format())
+	})
+}
@@ -1,6 +1,7 @@
 package httptransport
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -63,6 +64,68 @@ func (pattern *PathnamePattern) Stringify(params httprouter.Params) string {
 	return (&PathnamePattern{parts: parts}).String()
 }
 
+// Keys of path params in order of parts
+func (pattern *PathnamePattern) Keys() []string {
+	keys := make([]string, 0, len(pattern.idxKeys))
+	for i := range pattern.parts {
+		if key, ok := pattern.idxKeys[i]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// PathParamsUnmatchedError returned when path params of pattern not matched with path params declared
+type PathParamsUnmatchedError struct {
+	Pattern string
+	// Missing path params in pattern but not declared
+	Missing []string
+	// Extra path params declared but not in pattern
+	Extra []string
+}
+
+func (e *PathParamsUnmatchedError) Error() string {
+	msg := "path params of " + e.Pattern + " unmatched"
+	if len(e.Missing) > 0 {
+		msg += ", missing [" + strings.Join(e.Missing, ", ") + "]"
+	}
+	if len(e.Extra) > 0 {
+		msg += ", extra [" + strings.Join(e.Extra, ", ") + "]"
+	}
+	return msg
+}
+
+// MatchParams checks names of path params declared, like name of fields tagged `in:"path"`,
+// *PathParamsUnmatchedError returned when missing or extra
+func (pattern *PathnamePattern) MatchParams(names ...string) error {
+	declared := map[string]bool{}
+	for _, name := range names {
+		declared[name] = true
+	}
+
+	e := &PathParamsUnmatchedError{Pattern: pattern.String()}
+
+	keys := map[string]bool{}
+	for _, key := range pattern.Keys() {
+		keys[key] = true
+		if !declared[key] {
+			e.Missing = append(e.Missing, key)
+		}
+	}
+
+	for name := range declared {
+		if !keys[name] {
+			e.Extra = append(e.Extra, name)
+		}
+	}
+	sort.Strings(e.Extra)
+
+	if len(e.Missing) > 0 || len(e.Extra) > 0 {
+		return e
+	}
+	return nil
+}
+
 func (pattern *PathnamePattern) Parse(pathname string) (params httprouter.Params, err error) {
 	parts := toPathParts(pathname)
 
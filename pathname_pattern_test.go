@@ -63,3 +63,20 @@ func TestPathnamePatternWithoutParams(t *testing.T) {
 		tt.Equal("/auth/user", pathname)
 	}
 }
+
+func TestPathnamePattern_MatchParams(t *testing.T) {
+	p := NewPathnamePattern("/users/:userID/repos/:repoID")
+
+	require.Equal(t, []string{"userID", "repoID"}, p.Keys())
+	require.NoError(t, p.MatchParams("repoID", "userID"))
+
+	err := p.MatchParams("userID", "name", "id")
+	require.Equal(t, &PathParamsUnmatchedError{
+		Pattern: "/users/:userID/repos/:repoID",
+		Missing: []string{"repoID"},
+		Extra:   []string{"id", "name"},
+	}, err)
+	require.Equal(t, "path params of /users/:userID/repos/:repoID unmatched, missing [repoID], extra [id, name]", err.Error())
+
+	require.NoError(t, NewPathnamePattern("/auth/user").MatchParams())
+}
@@ -0,0 +1,166 @@
+package httptransport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// ErrRequestHeaderTooLarge will be wrapped as status error RequestHeaderTooLarge,
+// when headers of request beyond MaxHeaderBytes of http.Server
+var ErrRequestHeaderTooLarge = errors.New("request header too large")
+
+// ErrRequestURITooLong will be wrapped as status error RequestURITooLong,
+// when request line not ended in MaxHeaderBytes of http.Server
+var ErrRequestURITooLong = errors.New("request uri too long")
+
+// ErrMalformedRequest will be wrapped as status error MalformedRequest,
+// when request could not be parsed by net/http
+var ErrMalformedRequest = errors.New("malformed request")
+
+// ProtocolErrorWriter writes response of request rejected by net/http before handling,
+// connection will be closed after written
+type ProtocolErrorWriter func(rw http.ResponseWriter, statusErr *statuserror.StatusErr)
+
+// ProtocolErrorListener hooks raw text responses written by net/http for requests failed to read,
+// like oversized headers or uri and malformed requests, and writes them by writeErr instead.
+// when served under tls, responses are encrypted except the one of plain http request sent to tls listener,
+// which is the only one could be hooked.
+//
+//	srv.Serve(httptransport.ProtocolErrorListener(ln, writeErr))
+func ProtocolErrorListener(ln net.Listener, writeErr ProtocolErrorWriter) net.Listener {
+	return &protocolErrorListener{Listener: ln, writeErr: writeErr}
+}
+
+type protocolErrorListener struct {
+	net.Listener
+	writeErr ProtocolErrorWriter
+}
+
+func (l *protocolErrorListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &protocolErrorConn{Conn: conn, writeErr: l.writeErr}, nil
+}
+
+// raw response of net/http, see errorHeaders in net/http/server.go
+var reRawErrorResponse = regexp.MustCompile(`^HTTP/1\.1 (\d{3}) [^\r\n]*\r\nContent-Type: text/plain; charset=utf-8\r\nConnection: close\r\n\r\n`)
+
+// raw response of net/http written into conn under tls, when plain http request sent to tls listener
+var rawPlainHTTPToTLSResponse = []byte("HTTP/1.0 400 Bad Request\r\n\r\nClient sent an HTTP request to an HTTPS server.\n")
+
+type protocolErrorConn struct {
+	net.Conn
+	writeErr ProtocolErrorWriter
+	// whether line break read since last response written,
+	// 431 without it means the request line too long
+	lineBroken int32
+}
+
+func (c *protocolErrorConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 && bytes.IndexByte(p[:n], '\n') >= 0 {
+		atomic.StoreInt32(&c.lineBroken, 1)
+	}
+	return n, err
+}
+
+func (c *protocolErrorConn) Write(p []byte) (int, error) {
+	if bytes.Equal(p, rawPlainHTTPToTLSResponse) {
+		if _, err := c.Conn.Write(c.rewrite(http.StatusBadRequest, "Client sent an HTTP request to an HTTPS server.")); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if loc := reRawErrorResponse.FindSubmatchIndex(p); loc != nil {
+		code, _ := strconv.Atoi(string(p[loc[2]:loc[3]]))
+
+		if _, err := c.Conn.Write(c.rewrite(code, string(p[loc[1]:]))); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	atomic.StoreInt32(&c.lineBroken, 0)
+	return c.Conn.Write(p)
+}
+
+func (c *protocolErrorConn) rewrite(code int, text string) []byte {
+	// like "400 Bad Request: missing required Host header"
+	msg := strings.TrimPrefix(strings.TrimPrefix(text, strconv.Itoa(code)+" "+http.StatusText(code)), ": ")
+	if msg == "" {
+		msg = http.StatusText(code)
+	}
+
+	var statusErr *statuserror.StatusErr
+
+	switch code {
+	case http.StatusRequestHeaderFieldsTooLarge:
+		if atomic.LoadInt32(&c.lineBroken) == 0 {
+			statusErr = statuserror.Wrap(ErrRequestURITooLong, http.StatusRequestURITooLong, "RequestURITooLong")
+		} else {
+			statusErr = statuserror.Wrap(ErrRequestHeaderTooLarge, http.StatusRequestHeaderFieldsTooLarge, "RequestHeaderTooLarge")
+		}
+	case http.StatusBadRequest:
+		statusErr = statuserror.Wrap(errors.Wrap(ErrMalformedRequest, msg), http.StatusBadRequest, "MalformedRequest", msg)
+	default:
+		statusErr = statuserror.Wrap(errors.Wrap(ErrMalformedRequest, msg), code, strings.ReplaceAll(http.StatusText(code), " ", ""), msg)
+	}
+
+	rw := &rawResponseWriter{header: http.Header{}}
+	c.writeErr(rw, statusErr)
+
+	return rw.Bytes()
+}
+
+// rawResponseWriter serializes response as HTTP/1.1 with connection closed
+type rawResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *rawResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *rawResponseWriter) WriteHeader(statusCode int) {
+	if rw.statusCode == 0 {
+		rw.statusCode = statusCode
+	}
+}
+
+func (rw *rawResponseWriter) Write(data []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	return rw.body.Write(data)
+}
+
+func (rw *rawResponseWriter) Bytes() []byte {
+	rw.WriteHeader(http.StatusOK)
+
+	resp := &http.Response{
+		StatusCode:    rw.statusCode,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rw.header,
+		ContentLength: int64(rw.body.Len()),
+		Body:          ioutil.NopCloser(&rw.body),
+		Close:         true,
+	}
+
+	b := bytes.NewBuffer(nil)
+	_ = resp.Write(b)
+
+	return b.Bytes()
+}
@@ -0,0 +1,113 @@
+package httptransport
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolErrorListener(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	srv.Config.MaxHeaderBytes = 1024
+	srv.Listener = ProtocolErrorListener(srv.Listener, func(rw http.ResponseWriter, statusErr *statuserror.StatusErr) {
+		httpx.WriteStatusErr(rw, statusErr.AppendSource("test"))
+	})
+	srv.Start()
+	defer srv.Close()
+
+	do := func(t *testing.T, raw string) *http.Response {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+
+		_, _ = conn.Write([]byte(raw))
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		return resp
+	}
+
+	statusErrOf := func(t *testing.T, resp *http.Response) *statuserror.StatusErr {
+		require.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.True(t, resp.Close)
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(statusErr))
+		require.Equal(t, []string{"test"}, statusErr.Sources)
+		return statusErr
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		resp := do(t, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("header too large", func(t *testing.T) {
+		resp := do(t, "GET / HTTP/1.1\r\nHost: localhost\r\n"+strings.Repeat("X-Large: "+strings.Repeat("x", 100)+"\r\n", 100)+"\r\n")
+		require.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+		require.Equal(t, "RequestHeaderTooLarge", statusErrOf(t, resp).Key)
+	})
+
+	t.Run("uri too long", func(t *testing.T) {
+		resp := do(t, "GET /"+strings.Repeat("x", 10240)+" HTTP/1.1\r\nHost: localhost\r\n\r\n")
+		require.Equal(t, http.StatusRequestURITooLong, resp.StatusCode)
+		require.Equal(t, "RequestURITooLong", statusErrOf(t, resp).Key)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		resp := do(t, "GET / HTTP/1.1\r\n\r\n")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		statusErr := statusErrOf(t, resp)
+		require.Equal(t, "MalformedRequest", statusErr.Key)
+		require.Equal(t, "missing required Host header", statusErr.Msg)
+	})
+}
+
+func TestProtocolErrorListenerWithTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	srv.Listener = ProtocolErrorListener(srv.Listener, func(rw http.ResponseWriter, statusErr *statuserror.StatusErr) {
+		httpx.WriteStatusErr(rw, statusErr)
+	})
+	srv.StartTLS()
+	defer srv.Close()
+
+	t.Run("ok", func(t *testing.T) {
+		resp, err := srv.Client().Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("plain http request", func(t *testing.T) {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(statusErr))
+		require.Equal(t, "MalformedRequest", statusErr.Key)
+		require.Equal(t, "Client sent an HTTP request to an HTTPS server.", statusErr.Msg)
+	})
+}
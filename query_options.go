@@ -0,0 +1,64 @@
+package httptransport
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EmptySliceEncoding controls how empty slices of query (without omitempty) encoded in requests out,
+// servers built on different stacks disagree on it.
+type EmptySliceEncoding int
+
+const (
+	// EmptySliceAsAbsent skips the key, default
+	EmptySliceAsAbsent EmptySliceEncoding = iota
+	// EmptySliceAsEmptyValue encodes as `key=`
+	EmptySliceAsEmptyValue
+	// EmptySliceAsBrackets encodes as `key[]`
+	EmptySliceAsBrackets
+)
+
+// EmptyValueDecoding controls how empty string of query decoded
+type EmptyValueDecoding int
+
+const (
+	// EmptyValueByOmitempty decodes empty string as zero value when omitempty, otherwise by transformer, default
+	EmptyValueByOmitempty EmptyValueDecoding = iota
+	// EmptyValueAsZero decodes empty string as zero value always,
+	// and single empty string of slice (`key=`) as empty slice
+	EmptyValueAsZero
+	// EmptyValueAsInvalid reports empty string as invalid parameter always
+	EmptyValueAsInvalid
+)
+
+var ErrEmptyQueryValue = errors.New("empty value")
+
+// QueryOptions of empty values, keys of empty slices like `key[]` always decoded as empty slices
+type QueryOptions struct {
+	EmptySlice EmptySliceEncoding
+	EmptyValue EmptyValueDecoding
+}
+
+// encode query with empty slices, url.Values always escapes brackets and appends `=`
+func (opts QueryOptions) encode(query url.Values, emptySlices []string) string {
+	rawQuery := query.Encode()
+
+	if opts.EmptySlice != EmptySliceAsBrackets {
+		return rawQuery
+	}
+
+	b := strings.Builder{}
+	b.WriteString(rawQuery)
+
+	for _, name := range emptySlices {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(name))
+		b.WriteString("[]")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+func TestQueryOptions(t *testing.T) {
+	type Req struct {
+		Int     int      `name:"int,omitempty" in:"query"`
+		Slice   []string `name:"slice" in:"query"`
+		Omitted []string `name:"omitted,omitempty" in:"query"`
+	}
+
+	newRequestTransformer := func(opts httptransport.QueryOptions) *httptransport.RequestTransformer {
+		mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+		mgr.QueryOptions = opts
+		rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(Req{}))
+		require.NoError(t, err)
+		return rt
+	}
+
+	t.Run("encode empty slices", func(t *testing.T) {
+		cases := map[httptransport.EmptySliceEncoding]string{
+			httptransport.EmptySliceAsAbsent:     "int=1",
+			httptransport.EmptySliceAsEmptyValue: "int=1&slice=",
+			httptransport.EmptySliceAsBrackets:   "int=1&slice[]",
+		}
+
+		for encoding, rawQuery := range cases {
+			rt := newRequestTransformer(httptransport.QueryOptions{EmptySlice: encoding})
+
+			req, err := rt.NewRequest(http.MethodGet, "/", &Req{Int: 1})
+			require.NoError(t, err)
+			require.Equal(t, rawQuery, req.URL.RawQuery)
+		}
+	})
+
+	decode := func(opts httptransport.QueryOptions, rawQuery string) (*Req, error) {
+		rt := newRequestTransformer(opts)
+		r := &Req{}
+		err := rt.DecodeFrom(httptransport.NewRequestInfo(httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)), &courier.OperatorFactory{}, r)
+		return r, err
+	}
+
+	t.Run("decode brackets as empty slice", func(t *testing.T) {
+		r, err := decode(httptransport.QueryOptions{}, "slice=a&omitted[]")
+		require.NoError(t, err)
+		require.Equal(t, []string{}, r.Omitted)
+	})
+
+	t.Run("decode empty value by omitempty", func(t *testing.T) {
+		r, err := decode(httptransport.QueryOptions{}, "int=&slice=a")
+		require.NoError(t, err)
+		require.Equal(t, 0, r.Int)
+
+		// kept as element of slice
+		_, err = decode(httptransport.QueryOptions{}, "slice=a&omitted=")
+		require.Error(t, err)
+	})
+
+	t.Run("decode empty value as zero", func(t *testing.T) {
+		opts := httptransport.QueryOptions{EmptyValue: httptransport.EmptyValueAsZero}
+
+		r, err := decode(opts, "int=&slice=a&omitted=")
+		require.NoError(t, err)
+		require.Equal(t, 0, r.Int)
+		require.Equal(t, []string{}, r.Omitted)
+	})
+
+	t.Run("decode empty value as invalid", func(t *testing.T) {
+		_, err := decode(httptransport.QueryOptions{EmptyValue: httptransport.EmptyValueAsInvalid}, "int=&slice=a&omitted=")
+
+		statusErr := statuserror.FromErr(err)
+		require.Equal(t, http.StatusBadRequest, statusErr.StatusCode())
+
+		fields := map[string]string{}
+		for _, f := range statusErr.ErrorFields {
+			fields[f.Field] = f.Msg
+		}
+		require.Equal(t, map[string]string{
+			"int":        httptransport.ErrEmptyQueryValue.Error(),
+			"omitted[0]": httptransport.ErrEmptyQueryValue.Error(),
+		}, fields)
+	})
+}
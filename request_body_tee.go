@@ -0,0 +1,94 @@
+package httptransport
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+)
+
+// RequestBodyTee for debugging,
+// raw request body will be teed into desc of the error when decoding or validation failed
+type RequestBodyTee struct {
+	// max bytes of body to tee, default 4096
+	Limit int
+	// keys of json or form which values will be redacted, default password, secret, token
+	RedactKeys []string
+}
+
+func (t *RequestBodyTee) SetDefaults() {
+	if t.Limit == 0 {
+		t.Limit = 4096
+	}
+	if t.RedactKeys == nil {
+		t.RedactKeys = []string{"password", "secret", "token"}
+	}
+}
+
+type contextKeyRequestBodyTee int
+
+func ContextWithRequestBodyTee(ctx context.Context, tee *RequestBodyTee) context.Context {
+	return context.WithValue(ctx, contextKeyRequestBodyTee(1), tee)
+}
+
+func RequestBodyTeeFromContext(ctx context.Context) *RequestBodyTee {
+	if tee, ok := ctx.Value(contextKeyRequestBodyTee(1)).(*RequestBodyTee); ok {
+		return tee
+	}
+	return nil
+}
+
+func (t *RequestBodyTee) Tee(body io.ReadCloser) *teeReadCloser {
+	return &teeReadCloser{ReadCloser: body, limit: t.Limit}
+}
+
+// Attach appends teed body into desc of status error
+func (t *RequestBodyTee) Attach(err error, body *teeReadCloser) error {
+	statusErr, ok := statuserror.IsStatusErr(err)
+	if !ok || body == nil {
+		return err
+	}
+
+	raw := t.Redact(body.buf.String())
+	if body.truncated {
+		raw += "...(truncated)"
+	}
+
+	desc := statusErr.Desc
+	if desc != "" {
+		desc += "\n"
+	}
+
+	return statusErr.WithDesc(desc + "request body: " + raw)
+}
+
+// Redact values of redact keys in json or form
+func (t *RequestBodyTee) Redact(raw string) string {
+	return httpx.Redact(raw, t.RedactKeys...)
+}
+
+type teeReadCloser struct {
+	io.ReadCloser
+	limit     int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (r *teeReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if rest := r.limit - r.buf.Len(); rest > 0 {
+			if n > rest {
+				r.buf.Write(p[:rest])
+				r.truncated = true
+			} else {
+				r.buf.Write(p[:n])
+			}
+		} else {
+			r.truncated = true
+		}
+	}
+	return n, err
+}
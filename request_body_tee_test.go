@@ -0,0 +1,65 @@
+package httptransport_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/__examples__/server/cmd/app/routes"
+	"github.com/go-courier/httptransport/testify"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+func TestRequestBodyTee(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/root"))
+	rootRouter.Register(courier.NewRouter(routes.Create{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	httpRouterHandler := httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr)
+
+	tee := &httptransport.RequestBodyTee{Limit: 32}
+	tee.SetDefaults()
+
+	serve := func(ctx context.Context, body string) *statuserror.StatusErr {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		rw := testify.NewMockResponseWriter()
+		httpRouterHandler.ServeHTTP(rw, req)
+		require.Equal(t, http.StatusBadRequest, rw.StatusCode)
+
+		statusErr := &statuserror.StatusErr{}
+		require.NoError(t, json.Unmarshal(rw.Bytes(), statusErr))
+		return statusErr
+	}
+
+	t.Run("teed into desc", func(t *testing.T) {
+		statusErr := serve(httptransport.ContextWithRequestBodyTee(context.Background(), tee), `{"id":"123","password":"x"}`)
+		require.Equal(t, `request body: {"id":"123","password":"***"}`, statusErr.Desc)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		statusErr := serve(httptransport.ContextWithRequestBodyTee(context.Background(), tee), `{"id":"1234567890123456789012345678901234567890"}`)
+		require.Equal(t, `request body: {"id":"1234567890123456789012345...(truncated)`, statusErr.Desc)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		statusErr := serve(context.Background(), `{"id":"123"}`)
+		require.Equal(t, "", statusErr.Desc)
+	})
+}
+
+func TestRequestBodyTeeRedact(t *testing.T) {
+	tee := &httptransport.RequestBodyTee{}
+	tee.SetDefaults()
+
+	require.Equal(t, `{"user":"a","Password":"***","accessToken":"***","n":1}`, tee.Redact(`{"user":"a","Password":"p\"w","accessToken":123,"n":1}`))
+	require.Equal(t, `user=a&password=***&client_secret=***`, tee.Redact(`user=a&password=pw&client_secret=s`))
+}
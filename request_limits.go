@@ -0,0 +1,147 @@
+package httptransport
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// ErrRequestBodyTooLarge will be wrapped as status error RequestBodyTooLarge,
+// when body of request beyond MaxBodyBytes of route or TransportProfile
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// ErrRequestBodyTooDeep will be wrapped as status error RequestBodyTooDeep,
+// when nesting of json body beyond MaxJSONDepth of TransportProfile
+var ErrRequestBodyTooDeep = errors.New("request body too deep")
+
+// ErrTooManyConcurrentRequests will be wrapped as status error TooManyConcurrentRequests,
+// when requests in handling reached MaxConcurrentRequests of HttpTransport
+var ErrTooManyConcurrentRequests = errors.New("too many concurrent requests")
+
+// concurrencyGuard rejects requests beyond limit in handling with 503 and Retry-After, instead of queueing
+type concurrencyGuard struct {
+	sem        chan struct{}
+	retryAfter int
+}
+
+func newConcurrencyGuard(limit int) *concurrencyGuard {
+	return &concurrencyGuard{sem: make(chan struct{}, limit), retryAfter: 1}
+}
+
+func (g *concurrencyGuard) acquire(rw http.ResponseWriter) (func(), bool) {
+	select {
+	case g.sem <- struct{}{}:
+		return func() {
+			<-g.sem
+		}, true
+	default:
+		rw.Header().Set(httpx.HeaderRetryAfter, strconv.Itoa(g.retryAfter))
+		httpx.WriteStatusErr(rw, statuserror.Wrap(errors.Wrapf(ErrTooManyConcurrentRequests, "limit %d", cap(g.sem)), http.StatusServiceUnavailable, "TooManyConcurrentRequests"))
+		return nil, false
+	}
+}
+
+// bodyLimits of route, MaxBodyBytes declared by operators wins
+func (handler *HttpRouteHandler) bodyLimits() (maxBodyBytes int64, maxJSONDepth int) {
+	if handler.profile != nil {
+		maxBodyBytes, maxJSONDepth = handler.profile.MaxBodyBytes, handler.profile.MaxJSONDepth
+	}
+	if handler.policies.MaxBodyBytes > 0 {
+		maxBodyBytes = handler.policies.MaxBodyBytes
+	}
+	return
+}
+
+// guardBody rejects request by Content-Length first,
+// or returns body which fails reading once beyond limits, error of guard should win when decoding failed.
+func guardBody(r *http.Request, maxBodyBytes int64, maxJSONDepth int) (*guardedBody, error) {
+	if maxBodyBytes > 0 && r.ContentLength > maxBodyBytes {
+		return nil, errRequestBodyTooLarge(maxBodyBytes)
+	}
+
+	body := &guardedBody{ReadCloser: r.Body, maxBytes: maxBodyBytes}
+
+	if maxJSONDepth > 0 {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get(httpx.HeaderContentType)); mediaType == httpx.MIME_JSON || strings.HasSuffix(mediaType, "+json") {
+			body.maxDepth = maxJSONDepth
+		}
+	}
+
+	return body, nil
+}
+
+func errRequestBodyTooLarge(maxBytes int64) error {
+	return statuserror.Wrap(errors.Wrapf(ErrRequestBodyTooLarge, "limit %d bytes", maxBytes), http.StatusRequestEntityTooLarge, "RequestBodyTooLarge")
+}
+
+type guardedBody struct {
+	io.ReadCloser
+	maxBytes int64
+	maxDepth int
+
+	read int64
+	err  error
+
+	// states of scanning json
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+func (b *guardedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	n, err := b.ReadCloser.Read(p)
+
+	b.read += int64(n)
+	if b.maxBytes > 0 && b.read > b.maxBytes {
+		b.err = errRequestBodyTooLarge(b.maxBytes)
+		return 0, b.err
+	}
+
+	if b.maxDepth > 0 {
+		b.scan(p[:n])
+		if b.err != nil {
+			return 0, b.err
+		}
+	}
+
+	return n, err
+}
+
+func (b *guardedBody) scan(data []byte) {
+	for _, c := range data {
+		if b.inString {
+			switch {
+			case b.escaped:
+				b.escaped = false
+			case c == '\\':
+				b.escaped = true
+			case c == '"':
+				b.inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			b.inString = true
+		case '{', '[':
+			b.depth++
+			if b.depth > b.maxDepth {
+				b.err = statuserror.Wrap(errors.Wrapf(ErrRequestBodyTooDeep, "limit %d", b.maxDepth), http.StatusBadRequest, "RequestBodyTooDeep")
+				return
+			}
+		case '}', ']':
+			b.depth--
+		}
+	}
+}
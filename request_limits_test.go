@@ -0,0 +1,85 @@
+package httptransport
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+type UploadAvatar struct {
+	httpx.MethodPut
+	Data []byte `in:"body"`
+}
+
+func (UploadAvatar) MaxBodyBytes() int64 {
+	return 8
+}
+
+func (UploadAvatar) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+var blocking = make(chan struct{})
+
+type BlockingPing struct {
+	httpx.MethodGet
+}
+
+func (BlockingPing) Output(ctx context.Context) (interface{}, error) {
+	blocking <- struct{}{}
+	<-blocking
+	return nil, nil
+}
+
+func TestRequestLimits(t *testing.T) {
+	t.Run("max body bytes of route", func(t *testing.T) {
+		route := NewHttpRouteMeta(courier.NewRouter(UploadAvatar{}).Routes()[0])
+		require.Equal(t, int64(8), route.Policies().MaxBodyBytes)
+
+		handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, route, NewRequestTransformerMgr(nil, nil))
+		// route wins
+		handler.profile = &TransportProfile{MaxBodyBytes: 1024}
+
+		serve := func(body string) int {
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(body))
+			req.ContentLength = -1
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			return rw.Code
+		}
+
+		require.Equal(t, http.StatusNoContent, serve("12345678"))
+		require.Equal(t, http.StatusRequestEntityTooLarge, serve(strings.Repeat("x", 9)))
+	})
+
+	t.Run("max concurrent requests", func(t *testing.T) {
+		tr := &HttpTransport{MaxConcurrentRequests: 1}
+		tr.SetDefaults()
+		tr.httpRouter = tr.convertRouterToHttpRouter(courier.NewRouter(BlockingPing{}))
+
+		done := make(chan int)
+		go func() {
+			rw := httptest.NewRecorder()
+			tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+			done <- rw.Code
+		}()
+
+		<-blocking
+
+		rw := httptest.NewRecorder()
+		tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+		require.Equal(t, "1", rw.Header().Get(httpx.HeaderRetryAfter))
+		require.Contains(t, rw.Body.String(), "TooManyConcurrentRequests")
+
+		blocking <- struct{}{}
+		require.Equal(t, http.StatusNoContent, <-done)
+	})
+}
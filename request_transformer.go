@@ -96,13 +96,30 @@ func isRequestOut(ctx context.Context) bool {
 func (mgr *RequestTransformerMgr) newRequestTransformer(ctx context.Context, typ reflect.Type) (*RequestTransformer, error) {
 	errSet := verrors.NewErrorSet("")
 
-	rt := &RequestTransformer{}
+	rt := &RequestTransformer{transformerMgr: mgr.TransformerMgr, queryOptions: mgr.QueryOptions, strictPathParams: mgr.StrictPathParams}
 	rt.Type = reflectx.Deref(typ)
 	rt.Parameters = map[string]*RequestParameter{}
 
-	typesutil.EachField(typesutil.FromRType(rt.Type), "name", func(field typesutil.StructField, fieldDisplayName string, omitempty bool) bool {
+	mgr.rangeRequestParameters(ctx, rt.Type, func(field typesutil.StructField, parameter *RequestParameter, err error) {
+		if err != nil {
+			errSet.AddErr(err, field.Name())
+			return
+		}
+		rt.Parameters[field.Name()] = parameter
+	})
+
+	return rt, errSet.Err()
+}
+
+// rangeRequestParameters creates parameter with transformer and validator for each field with tag `in`
+func (mgr *RequestTransformerMgr) rangeRequestParameters(ctx context.Context, typ reflect.Type, each func(field typesutil.StructField, parameter *RequestParameter, err error)) {
+	typesutil.EachField(typesutil.FromRType(typ), "name", func(field typesutil.StructField, fieldDisplayName string, omitempty bool) bool {
 		tag := field.Tag()
-		fieldName := field.Name()
+
+		if _, ok := tag.Lookup(TagInject); ok {
+			// populated by Injector
+			return true
+		}
 
 		in, exists := tag.Lookup("in")
 		if !exists {
@@ -115,10 +132,18 @@ func (mgr *RequestTransformerMgr) newRequestTransformer(ctx context.Context, typ
 
 		parameter := NewRequestParameter(fieldDisplayName, in)
 		parameter.Omitempty = omitempty
+		if in != "body" {
+			parameter.Default = tag.Get("default")
+		}
 
 		transformOpt := transformers.TransformerOptionFromStructField(field)
+		parameter.Style = transformOpt.Style
 
 		getTransformer := func() (transformers.Transformer, error) {
+			if parameter.Style != transformers.ParamStyleForm && in != "query" {
+				return nil, errors.Errorf("style `%s` only supported for parameter in query, but got in %s", parameter.Style, in)
+			}
+
 			targetType := field.Type()
 			if !(in == "body" || in == "path") {
 				if !transformers.IsBytes(targetType) {
@@ -129,12 +154,22 @@ func (mgr *RequestTransformerMgr) newRequestTransformer(ctx context.Context, typ
 					}
 				}
 			}
+
+			if parameter.Style.IsDelimited() && !parameter.Explode {
+				return nil, errors.Errorf("style `%s` should be used for slice, but got %s", parameter.Style, field.Type())
+			}
+
+			if parameter.Style == transformers.ParamStyleDeepObject {
+				// properties of deep object as fields of form
+				transformOpt.MIME = "urlencoded"
+			}
+
 			return mgr.NewTransformer(ctx, targetType, transformOpt)
 		}
 
 		transformer, err := getTransformer()
 		if err != nil {
-			errSet.AddErr(err, field.Name())
+			each(field, parameter, err)
 			return true
 		}
 		parameter.Transformer = transformer
@@ -142,29 +177,60 @@ func (mgr *RequestTransformerMgr) newRequestTransformer(ctx context.Context, typ
 		if !isRequestOut(ctx) {
 			parameterValidator, err := transformers.NewValidator(validator.ContextWithValidatorMgr(context.Background(), mgr.ValidatorMgr), field, tag.Get(validator.TagValidate), omitempty, transformer)
 			if err != nil {
-				errSet.AddErr(err, field.Name())
+				each(field, parameter, err)
 				return true
 			}
 			parameter.Validator = parameterValidator
+
+			if in == "path" {
+				converter, err := pathParamConverterOf(field)
+				if err != nil {
+					each(field, parameter, err)
+					return true
+				}
+				parameter.PathParamConverter = converter
+			}
+
+			if in == "body" {
+				parameter.ContentTypes = contentTypesOfBody(transformer, transformOpt.MIME)
+			}
 		}
 
-		rt.Parameters[fieldName] = parameter
+		each(field, parameter, nil)
 
 		return true
 	}, "in")
-
-	return rt, errSet.Err()
 }
 
 type RequestTransformerMgr struct {
 	validator.ValidatorMgr
 	transformers.TransformerMgr
-	cache sync.Map
+	// empty values of query, should be set before transformers created
+	QueryOptions QueryOptions
+	// fail new requests when fields of path params not in path template too,
+	// missing params of path template always fail new requests.
+	// should be set before transformers created
+	StrictPathParams bool
+	cache            sync.Map
 }
 
 type RequestTransformer struct {
 	Type       reflect.Type
 	Parameters map[string]*RequestParameter
+
+	transformerMgr   transformers.TransformerMgr
+	queryOptions     QueryOptions
+	strictPathParams bool
+}
+
+func (t *RequestTransformer) pathParamNames() []string {
+	names := make([]string, 0)
+	for _, param := range t.Parameters {
+		if param.In == "path" {
+			names = append(names, param.Name)
+		}
+	}
+	return names
 }
 
 func (t *RequestTransformer) NewRequest(method string, rawUrl string, v interface{}) (*http.Request, error) {
@@ -189,9 +255,12 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 	errSet := verrors.NewErrorSet("")
 	params := httprouter.Params{}
 	query := url.Values{}
+	emptySlices := make([]string, 0)
 	header := http.Header{}
 	cookies := make([]*http.Cookie, 0)
 	body := bytes.NewBuffer(nil)
+	// chunked body without GetBody, written by stream encoder when request sending
+	var stream io.ReadCloser
 
 	addParam := func(param *RequestParameter, value string) {
 		if param.Omitempty && !param.Explode && value == "" {
@@ -223,6 +292,14 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 		}
 
 		if param.In == "body" {
+			if streamEncoder, ok := param.Transformer.(transformers.StreamEncoder); ok {
+				if contentType, writeTo, ok := streamEncoder.StreamEncode(fieldValue); ok {
+					header.Set(httpx.HeaderContentType, contentType)
+					stream = newStreamBody(writeTo, UploadProgressFromContext(ctx))
+					return
+				}
+			}
+
 			contentType, err := param.Transformer.EncodeToWriter(body, fieldValue)
 			if err != nil {
 				errSet.AddErr(err, param.Name)
@@ -233,7 +310,17 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 		}
 
 		if param.Explode {
+			if param.In == "query" && !param.Omitempty && (!fieldValue.IsValid() || fieldValue.Len() == 0) {
+				switch t.queryOptions.EmptySlice {
+				case EmptySliceAsEmptyValue:
+					query.Add(param.Name, "")
+				case EmptySliceAsBrackets:
+					emptySlices = append(emptySlices, param.Name)
+				}
+				return
+			}
 			if fieldValue.IsValid() {
+				items := make([]string, 0, fieldValue.Len())
 				// slice should keep empty value
 				for i := 0; i < fieldValue.Len(); i++ {
 					buf := bytes.NewBuffer(nil)
@@ -241,7 +328,15 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 						errSet.AddErr(err, param.Name, i)
 						return
 					}
-					addParam(param, buf.String())
+					items = append(items, buf.String())
+				}
+
+				if param.Style.IsDelimited() {
+					addParam(param, param.Style.Join(items))
+				} else {
+					for _, item := range items {
+						addParam(param, item)
+					}
 				}
 			}
 		} else {
@@ -252,6 +347,26 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 					errSet.AddErr(err, param.Name)
 					return
 				}
+
+				if param.Style == transformers.ParamStyleDeepObject {
+					props, err := url.ParseQuery(buf.String())
+					if err != nil {
+						errSet.AddErr(err, param.Name)
+						return
+					}
+					for prop, values := range props {
+						for _, value := range values {
+							query.Add(transformers.DeepObjectKey(param.Name, prop), value)
+						}
+					}
+					return
+				}
+
+				if param.Default != "" && buf.String() == param.Default {
+					// filled by default when binding
+					return
+				}
+
 				addParam(param, buf.String())
 			}
 		}
@@ -262,20 +377,34 @@ func (t *RequestTransformer) NewRequestWithContext(ctx context.Context, method s
 		return nil, err
 	}
 
-	u.Path = NewPathnamePattern(u.Path).Stringify(params)
+	pattern := NewPathnamePattern(u.Path)
+	if err := pattern.MatchParams(t.pathParamNames()...); err != nil {
+		if unmatched := err.(*PathParamsUnmatchedError); len(unmatched.Missing) > 0 || t.strictPathParams {
+			return nil, errors.Wrapf(err, "request %s", t.Type)
+		}
+	}
+
+	u.Path = pattern.Stringify(params)
+
+	if len(query) > 0 || len(emptySlices) > 0 {
+		rawQuery := t.queryOptions.encode(query, emptySlices)
 
-	if len(query) > 0 {
 		if method == http.MethodGet && ShouldQueryInBodyForHttpGet(ctx) {
 			header.Set("Content-Type", mime.FormatMediaType("application/x-www-form-urlencoded", map[string]string{
 				"param": "value",
 			}))
-			body = bytes.NewBufferString(query.Encode())
+			body = bytes.NewBufferString(rawQuery)
 		} else {
-			u.RawQuery = query.Encode()
+			u.RawQuery = rawQuery
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), body)
+	var reqBody io.Reader = body
+	if stream != nil {
+		reqBody = stream
+	}
+
+	req, err := http.NewRequest(method, u.String(), reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +439,8 @@ type BadRequest struct {
 	errTalk     bool
 	msg         string
 	errorFields []*statuserror.ErrorField
+	// formats locations of error fields, dot path when nil
+	errorLocation transformers.ErrorLocation
 }
 
 func (e *BadRequest) EnableErrTalk() {
@@ -329,8 +460,13 @@ func (e *BadRequest) AddErr(err error, in string, nameOrIdx ...interface{}) {
 		errSet.AddErr(err, nameOrIdx...)
 	}
 
+	errorLocation := e.errorLocation
+	if errorLocation == nil {
+		errorLocation = transformers.ErrorLocationDotPath
+	}
+
 	errSet.Flatten().Each(func(fieldErr *verrors.FieldError) {
-		e.errorFields = append(e.errorFields, statuserror.NewErrorField(in, fieldErr.Field.String(), fieldErr.Error.Error()))
+		e.errorFields = append(e.errorFields, statuserror.NewErrorField(in, errorLocation(fieldErr.Field), fieldErr.Error.Error()))
 	})
 }
 
@@ -353,6 +489,40 @@ func (e *BadRequest) Err() error {
 	return err
 }
 
+// bodyTransformer resolves transformer by DecodeFallback of info when Content-Type of request missing or unknown
+func (t *RequestTransformer) bodyTransformer(info *RequestInfo, param *RequestParameter, fieldValue reflect.Value) (transformers.Transformer, io.Reader, error) {
+	if len(info.DecodeFallback) == 0 || t.transformerMgr == nil || info.Request.Body == nil {
+		return param.Transformer, info.Body(), nil
+	}
+
+	ctx := info.Request.Context()
+	typ := typesutil.FromRType(fieldValue.Type())
+
+	if !info.DecodeFallback.ShouldFallback(ctx, t.transformerMgr, typ, info.Request.Header.Get(httpx.HeaderContentType)) {
+		return param.Transformer, info.Body(), nil
+	}
+
+	return info.DecodeFallback.Resolve(ctx, t.transformerMgr, typ, info.Body())
+}
+
+// maskErrorSet drops field errors of body which not selected by field mask
+func maskErrorSet(err error, fieldMask transformers.FieldMask) error {
+	es, ok := err.(*verrors.ErrorSet)
+	if !ok {
+		return err
+	}
+
+	errSet := verrors.NewErrorSet("")
+
+	es.Flatten().Each(func(fieldErr *verrors.FieldError) {
+		if fieldMask.Contains(fieldErr.Field.String()) {
+			errSet.AddErr(fieldErr.Error, fieldErr.Field...)
+		}
+	})
+
+	return errSet.Err()
+}
+
 type PostValidator interface {
 	PostValidate(badRequest *BadRequest)
 }
@@ -368,7 +538,16 @@ func (t *RequestTransformer) DecodeFrom(info *RequestInfo, meta *courier.Operato
 		return errors.Errorf("unmatched request transformer, need %s but got %s", t.Type, typ)
 	}
 
-	badRequestError := &BadRequest{}
+	badRequestError := &BadRequest{errorLocation: transformers.ErrorLocationOf(t.transformerMgr)}
+
+	fieldMask := info.FieldMask()
+
+	bodyHeader := textproto.MIMEHeader(info.Request.Header)
+	if fieldMask != nil {
+		// pass field mask from query to body transformer too
+		bodyHeader = textproto.MIMEHeader(info.Request.Header.Clone())
+		bodyHeader.Set(httpx.HeaderFieldMask, fieldMask.String())
+	}
 
 	getValues := func(in string, name string) []string {
 		if in == "meta" {
@@ -387,17 +566,56 @@ func (t *RequestTransformer) DecodeFrom(info *RequestInfo, meta *courier.Operato
 		}
 
 		if param.In == "body" {
-			if err := param.Transformer.DecodeFromReader(info.Body(), fieldValue, textproto.MIMEHeader(info.Request.Header)); err != nil && err != io.EOF {
+			transformer, body, err := t.bodyTransformer(info, param, fieldValue)
+			if err != nil {
+				badRequestError.AddErr(err, param.In, param.Name)
+				return
+			}
+			if err := transformer.DecodeFromReader(body, fieldValue, bodyHeader); err != nil && err != io.EOF {
 				badRequestError.AddErr(err, param.In, param.Name)
 			}
 		} else {
 			maybe := transformers.NewMaybeTransformer(param.Transformer, &param.CommonTransformOption)
 			values := getValues(param.In, param.Name)
 
-			if param.Explode {
+			// missing value filled by default before decoding and validating, as sent by client
+			if len(values) == 0 && param.Default != "" && !param.Explode {
+				values = []string{param.Default}
+			}
+
+			// empty values of query decoded by QueryOptions
+			isEmptyQueryValue := func(value string) bool {
+				return param.In == "query" && value == "" && t.queryOptions.EmptyValue != EmptyValueByOmitempty
+			}
+
+			if param.Style == transformers.ParamStyleDeepObject {
+				if err := maybe.DecodeFromReader(bytes.NewBufferString(info.DeepObjectValues(param.Name).Encode()), fieldValue); err != nil {
+					badRequestError.AddErr(err, param.In, param.Name)
+				}
+			} else if param.Explode {
+				// empty slice declared explicitly, should be kept even omitempty
+				emptySlice := false
+
+				if param.Style.IsDelimited() {
+					items := make([]string, 0)
+					for _, value := range values {
+						items = append(items, param.Style.Split(value)...)
+					}
+					// empty value as empty slice declared explicitly
+					values, emptySlice = items, len(values) > 0 && len(items) == 0
+				}
+
+				if param.In == "query" {
+					if len(values) == 0 && info.QueryValues(param.Name+"[]") != nil {
+						values, emptySlice = []string{}, true
+					} else if len(values) == 1 && values[0] == "" && t.queryOptions.EmptyValue == EmptyValueAsZero {
+						values, emptySlice = []string{}, true
+					}
+				}
+
 				lenOfValues := len(values)
 
-				if param.Omitempty && lenOfValues == 0 {
+				if param.Omitempty && lenOfValues == 0 && !emptySlice {
 					return
 				}
 
@@ -405,19 +623,38 @@ func (t *RequestTransformer) DecodeFrom(info *RequestInfo, meta *courier.Operato
 					fieldValue.Set(reflect.MakeSlice(field.Type, lenOfValues, lenOfValues))
 				}
 
+				invalid := false
+
 				for idx := 0; idx < fieldValue.Len(); idx++ {
 					if lenOfValues > idx {
+						if isEmptyQueryValue(values[idx]) {
+							if t.queryOptions.EmptyValue == EmptyValueAsInvalid {
+								badRequestError.AddErr(ErrEmptyQueryValue, param.In, param.Name, idx)
+								invalid = true
+							}
+							continue
+						}
 						if err := maybe.DecodeFromReader(bytes.NewBufferString(values[idx]), fieldValue.Index(idx)); err != nil {
 							badRequestError.AddErr(err, param.In, param.Name, idx)
 						}
 					}
 				}
+
+				if invalid {
+					return
+				}
 			} else {
 				value := ""
 				if len(values) > 0 {
 					value = values[0]
 				}
-				if err := maybe.DecodeFromReader(bytes.NewBufferString(value), fieldValue); err != nil {
+
+				if len(values) > 0 && isEmptyQueryValue(value) {
+					if t.queryOptions.EmptyValue == EmptyValueAsInvalid {
+						badRequestError.AddErr(ErrEmptyQueryValue, param.In, param.Name)
+						return
+					}
+				} else if err := maybe.DecodeFromReader(bytes.NewBufferString(value), fieldValue); err != nil {
 					badRequestError.AddErr(err, param.In, param.Name)
 				}
 			}
@@ -425,7 +662,12 @@ func (t *RequestTransformer) DecodeFrom(info *RequestInfo, meta *courier.Operato
 
 		if param.Validator != nil {
 			if err := param.Validator.Validate(fieldValue); err != nil {
-				badRequestError.AddErr(err, param.In, param.Name)
+				if param.In == "body" && fieldMask != nil {
+					err = maskErrorSet(err, fieldMask)
+				}
+				if err != nil {
+					badRequestError.AddErr(err, param.In, param.Name)
+				}
 			}
 		}
 
@@ -448,9 +690,17 @@ func NewRequestParameter(name string, in string) *RequestParameter {
 type RequestParameter struct {
 	Name string
 	In   string
+	// style of parameter in query, items as repeated keys by default
+	Style transformers.ParamStyle
+	// raw value of tag `default`, filled when parameter missing
+	Default string
 	transformers.CommonTransformOption
 	Transformer transformers.Transformer
 	Validator   validator.Validator
+	// checks raw value of path parameter before operators of route run
+	PathParamConverter PathParamConverter
+	// media types accepted of body, any when empty
+	ContentTypes []string
 }
 
 func NewRequestInfo(r *http.Request) *RequestInfo {
@@ -467,13 +717,32 @@ func NewRequestInfo(r *http.Request) *RequestInfo {
 }
 
 type RequestInfo struct {
-	Request    *http.Request
+	Request *http.Request
+	// transformers to decode body when Content-Type of request missing or unknown
+	DecodeFallback transformers.FallbackChain
+
 	receivedAt time.Time
 	query      url.Values
 	cookies    []*http.Cookie
 	params     httprouter.Params
+	fieldMask  *transformers.FieldMask
+}
+
+// FieldMask from header X-Field-Mask or query fieldMask
+func (info *RequestInfo) FieldMask() transformers.FieldMask {
+	if info.fieldMask == nil {
+		fieldMask := transformers.FieldMaskFromHeaders(textproto.MIMEHeader(info.Request.Header))
+		if fieldMask == nil {
+			// query of url only, QueryValues may consume body of GET
+			fieldMask = transformers.ParseFieldMask(strings.Join(info.Request.URL.Query()[QueryFieldMask], ","))
+		}
+		info.fieldMask = &fieldMask
+	}
+	return *info.fieldMask
 }
 
+const QueryFieldMask = "fieldMask"
+
 func (info *RequestInfo) Value(in string, name string) string {
 	values := info.Values(in, name)
 	if len(values) == 0 {
@@ -525,6 +794,20 @@ func (info *RequestInfo) QueryValues(name string) []string {
 	return info.query[name]
 }
 
+// DeepObjectValues collects properties of parameter in query by style deepObject, like filter[name]=x as name=x
+func (info *RequestInfo) DeepObjectValues(name string) url.Values {
+	// parse query first
+	info.QueryValues(name)
+
+	values := url.Values{}
+	for key, vs := range info.query {
+		if prop, ok := transformers.PropOfDeepObjectKey(name, key); ok {
+			values[prop] = vs
+		}
+	}
+	return values
+}
+
 func (info *RequestInfo) HeaderValues(name string) []string {
 	return info.Request.Header[textproto.CanonicalMIMEHeaderKey(name)]
 }
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httputil"
@@ -12,11 +14,14 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-courier/courier"
 	"github.com/go-courier/httptransport/__examples__/server/pkg/types"
+	"github.com/go-courier/httptransport/httpx"
 	"github.com/go-courier/httptransport/transformers"
 	"github.com/go-courier/reflectx"
 	"github.com/go-courier/statuserror"
@@ -301,6 +306,82 @@ func ExampleNewRequestTransformerMgr() {
 	// a "string length should be larger than 2, but got invalid value 1"
 }
 
+func TestRequestTransformer_WithUnmatchedPathParams(t *testing.T) {
+	type Req struct {
+		ID   string `name:"id" in:"path"`
+		Name string `name:"name" in:"path"`
+	}
+
+	t.Run("extra allowed but missing failed by default", func(t *testing.T) {
+		mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+		rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+		require.NoError(t, err)
+
+		req, err := rt.NewRequest(http.MethodGet, "/users/:id", &Req{ID: "1", Name: "x"})
+		require.NoError(t, err)
+		require.Equal(t, "/users/1", req.URL.Path)
+
+		_, err = rt.NewRequest(http.MethodGet, "/users/:id/repos/:repoID", &Req{ID: "1", Name: "x"})
+		unmatched := &httptransport.PathParamsUnmatchedError{}
+		require.True(t, perrors.As(err, &unmatched))
+		require.Equal(t, []string{"repoID"}, unmatched.Missing)
+	})
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+	mgr.StrictPathParams = true
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	_, err = rt.NewRequest(http.MethodGet, "/users/:id/repos/:repoID", &Req{ID: "1", Name: "x"})
+	require.Error(t, err)
+
+	unmatched := &httptransport.PathParamsUnmatchedError{}
+	require.True(t, perrors.As(err, &unmatched))
+	require.Equal(t, []string{"repoID"}, unmatched.Missing)
+	require.Equal(t, []string{"name"}, unmatched.Extra)
+
+	_, err = rt.NewRequest(http.MethodGet, "/users/:id", &Req{ID: "1", Name: "x"})
+	require.True(t, perrors.As(err, &unmatched))
+	require.Equal(t, []string{"name"}, unmatched.Extra)
+
+	req, err := rt.NewRequest(http.MethodGet, "/users/:id/:name", &Req{ID: "1", Name: "x"})
+	require.NoError(t, err)
+	require.Equal(t, "/users/1/x", req.URL.Path)
+}
+
+func TestRequestTransformer_DecodeFromRequestInfo_WithErrorLocation(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	type Req struct {
+		IDs  []int `name:"ids" in:"query"`
+		Data struct {
+			Items []Item `json:"items"`
+		} `in:"body"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(transformers.WithErrorLocation(transformers.TransformerMgrDefault, transformers.ErrorLocationJSONPointer), nil)
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodPost, "/?ids=1&ids=x", bytes.NewBufferString(`{"items":[{"name":"a"},{"name":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	err = rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, &Req{})
+	require.Error(t, err)
+
+	locations := map[string]bool{}
+	for _, errorField := range err.(*statuserror.StatusErr).ErrorFields {
+		locations[errorField.In+" "+errorField.Field] = true
+	}
+
+	require.Equal(t, map[string]bool{"body /items/1/name": true, "query /ids/1": true}, locations)
+}
+
 func TestRequestTransformer_DecodeFromRequestInfo_WithDefaults(t *testing.T) {
 	type Data struct {
 		String string `json:"string,omitempty" default:"111" validate:"@string[3,]"`
@@ -354,6 +435,124 @@ func TestRequestTransformer_DecodeFromRequestInfo_WithDefaults(t *testing.T) {
 	}, r)
 }
 
+func TestRequestTransformer_WithParamStyles(t *testing.T) {
+	type Filter struct {
+		Name string `name:"name,omitempty"`
+		Age  int    `name:"age,omitempty" validate:"@int[0,150]"`
+	}
+
+	type Req struct {
+		IDs    []int    `name:"ids,csv,omitempty" in:"query"`
+		Tags   []string `name:"tags,pipes,omitempty" in:"query"`
+		Words  []string `name:"words,ssv,omitempty" in:"query"`
+		Filter Filter   `name:"filter,deepObject,omitempty" in:"query"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		req, err := rt.NewRequest(http.MethodGet, "/", &Req{
+			IDs:   []int{1, 2},
+			Tags:  []string{"a", "b"},
+			Words: []string{"x", "y"},
+			Filter: Filter{
+				Name: "n",
+				Age:  1,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "filter%5Bage%5D=1&filter%5Bname%5D=n&ids=1%2C2&tags=a%7Cb&words=x+y", req.URL.RawQuery)
+
+		r := &Req{}
+		err = rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, r)
+		require.NoError(t, err)
+		require.Equal(t, &Req{
+			IDs:   []int{1, 2},
+			Tags:  []string{"a", "b"},
+			Words: []string{"x", "y"},
+			Filter: Filter{
+				Name: "n",
+				Age:  1,
+			},
+		}, r)
+	})
+
+	t.Run("invalid items", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/?ids=1,x&filter[age]=200", nil)
+
+		err := rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, &Req{})
+		require.Error(t, err)
+
+		fields := make([]string, 0)
+		for _, errField := range err.(*statuserror.StatusErr).ErrorFields {
+			fields = append(fields, errField.Field)
+		}
+		require.Contains(t, fields, "ids[1]")
+		require.Contains(t, fields, "filter.age")
+	})
+
+	t.Run("style for parameter not in query", func(t *testing.T) {
+		type ReqWithHeader struct {
+			IDs []int `name:"ids,csv" in:"header"`
+		}
+		_, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&ReqWithHeader{}))
+		require.Error(t, err)
+	})
+}
+
+func TestRequestTransformer_WithDefaultsOfParameters(t *testing.T) {
+	type Req struct {
+		Size   int    `name:"size" in:"query" default:"10" validate:"@int[1,100]"`
+		Offset int    `name:"offset" in:"query" default:"5"`
+		Sort   string `name:"sort" in:"query" default:"createdAt"`
+		Lang   string `name:"Accept-Language" in:"header" default:"en"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	t.Run("values equal to defaults omitted by client", func(t *testing.T) {
+		req, err := rt.NewRequest(http.MethodGet, "/", &Req{
+			Size:   10,
+			Offset: 1,
+			Sort:   "createdAt",
+			Lang:   "en",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "offset=1", req.URL.RawQuery)
+		require.Equal(t, "", req.Header.Get("Accept-Language"))
+
+		r := &Req{}
+		err = rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, r)
+		require.NoError(t, err)
+		require.Equal(t, &Req{
+			Size:   10,
+			Offset: 1,
+			Sort:   "createdAt",
+			Lang:   "en",
+		}, r)
+	})
+
+	t.Run("missing required parameters filled by defaults", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/?offset=1", nil)
+
+		r := &Req{}
+		err := rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, r)
+		require.NoError(t, err)
+		require.Equal(t, &Req{
+			Size:   10,
+			Offset: 1,
+			Sort:   "createdAt",
+			Lang:   "en",
+		}, r)
+	})
+}
+
 func TestRequestTransformer_DecodeFromRequestInfo_WithEnumValidate(t *testing.T) {
 	type Req struct {
 		Protocol types.Protocol `name:"protocol,omitempty" validate:"@string{HTTP}" in:"query" default:"HTTP"`
@@ -479,6 +678,56 @@ func TestRequestTransformer_DecodeFromRequestInfo_Failed(t *testing.T) {
 ]`, string(data))
 }
 
+func TestRequestTransformer_DecodeFromRequestInfo_WithFieldMask(t *testing.T) {
+	type Profile struct {
+		Email string `json:"email" validate:"@string[3,]"`
+		Phone string `json:"phone" validate:"@string[3,]"`
+	}
+
+	type Data struct {
+		Name    string  `json:"name" validate:"@string[1,]"`
+		Desc    string  `json:"desc" validate:"@string[1,]"`
+		Profile Profile `json:"profile"`
+	}
+
+	type Req struct {
+		Data `in:"body"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	body := `{"name":"x","desc":"d","profile":{"email":"a@b.c","phone":"1"}}`
+
+	t.Run("mask from header", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(httpx.HeaderFieldMask, "name,profile.email")
+
+		info := httptransport.NewRequestInfo(req)
+		require.Equal(t, transformers.FieldMask{"name", "profile.email"}, info.FieldMask())
+
+		r := &Req{}
+		err := rt.DecodeFrom(info, &courier.OperatorFactory{}, r)
+		require.NoError(t, err)
+		require.Equal(t, Data{Name: "x", Profile: Profile{Email: "a@b.c"}}, r.Data)
+	})
+
+	t.Run("mask from query", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/?fieldMask=profile.phone", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		e := rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, &Req{})
+		require.Error(t, e)
+
+		errFields := e.(*statuserror.StatusErr).ErrorFields
+		require.Len(t, errFields, 1)
+		require.Equal(t, "profile.phone", errFields[0].Field)
+	})
+}
+
 type ReqWithPostValidate struct {
 	StartedAt string `in:"query"`
 }
@@ -495,7 +744,7 @@ func ExampleRequestTransformer_DecodeFrom_requestInfo_failedOfPost() {
 		return
 	}
 
-	req, err := rtForSomeRequest.NewRequest(http.MethodPost, "/:id", &ReqWithPostValidate{})
+	req, err := rtForSomeRequest.NewRequest(http.MethodPost, "/", &ReqWithPostValidate{})
 	if err != nil {
 		return
 	}
@@ -518,3 +767,94 @@ func ExampleRequestTransformer_DecodeFrom_requestInfo_failedOfPost() {
 	// StartedAt in query - missing required field
 	// StartedAt in query - ops
 }
+
+func TestRequestTransformer_DecodeFromRequestInfo_WithDecodeFallback(t *testing.T) {
+	type Data struct {
+		Name string `json:"name" name:"name"`
+	}
+
+	type Req struct {
+		Data `in:"body"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+	mgr.SetDefaults()
+
+	rt, err := mgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	decode := func(contentType string, body string) (*Req, error) {
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		info := httptransport.NewRequestInfo(req)
+		info.DecodeFallback = transformers.FallbackChain{"json", "form"}
+
+		r := &Req{}
+		return r, rt.DecodeFrom(info, &courier.OperatorFactory{}, r)
+	}
+
+	t.Run("sniff json of missing Content-Type", func(t *testing.T) {
+		r, err := decode("", `{"name":"x"}`)
+		require.NoError(t, err)
+		require.Equal(t, "x", r.Name)
+	})
+
+	t.Run("sniff form of unknown Content-Type", func(t *testing.T) {
+		r, err := decode("application/x-unknown", `name=x`)
+		require.NoError(t, err)
+		require.Equal(t, "x", r.Name)
+	})
+
+	t.Run("declared transformer for known Content-Type", func(t *testing.T) {
+		_, err := decode("application/x-www-form-urlencoded", `name=x`)
+		require.Error(t, err)
+	})
+}
+
+func TestRequestTransformer_NewRequest_WithStreamingBody(t *testing.T) {
+	type Upload struct {
+		Name string    `name:"name"`
+		File io.Reader `name:"file"`
+	}
+
+	type Req struct {
+		Upload `in:"body" mime:"multipart"`
+	}
+
+	mgr := httptransport.NewRequestTransformerMgr(nil, nil)
+	mgr.SetDefaults()
+
+	// called on goroutine writing body
+	written := int64(0)
+	ctx := httptransport.ContextWithUploadProgress(context.Background(), func(n int64) {
+		atomic.StoreInt64(&written, n)
+	})
+
+	content := strings.Repeat("x", 1<<20)
+
+	req, err := mgr.NewRequestWithContext(ctx, http.MethodPost, "/upload", &Req{
+		Upload: Upload{Name: "big", File: strings.NewReader(content)},
+	})
+	require.NoError(t, err)
+
+	require.Nil(t, req.GetBody)
+	require.Equal(t, int64(0), req.ContentLength)
+	require.Equal(t, int64(0), atomic.LoadInt64(&written))
+
+	serverMgr := httptransport.NewRequestTransformerMgr(nil, nil)
+	serverMgr.SetDefaults()
+
+	rt, err := serverMgr.NewRequestTransformer(context.Background(), reflect.TypeOf(&Req{}))
+	require.NoError(t, err)
+
+	r := &Req{}
+	require.NoError(t, rt.DecodeFrom(httptransport.NewRequestInfo(req), &courier.OperatorFactory{}, r))
+
+	require.Equal(t, "big", r.Name)
+	data, _ := ioutil.ReadAll(r.File)
+	require.Equal(t, content, string(data))
+	require.True(t, atomic.LoadInt64(&written) > int64(len(content)))
+}
@@ -0,0 +1,90 @@
+package httptransport
+
+import (
+	"expvar"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+)
+
+// PathAliasesDescriber could be implemented by the last operator of route,
+// to keep old paths of renamed route served until clients migrated.
+// aliases are full paths with base path, and path params should be same with route.
+//
+//	func (GetAccount) PathAliases() []string {
+//		return []string{"/api/v1/users/:id"}
+//	}
+type PathAliasesDescriber interface {
+	PathAliases() []string
+}
+
+type PathAliasOptions struct {
+	// serves route under alias directly, instead of 308 redirecting to path of route,
+	// for clients not following redirects of non-GET requests
+	Forward bool
+	// written as Sunset of responses of aliases when not zero
+	Sunset time.Time
+}
+
+// hits of aliases by method and alias, exposed by /debug/vars of admin listener,
+// aliases could be dropped when no more hits
+var pathAliasHits = expvar.NewMap("httptransport_path_alias_hits")
+
+// PathAliasHits returns hits of alias of method since process started
+func PathAliasHits(method string, alias string) int64 {
+	if v, ok := pathAliasHits.Get(method + " " + alias).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func (route *HttpRouteMeta) PathAliases() []string {
+	return route.OperatorFactoryWithRouteMetas[len(route.OperatorFactoryWithRouteMetas)-1].PathAliases
+}
+
+// pathAliasHandler redirects or forwards requests of alias to route,
+// with Deprecation and Link of successor.
+func (t *HttpTransport) pathAliasHandler(httpRoute *HttpRouteMeta, alias string, handler http.Handler) http.Handler {
+	pattern := NewPathnamePattern(httpRoute.Path())
+
+	if err := NewPathnamePattern(alias).MatchParams(pattern.Keys()...); err != nil {
+		panic(errors.Wrapf(err, "invalid alias %s of %s", alias, httpRoute.Path()))
+	}
+
+	key := httpRoute.Method() + " " + alias
+	opts := t.PathAliases
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		pathAliasHits.Add(key, 1)
+
+		// params are decoded, should be escaped again to keep same resource
+		params := httprouter.ParamsFromContext(req.Context())
+		escapedParams := make(httprouter.Params, len(params))
+		for i, p := range params {
+			escapedParams[i] = httprouter.Param{Key: p.Key, Value: url.PathEscape(p.Value)}
+		}
+
+		target := pattern.Stringify(escapedParams)
+
+		rw.Header().Set(httpx.HeaderDeprecation, "true")
+		rw.Header().Add(httpx.HeaderLink, "<"+target+`>; rel="successor-version"`)
+		if !opts.Sunset.IsZero() {
+			rw.Header().Set(httpx.HeaderSunset, opts.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if opts.Forward {
+			handler.ServeHTTP(rw, req)
+			return
+		}
+
+		if req.URL.RawQuery != "" {
+			target += "?" + req.URL.RawQuery
+		}
+
+		http.Redirect(rw, req, target, http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,105 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+type GetAccount struct {
+	httpx.MethodGet `path:"/accounts/:id"`
+	ID              string `name:"id" in:"path"`
+}
+
+func (GetAccount) PathAliases() []string {
+	return []string{"/users/:id"}
+}
+
+func (req *GetAccount) Output(ctx context.Context) (interface{}, error) {
+	return map[string]string{"id": req.ID}, nil
+}
+
+type GetAccountWithInvalidAlias struct {
+	httpx.MethodGet `path:"/accounts/:id"`
+	ID              string `name:"id" in:"path"`
+}
+
+func (req *GetAccountWithInvalidAlias) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func (GetAccountWithInvalidAlias) PathAliases() []string {
+	return []string{"/users/:userID"}
+}
+
+func TestPathAliases(t *testing.T) {
+	t.Run("redirect", func(t *testing.T) {
+		tr := &HttpTransport{}
+		tr.SetDefaults()
+		tr.httpRouter = tr.convertRouterToHttpRouter(courier.NewRouter(&GetAccount{}))
+
+		hits := PathAliasHits(http.MethodGet, "/users/:id")
+
+		rw := httptest.NewRecorder()
+		tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/users/1?fields=name", nil))
+
+		require.Equal(t, http.StatusPermanentRedirect, rw.Code)
+		require.Equal(t, "/accounts/1?fields=name", rw.Header().Get("Location"))
+		require.Equal(t, "true", rw.Header().Get(httpx.HeaderDeprecation))
+		require.Equal(t, `</accounts/1>; rel="successor-version"`, rw.Header().Get(httpx.HeaderLink))
+		require.Empty(t, rw.Header().Get(httpx.HeaderSunset))
+		require.Equal(t, hits+1, PathAliasHits(http.MethodGet, "/users/:id"))
+
+		rw = httptest.NewRecorder()
+		tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/accounts/1", nil))
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Empty(t, rw.Header().Get(httpx.HeaderDeprecation))
+	})
+
+	t.Run("redirect with encoded param", func(t *testing.T) {
+		tr := &HttpTransport{}
+		tr.SetDefaults()
+		tr.httpRouter = tr.convertRouterToHttpRouter(courier.NewRouter(&GetAccount{}))
+
+		rw := httptest.NewRecorder()
+		tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/users/a%3Fb%20c%23d%25?fields=name", nil))
+
+		require.Equal(t, http.StatusPermanentRedirect, rw.Code)
+		require.Equal(t, "/accounts/a%3Fb%20c%23d%25?fields=name", rw.Header().Get("Location"))
+		require.Equal(t, `</accounts/a%3Fb%20c%23d%25>; rel="successor-version"`, rw.Header().Get(httpx.HeaderLink))
+	})
+
+	t.Run("forward", func(t *testing.T) {
+		tr := &HttpTransport{
+			PathAliases: PathAliasOptions{
+				Forward: true,
+				Sunset:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+		tr.SetDefaults()
+		tr.httpRouter = tr.convertRouterToHttpRouter(courier.NewRouter(&GetAccount{}))
+
+		rw := httptest.NewRecorder()
+		tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.JSONEq(t, `{"id":"1"}`, rw.Body.String())
+		require.Equal(t, "true", rw.Header().Get(httpx.HeaderDeprecation))
+		require.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rw.Header().Get(httpx.HeaderSunset))
+	})
+
+	t.Run("unmatched path params", func(t *testing.T) {
+		tr := &HttpTransport{}
+		tr.SetDefaults()
+
+		require.Panics(t, func() {
+			tr.convertRouterToHttpRouter(courier.NewRouter(&GetAccountWithInvalidAlias{}))
+		})
+	})
+}
@@ -0,0 +1,37 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+)
+
+// Use appends middlewares of routes, see RouteMiddlewares of HttpTransport
+func (t *HttpTransport) Use(middlewares ...HttpMiddleware) {
+	t.RouteMiddlewares = append(t.RouteMiddlewares, middlewares...)
+}
+
+type contextKeyHttpRouteMeta int
+
+func ContextWithHttpRouteMeta(ctx context.Context, meta *HttpRouteMeta) context.Context {
+	return context.WithValue(ctx, contextKeyHttpRouteMeta(1), meta)
+}
+
+// HttpRouteMetaFromContext returns meta of route resolved, nil when not in route
+func HttpRouteMetaFromContext(ctx context.Context) *HttpRouteMeta {
+	v, _ := ctx.Value(contextKeyHttpRouteMeta(1)).(*HttpRouteMeta)
+	return v
+}
+
+// serveWithMiddlewares serves by middlewares of route in order, with operation id and meta of route in context
+func (handler *HttpRouteHandler) serveWithMiddlewares(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if len(handler.middlewares) == 0 {
+		next(rw, r)
+		return
+	}
+
+	ctx := r.Context()
+	ctx = ContextWithOperationID(ctx, handler.operationID())
+	ctx = ContextWithHttpRouteMeta(ctx, handler.HttpRouteMeta)
+
+	MiddlewareChain(handler.middlewares...)(next).ServeHTTP(rw, r.WithContext(ctx))
+}
@@ -0,0 +1,56 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteMiddlewares(t *testing.T) {
+	calls := make([]string, 0)
+
+	tracing := func(name string) HttpMiddleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				meta := HttpRouteMetaFromContext(r.Context())
+				calls = append(calls, name+" "+OperationIDFromContext(r.Context())+" "+meta.Method())
+				next.ServeHTTP(rw, r)
+			})
+		}
+	}
+
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if OperationIDFromContext(r.Context()) == "DeleteArticle" {
+				rw.WriteHeader(http.StatusTeapot)
+				return
+			}
+			next.ServeHTTP(rw, r)
+		})
+	}
+
+	tr := &HttpTransport{}
+	tr.SetDefaults()
+	tr.Use(tracing("a"), tracing("b"))
+	tr.Use(deny)
+
+	router := courier.NewRouter(Group("/articles"))
+	router.Register(courier.NewRouter(ListArticles{}))
+	router.Register(courier.NewRouter(DeleteArticle{}))
+
+	tr.httpRouter = tr.convertRouterToHttpRouter(router)
+
+	rw := httptest.NewRecorder()
+	tr.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, []string{"a ListArticles GET", "b ListArticles GET"}, calls)
+
+	rw = httptest.NewRecorder()
+	tr.ServeHTTP(rw, httptest.NewRequest(http.MethodDelete, "/articles", nil))
+	require.Equal(t, http.StatusTeapot, rw.Code)
+
+	require.Nil(t, HttpRouteMetaFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
@@ -0,0 +1,222 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// PublicAccessDescriber could be implemented by operators of route group
+// to declare the route accessible without auth, AuthPolicy skipped and required scopes not checked.
+type PublicAccessDescriber interface {
+	PublicAccess() bool
+}
+
+// RateLimitDescriber could be implemented by operators of route group
+// to declare max requests of each client in window, consumed by RateLimitPolicy.
+type RateLimitDescriber interface {
+	RateLimit() (int, time.Duration)
+}
+
+// CORSDescriber could be implemented by operators of route group
+// to declare origins allowed of cross-origin requests, "*" for any, consumed by CORSPolicy.
+// Preflight requests of the path will be answered when no OPTIONS route registered.
+type CORSDescriber interface {
+	CORSAllowedOrigins() []string
+}
+
+// MaxBodyBytesDescriber could be implemented by operators of route group
+// to declare max bytes of request body, rejected with 413 when beyond, like large uploads.
+type MaxBodyBytesDescriber interface {
+	MaxBodyBytes() int64
+}
+
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RoutePolicies declared by operators of route
+type RoutePolicies struct {
+	OperationID        string
+	PublicAccess       bool
+	RateLimit          RateLimit
+	CORSAllowedOrigins []string
+	MaxBodyBytes       int64
+}
+
+// Policies of route declared by describers of operators, each one resolved by nearestRouteMeta
+func (route *HttpRouteMeta) Policies() RoutePolicies {
+	policies := RoutePolicies{}
+
+	if n := len(route.OperatorFactoryWithRouteMetas); n > 0 {
+		policies.OperationID = route.OperatorFactoryWithRouteMetas[n-1].ID
+	}
+
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return m.PublicAccess != nil }); m != nil {
+		policies.PublicAccess = *m.PublicAccess
+	}
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return m.RateLimit.Limit > 0 }); m != nil {
+		policies.RateLimit = m.RateLimit
+	}
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return m.CORSAllowedOrigins != nil }); m != nil {
+		policies.CORSAllowedOrigins = m.CORSAllowedOrigins
+	}
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return m.MaxBodyBytes > 0 }); m != nil {
+		policies.MaxBodyBytes = m.MaxBodyBytes
+	}
+
+	return policies
+}
+
+// RoutePolicyMiddleware consumes policies declared by operators of route, called in order before decoding request,
+// errors returned will be written like errors of operators.
+type RoutePolicyMiddleware func(ctx context.Context, rw http.ResponseWriter, r *http.Request, policies RoutePolicies) (context.Context, error)
+
+// AuthPolicy authenticates requests of routes without public access,
+// context returned by authenticate will be used by operators, like ContextWithGrantedScopes.
+func AuthPolicy(authenticate func(ctx context.Context, r *http.Request) (context.Context, error)) RoutePolicyMiddleware {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request, policies RoutePolicies) (context.Context, error) {
+		if policies.PublicAccess {
+			return ctx, nil
+		}
+		return authenticate(ctx, r)
+	}
+}
+
+// RateLimitPolicy limits requests of routes with rate limit by fixed window of each client of keyOf,
+// rejected with 429 and Retry-After. keyOf default httpx.ClientIP.
+func RateLimitPolicy(keyOf func(r *http.Request) string) RoutePolicyMiddleware {
+	if keyOf == nil {
+		keyOf = httpx.ClientIP
+	}
+
+	l := &rateLimiter{windows: map[string]*rateLimitWindow{}}
+
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request, policies RoutePolicies) (context.Context, error) {
+		if policies.RateLimit.Limit <= 0 || policies.RateLimit.Window <= 0 {
+			return ctx, nil
+		}
+
+		if retryAfter, ok := l.allow(policies.OperationID+"\x00"+keyOf(r), policies.RateLimit, time.Now()); !ok {
+			rw.Header().Set(httpx.HeaderRetryAfter, strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			return ctx, statuserror.Wrap(errors.Errorf("rate limit %d in %s exceeded", policies.RateLimit.Limit, policies.RateLimit.Window), http.StatusTooManyRequests, "TooManyRequests")
+		}
+
+		return ctx, nil
+	}
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*rateLimitWindow
+	lastSweep time.Time
+}
+
+type rateLimitWindow struct {
+	count     int
+	expiresAt time.Time
+}
+
+// allow returns duration until window reset when rejected
+func (l *rateLimiter) allow(key string, rateLimit RateLimit, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// expired windows of gone clients should be dropped
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, w := range l.windows {
+			if !now.Before(w.expiresAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.expiresAt) {
+		w = &rateLimitWindow{expiresAt: now.Add(rateLimit.Window)}
+		l.windows[key] = w
+	}
+
+	if w.count >= rateLimit.Limit {
+		return w.expiresAt.Sub(now), false
+	}
+
+	w.count++
+	return 0, true
+}
+
+// CORSPolicy allows cross-origin requests from origins declared by routes
+func CORSPolicy() RoutePolicyMiddleware {
+	return func(ctx context.Context, rw http.ResponseWriter, r *http.Request, policies RoutePolicies) (context.Context, error) {
+		if len(policies.CORSAllowedOrigins) == 0 {
+			return ctx, nil
+		}
+
+		rw.Header().Add(httpx.HeaderVary, httpx.HeaderOrigin)
+
+		if origin := r.Header.Get(httpx.HeaderOrigin); origin != "" && isOriginAllowed(policies.CORSAllowedOrigins, origin) {
+			rw.Header().Set(httpx.HeaderAccessControlAllowOrigin, origin)
+		}
+
+		return ctx, nil
+	}
+}
+
+func isOriginAllowed(allowedOrigins []string, origin string) bool {
+	for _, o := range allowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// corsPreflight answers preflight requests of routes with cors allowed origins, by path
+type corsPreflight struct {
+	methods map[string][]string
+}
+
+func (p *corsPreflight) add(method string, allowedOrigins []string) {
+	if p.methods == nil {
+		p.methods = map[string][]string{}
+	}
+	p.methods[method] = allowedOrigins
+}
+
+func (p *corsPreflight) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Add(httpx.HeaderVary, httpx.HeaderOrigin)
+
+	origin := r.Header.Get(httpx.HeaderOrigin)
+	method := r.Header.Get(httpx.HeaderAccessControlRequestMethod)
+
+	allowedOrigins, ok := p.methods[method]
+	if !ok || origin == "" || !isOriginAllowed(allowedOrigins, origin) {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	methods := make([]string, 0, len(p.methods))
+	for m, origins := range p.methods {
+		if isOriginAllowed(origins, origin) {
+			methods = append(methods, m)
+		}
+	}
+	sort.Strings(methods)
+
+	rw.Header().Set(httpx.HeaderAccessControlAllowOrigin, origin)
+	rw.Header().Set(httpx.HeaderAccessControlAllowMethods, strings.Join(methods, ", "))
+	if headers := r.Header.Get(httpx.HeaderAccessControlRequestHeaders); headers != "" {
+		rw.Header().Set(httpx.HeaderAccessControlAllowHeaders, headers)
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
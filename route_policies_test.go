@@ -0,0 +1,189 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type PublicGroup struct {
+	MetaOperator
+}
+
+func (PublicGroup) PublicAccess() bool {
+	return true
+}
+
+func (PublicGroup) CORSAllowedOrigins() []string {
+	return []string{"https://example.com"}
+}
+
+type ListArticles struct {
+	httpx.MethodGet
+}
+
+func (ListArticles) RateLimit() (int, time.Duration) {
+	return 2, time.Minute
+}
+
+func (ListArticles) Output(ctx context.Context) (interface{}, error) {
+	return []string{}, nil
+}
+
+type DeleteArticle struct {
+	httpx.MethodDelete
+}
+
+func (DeleteArticle) RequiredScopes() []string {
+	return []string{"articles:write"}
+}
+
+func (DeleteArticle) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestRoutePolicies(t *testing.T) {
+	rootRouter := courier.NewRouter(Group("/articles"))
+
+	publicRouter := courier.NewRouter(&PublicGroup{})
+	publicRouter.Register(courier.NewRouter(ListArticles{}))
+
+	rootRouter.Register(publicRouter)
+	rootRouter.Register(courier.NewRouter(DeleteArticle{}))
+
+	routes := rootRouter.Routes()
+
+	routeOf := func(method string) *HttpRouteMeta {
+		for _, route := range routes {
+			m := NewHttpRouteMeta(route)
+			if m.Method() == method {
+				return m
+			}
+		}
+		return nil
+	}
+
+	t.Run("policies of route", func(t *testing.T) {
+		policies := routeOf(http.MethodGet).Policies()
+
+		require.Equal(t, RoutePolicies{
+			OperationID:        "ListArticles",
+			PublicAccess:       true,
+			RateLimit:          RateLimit{Limit: 2, Window: time.Minute},
+			CORSAllowedOrigins: []string{"https://example.com"},
+		}, policies)
+
+		require.Equal(t, RoutePolicies{OperationID: "DeleteArticle"}, routeOf(http.MethodDelete).Policies())
+	})
+
+	authenticated := 0
+
+	newHandler := func(method string) http.Handler {
+		handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, routeOf(method), NewRequestTransformerMgr(nil, nil))
+		handler.policyMiddlewares = []RoutePolicyMiddleware{
+			CORSPolicy(),
+			AuthPolicy(func(ctx context.Context, r *http.Request) (context.Context, error) {
+				authenticated++
+				if r.Header.Get("Authorization") == "" {
+					return nil, statuserror.Wrap(errors.New("missing credential"), http.StatusUnauthorized, "Unauthorized")
+				}
+				return ContextWithGrantedScopes(ctx, "articles:write"), nil
+			}),
+			RateLimitPolicy(nil),
+		}
+		return handler
+	}
+
+	t.Run("public access with rate limit and cors", func(t *testing.T) {
+		handler := newHandler(http.MethodGet)
+
+		for i := 0; i < 2; i++ {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+			req.Header.Set(httpx.HeaderOrigin, "https://example.com")
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, "https://example.com", rw.Header().Get(httpx.HeaderAccessControlAllowOrigin))
+		}
+
+		require.Equal(t, 0, authenticated)
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/articles", nil))
+		require.Equal(t, http.StatusTooManyRequests, rw.Code)
+		require.Equal(t, "60", rw.Header().Get(httpx.HeaderRetryAfter))
+	})
+
+	t.Run("auth required", func(t *testing.T) {
+		handler := newHandler(http.MethodDelete)
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodDelete, "/articles", nil))
+		require.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		rw = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/articles", nil)
+		req.Header.Set("Authorization", "Bearer xxx")
+		handler.ServeHTTP(rw, req)
+		require.Equal(t, http.StatusNoContent, rw.Code)
+		require.Empty(t, rw.Header().Get(httpx.HeaderAccessControlAllowOrigin))
+
+		require.Equal(t, 2, authenticated)
+	})
+
+	t.Run("cors preflight", func(t *testing.T) {
+		preflight := &corsPreflight{}
+		preflight.add(http.MethodGet, []string{"https://example.com"})
+		preflight.add(http.MethodPut, []string{"*"})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/articles", nil)
+		req.Header.Set(httpx.HeaderOrigin, "https://example.com")
+		req.Header.Set(httpx.HeaderAccessControlRequestMethod, http.MethodGet)
+		req.Header.Set(httpx.HeaderAccessControlRequestHeaders, "Authorization")
+		preflight.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusNoContent, rw.Code)
+		require.Equal(t, "GET, PUT", rw.Header().Get(httpx.HeaderAccessControlAllowMethods))
+		require.Equal(t, "Authorization", rw.Header().Get(httpx.HeaderAccessControlAllowHeaders))
+
+		rw = httptest.NewRecorder()
+		req.Header.Set(httpx.HeaderOrigin, "https://evil.com")
+		preflight.ServeHTTP(rw, req)
+		require.Equal(t, http.StatusForbidden, rw.Code)
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := &rateLimiter{windows: map[string]*rateLimitWindow{}}
+	rateLimit := RateLimit{Limit: 1, Window: time.Second}
+
+	now := time.Now()
+
+	_, ok := l.allow("a", rateLimit, now)
+	require.True(t, ok)
+
+	retryAfter, ok := l.allow("a", rateLimit, now.Add(300*time.Millisecond))
+	require.False(t, ok)
+	require.Equal(t, 700*time.Millisecond, retryAfter)
+
+	_, ok = l.allow("b", rateLimit, now.Add(300*time.Millisecond))
+	require.True(t, ok)
+
+	_, ok = l.allow("a", rateLimit, now.Add(time.Second))
+	require.True(t, ok)
+
+	// expired windows swept
+	_, ok = l.allow("c", rateLimit, now.Add(2*time.Minute))
+	require.True(t, ok)
+	require.Len(t, l.windows, 1)
+}
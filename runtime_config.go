@@ -0,0 +1,182 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/logr"
+	"github.com/go-courier/statuserror"
+)
+
+// RuntimeSettings of HttpTransport which could be changed without restart
+type RuntimeSettings struct {
+	// level of access log, x-log-level of request wins
+	LogLevel logr.Level `json:"logLevel"`
+	// tee raw request bodies into errors like DebugRequestBodyTee, default tee used when DebugRequestBodyTee nil
+	RequestDump bool `json:"requestDump"`
+	// timeout of handling requests of all routes, none when 0
+	Timeout Duration `json:"timeout,omitempty"`
+	// overrides of routes by operation id
+	Routes map[string]RouteSettings `json:"routes,omitempty"`
+}
+
+// RouteSettings overrides settings declared by operators of route
+type RouteSettings struct {
+	// overrides limit of RateLimitDescriber, disabled when negative
+	RateLimit       int      `json:"rateLimit,omitempty"`
+	RateLimitWindow Duration `json:"rateLimitWindow,omitempty"`
+	// overrides Timeout of RuntimeSettings
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// Duration in json like 5s
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func NewRuntimeConfig() *RuntimeConfig {
+	return &RuntimeConfig{
+		settings: RuntimeSettings{LogLevel: logr.TraceLevel},
+	}
+}
+
+// RuntimeConfig holds RuntimeSettings, and serves them for admin listener,
+//
+//	GET /debug/config   current settings
+//	PUT /debug/config   fields of json body updated, like {"logLevel":"debug","routes":{"ListUsers":{"rateLimit":10}}}
+type RuntimeConfig struct {
+	// loggers of app, level set when log level changed
+	LevelSetters []logr.LevelSetter
+
+	mu       sync.RWMutex
+	settings RuntimeSettings
+}
+
+// Settings returns copy of current settings
+func (c *RuntimeConfig) Settings() RuntimeSettings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	settings := c.settings
+
+	if c.settings.Routes != nil {
+		settings.Routes = make(map[string]RouteSettings, len(c.settings.Routes))
+		for operationID, routeSettings := range c.settings.Routes {
+			settings.Routes[operationID] = routeSettings
+		}
+	}
+
+	return settings
+}
+
+func (c *RuntimeConfig) Update(settings RuntimeSettings) {
+	c.mu.Lock()
+	levelChanged := c.settings.LogLevel != settings.LogLevel
+	c.settings = settings
+	c.mu.Unlock()
+
+	if levelChanged {
+		for _, levelSetter := range c.LevelSetters {
+			levelSetter.SetLevel(settings.LogLevel)
+		}
+	}
+}
+
+func (c *RuntimeConfig) LogLevel() logr.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.LogLevel
+}
+
+func (c *RuntimeConfig) SetLogLevel(level logr.Level) {
+	settings := c.Settings()
+	settings.LogLevel = level
+	c.Update(settings)
+}
+
+func (c *RuntimeConfig) RequestDump() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.RequestDump
+}
+
+func (c *RuntimeConfig) SetRequestDump(enabled bool) {
+	settings := c.Settings()
+	settings.RequestDump = enabled
+	c.Update(settings)
+}
+
+func (c *RuntimeConfig) SetTimeout(timeout time.Duration) {
+	settings := c.Settings()
+	settings.Timeout = Duration(timeout)
+	c.Update(settings)
+}
+
+func (c *RuntimeConfig) SetRouteSettings(operationID string, routeSettings RouteSettings) {
+	settings := c.Settings()
+	if settings.Routes == nil {
+		settings.Routes = map[string]RouteSettings{}
+	}
+	settings.Routes[operationID] = routeSettings
+	c.Update(settings)
+}
+
+// routePolicies returns policies with overrides of route, and timeout of handling
+func (c *RuntimeConfig) routePolicies(policies RoutePolicies) (RoutePolicies, time.Duration) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	timeout := time.Duration(c.settings.Timeout)
+
+	if routeSettings, ok := c.settings.Routes[policies.OperationID]; ok {
+		if routeSettings.RateLimit < 0 {
+			policies.RateLimit = RateLimit{}
+		} else if routeSettings.RateLimit > 0 {
+			policies.RateLimit.Limit = routeSettings.RateLimit
+			if routeSettings.RateLimitWindow > 0 {
+				policies.RateLimit.Window = time.Duration(routeSettings.RateLimitWindow)
+			}
+			if policies.RateLimit.Window <= 0 {
+				policies.RateLimit.Window = time.Second
+			}
+		}
+		if routeSettings.Timeout > 0 {
+			timeout = time.Duration(routeSettings.Timeout)
+		}
+	}
+
+	return policies, timeout
+}
+
+func (c *RuntimeConfig) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPatch:
+		settings := c.Settings()
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			httpx.WriteStatusErr(rw, statuserror.Wrap(err, http.StatusBadRequest, "InvalidRuntimeSettings"))
+			return
+		}
+		c.Update(settings)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(rw).Encode(c.Settings())
+}
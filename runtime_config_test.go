@@ -0,0 +1,112 @@
+package httptransport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type levelSetter struct {
+	level logr.Level
+}
+
+func (s *levelSetter) SetLevel(level logr.Level) {
+	s.level = level
+}
+
+type GetDeadline struct {
+	httpx.MethodGet
+}
+
+func (GetDeadline) Output(ctx context.Context) (interface{}, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("missing deadline")
+	}
+	return nil, nil
+}
+
+func TestRuntimeConfig(t *testing.T) {
+	t.Run("serve settings", func(t *testing.T) {
+		setter := &levelSetter{}
+
+		c := NewRuntimeConfig()
+		c.LevelSetters = []logr.LevelSetter{setter}
+
+		rw := httptest.NewRecorder()
+		c.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "/debug/config", bytes.NewBufferString(`{"logLevel":"warn","requestDump":true,"routes":{"ListArticles":{"rateLimit":1}}}`)))
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		rw = httptest.NewRecorder()
+		c.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "/debug/config", bytes.NewBufferString(`{"timeout":"5s"}`)))
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		settings := RuntimeSettings{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &settings))
+		require.Equal(t, RuntimeSettings{
+			LogLevel:    logr.WarnLevel,
+			RequestDump: true,
+			Timeout:     Duration(5 * time.Second),
+			Routes:      map[string]RouteSettings{"ListArticles": {RateLimit: 1}},
+		}, settings)
+		require.Equal(t, logr.WarnLevel, setter.level)
+
+		rw = httptest.NewRecorder()
+		c.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "/debug/config", bytes.NewBufferString(`{"timeout":"5"}`)))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Equal(t, settings, c.Settings())
+	})
+
+	newHandler := func(c *RuntimeConfig, operator courier.Operator) http.Handler {
+		route := NewHttpRouteMeta(courier.NewRouter(operator).Routes()[0])
+		handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, route, NewRequestTransformerMgr(nil, nil))
+		handler.policyMiddlewares = []RoutePolicyMiddleware{RateLimitPolicy(nil)}
+		handler.runtimeConfig = c
+		return handler
+	}
+
+	t.Run("override rate limit", func(t *testing.T) {
+		c := NewRuntimeConfig()
+		c.SetRouteSettings("ListArticles", RouteSettings{RateLimit: 1})
+
+		handler := newHandler(c, ListArticles{})
+
+		serve := func() int {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+			return rw.Code
+		}
+
+		require.Equal(t, http.StatusOK, serve())
+		require.Equal(t, http.StatusTooManyRequests, serve())
+
+		c.SetRouteSettings("ListArticles", RouteSettings{RateLimit: -1})
+		for i := 0; i < 3; i++ {
+			require.Equal(t, http.StatusOK, serve())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := NewRuntimeConfig()
+		handler := newHandler(c, GetDeadline{})
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+
+		c.SetRouteSettings("GetDeadline", RouteSettings{Timeout: Duration(time.Second)})
+
+		rw = httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusNoContent, rw.Code)
+	})
+}
@@ -0,0 +1,142 @@
+package httptransport
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/validator"
+	"github.com/pkg/errors"
+)
+
+// SchemaReport of parameters and validators of all operators,
+// to diff validation behavior between releases or feed external api linting tools.
+type SchemaReport struct {
+	Service string               `json:"service"`
+	Routes  []*RouteSchemaReport `json:"routes"`
+}
+
+type RouteSchemaReport struct {
+	Method    string                  `json:"method"`
+	Path      string                  `json:"path"`
+	Operators []*OperatorSchemaReport `json:"operators"`
+}
+
+type OperatorSchemaReport struct {
+	ID         string                   `json:"id"`
+	Type       string                   `json:"type"`
+	Parameters []*ParameterSchemaReport `json:"parameters,omitempty"`
+	// error which not belongs to any parameter
+	Error string `json:"error,omitempty"`
+}
+
+type ParameterSchemaReport struct {
+	Field string `json:"field"`
+	Name  string `json:"name"`
+	In    string `json:"in"`
+	Type  string `json:"type"`
+	// content type or name of transformer
+	Transformer string `json:"transformer,omitempty"`
+	// raw rule of tag `validate`
+	Validate string `json:"validate,omitempty"`
+	// normalized rule of created validator
+	Validator string `json:"validator,omitempty"`
+	// openapi format of path parameter checked by PathParamConverter
+	Format    string `json:"format,omitempty"`
+	Omitempty bool   `json:"omitempty,omitempty"`
+	Explode   bool   `json:"explode,omitempty"`
+	Style     string `json:"style,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewSchemaReport collects all errors of transformer and validator setup instead of panic at the first one
+func NewSchemaReport(serviceMeta ServiceMeta, routeMetas []*HttpRouteMeta, mgr *RequestTransformerMgr) *SchemaReport {
+	report := &SchemaReport{
+		Service: serviceMeta.String(),
+		Routes:  make([]*RouteSchemaReport, 0, len(routeMetas)),
+	}
+
+	for _, routeMeta := range routeMetas {
+		routeReport := &RouteSchemaReport{
+			Method: routeMeta.Method(),
+			Path:   routeMeta.Path(),
+		}
+
+		for _, opFactory := range routeMeta.OperatorFactoryWithRouteMetas {
+			routeReport.Operators = append(routeReport.Operators, newOperatorSchemaReport(opFactory, mgr))
+		}
+
+		report.Routes = append(report.Routes, routeReport)
+	}
+
+	return report
+}
+
+func newOperatorSchemaReport(opFactory *OperatorFactoryWithRouteMeta, mgr *RequestTransformerMgr) *OperatorSchemaReport {
+	opReport := &OperatorSchemaReport{
+		ID:         opFactory.ID,
+		Type:       opFactory.Type.String(),
+		Parameters: make([]*ParameterSchemaReport, 0),
+	}
+
+	if err := TryCatch(func() {
+		mgr.rangeRequestParameters(context.Background(), opFactory.Type, func(field typesutil.StructField, parameter *RequestParameter, err error) {
+			paramReport := &ParameterSchemaReport{
+				Field:     field.Name(),
+				Name:      parameter.Name,
+				In:        parameter.In,
+				Type:      field.Type().String(),
+				Validate:  field.Tag().Get(validator.TagValidate),
+				Omitempty: parameter.Omitempty,
+				Explode:   parameter.Explode,
+				Style:     string(parameter.Style),
+			}
+
+			if parameter.Transformer != nil {
+				paramReport.Transformer = parameter.Transformer.String()
+			}
+
+			if parameter.Validator != nil {
+				paramReport.Validator = parameter.Validator.String()
+			}
+
+			if parameter.PathParamConverter != nil {
+				paramReport.Format = parameter.PathParamConverter.Format()
+			}
+
+			if err != nil {
+				paramReport.Error = err.Error()
+			}
+
+			opReport.Parameters = append(opReport.Parameters, paramReport)
+		})
+	}); err != nil {
+		opReport.Error = err.Error()
+	}
+
+	return opReport
+}
+
+// Err joins all errors in report
+func (report *SchemaReport) Err() error {
+	messages := make([]string, 0)
+
+	for _, route := range report.Routes {
+		for _, op := range route.Operators {
+			if op.Error != "" {
+				messages = append(messages, route.Method+" "+route.Path+" "+op.ID+": "+op.Error)
+			}
+			for _, param := range op.Parameters {
+				if param.Error != "" {
+					messages = append(messages, route.Method+" "+route.Path+" "+op.ID+"."+param.Field+": "+param.Error)
+				}
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		return errors.Errorf("invalid operators:\n\t%s", strings.Join(messages, "\n\t"))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package httptransport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+type ListItems struct {
+	httpx.MethodGet
+	Size   int      `name:"size,omitempty" in:"query" validate:"@int[1,100]"`
+	Labels []string `name:"label,omitempty" in:"query"`
+}
+
+func (ListItems) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+type InvalidItem struct {
+	httpx.MethodPost
+	Name string `name:"name" in:"query" validate:"@int[1,"`
+}
+
+func (InvalidItem) Path() string {
+	return "/invalid"
+}
+
+func (InvalidItem) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestSchemaReport(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/root"))
+	rootRouter.Register(courier.NewRouter(ListItems{}))
+
+	ht := httptransport.NewHttpTransport()
+
+	t.Run("valid", func(t *testing.T) {
+		report := ht.SchemaReport(rootRouter)
+		require.NoError(t, report.Err())
+
+		require.Len(t, report.Routes, 1)
+		require.Equal(t, "GET", report.Routes[0].Method)
+		require.Equal(t, "/root", report.Routes[0].Path)
+
+		op := report.Routes[0].Operators[len(report.Routes[0].Operators)-1]
+		require.Equal(t, "ListItems", op.ID)
+		require.Len(t, op.Parameters, 2)
+
+		require.Equal(t, "size", op.Parameters[0].Name)
+		require.Equal(t, "query", op.Parameters[0].In)
+		require.Equal(t, "@int[1,100]", op.Parameters[0].Validate)
+		require.NotEmpty(t, op.Parameters[0].Validator)
+		require.True(t, op.Parameters[0].Omitempty)
+
+		require.True(t, op.Parameters[1].Explode)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rootRouter.Register(courier.NewRouter(InvalidItem{}))
+
+		report := ht.SchemaReport(rootRouter)
+
+		err := report.Err()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "POST /root/invalid InvalidItem.Name")
+	})
+}
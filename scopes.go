@@ -0,0 +1,65 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// RequiredScopesDescriber could be implemented by operator
+// to declare OAuth scopes or permissions which must be all granted before calling
+type RequiredScopesDescriber interface {
+	RequiredScopes() []string
+}
+
+type contextKeyGrantedScopes int
+
+// ContextWithGrantedScopes should be called by auth middleware or auth operator
+// to mark the scopes granted to current request
+func ContextWithGrantedScopes(ctx context.Context, scopes ...string) context.Context {
+	grantedScopes := append([]string{}, GrantedScopesFromContext(ctx)...)
+	return context.WithValue(ctx, contextKeyGrantedScopes(1), append(grantedScopes, scopes...))
+}
+
+func GrantedScopesFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	scopes, _ := ctx.Value(contextKeyGrantedScopes(1)).([]string)
+	return scopes
+}
+
+// CheckScopes returns status error when any of requiredScopes not granted in ctx
+func CheckScopes(ctx context.Context, requiredScopes ...string) error {
+	if len(requiredScopes) == 0 {
+		return nil
+	}
+
+	grantedScopes := GrantedScopesFromContext(ctx)
+
+	if grantedScopes == nil {
+		return statuserror.Wrap(errors.New("missing granted scopes"), http.StatusUnauthorized, "Unauthorized")
+	}
+
+	granted := map[string]bool{}
+	for _, scope := range grantedScopes {
+		granted[scope] = true
+	}
+
+	missingScopes := make([]string, 0)
+
+	for _, scope := range requiredScopes {
+		if !granted[scope] {
+			missingScopes = append(missingScopes, scope)
+		}
+	}
+
+	if len(missingScopes) > 0 {
+		return statuserror.Wrap(errors.Errorf("missing scopes: %s", strings.Join(missingScopes, ", ")), http.StatusForbidden, "InsufficientScope")
+	}
+
+	return nil
+}
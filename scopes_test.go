@@ -0,0 +1,78 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/httptransport/testify"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+type ScopesGranter struct {
+	Scopes []string `name:"X-Scopes,omitempty" in:"header"`
+}
+
+func (g ScopesGranter) Output(ctx context.Context) (interface{}, error) {
+	if g.Scopes == nil {
+		return ctx, nil
+	}
+	return httptransport.ContextWithGrantedScopes(ctx, g.Scopes...), nil
+}
+
+type GetWithScopes struct {
+	httpx.MethodGet
+}
+
+func (GetWithScopes) RequiredScopes() []string {
+	return []string{"user:read", "user:write"}
+}
+
+func (GetWithScopes) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestRequiredScopes(t *testing.T) {
+	rootRouter := courier.NewRouter(httptransport.Group("/root"))
+	rootRouter.Register(courier.NewRouter(ScopesGranter{}, GetWithScopes{}))
+
+	httpRoute := httptransport.NewHttpRouteMeta(rootRouter.Routes()[0])
+	httpRouterHandler := httptransport.NewHttpRouteHandler(serviceMeta, httpRoute, rtMgr)
+
+	serve := func(scopes ...string) int {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		for _, scope := range scopes {
+			req.Header.Add("X-Scopes", scope)
+		}
+		rw := testify.NewMockResponseWriter()
+		httpRouterHandler.ServeHTTP(rw, req)
+		return rw.StatusCode
+	}
+
+	t.Run("all granted", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, serve("user:read", "user:write"))
+	})
+
+	t.Run("partial granted", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, serve("user:read"))
+	})
+
+	t.Run("no grants", func(t *testing.T) {
+		require.Equal(t, http.StatusUnauthorized, serve())
+	})
+}
+
+func TestCheckScopes(t *testing.T) {
+	ctx := httptransport.ContextWithGrantedScopes(context.Background(), "a")
+	ctx = httptransport.ContextWithGrantedScopes(ctx, "b")
+
+	require.Equal(t, []string{"a", "b"}, httptransport.GrantedScopesFromContext(ctx))
+	require.NoError(t, httptransport.CheckScopes(ctx, "a", "b"))
+	require.Error(t, httptransport.CheckScopes(ctx, "c"))
+	require.NoError(t, httptransport.CheckScopes(context.Background()))
+}
@@ -0,0 +1,87 @@
+package httptransport
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// StatusErrorLocalizer could be implemented by status error to localize msg,
+// generated by i18n/generator from comments like `@i18n zh 未授权` of status error constants.
+type StatusErrorLocalizer interface {
+	// LocalizedMsg of lower cased language tag
+	LocalizedMsg(lang string) (string, bool)
+}
+
+// LocalizeStatusErr replaces msg of statusErr by the best matched language of Accept-Language,
+// when err or any error it wraps implements StatusErrorLocalizer.
+// Key of status error keeps stable, the picked language returned for Content-Language.
+func LocalizeStatusErr(err error, statusErr *statuserror.StatusErr, acceptLanguage string) (*statuserror.StatusErr, string) {
+	if acceptLanguage == "" {
+		return statusErr, ""
+	}
+
+	var localizer StatusErrorLocalizer
+	if !errors.As(err, &localizer) {
+		return statusErr, ""
+	}
+
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if msg, ok := localizer.LocalizedMsg(lang); ok {
+			return statusErr.WithMsg(msg), lang
+		}
+		// fallback to primary language, zh for zh-cn
+		if i := strings.Index(lang, "-"); i > 0 {
+			if msg, ok := localizer.LocalizedMsg(lang[0:i]); ok {
+				return statusErr.WithMsg(msg), lang[0:i]
+			}
+		}
+	}
+
+	return statusErr, ""
+}
+
+// parseAcceptLanguage returns lower cased language tags ordered by q values, * and q=0 dropped
+func parseAcceptLanguage(acceptLanguage string) []string {
+	type langQ struct {
+		lang string
+		q    float64
+	}
+
+	list := make([]langQ, 0)
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		kv := strings.Split(strings.TrimSpace(part), ";")
+
+		lang := strings.ToLower(strings.TrimSpace(kv[0]))
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range kv[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q > 0 {
+			list = append(list, langQ{lang: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].q > list[j].q
+	})
+
+	langs := make([]string, len(list))
+	for i := range list {
+		langs[i] = list[i].lang
+	}
+	return langs
+}
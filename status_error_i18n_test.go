@@ -0,0 +1,75 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport"
+	"github.com/go-courier/httptransport/__examples__/server/pkg/errors"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type GetWithLocalizedErr struct {
+	httpx.MethodGet
+}
+
+func (GetWithLocalizedErr) Output(ctx context.Context) (interface{}, error) {
+	return nil, errors.Unauthorized
+}
+
+func TestLocalizeStatusErr(t *testing.T) {
+	statusErr := errors.Unauthorized.StatusErr()
+
+	cases := map[string]struct {
+		acceptLanguage string
+		msg            string
+		lang           string
+	}{
+		"exact":        {"ja", "認証されていません", "ja"},
+		"primary":      {"zh-CN,en;q=0.8", "未授权", "zh"},
+		"by q":         {"zh;q=0.5, ja;q=0.9", "認証されていません", "ja"},
+		"not matched":  {"fr, *;q=0.5", "Unauthorized", ""},
+		"q zero":       {"ja;q=0", "Unauthorized", ""},
+		"not provided": {"", "Unauthorized", ""},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			localized, lang := httptransport.LocalizeStatusErr(errors.Unauthorized, statusErr, c.acceptLanguage)
+			require.Equal(t, c.msg, localized.Msg)
+			require.Equal(t, c.lang, lang)
+			require.Equal(t, "Unauthorized", localized.Key)
+		})
+	}
+
+	t.Run("not localizer", func(t *testing.T) {
+		err := statuserror.Wrap(pkgerrors.New("forbidden"), http.StatusForbidden, "Forbidden")
+		localized, lang := httptransport.LocalizeStatusErr(err, err, "zh")
+		require.Equal(t, err, localized)
+		require.Equal(t, "", lang)
+	})
+
+	t.Run("route handler", func(t *testing.T) {
+		rootRouter := courier.NewRouter(httptransport.Group("/root"))
+		rootRouter.Register(courier.NewRouter(GetWithLocalizedErr{}))
+
+		handler := httptransport.NewHttpRouteHandler(serviceMeta, httptransport.NewHttpRouteMeta(rootRouter.Routes()[0]), rtMgr)
+
+		req := httptest.NewRequest(http.MethodGet, "/root", nil)
+		req.Header.Set(httpx.HeaderAcceptLanguage, "zh-CN")
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusUnauthorized, rw.Code)
+		require.Equal(t, "zh", rw.Header().Get(httpx.HeaderContentLanguage))
+		require.Contains(t, rw.Body.String(), `"key":"Unauthorized"`)
+		require.Contains(t, rw.Body.String(), `"msg":"未授权"`)
+	})
+}
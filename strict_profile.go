@@ -0,0 +1,86 @@
+package httptransport
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportProfile bundles hardening of HttpTransport, zero values are not applied.
+// settings of http.Server could still be changed by ServerModifiers.
+type TransportProfile struct {
+	// max bytes of request body, 413 when beyond, could be overwritten by MaxBodyBytesDescriber of route
+	MaxBodyBytes int64
+	// max nesting of objects and arrays in json body, 400 when beyond
+	MaxJSONDepth int
+
+	// slowloris protections by limiting reading of headers
+	ReadHeaderTimeout time.Duration
+	MaxHeaderBytes    int
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// min version of tls when served with CertFile and KeyFile, like tls.VersionTLS12
+	TLSMinVersion uint16
+
+	// set on every response, could be overwritten by operators
+	SecurityHeaders map[string]string
+}
+
+// StrictProfile returns hardened profile for teams who won't tune each knob,
+//
+//	t := &httptransport.HttpTransport{Profile: httptransport.StrictProfile()}
+func StrictProfile() *TransportProfile {
+	return &TransportProfile{
+		MaxBodyBytes: 1 << 20,
+		MaxJSONDepth: 32,
+
+		ReadHeaderTimeout: 5 * time.Second,
+		MaxHeaderBytes:    64 << 10,
+
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+
+		TLSMinVersion: tls.VersionTLS12,
+
+		SecurityHeaders: map[string]string{
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "no-referrer",
+			"Strict-Transport-Security": "max-age=63072000; includeSubDomains",
+		},
+	}
+}
+
+func (p *TransportProfile) modifyServer(srv *http.Server) {
+	if p.ReadHeaderTimeout > 0 {
+		srv.ReadHeaderTimeout = p.ReadHeaderTimeout
+	}
+	if p.MaxHeaderBytes > 0 {
+		srv.MaxHeaderBytes = p.MaxHeaderBytes
+	}
+	if p.ReadTimeout > 0 {
+		srv.ReadTimeout = p.ReadTimeout
+	}
+	if p.WriteTimeout > 0 {
+		srv.WriteTimeout = p.WriteTimeout
+	}
+	if p.IdleTimeout > 0 {
+		srv.IdleTimeout = p.IdleTimeout
+	}
+	if p.TLSMinVersion > 0 {
+		if srv.TLSConfig == nil {
+			srv.TLSConfig = &tls.Config{}
+		}
+		srv.TLSConfig.MinVersion = p.TLSMinVersion
+	}
+}
+
+func (p *TransportProfile) setSecurityHeaders(rw http.ResponseWriter) {
+	for key, value := range p.SecurityHeaders {
+		rw.Header().Set(key, value)
+	}
+}
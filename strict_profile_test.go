@@ -0,0 +1,85 @@
+package httptransport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+type CreateNote struct {
+	httpx.MethodPost
+	Data map[string]interface{} `in:"body"`
+}
+
+func (CreateNote) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestStrictProfile(t *testing.T) {
+	profile := StrictProfile()
+	profile.MaxBodyBytes = 64
+	profile.MaxJSONDepth = 3
+
+	route := NewHttpRouteMeta(courier.NewRouter(CreateNote{}).Routes()[0])
+	handler := NewHttpRouteHandler(&ServiceMeta{Name: "test"}, route, NewRequestTransformerMgr(nil, nil))
+	handler.profile = profile
+
+	serve := func(body string, contentLength int64) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set(httpx.HeaderContentType, httpx.MIME_JSON)
+		req.ContentLength = contentLength
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		statusErr := &statuserror.StatusErr{}
+		if rw.Code >= http.StatusBadRequest {
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), statusErr))
+		}
+		return rw.Code, statusErr.Key
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		code, _ := serve(`{"a":{"b":["[{"]}}`, -1)
+		require.Equal(t, http.StatusNoContent, code)
+	})
+
+	t.Run("too large by content length", func(t *testing.T) {
+		code, key := serve(`{}`, 65)
+		require.Equal(t, http.StatusRequestEntityTooLarge, code)
+		require.Equal(t, "RequestBodyTooLarge", key)
+	})
+
+	t.Run("too large when reading", func(t *testing.T) {
+		code, key := serve(`{"a":"`+strings.Repeat("x", 64)+`"}`, -1)
+		require.Equal(t, http.StatusRequestEntityTooLarge, code)
+		require.Equal(t, "RequestBodyTooLarge", key)
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		code, key := serve(`{"a":{"b":[[1]]}}`, -1)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, "RequestBodyTooDeep", key)
+	})
+
+	t.Run("server and headers", func(t *testing.T) {
+		srv := &http.Server{}
+		StrictProfile().modifyServer(srv)
+		require.Equal(t, uint16(tls.VersionTLS12), srv.TLSConfig.MinVersion)
+		require.NotZero(t, srv.ReadHeaderTimeout)
+
+		rw := httptest.NewRecorder()
+		StrictProfile().setSecurityHeaders(rw)
+		require.Equal(t, "nosniff", rw.Header().Get("X-Content-Type-Options"))
+	})
+}
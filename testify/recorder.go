@@ -0,0 +1,152 @@
+package testify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Recording is golden request/response pair of operation,
+// stored as <Dir>/<OperationID>/<Name>.json by Recorder
+type Recording struct {
+	OperationID string           `json:"operationId"`
+	Name        string           `json:"name"`
+	Request     RecordedRequest  `json:"request"`
+	Response    RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	ContentType string          `json:"contentType,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode  int             `json:"statusCode"`
+	ContentType string          `json:"contentType,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// Recorder records golden request/response pairs of handler served in tests,
+// which could be harvested as examples of operations by openapi generator.
+// operation id resolved from X-Meta of response written by route handler.
+type Recorder struct {
+	Dir string
+}
+
+// Record serves req by handler and writes recording named name,
+// response returned for assertions of test.
+func (r *Recorder) Record(name string, handler http.Handler, req *http.Request) (*MockResponseWriter, error) {
+	reqBody := []byte(nil)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		reqBody = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	rw := NewMockResponseWriter()
+	handler.ServeHTTP(rw, req)
+
+	operationID := operationIDOf(rw.Header().Get("X-Meta"))
+	if operationID == "" {
+		return rw, errors.Errorf("missing operation id of %s %s, should be served by route handler", req.Method, req.URL)
+	}
+
+	recording := &Recording{
+		OperationID: operationID,
+		Name:        name,
+		Request: RecordedRequest{
+			Method:      req.Method,
+			URL:         req.URL.RequestURI(),
+			ContentType: req.Header.Get("Content-Type"),
+			Body:        rawBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode:  rw.StatusCode,
+			ContentType: rw.Header().Get("Content-Type"),
+			Body:        rawBody(rw.Bytes()),
+		},
+	}
+
+	if recording.Response.StatusCode == 0 {
+		recording.Response.StatusCode = http.StatusOK
+	}
+
+	return rw, r.write(recording)
+}
+
+func (r *Recorder) write(recording *Recording) error {
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(r.Dir, recording.OperationID, recording.Name+".json")
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// LoadRecordings loads all recordings under dir
+func LoadRecordings(dir string) ([]*Recording, error) {
+	recordings := make([]*Recording, 0)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		recording := &Recording{}
+		if err := json.Unmarshal(data, recording); err != nil {
+			return errors.Wrapf(err, "invalid recording %s", path)
+		}
+		recordings = append(recordings, recording)
+
+		return nil
+	})
+
+	return recordings, err
+}
+
+// operationIDOf returns operation id from X-Meta like service@v1/OperationID
+func operationIDOf(meta string) string {
+	if i := strings.LastIndex(meta, "/"); i >= 0 {
+		return meta[i+1:]
+	}
+	return meta
+}
+
+// rawBody keeps json as is, others as json string
+func rawBody(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return bytes.TrimSpace(data)
+	}
+	s, _ := json.Marshal(string(data))
+	return s
+}
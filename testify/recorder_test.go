@@ -0,0 +1,50 @@
+package testify
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	recorder := &Recorder{Dir: dir}
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Meta", "srv-test@v1/CreateUser")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`{"id":"1"}`))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users?dry=1", bytes.NewBufferString(`{"name":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rw, err := recorder.Record("created", handler, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, rw.StatusCode)
+
+	recordings, err := LoadRecordings(dir)
+	require.NoError(t, err)
+	require.Len(t, recordings, 1)
+
+	recording := recordings[0]
+	require.Equal(t, "CreateUser", recording.OperationID)
+	require.Equal(t, "created", recording.Name)
+	require.Equal(t, "/users?dry=1", recording.Request.URL)
+	require.JSONEq(t, `{"name":"x"}`, string(recording.Request.Body))
+	require.Equal(t, http.StatusCreated, recording.Response.StatusCode)
+	require.JSONEq(t, `{"id":"1"}`, string(recording.Response.Body))
+
+	t.Run("without operation id", func(t *testing.T) {
+		_, err := recorder.Record("x", http.NotFoundHandler(), httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Error(t, err)
+	})
+
+	t.Run("plain body as string", func(t *testing.T) {
+		require.Equal(t, `"not json"`, string(rawBody([]byte("not json"))))
+	})
+}
@@ -0,0 +1,82 @@
+package transformers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/pkg/errors"
+)
+
+/*
+Checkbox of bool field flagged by tag `name` in body of form or multipart,
+browsers submit checked checkbox as "on" by default and omit unchecked ones.
+
+	type Subscribe struct {
+		Agree bool `name:"agree,checkbox"`
+	}
+
+values (case-insensitive) in CheckboxTrueValues as true, in CheckboxFalseValues or missing as false,
+others rejected. false will be omitted when encoding, like unchecked checkbox.
+*/
+const FlagCheckbox = "checkbox"
+
+var (
+	CheckboxTrueValues  = []string{"on", "true", "1", "yes"}
+	CheckboxFalseValues = []string{"off", "false", "0", "no", ""}
+)
+
+// CheckboxValueOf coerces value of checkbox into bool
+func CheckboxValueOf(value string) (bool, error) {
+	for _, v := range CheckboxTrueValues {
+		if strings.EqualFold(v, value) {
+			return true, nil
+		}
+	}
+	for _, v := range CheckboxFalseValues {
+		if strings.EqualFold(v, value) {
+			return false, nil
+		}
+	}
+	return false, errors.Errorf("invalid value `%s` of checkbox, should be one of %s or %s", value, strings.Join(CheckboxTrueValues, ", "), strings.Join(CheckboxFalseValues, ", "))
+}
+
+func checkCheckboxField(field typesutil.StructField) error {
+	if typesutil.Deref(field.Type()).Kind() != reflect.Bool {
+		return errors.Errorf("flag `%s` should be used for bool field, but got %s", FlagCheckbox, field.Type())
+	}
+	return nil
+}
+
+// setCheckbox sets bool or *bool by values of field, false when missing
+func setCheckbox(rv reflect.Value, values []string) error {
+	value := ""
+	if len(values) > 0 {
+		value = values[0]
+	}
+
+	checked, err := CheckboxValueOf(value)
+	if err != nil {
+		return err
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	rv.SetBool(checked)
+	return nil
+}
+
+func isChecked(rv reflect.Value) bool {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Bool()
+}
@@ -0,0 +1,122 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"net/textproto"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/ptr"
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/validator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckboxValueOf(t *testing.T) {
+	for value, checked := range map[string]bool{
+		"on":    true,
+		"ON":    true,
+		"true":  true,
+		"1":     true,
+		"yes":   true,
+		"off":   false,
+		"false": false,
+		"0":     false,
+		"":      false,
+	} {
+		v, err := CheckboxValueOf(value)
+		require.NoError(t, err)
+		require.Equal(t, checked, v, value)
+	}
+
+	_, err := CheckboxValueOf("checked")
+	require.Error(t, err)
+}
+
+func TestCheckboxOfForm(t *testing.T) {
+	type Subscription struct {
+		Email     string `name:"email"`
+		Agree     bool   `name:"agree,checkbox"`
+		Subscribe *bool  `name:"subscribe,checkbox"`
+	}
+
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(Subscription{})), TransformerOption{
+		MIME: "urlencoded",
+	})
+	require.NoError(t, err)
+
+	t.Run("decode checked", func(t *testing.T) {
+		s := Subscription{}
+		require.NoError(t, ct.DecodeFromReader(bytes.NewBufferString(`email=a@b.c&agree=on&subscribe=1`), &s))
+		require.Equal(t, Subscription{Email: "a@b.c", Agree: true, Subscribe: ptr.Bool(true)}, s)
+	})
+
+	t.Run("decode missing as unchecked", func(t *testing.T) {
+		s := Subscription{Agree: true}
+		require.NoError(t, ct.DecodeFromReader(bytes.NewBufferString(`email=a@b.c`), &s))
+		require.Equal(t, Subscription{Email: "a@b.c", Agree: false, Subscribe: ptr.Bool(false)}, s)
+	})
+
+	t.Run("decode invalid", func(t *testing.T) {
+		s := Subscription{}
+		require.Error(t, ct.DecodeFromReader(bytes.NewBufferString(`agree=checked`), &s))
+	})
+
+	t.Run("encode unchecked omitted", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		_, err := ct.EncodeToWriter(b, Subscription{Email: "a@b.c", Subscribe: ptr.Bool(true)})
+		require.NoError(t, err)
+		require.Equal(t, `email=a%40b.c&subscribe=true`, b.String())
+	})
+
+	t.Run("validate missing", func(t *testing.T) {
+		ctx := validator.ContextWithValidatorMgr(context.Background(), validator.ValidatorMgrDefault)
+		v, err := ct.(MayValidator).NewValidator(ctx, typesutil.FromRType(reflect.TypeOf(Subscription{})))
+		require.NoError(t, err)
+		require.NoError(t, v.Validate(reflect.ValueOf(Subscription{Email: "a@b.c"})))
+	})
+}
+
+func TestCheckboxOfMultipart(t *testing.T) {
+	type Agreement struct {
+		Agree bool `name:"agree,checkbox"`
+	}
+
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(Agreement{})), TransformerOption{
+		MIME: "multipart",
+	})
+	require.NoError(t, err)
+
+	b := bytes.NewBuffer(nil)
+	contentType, err := ct.EncodeToWriter(b, Agreement{Agree: true})
+	require.NoError(t, err)
+
+	_, params, _ := mime.ParseMediaType(contentType)
+	require.NotEmpty(t, params["boundary"])
+
+	a := Agreement{}
+	require.NoError(t, ct.DecodeFromReader(b, &a, textproto.MIMEHeader{"Content-Type": {contentType}}))
+	require.True(t, a.Agree)
+
+	b.Reset()
+	contentType, err = ct.EncodeToWriter(b, Agreement{})
+	require.NoError(t, err)
+	require.NotContains(t, b.String(), `name="agree"`)
+
+	a = Agreement{Agree: true}
+	require.NoError(t, ct.DecodeFromReader(b, &a, textproto.MIMEHeader{"Content-Type": {contentType}}))
+	require.False(t, a.Agree)
+}
+
+func TestCheckboxOfNonBool(t *testing.T) {
+	type Invalid struct {
+		Agree string `name:"agree,checkbox"`
+	}
+
+	_, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(Invalid{})), TransformerOption{
+		MIME: "urlencoded",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,288 @@
+package transformers
+
+import (
+	"context"
+	"encoding/csv"
+	"go/ast"
+	"io"
+	"mime"
+	"net/textproto"
+	"reflect"
+	"strings"
+
+	"github.com/go-courier/reflectx"
+	"github.com/go-courier/reflectx/typesutil"
+	verrors "github.com/go-courier/validator/errors"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	TransformerMgrDefault.Register(&CSVTransformer{})
+}
+
+/*
+CSVTransformer for text/csv of slice of flat struct,
+columns in order of fields, header named by tag `csv`, field name used when not tagged, `csv:"-"` skipped.
+
+	type Row struct {
+		ID   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+
+	[]Row{{ID: 1, Name: "a"}}
+
+will be transform to
+
+	id,name
+	1,a
+
+when decoding, columns matched by header, unknown columns ignored, empty cells kept zero,
+errors located by row index (header excluded) and column, like [1].id
+*/
+type CSVTransformer struct {
+	columns []csvColumn
+}
+
+type csvColumn struct {
+	Name string
+	// field names from the row struct, embedded structs included
+	FieldPath []string
+}
+
+func (CSVTransformer) Names() []string {
+	return []string{"text/csv", "csv"}
+}
+
+func (CSVTransformer) NamedByTag() string {
+	return "csv"
+}
+
+func (t *CSVTransformer) String() string {
+	return t.Names()[0]
+}
+
+func (CSVTransformer) New(ctx context.Context, typ typesutil.Type) (Transformer, error) {
+	transformer := &CSVTransformer{}
+
+	typ = typesutil.Deref(typ)
+	if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+		return nil, errors.Errorf("content transformer `%s` should be used for slice of struct, but got %s", transformer, typ)
+	}
+
+	rowType := typesutil.Deref(typ.Elem())
+	if rowType.Kind() != reflect.Struct {
+		return nil, errors.Errorf("content transformer `%s` should be used for slice of struct, but got %s", transformer, typ)
+	}
+
+	columns, err := csvColumnsOf(rowType, nil)
+	if err != nil {
+		return nil, err
+	}
+	transformer.columns = columns
+
+	return transformer, nil
+}
+
+func csvColumnsOf(typ typesutil.Type, parent []string) ([]csvColumn, error) {
+	columns := make([]csvColumn, 0)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !ast.IsExported(f.Name()) {
+			continue
+		}
+
+		name, exists := f.Tag().Lookup("csv")
+		if name == "-" {
+			continue
+		}
+
+		path := append(append([]string{}, parent...), f.Name())
+
+		fieldType := typesutil.Deref(f.Type())
+
+		if _, ok := typesutil.EncodingTextMarshalerTypeReplacer(f.Type()); !ok {
+			switch fieldType.Kind() {
+			case reflect.Struct:
+				if f.Anonymous() && !exists {
+					embedded, err := csvColumnsOf(fieldType, path)
+					if err != nil {
+						return nil, err
+					}
+					columns = append(columns, embedded...)
+					continue
+				}
+				fallthrough
+			case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+				return nil, errors.Errorf("field %s of %s should be flat for csv, but got %s", f.Name(), typ, f.Type())
+			}
+		}
+
+		if name == "" {
+			name = f.Name()
+		}
+
+		columns = append(columns, csvColumn{Name: name, FieldPath: path})
+	}
+
+	return columns, nil
+}
+
+func (t *CSVTransformer) EncodeToWriter(w io.Writer, v interface{}) (string, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+	rv = reflect.Indirect(rv)
+
+	return superWrite(w, func(w io.Writer) error {
+		csvWriter := csv.NewWriter(w)
+
+		record := make([]string, len(t.columns))
+
+		for i, col := range t.columns {
+			record[i] = col.Name
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+
+		errSet := verrors.NewErrorSet("")
+
+		for idx := 0; idx < rv.Len(); idx++ {
+			row := reflect.Indirect(rv.Index(idx))
+
+			for i, col := range t.columns {
+				record[i] = ""
+
+				fieldValue, ok := csvFieldValue(row, col.FieldPath, false)
+				if !ok {
+					continue
+				}
+
+				data, err := reflectx.MarshalText(fieldValue)
+				if err != nil {
+					errSet.AddErr(err, idx, col.Name)
+					continue
+				}
+				record[i] = string(data)
+			}
+
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+
+		if err := errSet.Err(); err != nil {
+			return err
+		}
+
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}, mime.FormatMediaType(t.String(), map[string]string{
+		"charset": "utf-8",
+	}))
+}
+
+func (t *CSVTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflectx.New(rv.Type()))
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return errors.Errorf("csv should be decoded into slice, but got %s", rv.Type())
+	}
+
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	// utf-8 bom of csv exported by spreadsheets
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	columnsByName := map[string]csvColumn{}
+	for _, col := range t.columns {
+		columnsByName[col.Name] = col
+	}
+
+	columns := make([]*csvColumn, len(header))
+	for i, name := range header {
+		if col, ok := columnsByName[name]; ok {
+			columns[i] = &col
+		}
+	}
+
+	errSet := verrors.NewErrorSet("")
+
+	rows := reflect.MakeSlice(rv.Type(), 0, 0)
+
+	for idx := 0; ; idx++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errSet.AddErr(err, idx)
+			break
+		}
+
+		row := reflectx.New(rv.Type().Elem())
+
+		for i, cell := range record {
+			if i >= len(columns) || columns[i] == nil || cell == "" {
+				continue
+			}
+
+			fieldValue, _ := csvFieldValue(reflect.Indirect(row), columns[i].FieldPath, true)
+
+			if err := reflectx.UnmarshalText(fieldValue, []byte(cell)); err != nil {
+				errSet.AddErr(err, idx, columns[i].Name)
+			}
+		}
+
+		rows = reflect.Append(rows, row)
+	}
+
+	if err := errSet.Err(); err != nil {
+		return err
+	}
+
+	rv.Set(rows)
+
+	return nil
+}
+
+// csvFieldValue walks field path of row, nil embedded pointers created when alloc, or not ok
+func csvFieldValue(row reflect.Value, path []string, alloc bool) (reflect.Value, bool) {
+	fieldValue := row
+
+	for i, name := range path {
+		if i > 0 && fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				fieldValue.Set(reflectx.New(fieldValue.Type()))
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		fieldValue = fieldValue.FieldByName(name)
+	}
+
+	return fieldValue, true
+}
@@ -0,0 +1,79 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-courier/reflectx/typesutil"
+	verrors "github.com/go-courier/validator/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type CSVAudit struct {
+	CreatedAt *time.Time `csv:"createdAt"`
+}
+
+type CSVRow struct {
+	ID     int    `csv:"id"`
+	Name   string `csv:"name"`
+	Score  *float64
+	Secret string `csv:"-"`
+	CSVAudit
+}
+
+func TestCSVTransformer(t *testing.T) {
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf([]CSVRow{})), TransformerOption{
+		MIME: "csv",
+	})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	score := 1.5
+
+	t.Run("encode", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		contentType, err := ct.EncodeToWriter(b, []CSVRow{
+			{ID: 1, Name: "a, b", Score: &score, Secret: "x", CSVAudit: CSVAudit{CreatedAt: &createdAt}},
+			{ID: 2, Name: "c"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "text/csv; charset=utf-8", contentType)
+		require.Equal(t, "id,name,Score,createdAt\n1,\"a, b\",1.5,2020-01-01T00:00:00Z\n2,c,,\n", b.String())
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		rows := make([]CSVRow, 0)
+		err := ct.DecodeFromReader(strings.NewReader("\ufeffname,id,unknown,createdAt\na,1,x,2020-01-01T00:00:00Z\nb,2,,\n"), &rows)
+		require.NoError(t, err)
+		require.Equal(t, []CSVRow{
+			{ID: 1, Name: "a", CSVAudit: CSVAudit{CreatedAt: &createdAt}},
+			{ID: 2, Name: "b"},
+		}, rows)
+	})
+
+	t.Run("decode with locations of errors", func(t *testing.T) {
+		rows := make([]CSVRow, 0)
+		err := ct.DecodeFromReader(strings.NewReader("id,name,Score\n1,a,x\ny,b,2\n"), &rows)
+		require.Error(t, err)
+
+		errSet := err.(*verrors.ErrorSet)
+		locations := make([]string, 0)
+		errSet.Each(func(fieldErr *verrors.FieldError) {
+			locations = append(locations, fieldErr.Field.String())
+		})
+		require.Equal(t, []string{"[0].Score", "[1].id"}, locations)
+	})
+
+	t.Run("not flat", func(t *testing.T) {
+		_, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf([]struct {
+			Tags []string
+		}{})), TransformerOption{
+			MIME: "csv",
+		})
+		require.Error(t, err)
+	})
+}
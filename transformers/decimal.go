@@ -0,0 +1,346 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/validator"
+	"github.com/go-courier/validator/errors"
+	"github.com/go-courier/validator/rules"
+)
+
+func init() {
+	validator.ValidatorMgrDefault.Register(&DecimalValidator{})
+}
+
+var reDecimal = regexp.MustCompile(`^[+-]?(\d+)(\.(\d+))?$`)
+
+// Decimal is decimal number with its scale kept, like "12.30",
+// always encoded as json string to avoid rounding of float64,
+// json number will be accepted when decoding.
+//
+// validate by tag `validate:"@decimal<MAX_DIGITS,SCALE>[min,max]"`.
+type Decimal string
+
+func ParseDecimal(s string) (Decimal, error) {
+	parts := reDecimal.FindStringSubmatch(s)
+	if parts == nil {
+		return "", fmt.Errorf("invalid decimal `%s`", s)
+	}
+
+	digits := strings.TrimLeft(parts[1], "0")
+	if digits == "" {
+		digits = "0"
+	}
+	if parts[2] != "" {
+		digits += parts[2]
+	}
+
+	if s[0] == '-' {
+		return Decimal("-" + digits), nil
+	}
+	return Decimal(digits), nil
+}
+
+func (Decimal) OpenAPISchemaType() []string { return []string{"string"} }
+func (Decimal) OpenAPISchemaFormat() string { return "decimal" }
+
+// Scale is count of digits after decimal point
+func (d Decimal) Scale() int {
+	if i := strings.Index(string(d), "."); i >= 0 {
+		return len(d) - i - 1
+	}
+	return 0
+}
+
+// Digits is count of digits of integer part without leading zeros and digits of scale,
+// like precision of DECIMAL in SQL.
+func (d Decimal) Digits() int {
+	integer := strings.TrimLeft(strings.SplitN(strings.TrimLeft(string(d), "+-"), ".", 2)[0], "0")
+	if n := len(integer) + d.Scale(); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// Rat returns value as big.Rat for comparing or computing
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Rescale pads zeros to scale, failed when digits would be dropped
+func (d Decimal) Rescale(scale int) (Decimal, error) {
+	current := d.Scale()
+
+	if current == scale {
+		return d, nil
+	}
+
+	if current < scale {
+		s := string(d)
+		if current == 0 {
+			s += "."
+		}
+		return Decimal(s + strings.Repeat("0", scale-current)), nil
+	}
+
+	dropped := string(d)[len(d)-(current-scale):]
+	if strings.Trim(dropped, "0") != "" {
+		return "", fmt.Errorf("decimal %s can not be rescaled to %d without rounding", d, scale)
+	}
+
+	s := string(d)[:len(d)-(current-scale)]
+	return Decimal(strings.TrimSuffix(s, ".")), nil
+}
+
+func (d Decimal) String() string {
+	return string(d)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func (d *Decimal) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = ""
+		return nil
+	}
+	v, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(string(d))), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s := ""
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	return d.UnmarshalText(data)
+}
+
+// Money is amount in currency of ISO 4217,
+// scale of amount could be validated by tag, like `validate:"@decimal<15,2>"`.
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency" validate:"@string/^[A-Z]{3}$/"`
+}
+
+var (
+	TargetDecimalValue           = "decimal value"
+	TargetScaleOfDecimal         = "scale of decimal value"
+	TargetDigitsOfDecimal        = "total digits of decimal value"
+	TargetIntegerDigitsOfDecimal = "integer digits of decimal value"
+	DefaultMaxDigitsOfDecimal    = 38
+)
+
+/*
+DecimalValidator for Decimal or string of decimal
+
+Rules:
+
+	@decimal<MAX_DIGITS,SCALE>
+	@decimal<15,2> // at most 15 digits, and at most 2 digits after decimal point
+
+ranges
+
+	@decimal<15,2>[0,10000.00]
+	@decimal(0,)  // value should large than 0
+*/
+type DecimalValidator struct {
+	MaxDigits int
+	Scale     *int
+
+	Minimum          Decimal
+	Maximum          Decimal
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+}
+
+func (DecimalValidator) Names() []string {
+	return []string{"decimal"}
+}
+
+func (DecimalValidator) New(ctx context.Context, rule *validator.Rule) (validator.Validator, error) {
+	v := &DecimalValidator{MaxDigits: DefaultMaxDigitsOfDecimal}
+
+	if rule.Type.Kind() != reflect.String {
+		return nil, errors.NewUnsupportedTypeError(rule.Type.String(), v.String())
+	}
+
+	if rule.Params != nil {
+		if len(rule.Params) > 2 {
+			return nil, fmt.Errorf("decimal should only 1 or 2 parameter, but got %d", len(rule.Params))
+		}
+
+		if b := rule.Params[0].Bytes(); len(b) > 0 {
+			maxDigits, err := strconv.Atoi(string(b))
+			if err != nil || maxDigits <= 0 {
+				return nil, errors.NewSyntaxError("max digits of decimal should be a positive int, but got `%s`", b)
+			}
+			v.MaxDigits = maxDigits
+		}
+
+		if len(rule.Params) > 1 {
+			if b := rule.Params[1].Bytes(); len(b) > 0 {
+				scale, err := strconv.Atoi(string(b))
+				if err != nil || scale < 0 || scale > v.MaxDigits {
+					return nil, errors.NewSyntaxError("scale of decimal should be a int in [0,%d], but got `%s`", v.MaxDigits, b)
+				}
+				v.Scale = &scale
+			}
+		}
+	}
+
+	if rule.Range != nil {
+		for i, lit := range rule.Range {
+			if lit == nil || len(lit.Bytes()) == 0 {
+				continue
+			}
+			d, err := ParseDecimal(string(lit.Bytes()))
+			if err != nil {
+				return nil, errors.NewSyntaxError("range of decimal should be decimal values, but got `%s`", lit.Bytes())
+			}
+			if i == 0 {
+				v.Minimum = d
+			} else {
+				v.Maximum = d
+			}
+		}
+
+		v.ExclusiveMinimum = rule.ExclusiveLeft
+		v.ExclusiveMaximum = rule.ExclusiveRight
+	}
+
+	return v, nil
+}
+
+func (v *DecimalValidator) Validate(value interface{}) error {
+	rv, ok := value.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(value)
+	}
+
+	if rv.Kind() != reflect.String {
+		return errors.NewUnsupportedTypeError(rv.Type().String(), v.String())
+	}
+
+	d, err := ParseDecimal(rv.String())
+	if err != nil {
+		return err
+	}
+
+	if digits := d.Digits(); digits > v.MaxDigits {
+		return &errors.OutOfRangeError{
+			Target:  TargetDigitsOfDecimal,
+			Current: digits,
+			Maximum: v.MaxDigits,
+		}
+	}
+
+	if v.Scale != nil {
+		scale := d.Scale()
+		if scale > *v.Scale {
+			return &errors.OutOfRangeError{
+				Target:  TargetScaleOfDecimal,
+				Current: scale,
+				Maximum: *v.Scale,
+			}
+		}
+		// digits of integer part should be left when rescaled
+		if integerDigits := d.Digits() - scale; integerDigits > v.MaxDigits-*v.Scale {
+			return &errors.OutOfRangeError{
+				Target:  TargetIntegerDigitsOfDecimal,
+				Current: integerDigits,
+				Maximum: v.MaxDigits - *v.Scale,
+			}
+		}
+	}
+
+	if v.Minimum != "" {
+		c := d.Rat().Cmp(v.Minimum.Rat())
+		if c < 0 || (v.ExclusiveMinimum && c == 0) {
+			return &errors.OutOfRangeError{
+				Target:           TargetDecimalValue,
+				Current:          d,
+				Minimum:          v.Minimum,
+				ExclusiveMinimum: v.ExclusiveMinimum,
+			}
+		}
+	}
+
+	if v.Maximum != "" {
+		c := d.Rat().Cmp(v.Maximum.Rat())
+		if c > 0 || (v.ExclusiveMaximum && c == 0) {
+			return &errors.OutOfRangeError{
+				Target:           TargetDecimalValue,
+				Current:          d,
+				Maximum:          v.Maximum,
+				ExclusiveMaximum: v.ExclusiveMaximum,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *DecimalValidator) String() string {
+	rule := rules.NewRule(v.Names()[0])
+
+	rule.Params = []rules.RuleNode{
+		rules.NewRuleLit([]byte(strconv.Itoa(v.MaxDigits))),
+	}
+
+	if v.Scale != nil {
+		rule.Params = append(rule.Params, rules.NewRuleLit([]byte(strconv.Itoa(*v.Scale))))
+	}
+
+	if v.Minimum != "" || v.Maximum != "" {
+		rule.Range = []*rules.RuleLit{
+			rules.NewRuleLit([]byte(v.Minimum)),
+			rules.NewRuleLit([]byte(v.Maximum)),
+		}
+		rule.ExclusiveLeft = v.ExclusiveMinimum
+		rule.ExclusiveRight = v.ExclusiveMaximum
+	}
+
+	return string(rule.Bytes())
+}
+
+// Pattern of decimal string by Scale, for schema
+func (v *DecimalValidator) Pattern() string {
+	if v.Scale == nil {
+		return `^[+-]?\d+(\.\d+)?$`
+	}
+	if *v.Scale == 0 {
+		return `^[+-]?\d+$`
+	}
+	return `^[+-]?\d+(\.\d{1,` + strconv.Itoa(*v.Scale) + `})?$`
+}
@@ -0,0 +1,111 @@
+package transformers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/validator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		d, err := ParseDecimal("+0012.30")
+		require.NoError(t, err)
+		require.Equal(t, Decimal("12.30"), d)
+		require.Equal(t, 2, d.Scale())
+		require.Equal(t, 4, d.Digits())
+
+		d, err = ParseDecimal("-0.05")
+		require.NoError(t, err)
+		require.Equal(t, Decimal("-0.05"), d)
+		require.Equal(t, 2, d.Digits())
+
+		_, err = ParseDecimal("1e10")
+		require.Error(t, err)
+		_, err = ParseDecimal("1.")
+		require.Error(t, err)
+	})
+
+	t.Run("rescale", func(t *testing.T) {
+		d, err := Decimal("12").Rescale(2)
+		require.NoError(t, err)
+		require.Equal(t, Decimal("12.00"), d)
+
+		d, err = Decimal("12.300").Rescale(1)
+		require.NoError(t, err)
+		require.Equal(t, Decimal("12.3"), d)
+
+		d, err = Decimal("12.00").Rescale(0)
+		require.NoError(t, err)
+		require.Equal(t, Decimal("12"), d)
+
+		_, err = Decimal("12.35").Rescale(1)
+		require.Error(t, err)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		v := struct {
+			Price Money    `json:"price"`
+			Fee   Decimal  `json:"fee"`
+			Tax   *Decimal `json:"tax"`
+		}{}
+
+		require.NoError(t, json.Unmarshal([]byte(`{"price":{"amount":"0.30","currency":"USD"},"fee":19.990000000000000001,"tax":null}`), &v))
+		require.Equal(t, Decimal("0.30"), v.Price.Amount)
+		require.Equal(t, Decimal("19.990000000000000001"), v.Fee)
+		require.Nil(t, v.Tax)
+
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"price":{"amount":"0.30","currency":"USD"},"fee":"19.990000000000000001","tax":null}`, string(data))
+
+		require.Error(t, json.Unmarshal([]byte(`{"fee":"abc"}`), &v))
+	})
+}
+
+func TestDecimalValidator(t *testing.T) {
+	compile := func(t *testing.T, rule string) validator.Validator {
+		v, err := validator.ValidatorMgrDefault.Compile(context.Background(), []byte(rule), typesutil.FromRType(reflect.TypeOf(Decimal(""))), nil)
+		require.NoError(t, err)
+		return v
+	}
+
+	t.Run("digits and scale", func(t *testing.T) {
+		v := compile(t, "@decimal<5,2>")
+
+		require.NoError(t, v.Validate(Decimal("123.45")))
+		require.NoError(t, v.Validate(Decimal("0.05")))
+		require.Error(t, v.Validate(Decimal("1234.5")))
+		require.Error(t, v.Validate(Decimal("1.234")))
+		require.Equal(t, "@decimal<5,2>", v.String())
+	})
+
+	t.Run("range", func(t *testing.T) {
+		v := compile(t, "@decimal<10,2>(0,100.00]")
+
+		require.NoError(t, v.Validate(Decimal("100")))
+		require.NoError(t, v.Validate(Decimal("0.01")))
+		require.Error(t, v.Validate(Decimal("0.00")))
+		require.Error(t, v.Validate(Decimal("100.01")))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		v, err := validator.ValidatorMgrDefault.Compile(context.Background(), []byte("@decimal<10,2>"), typesutil.FromRType(reflect.TypeOf("")), nil)
+		require.NoError(t, err)
+		require.NoError(t, v.Validate("1.20"))
+		require.Error(t, v.Validate("1.2.0"))
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := validator.ValidatorMgrDefault.Compile(context.Background(), []byte("@decimal"), typesutil.FromRType(reflect.TypeOf(1.1)), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		require.Equal(t, `^[+-]?\d+(\.\d{1,2})?$`, compile(t, "@decimal<10,2>").(*validator.ValidatorLoader).Validator.(*DecimalValidator).Pattern())
+	})
+}
@@ -0,0 +1,119 @@
+package transformers
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/go-courier/reflectx/typesutil"
+)
+
+var (
+	rtypeReader   = typesutil.FromRType(reflect.TypeOf((*io.Reader)(nil)).Elem())
+	rtypeWriterTo = typesutil.FromRType(reflect.TypeOf((*io.WriterTo)(nil)).Elem())
+)
+
+// isStream returns true when type could be copied directly by io.Reader or io.WriterTo, like *bytes.Buffer or *os.File,
+// plain text transformer used by default for them.
+func isStream(typ typesutil.Type) bool {
+	switch t := typ.(type) {
+	case *typesutil.RType:
+		return t.Implements(rtypeReader) || t.Implements(rtypeWriterTo)
+	case *typesutil.TType:
+		return t.Implements(typesutil.FromTType(typesutil.TypeByName("io", "Reader").Underlying())) ||
+			t.Implements(typesutil.FromTType(typesutil.TypeByName("io", "WriterTo").Underlying()))
+	}
+	return false
+}
+
+// encodeDirectly copies v to w without reflection when v is io.WriterTo or io.Reader.
+// returns false when v should be encoded by transformer.
+func encodeDirectly(w io.Writer, v interface{}) (bool, error) {
+	if rv, ok := v.(reflect.Value); ok {
+		if !rv.IsValid() || !rv.CanInterface() {
+			return false, nil
+		}
+		v = rv.Interface()
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return false, nil
+	}
+
+	switch x := v.(type) {
+	case io.WriterTo:
+		_, err := x.WriteTo(w)
+		return true, err
+	case io.Reader:
+		_, err := io.Copy(w, x)
+		return true, err
+	}
+
+	return false, nil
+}
+
+// decodeDirectly copies r into v without reflection when v is io.ReaderFrom or io.Writer.
+// nil *bytes.Buffer will be created, and nil *os.File will be created as temp file rewound for reading,
+// which should be closed and removed by the operator.
+// returns false when v should be decoded by transformer.
+func decodeDirectly(r io.Reader, v interface{}) (bool, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	if !rv.IsValid() {
+		return false, nil
+	}
+
+	// pointer of target, like **bytes.Buffer
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		if !rv.CanSet() {
+			return false, nil
+		}
+
+		switch rv.Type() {
+		case reflect.TypeOf(&bytes.Buffer{}):
+			rv.Set(reflect.ValueOf(&bytes.Buffer{}))
+		case reflect.TypeOf(&os.File{}):
+			f, err := ioutil.TempFile("", "httptransport-")
+			if err != nil {
+				return true, err
+			}
+			if _, err := io.Copy(f, r); err != nil {
+				return true, err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return true, err
+			}
+			rv.Set(reflect.ValueOf(f))
+			return true, nil
+		default:
+			return false, nil
+		}
+	} else if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		// like bytes.Buffer
+		rv = rv.Addr()
+	}
+
+	if !rv.CanInterface() {
+		return false, nil
+	}
+
+	switch x := rv.Interface().(type) {
+	case io.ReaderFrom:
+		_, err := x.ReadFrom(r)
+		return true, err
+	case io.Writer:
+		_, err := io.Copy(x, r)
+		return true, err
+	}
+
+	return false, nil
+}
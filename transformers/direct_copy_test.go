@@ -0,0 +1,69 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectCopy(t *testing.T) {
+	t.Run("plain text by default for streams", func(t *testing.T) {
+		for _, v := range []interface{}{&bytes.Buffer{}, &os.File{}, &strings.Reader{}} {
+			ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(v)), TransformerOption{})
+			require.NoError(t, err)
+			require.Equal(t, "text/plain", ct.String())
+		}
+	})
+
+	ct := &PlainTextTransformer{}
+
+	t.Run("encode", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		_, err := ct.EncodeToWriter(b, reflect.ValueOf(bytes.NewBufferString("\x00binary")))
+		require.NoError(t, err)
+		require.Equal(t, "\x00binary", b.String())
+
+		b.Reset()
+		_, err = ct.EncodeToWriter(b, strings.NewReader("reader"))
+		require.NoError(t, err)
+		require.Equal(t, "reader", b.String())
+	})
+
+	t.Run("decode into nil buffer", func(t *testing.T) {
+		v := struct {
+			Buf *bytes.Buffer
+		}{}
+
+		err := ct.DecodeFromReader(strings.NewReader("\x00binary"), reflect.ValueOf(&v).Elem().Field(0))
+		require.NoError(t, err)
+		require.Equal(t, "\x00binary", v.Buf.String())
+	})
+
+	t.Run("decode into buffer", func(t *testing.T) {
+		buf := bytes.NewBufferString("a")
+
+		err := ct.DecodeFromReader(strings.NewReader("b"), buf)
+		require.NoError(t, err)
+		require.Equal(t, "ab", buf.String())
+	})
+
+	t.Run("decode into temp file", func(t *testing.T) {
+		var f *os.File
+
+		err := ct.DecodeFromReader(strings.NewReader("upload"), &f)
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		data, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, "upload", string(data))
+	})
+}
@@ -0,0 +1,69 @@
+package transformers
+
+import (
+	"strconv"
+	"strings"
+
+	verrors "github.com/go-courier/validator/errors"
+)
+
+// ErrorLocation formats key path of field error as location of error field,
+// key path is relative to parameter or body, like ["data", "structSlice", 2, "name"]
+type ErrorLocation func(keyPath verrors.KeyPath) string
+
+// ErrorLocationDotPath formats like data.structSlice[2].name, as default
+func ErrorLocationDotPath(keyPath verrors.KeyPath) string {
+	return keyPath.String()
+}
+
+// ErrorLocationJSONPointer formats as JSON Pointer of RFC 6901, like /data/structSlice/2/name
+func ErrorLocationJSONPointer(keyPath verrors.KeyPath) string {
+	b := strings.Builder{}
+
+	for _, keyOrIndex := range keyPath {
+		b.WriteRune('/')
+
+		switch v := keyOrIndex.(type) {
+		case string:
+			b.WriteString(jsonPointerEscaper.Replace(v))
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		}
+	}
+
+	return b.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// ErrorLocationDescriber could be implemented by TransformerMgr,
+// to format locations of errors of all values transformed, like errors of body and parameters of request
+type ErrorLocationDescriber interface {
+	ErrorLocation() ErrorLocation
+}
+
+// WithErrorLocation creates TransformerMgr with locations of errors formatted by errorLocation
+//
+//	t.TransformerMgr = transformers.WithErrorLocation(transformers.TransformerMgrDefault, transformers.ErrorLocationJSONPointer)
+func WithErrorLocation(mgr TransformerMgr, errorLocation ErrorLocation) TransformerMgr {
+	return &transformerMgrWithErrorLocation{TransformerMgr: mgr, errorLocation: errorLocation}
+}
+
+type transformerMgrWithErrorLocation struct {
+	TransformerMgr
+	errorLocation ErrorLocation
+}
+
+func (mgr *transformerMgrWithErrorLocation) ErrorLocation() ErrorLocation {
+	return mgr.errorLocation
+}
+
+// ErrorLocationOf returns ErrorLocation of mgr, ErrorLocationDotPath when not described
+func ErrorLocationOf(mgr TransformerMgr) ErrorLocation {
+	if describer, ok := mgr.(ErrorLocationDescriber); ok {
+		if errorLocation := describer.ErrorLocation(); errorLocation != nil {
+			return errorLocation
+		}
+	}
+	return ErrorLocationDotPath
+}
@@ -0,0 +1,48 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	verrors "github.com/go-courier/validator/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorLocation(t *testing.T) {
+	keyPath := verrors.KeyPath{"data", "structSlice", 2, "a/b~c"}
+
+	require.Equal(t, "data.structSlice[2].a/b~c", ErrorLocationDotPath(keyPath))
+	require.Equal(t, "/data/structSlice/2/a~1b~0c", ErrorLocationJSONPointer(keyPath))
+	require.Equal(t, "", ErrorLocationJSONPointer(verrors.KeyPath{}))
+
+	t.Run("of mgr", func(t *testing.T) {
+		require.Equal(t, "data.structSlice[2].a/b~c", ErrorLocationOf(TransformerMgrDefault)(keyPath))
+		require.Equal(t, "/data/structSlice/2/a~1b~0c", ErrorLocationOf(WithErrorLocation(TransformerMgrDefault, ErrorLocationJSONPointer))(keyPath))
+	})
+
+	t.Run("key path of json", func(t *testing.T) {
+		data := struct {
+			Data struct {
+				NestedSlice []struct {
+					Names []string `json:"names"`
+				} `json:"nestedSlice"`
+			} `json:"data"`
+		}{}
+
+		mgr := WithErrorLocation(TransformerMgrDefault, ErrorLocationJSONPointer)
+
+		ct, err := mgr.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(data)), TransformerOption{})
+		require.NoError(t, err)
+
+		err = ct.DecodeFromReader(bytes.NewBufferString(`{"data":{"nestedSlice":[{"names":["1"]},{"names":["1",2]}]}}`), &data)
+
+		locations := make([]string, 0)
+		err.(*verrors.ErrorSet).Flatten().Each(func(fieldErr *verrors.FieldError) {
+			locations = append(locations, ErrorLocationOf(mgr)(fieldErr.Field))
+		})
+		require.Equal(t, []string{"/data/nestedSlice/1/names/1"}, locations)
+	})
+}
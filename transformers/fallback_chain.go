@@ -0,0 +1,77 @@
+package transformers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/pkg/errors"
+)
+
+// Sniffer could be implemented by Transformer
+// to check whether content could be decoded by it from head bytes of content.
+// Transformer without Sniffer matches any content in FallbackChain.
+type Sniffer interface {
+	Sniff(head []byte) bool
+}
+
+// FallbackChain is ordered names of transformers
+// for decoding content of unknown or missing Content-Type, like json, form
+type FallbackChain []string
+
+// sniffLen same as http.DetectContentType
+const sniffLen = 512
+
+// ShouldFallback checks whether contentType is missing or unknown by mgr
+func (chain FallbackChain) ShouldFallback(ctx context.Context, mgr TransformerMgr, typ typesutil.Type, contentType string) bool {
+	if len(chain) == 0 {
+		return false
+	}
+
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+
+	_, err = mgr.NewTransformer(ctx, typ, TransformerOption{MIME: mediaType})
+	return err != nil
+}
+
+// Resolve picks the first transformer in chain which valid for typ and sniffed head of content,
+// the returned reader should be used instead of r for decoding.
+func (chain FallbackChain) Resolve(ctx context.Context, mgr TransformerMgr, typ typesutil.Type, r io.Reader) (Transformer, io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, _ := br.Peek(sniffLen)
+
+	for _, name := range chain {
+		transformer, err := mgr.NewTransformer(ctx, typ, TransformerOption{MIME: name})
+		if err != nil {
+			continue
+		}
+
+		if sniffer, ok := transformer.(Sniffer); ok && len(head) > 0 && !sniffer.Sniff(head) {
+			continue
+		}
+
+		return transformer, br, nil
+	}
+
+	return nil, br, errors.Errorf("none of fallback transformers %v matched content", []string(chain))
+}
+
+func firstNonSpace(head []byte) byte {
+	for _, b := range head {
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b
+	}
+	return 0
+}
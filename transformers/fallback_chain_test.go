@@ -0,0 +1,51 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackChain(t *testing.T) {
+	type Data struct {
+		Name string `json:"name" xml:"name" name:"name"`
+	}
+
+	typ := typesutil.FromRType(reflect.TypeOf(&Data{}))
+
+	chain := FallbackChain{"json", "urlencoded", "xml"}
+
+	t.Run("should fallback", func(t *testing.T) {
+		require.True(t, chain.ShouldFallback(context.Background(), TransformerMgrDefault, typ, ""))
+		require.True(t, chain.ShouldFallback(context.Background(), TransformerMgrDefault, typ, "application/x-unknown"))
+		require.False(t, chain.ShouldFallback(context.Background(), TransformerMgrDefault, typ, "application/json; charset=utf-8"))
+		require.False(t, FallbackChain{}.ShouldFallback(context.Background(), TransformerMgrDefault, typ, ""))
+	})
+
+	cases := map[string]string{
+		" {\"name\":\"x\"}":           "application/json",
+		"name=x":                      "application/x-www-form-urlencoded",
+		"<Data><name>x</name></Data>": "application/xml",
+	}
+
+	for content, contentType := range cases {
+		t.Run("sniff "+contentType, func(t *testing.T) {
+			transformer, r, err := chain.Resolve(context.Background(), TransformerMgrDefault, typ, bytes.NewBufferString(content))
+			require.NoError(t, err)
+			require.Equal(t, contentType, transformer.String())
+
+			data, _ := ioutil.ReadAll(r)
+			require.Equal(t, content, string(data))
+		})
+	}
+
+	t.Run("none matched", func(t *testing.T) {
+		_, _, err := FallbackChain{"json", "xml"}.Resolve(context.Background(), TransformerMgrDefault, typ, bytes.NewBufferString("name=x"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,150 @@
+package transformers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/textproto"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+)
+
+// FieldMask declares json paths which should be decoded, like `name,profile.email`.
+// parts not in the mask will be dropped before decoding and skip validation.
+type FieldMask []string
+
+func ParseFieldMask(s string) FieldMask {
+	m := FieldMask{}
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			m = append(m, p)
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	sort.Strings(m)
+	return m
+}
+
+// FieldMaskFromHeaders picks field mask from header X-Field-Mask
+func FieldMaskFromHeaders(headers ...textproto.MIMEHeader) FieldMask {
+	for _, h := range headers {
+		if v := h.Get(httpx.HeaderFieldMask); v != "" {
+			return ParseFieldMask(v)
+		}
+	}
+	return nil
+}
+
+func (m FieldMask) String() string {
+	return strings.Join(m, ",")
+}
+
+var reIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Contains checks path like `items[0].name` is selected by the mask,
+// ancestors of selected paths are contained too.
+func (m FieldMask) Contains(path string) bool {
+	if len(m) == 0 {
+		return true
+	}
+
+	path = reIndex.ReplaceAllString(path, "")
+
+	for _, p := range m {
+		if p == path || strings.HasPrefix(path, p+".") || strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune drops json object fields not in the mask,
+// arrays are pruned item by item.
+func (m FieldMask) Prune(data []byte) ([]byte, error) {
+	if len(m) == 0 {
+		return data, nil
+	}
+	return m.tree().prune(data)
+}
+
+func (m FieldMask) tree() fieldMaskTree {
+	root := fieldMaskTree{}
+
+	for _, p := range m {
+		node := root
+		keys := strings.Split(p, ".")
+
+		for i, key := range keys {
+			child, ok := node[key]
+			if ok && child == nil {
+				// parent selected already
+				break
+			}
+			if i == len(keys)-1 {
+				node[key] = nil
+				break
+			}
+			if !ok {
+				child = fieldMaskTree{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+
+	return root
+}
+
+// nil node means whole value selected
+type fieldMaskTree map[string]fieldMaskTree
+
+func (tree fieldMaskTree) prune(data []byte) ([]byte, error) {
+	if tree == nil {
+		return data, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return data, nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return nil, err
+		}
+		for key := range fields {
+			sub, ok := tree[key]
+			if !ok {
+				delete(fields, key)
+				continue
+			}
+			pruned, err := sub.prune(fields[key])
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = pruned
+		}
+		return json.Marshal(fields)
+	case '[':
+		items := make([]json.RawMessage, 0)
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			pruned, err := tree.prune(items[i])
+			if err != nil {
+				return nil, err
+			}
+			items[i] = pruned
+		}
+		return json.Marshal(items)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,37 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMask(t *testing.T) {
+	mask := ParseFieldMask(" profile.email, name,,items.id")
+
+	require.Equal(t, FieldMask{"items.id", "name", "profile.email"}, mask)
+	require.Equal(t, "items.id,name,profile.email", mask.String())
+	require.Nil(t, ParseFieldMask(""))
+
+	t.Run("contains", func(t *testing.T) {
+		require.True(t, mask.Contains("name"))
+		require.True(t, mask.Contains("profile"))
+		require.True(t, mask.Contains("profile.email"))
+		require.True(t, mask.Contains("items[1].id"))
+		require.False(t, mask.Contains("profile.phone"))
+		require.False(t, mask.Contains("items[1].name"))
+		require.True(t, FieldMask(nil).Contains("any"))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		data, err := mask.Prune([]byte(`{"name":"x","desc":"d","profile":{"email":"e","phone":"p"},"items":[{"id":1,"name":"n"}]}`))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"x","profile":{"email":"e"},"items":[{"id":1}]}`, string(data))
+	})
+
+	t.Run("parent selected", func(t *testing.T) {
+		data, err := ParseFieldMask("profile,profile.email").Prune([]byte(`{"profile":{"email":"e","phone":"p"}}`))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"profile":{"email":"e","phone":"p"}}`, string(data))
+	})
+}
@@ -25,7 +25,9 @@ func (params *FlattenParams) NewValidator(ctx context.Context, typ typesutil.Typ
 
 	typesutil.EachField(typ, "name", func(field typesutil.StructField, fieldDisplayName string, omitempty bool) bool {
 		fieldName := field.Name()
-		fieldValidator, err := NewValidator(ctx, field, field.Tag().Get("validate"), omitempty, params.fieldTransformers[fieldName])
+		// unchecked checkbox is missing
+		optional := omitempty || params.fieldOpts[fieldName].Checkbox
+		fieldValidator, err := NewValidator(ctx, field, field.Tag().Get("validate"), optional, params.fieldTransformers[fieldName])
 		if err != nil {
 			errSet.AddErr(err, fieldName)
 			return true
@@ -93,6 +95,13 @@ func (params *FlattenParams) CollectParams(ctx context.Context, typ typesutil.Ty
 		targetType := field.Type()
 		fieldName := field.Name()
 
+		if opt.Checkbox {
+			if err := checkCheckboxField(field); err != nil {
+				errSet.AddErr(err, fieldName)
+				return true
+			}
+		}
+
 		if !IsBytes(targetType) {
 			switch targetType.Kind() {
 			case reflect.Array, reflect.Slice:
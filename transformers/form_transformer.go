@@ -51,6 +51,20 @@ func (transformer *FormTransformer) String() string {
 	return transformer.Names()[0]
 }
 
+// Sniff checks whether head like key=value&key2=value2
+func (FormTransformer) Sniff(head []byte) bool {
+	if i := bytes.IndexByte(head, '='); i <= 0 {
+		return false
+	}
+	for _, b := range head {
+		switch b {
+		case ' ', '\t', '\r', '\n', '{', '}', '<', '>', '"':
+			return false
+		}
+	}
+	return true
+}
+
 func (FormTransformer) New(ctx context.Context, typ typesutil.Type) (Transformer, error) {
 	transformer := &FormTransformer{}
 
@@ -91,6 +105,9 @@ func (transformer *FormTransformer) EncodeToWriter(w io.Writer, v interface{}) (
 				}
 			}
 		} else {
+			if fieldOpt.Checkbox && !isChecked(fieldValue) {
+				return
+			}
 			if err := maybe.Add(fieldOpt.FieldName, fieldValue, valueAdder); err != nil {
 				errSet.AddErr(err, fieldOpt.FieldName)
 			}
@@ -151,6 +168,12 @@ func (transformer *FormTransformer) DecodeFromReader(r io.Reader, v interface{},
 				}
 			}
 		} else {
+			if fieldOpt.Checkbox {
+				if err := setCheckbox(fieldValue, values[fieldOpt.FieldName]); err != nil {
+					errSet.AddErr(err, fieldOpt.FieldName)
+				}
+				return
+			}
 			if err := maybe.DecodeFromReader(bytes.NewBufferString(values.Get(fieldOpt.FieldName)), fieldValue); err != nil {
 				errSet.AddErr(err, fieldOpt.FieldName)
 				return
@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+)
+
+type Paged[T any] struct {
+	Data  []T `json:"data"`
+	Total int `json:"total"`
+}
+
+type PagedItem struct {
+	Name string `json:"name"`
+}
+
+func TestGenericInstantiation(t *testing.T) {
+	pagedItem := typesutil.FromRType(reflect.TypeOf(Paged[PagedItem]{}))
+	pagedString := typesutil.FromRType(reflect.TypeOf(Paged[string]{}))
+
+	require.NotEqual(t, FullTypeName(pagedItem), FullTypeName(pagedString))
+
+	t.Run("transformers of instantiations should not share cache", func(t *testing.T) {
+		tf1, err := TransformerMgrDefault.NewTransformer(context.Background(), pagedItem, TransformerOption{MIME: "json"})
+		require.NoError(t, err)
+
+		data := Paged[PagedItem]{Data: []PagedItem{{Name: "a"}}, Total: 1}
+		b := bytes.NewBuffer(nil)
+		_, err = tf1.EncodeToWriter(b, data)
+		require.NoError(t, err)
+		require.Equal(t, `{"data":[{"name":"a"}],"total":1}`+"\n", b.String())
+	})
+
+	t.Run("instantiations of types.Type should be named with type arguments", func(t *testing.T) {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "generics.go", `package generics
+
+type Paged[T any] struct {
+	Data []T
+}
+
+var (
+	PagedString Paged[string]
+	PagedInt    Paged[int]
+)
+`, 0)
+		require.NoError(t, err)
+
+		pkg, err := (&types.Config{Importer: importer.Default()}).Check("generics", fset, []*ast.File{f}, nil)
+		require.NoError(t, err)
+
+		pagedString := typesutil.FromTType(pkg.Scope().Lookup("PagedString").Type())
+		pagedInt := typesutil.FromTType(pkg.Scope().Lookup("PagedInt").Type())
+
+		require.Equal(t, typesutil.FullTypeName(pagedString), typesutil.FullTypeName(pagedInt))
+		require.Equal(t, "generics.Paged[string]", FullTypeName(pagedString))
+		require.Equal(t, "generics.Paged[int]", FullTypeName(pagedInt))
+	})
+}
@@ -34,6 +34,31 @@ func (transformer *JSONTransformer) String() string {
 	return transformer.Names()[0]
 }
 
+func (JSONTransformer) Sniff(head []byte) bool {
+	head = bytes.TrimLeft(head, " \t\r\n")
+	if len(head) == 0 {
+		return false
+	}
+	switch head[0] {
+	case '{', '[', '"', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	for _, literal := range []string{"true", "false", "null"} {
+		if bytes.HasPrefix(head, []byte(literal)) && (len(head) == len(literal) || isJSONDelimiter(head[len(literal)])) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONDelimiter(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', ',', ']', '}':
+		return true
+	}
+	return false
+}
+
 func (JSONTransformer) New(context.Context, typesutil.Type) (Transformer, error) {
 	return &JSONTransformer{}, nil
 }
@@ -63,13 +88,20 @@ func (JSONTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...t
 		return errForRead
 	}
 
+	if mask := FieldMaskFromHeaders(headers...); mask != nil {
+		// invalid json will be reported by decoder below
+		if pruned, err := mask.Prune(data); err == nil {
+			data = pruned
+		}
+	}
+
 	dec := json.NewDecoder(bytes.NewBuffer(data))
 	err := dec.Decode(v)
 	if err != nil {
 		switch e := err.(type) {
 		case *json.UnmarshalTypeError:
 			errSet := errors.NewErrorSet("")
-			errSet.AddErr(e, location(data, int(e.Offset)))
+			errSet.AddErr(e, location(data, int(e.Offset))...)
 			return errSet.Err()
 		case *json.SyntaxError:
 			return e
@@ -77,7 +109,7 @@ func (JSONTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...t
 			offset := reflect.ValueOf(dec).Elem().Field(2 /*d*/).Field(1 /*off*/).Int()
 			if offset > 0 {
 				errSet := errors.NewErrorSet("")
-				errSet.AddErr(e, location(data, int(offset-1)))
+				errSet.AddErr(e, location(data, int(offset-1))...)
 				return errSet.Err()
 			}
 			return e
@@ -86,7 +118,8 @@ func (JSONTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...t
 	return nil
 }
 
-func location(data []byte, offset int) string {
+// location resolves key path of value at offset, like data.structSlice[2].name
+func location(data []byte, offset int) []interface{} {
 	i := 0
 	arrayPaths := map[string]bool{}
 	arrayIdxSet := map[string]int{}
@@ -145,7 +178,7 @@ func location(data []byte, offset int) string {
 		}
 	}
 
-	return pathWalker.String()
+	return append([]interface{}{}, pathWalker.Paths()...)
 }
 
 func nextToken(data []byte) int {
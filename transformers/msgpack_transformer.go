@@ -0,0 +1,62 @@
+package transformers
+
+import (
+	"context"
+	"io"
+	"net/textproto"
+	"reflect"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	TransformerMgrDefault.Register(&MsgPackTransformer{})
+}
+
+// MsgPackTransformer for compact payloads between internal services,
+// fields named by json tag as JSONTransformer
+type MsgPackTransformer struct {
+}
+
+func (MsgPackTransformer) Names() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "msgpack"}
+}
+
+func (MsgPackTransformer) NamedByTag() string {
+	return "json"
+}
+
+func (t *MsgPackTransformer) String() string {
+	return t.Names()[0]
+}
+
+func (MsgPackTransformer) New(context.Context, typesutil.Type) (Transformer, error) {
+	return &MsgPackTransformer{}, nil
+}
+
+func (t *MsgPackTransformer) EncodeToWriter(w io.Writer, v interface{}) (string, error) {
+	if rv, ok := v.(reflect.Value); ok {
+		v = rv.Interface()
+	}
+
+	return superWrite(w, func(w io.Writer) error {
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json")
+		enc.SetOmitEmpty(false)
+		return enc.Encode(v)
+	}, t.String())
+}
+
+func (MsgPackTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
+	if rv, ok := v.(reflect.Value); ok {
+		if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+			rv = rv.Addr()
+		}
+		v = rv.Interface()
+	}
+
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
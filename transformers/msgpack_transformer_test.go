@@ -0,0 +1,50 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgPackTransformer(t *testing.T) {
+	type Data struct {
+		ID    string   `json:"id"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(Data{})), TransformerOption{
+		MIME: "msgpack",
+	})
+	require.NoError(t, err)
+
+	data := Data{ID: "1", Count: 2, Tags: []string{"a"}}
+
+	rw := httptest.NewRecorder()
+
+	_, err = ct.EncodeToWriter(rw, reflect.ValueOf(data))
+	require.NoError(t, err)
+	require.Equal(t, "application/msgpack", rw.Header().Get("Content-Type"))
+
+	t.Run("named by json tag", func(t *testing.T) {
+		m := map[string]interface{}{}
+		require.NoError(t, msgpack.Unmarshal(rw.Body.Bytes(), &m))
+		require.Equal(t, "1", m["id"])
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		decoded := Data{}
+		require.NoError(t, ct.DecodeFromReader(bytes.NewBuffer(rw.Body.Bytes()), reflect.ValueOf(&decoded).Elem()))
+		require.Equal(t, data, decoded)
+	})
+
+	t.Run("decode failed", func(t *testing.T) {
+		require.Error(t, ct.DecodeFromReader(bytes.NewBufferString("\xc1"), &Data{}))
+	})
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime"
 	"mime/multipart"
 	"net/textproto"
+	"path/filepath"
 	"reflect"
 	"strconv"
 
@@ -67,91 +69,170 @@ func (transformer *MultipartTransformer) EncodeToWriter(w io.Writer, v interface
 	multipartWriter := multipart.NewWriter(w)
 
 	return superWrite(w, func(w io.Writer) error {
-		errSet := verrors.NewErrorSet("")
+		return transformer.encode(multipartWriter, rv)
+	}, multipartWriter.FormDataContentType())
+}
 
-		addPart := func(rv reflect.Value, fieldName string, fieldTransformer Transformer, omitempty bool) error {
-			buf := bytes.NewBuffer(nil)
-			contentType, err := fieldTransformer.EncodeToWriter(buf, rv)
-			if err != nil {
-				return err
-			}
+// StreamEncode streams parts when value contains files or io.Reader fields,
+// so large files will not be buffered in memory
+func (transformer *MultipartTransformer) StreamEncode(v interface{}) (string, func(w io.Writer) error, bool) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
 
-			if buf.Len() == 0 && omitempty {
-				return nil
-			}
+	if !transformer.hasFiles(reflect.Indirect(rv)) {
+		return "", nil, false
+	}
 
-			h := make(textproto.MIMEHeader)
-			h.Set(httpx.HeaderContentType, contentType)
-			h.Set(httpx.HeaderContentDisposition, fmt.Sprintf(`form-data; name=%s`, strconv.Quote(fieldName)))
+	boundary := multipart.NewWriter(ioutil.Discard).Boundary()
 
-			part, err := multipartWriter.CreatePart(h)
-			if err != nil {
-				return err
-			}
-			if _, err := part.Write(buf.Bytes()); err != nil {
-				return err
-			}
+	return mime.FormatMediaType(transformer.String(), map[string]string{"boundary": boundary}), func(w io.Writer) error {
+		multipartWriter := multipart.NewWriter(w)
+		if err := multipartWriter.SetBoundary(boundary); err != nil {
+			return err
+		}
+		return transformer.encode(multipartWriter, rv)
+	}, true
+}
+
+func (transformer *MultipartTransformer) hasFiles(rv reflect.Value) bool {
+	has := false
+
+	NamedStructFieldValueRange(rv, func(fieldValue reflect.Value, field *reflect.StructField) {
+		if has || !fieldValue.CanInterface() {
+			return
+		}
+		switch v := fieldValue.Interface().(type) {
+		case []*multipart.FileHeader:
+			has = len(v) > 0
+		case *multipart.FileHeader:
+			has = v != nil
+		case io.Reader:
+			has = v != nil
+		}
+	})
+
+	return has
+}
+
+// FileNamer could be implemented by io.Reader fields (like *os.File) to name the file part,
+// field name will be used when not implemented.
+type FileNamer interface {
+	Name() string
+}
+
+func (transformer *MultipartTransformer) encode(multipartWriter *multipart.Writer, rv reflect.Value) error {
+	errSet := verrors.NewErrorSet("")
+
+	addPart := func(rv reflect.Value, fieldName string, fieldTransformer Transformer, omitempty bool) error {
+		buf := bytes.NewBuffer(nil)
+		contentType, err := fieldTransformer.EncodeToWriter(buf, rv)
+		if err != nil {
+			return err
+		}
+
+		if buf.Len() == 0 && omitempty {
 			return nil
 		}
 
-		appendFile := func(fieldName string, fileHeader *multipart.FileHeader) error {
-			if fileHeader == nil {
-				return nil
-			}
+		h := make(textproto.MIMEHeader)
+		h.Set(httpx.HeaderContentType, contentType)
+		h.Set(httpx.HeaderContentDisposition, fmt.Sprintf(`form-data; name=%s`, strconv.Quote(fieldName)))
 
-			filePart, err := multipartWriter.CreateFormFile(fieldName, fileHeader.Filename)
-			if err != nil {
-				return err
-			}
+		part, err := multipartWriter.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		return nil
+	}
 
-			file, err := fileHeader.Open()
-			if err != nil {
-				return err
-			}
+	appendReader := func(fieldName string, filename string, r io.Reader) error {
+		filePart, err := multipartWriter.CreateFormFile(fieldName, filename)
+		if err != nil {
+			return err
+		}
 
-			if _, err := io.Copy(filePart, file); err != nil {
-				return err
-			}
+		if _, err := io.Copy(filePart, r); err != nil {
+			return err
+		}
 
+		return nil
+	}
+
+	appendFile := func(fieldName string, fileHeader *multipart.FileHeader) error {
+		if fileHeader == nil {
 			return nil
 		}
 
-		NamedStructFieldValueRange(reflect.Indirect(rv), func(fieldValue reflect.Value, field *reflect.StructField) {
-			fieldOpt := transformer.fieldOpts[field.Name]
-			fieldTransformer := transformer.fieldTransformers[field.Name]
+		file, err := fileHeader.Open()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
 
-			if fieldValue.CanInterface() {
-				switch v := fieldValue.Interface().(type) {
-				case []*multipart.FileHeader:
-					for i := range v {
-						_ = appendFile(fieldOpt.FieldName, v[i])
-					}
-					return
-				case *multipart.FileHeader:
-					_ = appendFile(fieldOpt.FieldName, v)
-					return
-				}
-			}
+		return appendReader(fieldName, fileHeader.Filename, file)
+	}
+
+	NamedStructFieldValueRange(reflect.Indirect(rv), func(fieldValue reflect.Value, field *reflect.StructField) {
+		fieldOpt := transformer.fieldOpts[field.Name]
+		fieldTransformer := transformer.fieldTransformers[field.Name]
 
-			if fieldOpt.Explode {
-				for i := 0; i < fieldValue.Len(); i++ {
-					if err := addPart(fieldValue.Index(i), fieldOpt.FieldName, fieldTransformer, fieldOpt.Omitempty); err != nil {
+		if fieldValue.CanInterface() {
+			switch v := fieldValue.Interface().(type) {
+			case []*multipart.FileHeader:
+				for i := range v {
+					if err := appendFile(fieldOpt.FieldName, v[i]); err != nil {
 						errSet.AddErr(err, fieldOpt.FieldName, i)
 					}
 				}
-			} else {
-				if err := addPart(fieldValue, fieldOpt.FieldName, fieldTransformer, fieldOpt.Omitempty); err != nil {
+				return
+			case *multipart.FileHeader:
+				if err := appendFile(fieldOpt.FieldName, v); err != nil {
+					errSet.AddErr(err, fieldOpt.FieldName)
+				}
+				return
+			case io.Reader:
+				filename := fieldOpt.FieldName
+				if namer, ok := v.(FileNamer); ok {
+					filename = filepath.Base(namer.Name())
+				}
+				if err := appendReader(fieldOpt.FieldName, filename, v); err != nil {
 					errSet.AddErr(err, fieldOpt.FieldName)
 				}
+				return
 			}
-		})
 
-		if err := errSet.Err(); err != nil {
-			return err
+			if field.Type == typeReader {
+				// nil io.Reader
+				return
+			}
 		}
 
-		return multipartWriter.Close()
-	}, multipartWriter.FormDataContentType())
+		if fieldOpt.Explode {
+			for i := 0; i < fieldValue.Len(); i++ {
+				if err := addPart(fieldValue.Index(i), fieldOpt.FieldName, fieldTransformer, fieldOpt.Omitempty); err != nil {
+					errSet.AddErr(err, fieldOpt.FieldName, i)
+				}
+			}
+		} else {
+			if fieldOpt.Checkbox && !isChecked(fieldValue) {
+				return
+			}
+			if err := addPart(fieldValue, fieldOpt.FieldName, fieldTransformer, fieldOpt.Omitempty); err != nil {
+				errSet.AddErr(err, fieldOpt.FieldName)
+			}
+		}
+	})
+
+	if err := errSet.Err(); err != nil {
+		return err
+	}
+
+	return multipartWriter.Close()
 }
 
 const (
@@ -159,6 +240,7 @@ const (
 )
 
 var typeFileHeader = reflect.TypeOf(&multipart.FileHeader{})
+var typeReader = reflect.TypeOf((*io.Reader)(nil)).Elem()
 
 func (transformer *MultipartTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
 	rv, ok := v.(reflect.Value)
@@ -187,6 +269,17 @@ func (transformer *MultipartTransformer) DecodeFromReader(r io.Reader, v interfa
 			return nil
 		}
 
+		if rv.Type() == typeReader {
+			if len(form.File[fieldName]) > idx {
+				file, err := form.File[fieldName][idx].Open()
+				if err != nil {
+					return err
+				}
+				rv.Set(reflect.ValueOf(file))
+			}
+			return nil
+		}
+
 		if len(form.Value[fieldName]) > idx {
 			if err := fieldTransformer.DecodeFromReader(bytes.NewBufferString(form.Value[fieldName][idx]), rv); err != nil {
 				return err
@@ -222,6 +315,12 @@ func (transformer *MultipartTransformer) DecodeFromReader(r io.Reader, v interfa
 				}
 			}
 		} else {
+			if fieldOpt.Checkbox {
+				if err := setCheckbox(fieldValue, form.Value[fieldOpt.FieldName]); err != nil {
+					errSet.AddErr(err, fieldOpt.FieldName)
+				}
+				return
+			}
 			if err := setValue(fieldValue, fieldTransformer, fieldOpt.FieldName, 0, fieldOpt.Omitempty); err != nil {
 				errSet.AddErr(err, fieldOpt.FieldName)
 				return
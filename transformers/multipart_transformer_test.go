@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"io/ioutil"
 	"mime"
 	"mime/multipart"
 	"net/textproto"
@@ -186,3 +187,40 @@ text
 		require.Equal(t, data, testData)
 	}
 }
+
+func TestMultipartTransformer_StreamEncode(t *testing.T) {
+	type Data struct {
+		Name string    `name:"name"`
+		File io.Reader `name:"file"`
+	}
+
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(Data{})), TransformerOption{
+		MIME: "multipart",
+	})
+	require.NoError(t, err)
+
+	streamEncoder := ct.(StreamEncoder)
+
+	t.Run("without files", func(t *testing.T) {
+		_, _, ok := streamEncoder.StreamEncode(Data{Name: "x"})
+		require.False(t, ok)
+	})
+
+	t.Run("stream io.Reader as file part", func(t *testing.T) {
+		contentType, writeTo, ok := streamEncoder.StreamEncode(Data{Name: "x", File: bytes.NewBufferString("content")})
+		require.True(t, ok)
+
+		b := bytes.NewBuffer(nil)
+		require.NoError(t, writeTo(b))
+
+		decoded := Data{}
+		err := ct.DecodeFromReader(b, &decoded, textproto.MIMEHeader{
+			"Content-Type": []string{contentType},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "x", decoded.Name)
+
+		content, _ := ioutil.ReadAll(decoded.File)
+		require.Equal(t, "content", string(content))
+	})
+}
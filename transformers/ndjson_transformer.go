@@ -0,0 +1,180 @@
+package transformers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"reflect"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/validator/errors"
+	perrors "github.com/pkg/errors"
+)
+
+func init() {
+	TransformerMgrDefault.Register(&NDJSONTransformer{})
+}
+
+// ItemIterator could be encoded as newline-delimited json
+type ItemIterator interface {
+	// Next returns false when no more items
+	Next() (item interface{}, ok bool, err error)
+}
+
+// NDJSONTransformer streams items of chan or ItemIterator as newline-delimited json
+type NDJSONTransformer struct {
+}
+
+func (NDJSONTransformer) Names() []string {
+	return []string{"application/x-ndjson", "ndjson"}
+}
+
+func (NDJSONTransformer) NamedByTag() string {
+	return "json"
+}
+
+func (t *NDJSONTransformer) String() string {
+	return t.Names()[0]
+}
+
+func (NDJSONTransformer) New(context.Context, typesutil.Type) (Transformer, error) {
+	return &NDJSONTransformer{}, nil
+}
+
+// EncodeToWriter writes items of chan (until closed), ItemIterator or slice, and flushes per item
+func (t *NDJSONTransformer) EncodeToWriter(w io.Writer, v interface{}) (string, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	return superWrite(w, func(w io.Writer) error {
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+
+		write := func(item interface{}) error {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}
+
+		if iter, ok := rv.Interface().(ItemIterator); ok {
+			for {
+				item, ok, err := iter.Next()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return nil
+				}
+				if err := write(item); err != nil {
+					return err
+				}
+			}
+		}
+
+		rv = reflect.Indirect(rv)
+
+		switch rv.Kind() {
+		case reflect.Chan:
+			for {
+				item, ok := rv.Recv()
+				if !ok {
+					return nil
+				}
+				if err := write(item.Interface()); err != nil {
+					return err
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				if err := write(rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return perrors.Errorf("unsupported item stream %s", rv.Type())
+	}, t.String())
+}
+
+// DecodeFromReader decodes items incrementally,
+// delivers into chan of item (closed when stream ended) or func(item T) error
+func (NDJSONTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	var itemType reflect.Type
+	var deliver func(item reflect.Value) error
+
+	switch rv.Kind() {
+	case reflect.Chan:
+		itemType = rv.Type().Elem()
+		defer rv.Close()
+
+		deliver = func(item reflect.Value) error {
+			rv.Send(item)
+			return nil
+		}
+	case reflect.Func:
+		typ := rv.Type()
+		if typ.NumIn() != 1 || typ.NumOut() != 1 || typ.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+			return perrors.Errorf("item receiver should be func(item T) error, but got %s", typ)
+		}
+
+		itemType = typ.In(0)
+
+		deliver = func(item reflect.Value) error {
+			if err, _ := rv.Call([]reflect.Value{item})[0].Interface().(error); err != nil {
+				return err
+			}
+			return nil
+		}
+	default:
+		return perrors.Errorf("unsupported item receiver %s", rv.Type())
+	}
+
+	reader := bufio.NewReader(r)
+
+	idx := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		eof := err == io.EOF
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			item := reflect.New(itemType)
+
+			if err := json.Unmarshal(line, item.Interface()); err != nil {
+				errSet := errors.NewErrorSet("")
+				errSet.AddErr(err, idx)
+				return errSet.Err()
+			}
+
+			if err := deliver(item.Elem()); err != nil {
+				return err
+			}
+
+			idx++
+		}
+
+		if eof {
+			return nil
+		}
+	}
+}
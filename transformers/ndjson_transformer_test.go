@@ -0,0 +1,91 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+)
+
+type ndjsonItem struct {
+	ID int `json:"id"`
+}
+
+type ndjsonItemIterator struct {
+	n int
+}
+
+func (iter *ndjsonItemIterator) Next() (interface{}, bool, error) {
+	if iter.n >= 2 {
+		return nil, false, nil
+	}
+	iter.n++
+	return &ndjsonItem{ID: iter.n}, true, nil
+}
+
+func TestNDJSONTransformer(t *testing.T) {
+	items := make(chan ndjsonItem, 2)
+	items <- ndjsonItem{ID: 1}
+	items <- ndjsonItem{ID: 2}
+	close(items)
+
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(items)), TransformerOption{
+		MIME: "ndjson",
+	})
+	require.NoError(t, err)
+
+	t.Run("encode chan", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		_, err := ct.EncodeToWriter(rw, items)
+		require.NoError(t, err)
+		require.True(t, rw.Flushed)
+		require.Equal(t, "application/x-ndjson", rw.Header().Get("Content-Type"))
+		require.Equal(t, "{\"id\":1}\n{\"id\":2}\n", rw.Body.String())
+	})
+
+	t.Run("encode iterator", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+
+		_, err := ct.EncodeToWriter(buf, &ndjsonItemIterator{})
+		require.NoError(t, err)
+		require.Equal(t, "{\"id\":1}\n{\"id\":2}\n", buf.String())
+	})
+
+	t.Run("decode by callback", func(t *testing.T) {
+		list := make([]ndjsonItem, 0)
+
+		err := ct.DecodeFromReader(bytes.NewBufferString("{\"id\":1}\n\n{\"id\":2}"), func(item ndjsonItem) error {
+			list = append(list, item)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ndjsonItem{{ID: 1}, {ID: 2}}, list)
+	})
+
+	t.Run("decode into channel", func(t *testing.T) {
+		ch := make(chan *ndjsonItem)
+
+		go func() {
+			_ = ct.DecodeFromReader(bytes.NewBufferString("{\"id\":1}\n{\"id\":2}\n"), ch)
+		}()
+
+		ids := make([]int, 0)
+		for item := range ch {
+			ids = append(ids, item.ID)
+		}
+		require.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("decode failed", func(t *testing.T) {
+		err := ct.DecodeFromReader(bytes.NewBufferString("{\"id\":1}\n{\"id\":\"x\"}\n"), func(item ndjsonItem) error {
+			return nil
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "[1]")
+	})
+}
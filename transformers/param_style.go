@@ -0,0 +1,86 @@
+package transformers
+
+import (
+	"strings"
+)
+
+/*
+ParamStyle of parameter in query, declared by flag of tag `name`
+
+	type ListPets struct {
+		IDs    []int    `name:"ids,csv" in:"query"`           // ids=1,2
+		Tags   []string `name:"tags,pipes" in:"query"`        // tags=a|b
+		Words  []string `name:"words,ssv" in:"query"`         // words=a%20b
+		Filter Filter   `name:"filter,deepObject" in:"query"` // filter[name]=x&filter[age]=1
+	}
+
+slices without style are taken as repeated keys, like ids=1&ids=2
+*/
+type ParamStyle string
+
+const (
+	ParamStyleForm       ParamStyle = ""
+	ParamStyleCSV        ParamStyle = "csv"
+	ParamStylePipes      ParamStyle = "pipes"
+	ParamStyleSSV        ParamStyle = "ssv"
+	ParamStyleDeepObject ParamStyle = "deepObject"
+)
+
+var paramStyles = []ParamStyle{
+	ParamStyleCSV,
+	ParamStylePipes,
+	ParamStyleSSV,
+	ParamStyleDeepObject,
+}
+
+// ParamStyleFromFlags picks the style from flags of tag `name`
+func ParamStyleFromFlags(flags map[string]bool) ParamStyle {
+	for _, style := range paramStyles {
+		if flags[string(style)] {
+			return style
+		}
+	}
+	return ParamStyleForm
+}
+
+// Delimiter of items joined in one value, empty when items not joined
+func (s ParamStyle) Delimiter() string {
+	switch s {
+	case ParamStyleCSV:
+		return ","
+	case ParamStylePipes:
+		return "|"
+	case ParamStyleSSV:
+		return " "
+	}
+	return ""
+}
+
+func (s ParamStyle) IsDelimited() bool {
+	return s.Delimiter() != ""
+}
+
+func (s ParamStyle) Join(items []string) string {
+	return strings.Join(items, s.Delimiter())
+}
+
+// Split value into items, empty value as no items
+func (s ParamStyle) Split(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, s.Delimiter())
+}
+
+// DeepObjectKey of property of parameter, like filter[name]
+func DeepObjectKey(name string, prop string) string {
+	return name + "[" + prop + "]"
+}
+
+// PropOfDeepObjectKey returns property of parameter from key, like name of filter[name]
+func PropOfDeepObjectKey(name string, key string) (string, bool) {
+	if len(key) <= len(name)+2 || !strings.HasPrefix(key, name+"[") || !strings.HasSuffix(key, "]") {
+		return "", false
+	}
+	return key[len(name)+1 : len(key)-1], true
+}
@@ -0,0 +1,24 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamStyle(t *testing.T) {
+	_, flags := TagValueAndFlagsByTagString("ids,omitempty,pipes")
+	require.Equal(t, ParamStylePipes, ParamStyleFromFlags(flags))
+
+	require.Equal(t, "1,2", ParamStyleCSV.Join([]string{"1", "2"}))
+	require.Equal(t, []string{"1", "2"}, ParamStyleSSV.Split("1 2"))
+	require.Equal(t, []string{}, ParamStylePipes.Split(""))
+	require.False(t, ParamStyleDeepObject.IsDelimited())
+
+	prop, ok := PropOfDeepObjectKey("filter", DeepObjectKey("filter", "name"))
+	require.True(t, ok)
+	require.Equal(t, "name", prop)
+
+	_, ok = PropOfDeepObjectKey("filter", "filter[]")
+	require.False(t, ok)
+}
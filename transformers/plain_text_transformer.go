@@ -42,6 +42,10 @@ func (t *PlainTextTransformer) EncodeToWriter(w io.Writer, v interface{}) (strin
 	}
 
 	return superWrite(w, func(w io.Writer) error {
+		if ok, err := encodeDirectly(w, rv); ok {
+			return err
+		}
+
 		if reflectx.IsBytes(rv.Type()) {
 			_, err := w.Write(rv.Bytes())
 			return err
@@ -65,6 +69,9 @@ func (PlainTextTransformer) DecodeFromReader(r io.Reader, v interface{}, headers
 	if !ok {
 		rv = reflect.ValueOf(v)
 	}
+	if ok, err := decodeDirectly(r, rv); ok {
+		return err
+	}
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
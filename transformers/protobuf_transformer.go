@@ -0,0 +1,139 @@
+package transformers
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"reflect"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	TransformerMgrDefault.Register(&ProtobufTransformer{})
+}
+
+var rtypeProtoMessage = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// ProtobufTransformer for proto.Message bodies
+type ProtobufTransformer struct {
+}
+
+func (ProtobufTransformer) Names() []string {
+	return []string{httpx.MIME_PROTOBUF, "application/protobuf", "protobuf"}
+}
+
+func (ProtobufTransformer) NamedByTag() string {
+	return ""
+}
+
+func (t *ProtobufTransformer) String() string {
+	return t.Names()[0]
+}
+
+func (ProtobufTransformer) New(ctx context.Context, typ typesutil.Type) (Transformer, error) {
+	transformer := &ProtobufTransformer{}
+
+	// types from go/types (openapi generator) could not be checked without loading protobuf packages
+	if rtype, ok := typ.(*typesutil.RType); ok {
+		t := rtype.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if !reflect.PtrTo(t).Implements(rtypeProtoMessage) {
+			return nil, errors.Errorf("content transformer `%s` should be used for proto.Message, but got %s", transformer, rtype)
+		}
+	}
+
+	return transformer, nil
+}
+
+func (t *ProtobufTransformer) EncodeToWriter(w io.Writer, v interface{}) (string, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	m, err := protoMessageOf(rv, false)
+	if err != nil {
+		return "", err
+	}
+
+	return superWrite(w, func(w io.Writer) error {
+		if m == nil {
+			return nil
+		}
+		data, err := proto.Marshal(m)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}, t.String())
+}
+
+func (ProtobufTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	m, err := protoMessageOf(rv, true)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, m)
+}
+
+// protoMessageOf resolves pointer of message from value,
+// nil pointers will be allocated when alloc.
+func protoMessageOf(rv reflect.Value, alloc bool) (proto.Message, error) {
+	for rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			if !alloc {
+				return nil, nil
+			}
+			if !rv.CanSet() {
+				return nil, errors.Errorf("%s should be settable for decoding", rv.Type())
+			}
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		if rv.Type().Elem().Kind() == reflect.Ptr {
+			return protoMessageOf(rv.Elem(), alloc)
+		}
+	case reflect.Struct:
+		if !rv.CanAddr() {
+			if alloc {
+				return nil, errors.Errorf("%s should be addressable for decoding", rv.Type())
+			}
+			p := reflect.New(rv.Type())
+			p.Elem().Set(rv)
+			rv = p.Elem()
+		}
+		rv = rv.Addr()
+	}
+
+	if rv.IsValid() {
+		if m, ok := rv.Interface().(proto.Message); ok {
+			return m, nil
+		}
+		return nil, errors.Errorf("%s is not a proto.Message", rv.Type())
+	}
+
+	return nil, errors.New("invalid value for proto.Message")
+}
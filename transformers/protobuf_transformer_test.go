@@ -0,0 +1,56 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestProtobufTransformer(t *testing.T) {
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(structpb.Struct{})), TransformerOption{
+		MIME: "protobuf",
+	})
+	require.NoError(t, err)
+
+	data, _ := structpb.NewStruct(map[string]interface{}{"id": "1", "count": 2})
+
+	rw := httptest.NewRecorder()
+
+	_, err = ct.EncodeToWriter(rw, data)
+	require.NoError(t, err)
+	require.Equal(t, "application/x-protobuf", rw.Header().Get("Content-Type"))
+
+	t.Run("decode into struct field", func(t *testing.T) {
+		v := struct {
+			Data structpb.Struct
+		}{}
+		require.NoError(t, ct.DecodeFromReader(bytes.NewBuffer(rw.Body.Bytes()), reflect.ValueOf(&v).Elem().Field(0)))
+		require.True(t, proto.Equal(data, &v.Data))
+	})
+
+	t.Run("decode into nil pointer field", func(t *testing.T) {
+		v := struct {
+			Data *structpb.Struct
+		}{}
+		require.NoError(t, ct.DecodeFromReader(bytes.NewBuffer(rw.Body.Bytes()), reflect.ValueOf(&v).Elem().Field(0)))
+		require.True(t, proto.Equal(data, v.Data))
+	})
+
+	t.Run("decode failed", func(t *testing.T) {
+		require.Error(t, ct.DecodeFromReader(bytes.NewBufferString("\xff"), &structpb.Struct{}))
+	})
+
+	t.Run("non proto.Message", func(t *testing.T) {
+		_, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(struct{}{})), TransformerOption{
+			MIME: "protobuf",
+		})
+		require.Error(t, err)
+	})
+}
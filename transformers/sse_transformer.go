@@ -0,0 +1,236 @@
+package transformers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	TransformerMgrDefault.Register(&SSETransformer{})
+}
+
+// Event of server-sent events
+type Event struct {
+	ID    string
+	Event string
+	// string or []byte will be written as is, others will be encoded as json.
+	// decoded as string
+	Data  interface{}
+	Retry time.Duration
+}
+
+// DataInto unmarshal json data into v
+func (e *Event) DataInto(v interface{}) error {
+	switch data := e.Data.(type) {
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	case []byte:
+		return json.Unmarshal(data, v)
+	}
+	return errors.Errorf("unsupported data %T of event", e.Data)
+}
+
+// EventStream could be returned by operator to push server-sent events,
+// response will be finished once the channel closed.
+// producer should stop sending when context of request done.
+type EventStream <-chan *Event
+
+func (EventStream) ContentType() string {
+	return "text/event-stream"
+}
+
+type SSETransformer struct {
+}
+
+func (SSETransformer) Names() []string {
+	return []string{"text/event-stream", "sse"}
+}
+
+func (SSETransformer) NamedByTag() string {
+	return ""
+}
+
+func (t *SSETransformer) String() string {
+	return t.Names()[0]
+}
+
+func (SSETransformer) New(context.Context, typesutil.Type) (Transformer, error) {
+	return &SSETransformer{}, nil
+}
+
+// EncodeToWriter writes events of EventStream or chan *Event, and flushes per event
+func (t *SSETransformer) EncodeToWriter(w io.Writer, v interface{}) (string, error) {
+	if rv, ok := v.(reflect.Value); ok {
+		v = rv.Interface()
+	}
+
+	var events <-chan *Event
+
+	switch x := v.(type) {
+	case EventStream:
+		events = x
+	case <-chan *Event:
+		events = x
+	case chan *Event:
+		events = x
+	default:
+		return "", errors.Errorf("unsupported event stream %T", v)
+	}
+
+	if rw, ok := w.(interface{ Header() http.Header }); ok {
+		rw.Header().Set("Cache-Control", "no-cache")
+	}
+
+	return superWrite(w, func(w io.Writer) error {
+		flusher, _ := w.(http.Flusher)
+
+		for e := range events {
+			if err := WriteEvent(w, e); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	}, t.String())
+}
+
+// DecodeFromReader delivers events into chan *Event (closed when stream ended) or func(e *Event) error
+func (SSETransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...textproto.MIMEHeader) error {
+	if rv, ok := v.(reflect.Value); ok {
+		v = rv.Interface()
+	}
+
+	switch x := v.(type) {
+	case chan *Event:
+		defer close(x)
+		return ReadEvents(r, func(e *Event) error {
+			x <- e
+			return nil
+		})
+	case func(e *Event) error:
+		return ReadEvents(r, x)
+	}
+
+	return errors.Errorf("unsupported event receiver %T", v)
+}
+
+func WriteEvent(w io.Writer, e *Event) error {
+	buf := bytes.NewBuffer(nil)
+
+	if e.ID != "" {
+		_, _ = fmt.Fprintf(buf, "id: %s\n", e.ID)
+	}
+	if e.Event != "" {
+		_, _ = fmt.Fprintf(buf, "event: %s\n", e.Event)
+	}
+	if e.Retry > 0 {
+		_, _ = fmt.Fprintf(buf, "retry: %d\n", e.Retry/time.Millisecond)
+	}
+
+	var data []byte
+
+	switch x := e.Data.(type) {
+	case string:
+		data = []byte(x)
+	case []byte:
+		data = x
+	case nil:
+	default:
+		d, err := json.Marshal(x)
+		if err != nil {
+			return err
+		}
+		data = d
+	}
+
+	if e.Data != nil {
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			buf.WriteString("data: ")
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	buf.WriteByte('\n')
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadEvents parses events from r until EOF or cb returns error
+func ReadEvents(r io.Reader, cb func(e *Event) error) error {
+	reader := bufio.NewReader(r)
+
+	e := &Event{}
+	data := make([]string, 0)
+	hasField := false
+
+	dispatch := func() error {
+		if !hasField {
+			return nil
+		}
+		if len(data) > 0 {
+			e.Data = strings.Join(data, "\n")
+		}
+		err := cb(e)
+		e, data, hasField = &Event{}, make([]string, 0), false
+		return err
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		eof := err == io.EOF
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if e := dispatch(); e != nil {
+				return e
+			}
+		} else if !strings.HasPrefix(line, ":") {
+			field, value := line, ""
+			if i := strings.Index(line, ":"); i >= 0 {
+				field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+			}
+
+			hasField = true
+
+			switch field {
+			case "id":
+				e.ID = value
+			case "event":
+				e.Event = value
+			case "data":
+				data = append(data, value)
+			case "retry":
+				if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+					e.Retry = time.Duration(ms) * time.Millisecond
+				}
+			}
+		}
+
+		if eof {
+			// pending event without blank line will be discarded
+			return nil
+		}
+	}
+}
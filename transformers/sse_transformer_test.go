@@ -0,0 +1,78 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-courier/reflectx/typesutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSETransformer(t *testing.T) {
+	ct, err := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(EventStream(nil))), TransformerOption{
+		MIME: EventStream(nil).ContentType(),
+	})
+	require.NoError(t, err)
+
+	events := make(chan *Event, 3)
+	events <- &Event{ID: "1", Event: "progress", Data: map[string]int{"percent": 50}}
+	events <- &Event{Data: "line1\nline2", Retry: time.Second}
+	events <- &Event{ID: "2"}
+	close(events)
+
+	rw := httptest.NewRecorder()
+
+	_, err = ct.EncodeToWriter(rw, EventStream(events))
+	require.NoError(t, err)
+
+	require.True(t, rw.Flushed)
+	require.Equal(t, "text/event-stream", rw.Header().Get("Content-Type"))
+	require.Equal(t, `id: 1
+event: progress
+data: {"percent":50}
+
+retry: 1000
+data: line1
+data: line2
+
+id: 2
+
+`, rw.Body.String())
+
+	t.Run("decode by callback", func(t *testing.T) {
+		list := make([]*Event, 0)
+
+		err := ct.DecodeFromReader(bytes.NewBuffer(rw.Body.Bytes()), func(e *Event) error {
+			list = append(list, e)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+
+		v := map[string]int{}
+		require.NoError(t, list[0].DataInto(&v))
+		require.Equal(t, 50, v["percent"])
+		require.Equal(t, "progress", list[0].Event)
+		require.Equal(t, "line1\nline2", list[1].Data)
+		require.Equal(t, time.Second, list[1].Retry)
+	})
+
+	t.Run("decode into channel", func(t *testing.T) {
+		ch := make(chan *Event)
+
+		go func() {
+			_ = ct.DecodeFromReader(bytes.NewBufferString(": comment\nid: 1\ndata: a\n\nid: 2\ndata: b"), ch)
+		}()
+
+		ids := make([]string, 0)
+		for e := range ch {
+			ids = append(ids, e.ID)
+		}
+		// pending event without blank line discarded
+		require.Equal(t, []string{"1"}, ids)
+	})
+}
@@ -49,6 +49,13 @@ type Transformer interface {
 	String() string
 }
 
+// StreamEncoder could be implemented by Transformer
+// to stream large values (like files) instead of buffering whole body in memory.
+// mediaType should be resolved before writing, ok is false when value should not be streamed.
+type StreamEncoder interface {
+	StreamEncode(v interface{}) (mediaType string, writeTo func(w io.Writer) error, ok bool)
+}
+
 var TagNameKey = "name"
 var TagMIMEKey = "mime"
 
@@ -67,6 +74,8 @@ func TransformerOptionFromStructField(field typesutil.StructField) TransformerOp
 		if flags["omitempty"] {
 			opt.Omitempty = true
 		}
+		opt.Style = ParamStyleFromFlags(flags)
+		opt.Checkbox = flags[FlagCheckbox]
 	}
 
 	if opt.FieldName == "" {
@@ -79,6 +88,10 @@ func TransformerOptionFromStructField(field typesutil.StructField) TransformerOp
 type TransformerOption struct {
 	FieldName string
 	MIME      string
+	// style of parameter in query, not effect to transformer of value
+	Style ParamStyle
+	// bool field as checkbox in body of form or multipart, not effect to transformer of value
+	Checkbox bool
 	CommonTransformOption
 }
 
@@ -135,7 +148,7 @@ func (c *TransformerFactory) NewTransformer(ctx context.Context, typ typesutil.T
 		ctx = context.Background()
 	}
 
-	key := typesutil.FullTypeName(typ) + opt.String()
+	key := FullTypeName(typ) + opt.String()
 
 	if v, ok := c.cache.Load(key); ok {
 		return v.(Transformer), nil
@@ -158,6 +171,10 @@ func (c *TransformerFactory) NewTransformer(ctx context.Context, typ typesutil.T
 		if _, ok := typesutil.EncodingTextMarshalerTypeReplacer(typ); ok {
 			opt.MIME = "plain"
 		}
+
+		if isStream(typ) {
+			opt.MIME = "plain"
+		}
 	}
 
 	if ct, ok := c.transformerSet[opt.MIME]; ok {
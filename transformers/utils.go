@@ -29,6 +29,15 @@ func PtrTo(typ typesutil.Type) typesutil.Type {
 	return nil
 }
 
+// FullTypeName returns unique name of type,
+// generic instantiations (like Paged[User]) of types.Type will be named with type arguments
+func FullTypeName(typ typesutil.Type) string {
+	if ttype, ok := typ.(*typesutil.TType); ok {
+		return types.TypeString(ttype.Type, nil)
+	}
+	return typesutil.FullTypeName(typ)
+}
+
 func IsBytes(tpe typesutil.Type) bool {
 	if tpe.Kind() == reflect.String {
 		return false
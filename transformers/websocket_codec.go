@@ -0,0 +1,58 @@
+package transformers
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/reflectx/typesutil"
+)
+
+// NewWebSocketCodec encodes and decodes values of websocket messages by transformers of mgr,
+// json when mime empty. messages of text/*, json or xml sent as text, others as binary.
+func NewWebSocketCodec(mgr TransformerMgr, mime string) httpx.WebSocketCodec {
+	if mime == "" {
+		mime = "json"
+	}
+	return &webSocketCodec{mgr: mgr, mime: mime}
+}
+
+type webSocketCodec struct {
+	mgr  TransformerMgr
+	mime string
+}
+
+func (c *webSocketCodec) transformerOf(v interface{}) (Transformer, error) {
+	return c.mgr.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(v)), TransformerOption{
+		MIME: c.mime,
+	})
+}
+
+func (c *webSocketCodec) EncodeMessage(v interface{}) (httpx.WebSocketMessageType, []byte, error) {
+	transformer, err := c.transformerOf(v)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	b := bytes.NewBuffer(nil)
+
+	mediaType, err := transformer.EncodeToWriter(b, v)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if strings.HasPrefix(mediaType, "text/") || strings.Contains(mediaType, "json") || strings.Contains(mediaType, "xml") {
+		return httpx.WebSocketMessageText, b.Bytes(), nil
+	}
+	return httpx.WebSocketMessageBinary, b.Bytes(), nil
+}
+
+func (c *webSocketCodec) DecodeMessage(data []byte, v interface{}) error {
+	transformer, err := c.transformerOf(v)
+	if err != nil {
+		return err
+	}
+	return transformer.DecodeFromReader(bytes.NewReader(data), v)
+}
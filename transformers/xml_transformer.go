@@ -1,14 +1,19 @@
 package transformers
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
+	"go/ast"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net/textproto"
 	"reflect"
+	"strings"
 
 	"github.com/go-courier/reflectx/typesutil"
+	"github.com/go-courier/validator/errors"
 )
 
 func init() {
@@ -26,6 +31,10 @@ func (t *XMLTransformer) String() string {
 	return t.Names()[0]
 }
 
+func (XMLTransformer) Sniff(head []byte) bool {
+	return firstNonSpace(head) == '<'
+}
+
 func (XMLTransformer) NamedByTag() string {
 	return "xml"
 }
@@ -53,11 +62,171 @@ func (XMLTransformer) DecodeFromReader(r io.Reader, v interface{}, headers ...te
 		}
 		v = rv.Interface()
 	}
-	d := xml.NewDecoder(r)
-	err := d.Decode(v)
-	if err != nil {
-		// todo resolve field path by InputOffset()
-		return err
+
+	data, errForRead := ioutil.ReadAll(r)
+	if errForRead != nil {
+		return errForRead
+	}
+
+	d := xml.NewDecoder(bytes.NewBuffer(data))
+	if err := d.Decode(v); err != nil {
+		switch e := err.(type) {
+		case *xml.SyntaxError:
+			return e
+		default:
+			if offset := d.InputOffset(); offset > 0 {
+				errSet := errors.NewErrorSet("")
+				errSet.AddErr(e, xmlLocation(data, offset, reflect.TypeOf(v))...)
+				return errSet.Err()
+			}
+			return e
+		}
 	}
 	return nil
 }
+
+type xmlFrame struct {
+	// type of element value, nil when unknown
+	typ reflect.Type
+	// rest element names of field tag like `xml:"a>b"`, and type of final element
+	chain    []string
+	chainTyp reflect.Type
+	// count of child elements by name, for index of slice items
+	counts map[string]int
+	// path segments entered by this element
+	entered int
+}
+
+// xmlLocation resolves path of element by xml names (like `Data.StructSlice[1].Name`),
+// which value decoded failed before offset.
+// slice items are indexed by the type of v.
+func xmlLocation(data []byte, offset int64, typ reflect.Type) []interface{} {
+	d := xml.NewDecoder(bytes.NewBuffer(data))
+	pathWalker := &PathWalker{}
+	frames := make([]*xmlFrame, 0)
+
+	lastClosed := []interface{}(nil)
+
+	for d.InputOffset() < offset {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			lastClosed = nil
+
+			if len(frames) == 0 {
+				// root element is the value self
+				frames = append(frames, &xmlFrame{typ: typ, counts: map[string]int{}})
+				continue
+			}
+
+			parent := frames[len(frames)-1]
+			frame := &xmlFrame{counts: map[string]int{}}
+
+			pathWalker.Enter(t.Name.Local)
+			frame.entered = 1
+
+			idx := parent.counts[t.Name.Local]
+			parent.counts[t.Name.Local]++
+
+			var fieldTyp reflect.Type
+
+			if len(parent.chain) > 0 {
+				if parent.chain[0] == t.Name.Local {
+					if len(parent.chain) > 1 {
+						frame.chain, frame.chainTyp = parent.chain[1:], parent.chainTyp
+					} else {
+						fieldTyp = parent.chainTyp
+					}
+				}
+			} else if parent.typ != nil {
+				chain, ft := xmlFieldOf(parent.typ, t.Name.Local)
+				if len(chain) > 0 {
+					frame.chain, frame.chainTyp = chain, ft
+				} else {
+					fieldTyp = ft
+				}
+			}
+
+			if fieldTyp != nil {
+				for fieldTyp.Kind() == reflect.Ptr {
+					fieldTyp = fieldTyp.Elem()
+				}
+				if fieldTyp.Kind() == reflect.Slice && fieldTyp.Elem().Kind() != reflect.Uint8 {
+					pathWalker.Enter(idx)
+					frame.entered++
+					fieldTyp = fieldTyp.Elem()
+				}
+				frame.typ = fieldTyp
+			}
+
+			frames = append(frames, frame)
+		case xml.EndElement:
+			lastClosed = append([]interface{}{}, pathWalker.Paths()...)
+
+			if len(frames) > 0 {
+				frame := frames[len(frames)-1]
+				frames = frames[:len(frames)-1]
+				for i := 0; i < frame.entered; i++ {
+					pathWalker.Exit()
+				}
+			}
+		}
+	}
+
+	if len(lastClosed) > 0 {
+		return lastClosed
+	}
+	return append([]interface{}{}, pathWalker.Paths()...)
+}
+
+// xmlFieldOf finds field of struct by element name,
+// returns rest element names when tag like `xml:"a>b"`
+func xmlFieldOf(typ reflect.Type, name string) ([]string, reflect.Type) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+
+		if !ast.IsExported(f.Name) || f.Name == "XMLName" {
+			continue
+		}
+
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		tagName, flags := TagValueAndFlagsByTagString(tag)
+		if flags["attr"] || flags["chardata"] || flags["innerxml"] || flags["comment"] || flags["any"] {
+			continue
+		}
+
+		if f.Anonymous && tagName == "" {
+			if chain, ft := xmlFieldOf(f.Type, name); ft != nil {
+				return chain, ft
+			}
+			continue
+		}
+
+		if tagName == "" {
+			tagName = f.Name
+		}
+
+		names := strings.Split(tagName, ">")
+		if names[0] == name {
+			return names[1:], f.Type
+		}
+	}
+
+	return nil, nil
+}
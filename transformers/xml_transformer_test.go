@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/go-courier/reflectx/typesutil"
+	verrors "github.com/go-courier/validator/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,3 +66,70 @@ func TestXMLTransformer(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestXMLTransformer_ErrorLocation(t *testing.T) {
+	type TestData struct {
+		Data struct {
+			Bool        bool
+			Age         int `xml:"profile>age"`
+			StructSlice []struct {
+				Name int `xml:"name"`
+			}
+			IntSlice    []int
+			NestedSlice []struct {
+				Values []int
+			}
+		}
+	}
+
+	ct, _ := TransformerMgrDefault.NewTransformer(context.Background(), typesutil.FromRType(reflect.TypeOf(TestData{})), TransformerOption{
+		MIME: "xml",
+	})
+
+	cases := []struct {
+		xml      string
+		location string
+	}{
+		{
+			"<TestData><Data><Bool>bool</Bool></Data></TestData>",
+			"Data.Bool",
+		},
+		{
+			"<TestData><Data><profile><age>x</age></profile></Data></TestData>",
+			"Data.profile.age",
+		},
+		{
+			`<TestData><Data>
+	<StructSlice><name>1</name></StructSlice>
+	<StructSlice><name>1</name></StructSlice>
+	<StructSlice><name>x</name></StructSlice>
+</Data></TestData>`,
+			"Data.StructSlice[2].name",
+		},
+		{
+			"<TestData><Data><IntSlice>1</IntSlice><IntSlice>x</IntSlice></Data></TestData>",
+			"Data.IntSlice[1]",
+		},
+		{
+			`<TestData><Data>
+	<Bool>true</Bool>
+	<NestedSlice><Values>1</Values></NestedSlice>
+	<NestedSlice><Values>1</Values><Values>2</Values><Values>x</Values></NestedSlice>
+</Data></TestData>`,
+			"Data.NestedSlice[1].Values[2]",
+		},
+	}
+
+	for _, c := range cases {
+		data := TestData{}
+		err := ct.DecodeFromReader(bytes.NewBufferString(c.xml), &data)
+		require.Error(t, err)
+
+		errSet, ok := err.(*verrors.ErrorSet)
+		require.True(t, ok, err.Error())
+
+		errSet.Each(func(fieldErr *verrors.FieldError) {
+			require.Equal(t, c.location, fieldErr.Field.String())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package httptransport
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// UploadProgress will be called with total bytes written of streaming request body.
+// It is called on the goroutine writing body into pipe, not the one sending request,
+// so values shared with other goroutines should be synchronized.
+type UploadProgress func(written int64)
+
+type contextKeyUploadProgress int
+
+// ContextWithUploadProgress to track progress of streaming request bodies, like multipart with files
+func ContextWithUploadProgress(ctx context.Context, progress UploadProgress) context.Context {
+	return context.WithValue(ctx, contextKeyUploadProgress(1), progress)
+}
+
+func UploadProgressFromContext(ctx context.Context) UploadProgress {
+	if ctx == nil {
+		return nil
+	}
+	progress, _ := ctx.Value(contextKeyUploadProgress(1)).(UploadProgress)
+	return progress
+}
+
+func newStreamBody(writeTo func(w io.Writer) error, progress UploadProgress) *streamBody {
+	pr, pw := io.Pipe()
+
+	return &streamBody{
+		pr:       pr,
+		pw:       pw,
+		writeTo:  writeTo,
+		progress: progress,
+	}
+}
+
+// streamBody writes body into pipe when first read,
+// so no goroutine leaks when request never sent
+type streamBody struct {
+	pr       *io.PipeReader
+	pw       *io.PipeWriter
+	writeTo  func(w io.Writer) error
+	progress UploadProgress
+	once     sync.Once
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			var w io.Writer = b.pw
+			if b.progress != nil {
+				w = &progressWriter{Writer: b.pw, progress: b.progress}
+			}
+			b.pw.CloseWithError(b.writeTo(w))
+		}()
+	})
+	return b.pr.Read(p)
+}
+
+func (b *streamBody) Close() error {
+	// unblock writing when body closed before fully read
+	return b.pr.Close()
+}
+
+type progressWriter struct {
+	io.Writer
+	written  int64
+	progress UploadProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if n > 0 {
+		w.written += int64(n)
+		w.progress(w.written)
+	}
+	return n, err
+}
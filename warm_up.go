@@ -0,0 +1,86 @@
+package httptransport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// WarmUp runs before accepting traffic, like connecting to dependencies or priming caches,
+// handler is the public handler with middlewares, for self-calling routes in process.
+// Serving will be aborted when any WarmUp failed.
+type WarmUp func(ctx context.Context, handler http.Handler) error
+
+// SmokeCheck creates WarmUp which calls route of method and path in process,
+// failed when response status code not 2xx.
+func SmokeCheck(method string, path string) WarmUp {
+	return func(ctx context.Context, handler http.Handler) error {
+		req := httptest.NewRequest(method, path, nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+
+		handler.ServeHTTP(rw, req)
+
+		if rw.Code < http.StatusOK || rw.Code >= http.StatusMultipleChoices {
+			return errors.Errorf("smoke check %s %s failed: %d %s", method, path, rw.Code, rw.Body.String())
+		}
+		return nil
+	}
+}
+
+// Readiness of HttpTransport, exposed on /readyz of admin listener,
+// ready only after all WarmUps passed and until shutdown.
+type Readiness struct {
+	mu    sync.RWMutex
+	ready bool
+	err   error
+}
+
+func (r *Readiness) set(ready bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ready = ready
+	r.err = err
+}
+
+func (r *Readiness) Ready() (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.ready, r.err
+}
+
+func (r *Readiness) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ready, err := r.Ready()
+	if !ready {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		if err != nil {
+			_, _ = fmt.Fprintf(rw, "not ready: %s", err)
+			return
+		}
+		_, _ = rw.Write([]byte("not ready"))
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write([]byte("ok"))
+}
+
+func (t *HttpTransport) warmUp(ctx context.Context, handler http.Handler) error {
+	for i := range t.WarmUps {
+		if err := t.WarmUps[i](ctx, handler); err != nil {
+			err = errors.Wrapf(err, "warm up of %s failed", t.ServiceMeta)
+			t.readiness.set(false, err)
+			return err
+		}
+	}
+	t.readiness.set(true, nil)
+	return nil
+}
+
+func (t *HttpTransport) Readiness() *Readiness {
+	return &t.readiness
+}
@@ -0,0 +1,76 @@
+package httptransport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+type WarmUpPing struct {
+	httpx.MethodGet
+}
+
+func (WarmUpPing) Output(ctx context.Context) (interface{}, error) {
+	return "pong", nil
+}
+
+func TestWarmUp(t *testing.T) {
+	router := courier.NewRouter(httptransport.Group("/root"))
+	router.Register(courier.NewRouter(WarmUpPing{}))
+
+	t.Run("smoke check", func(t *testing.T) {
+		handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/root" {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		})
+
+		require.NoError(t, httptransport.SmokeCheck(http.MethodGet, "/root")(context.Background(), handler))
+		require.Error(t, httptransport.SmokeCheck(http.MethodGet, "/missing")(context.Background(), handler))
+	})
+
+	t.Run("failed warm up aborts serving", func(t *testing.T) {
+		called := make([]string, 0)
+
+		ht := &httptransport.HttpTransport{
+			WarmUps: []httptransport.WarmUp{
+				func(ctx context.Context, handler http.Handler) error {
+					called = append(called, "prime")
+					return httptransport.SmokeCheck(http.MethodGet, "/root")(ctx, handler)
+				},
+				func(ctx context.Context, handler http.Handler) error {
+					called = append(called, "db")
+					return errors.New("connect refused")
+				},
+				func(ctx context.Context, handler http.Handler) error {
+					called = append(called, "never")
+					return nil
+				},
+			},
+		}
+
+		err := ht.ServeContext(context.Background(), router)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connect refused")
+		require.Equal(t, []string{"prime", "db"}, called)
+
+		ready, readyErr := ht.Readiness().Ready()
+		require.False(t, ready)
+		require.Equal(t, err, readyErr)
+
+		rw := httptest.NewRecorder()
+		ht.Readiness().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+		require.Contains(t, rw.Body.String(), "connect refused")
+	})
+}
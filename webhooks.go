@@ -0,0 +1,26 @@
+package httptransport
+
+// WebhookDescriber could be implemented by operators
+// to declare outbound notifications sent after the operator, for documenting async contracts.
+// Only documented by openapi generator, Webhooks should return composite literals for static scanning,
+//
+//	func (CreatePayment) Webhooks() []httptransport.Webhook {
+//		return []httptransport.Webhook{
+//			{Name: "onPaid", URL: "{$request.body#/callbackUrl}", Payload: &PaymentPaid{}},
+//		}
+//	}
+type WebhookDescriber interface {
+	Webhooks() []Webhook
+}
+
+type Webhook struct {
+	// name of callback or webhook, unique in operator
+	Name string
+	// method of request sent, POST when empty
+	Method string
+	// runtime expression of url of subscriber, like {$request.body#/callbackUrl},
+	// documented as callback of operation; when empty, documented as webhook subscribed out of band
+	URL string
+	// value of payload type, sent as json body
+	Payload interface{}
+}
@@ -0,0 +1,99 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-courier/statuserror"
+	"github.com/pkg/errors"
+)
+
+// WorkerPoolDescriber could be implemented by operators of route group
+// to run the route in the named worker pool of HttpTransport.WorkerPools,
+// so cpu intensive routes can't starve cheap routes of goroutines and cpu.
+type WorkerPoolDescriber interface {
+	WorkerPool() string
+}
+
+func (route *HttpRouteMeta) WorkerPool() string {
+	if m := route.nearestRouteMeta(func(m *RouteMeta) bool { return m.WorkerPool != "" }); m != nil {
+		return m.WorkerPool
+	}
+	return ""
+}
+
+// WorkerPool runs jobs by bounded workers,
+// jobs will be rejected with 503 when all workers busy and queue full.
+type WorkerPool struct {
+	// workers, default runtime.GOMAXPROCS(0)
+	Size int
+	// max waiting jobs, default Size
+	QueueSize int
+
+	once sync.Once
+	jobs chan *workerPoolJob
+}
+
+func (p *WorkerPool) SetDefaults() {
+	if p.Size == 0 {
+		p.Size = runtime.GOMAXPROCS(0)
+	}
+	if p.QueueSize == 0 {
+		p.QueueSize = p.Size
+	}
+}
+
+type workerPoolJob struct {
+	fn func()
+	// 1 when started, 2 when canceled before started
+	state int32
+	done  chan struct{}
+}
+
+func (p *WorkerPool) start() {
+	p.once.Do(func() {
+		p.SetDefaults()
+
+		p.jobs = make(chan *workerPoolJob, p.QueueSize)
+
+		for i := 0; i < p.Size; i++ {
+			go func() {
+				for job := range p.jobs {
+					if atomic.CompareAndSwapInt32(&job.state, 0, 1) {
+						job.fn()
+					}
+					close(job.done)
+				}
+			}()
+		}
+	})
+}
+
+// Do runs fn in worker and waits until done,
+// returns error when queue full or ctx done before fn started.
+func (p *WorkerPool) Do(ctx context.Context, fn func()) error {
+	p.start()
+
+	job := &workerPoolJob{fn: fn, done: make(chan struct{})}
+
+	select {
+	case p.jobs <- job:
+	default:
+		return statuserror.Wrap(errors.New("worker pool is busy"), http.StatusServiceUnavailable, "WorkerPoolBusy")
+	}
+
+	select {
+	case <-job.done:
+		return nil
+	case <-ctx.Done():
+		if atomic.CompareAndSwapInt32(&job.state, 0, 2) {
+			return ctx.Err()
+		}
+		// already started, fn must be finished before return
+		<-job.done
+		return nil
+	}
+}
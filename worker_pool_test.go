@@ -0,0 +1,127 @@
+package httptransport_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-courier/courier"
+	"github.com/go-courier/httptransport/httpx"
+	"github.com/go-courier/statuserror"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-courier/httptransport"
+)
+
+type HeavyReport struct {
+	httpx.MethodGet
+}
+
+func (HeavyReport) WorkerPool() string {
+	return "heavy"
+}
+
+func (HeavyReport) Output(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestWorkerPool(t *testing.T) {
+	t.Run("worker pool of route", func(t *testing.T) {
+		router := courier.NewRouter(httptransport.Group("/root"))
+		router.Register(courier.NewRouter(HeavyReport{}))
+
+		httpRoute := httptransport.NewHttpRouteMeta(router.Routes()[0])
+		require.Equal(t, "heavy", httpRoute.WorkerPool())
+	})
+
+	t.Run("bounded workers", func(t *testing.T) {
+		pool := &httptransport.WorkerPool{Size: 2, QueueSize: 10}
+
+		running := int32(0)
+		maxRunning := int32(0)
+
+		wg := sync.WaitGroup{}
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := pool.Do(context.Background(), func() {
+					n := atomic.AddInt32(&running, 1)
+					for {
+						m := atomic.LoadInt32(&maxRunning)
+						if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+				})
+				require.NoError(t, err)
+			}()
+		}
+
+		wg.Wait()
+		require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	})
+
+	t.Run("busy when queue full", func(t *testing.T) {
+		pool := &httptransport.WorkerPool{Size: 1, QueueSize: 1}
+
+		blocked := make(chan struct{})
+		started := make(chan struct{})
+
+		go func() {
+			_ = pool.Do(context.Background(), func() {
+				close(started)
+				<-blocked
+			})
+		}()
+		<-started
+
+		go func() {
+			_ = pool.Do(context.Background(), func() {})
+		}()
+
+		require.Eventually(t, func() bool {
+			// when queued before filler, cancel to retry
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err := pool.Do(ctx, func() {})
+			statusErr, ok := statuserror.IsStatusErr(err)
+			return ok && statusErr.Key == "WorkerPoolBusy"
+		}, time.Second, time.Millisecond)
+
+		close(blocked)
+	})
+
+	t.Run("canceled before started", func(t *testing.T) {
+		pool := &httptransport.WorkerPool{Size: 1, QueueSize: 1}
+
+		blocked := make(chan struct{})
+		started := make(chan struct{})
+		defer close(blocked)
+
+		go func() {
+			_ = pool.Do(context.Background(), func() {
+				close(started)
+				<-blocked
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		called := false
+		err := pool.Do(ctx, func() {
+			called = true
+		})
+		require.Equal(t, context.DeadlineExceeded, err)
+		require.False(t, called)
+	})
+}